@@ -103,6 +103,14 @@ func obtainGethWallet(chainID *big.Int, address common.Address) (accounts.Wallet
 }
 
 func obtainGethWallets(chainID *big.Int) ([]accounts.Wallet, error) {
+	backends := []accounts.Backend{keystore.NewKeyStore(GethKeystoreDir(chainID), keystore.StandardScryptN, keystore.StandardScryptP)}
+	accountManager := accounts.NewManager(nil, backends...)
+	defer accountManager.Close()
+	return accountManager.Wallets(), nil
+}
+
+// GethKeystoreDir returns the directory of the geth keystore for a given chain
+func GethKeystoreDir(chainID *big.Int) string {
 	keydir := DefaultDataDir()
 	if chainID.Cmp(params.MainnetChainConfig.ChainID) == 0 {
 		// Nothing to add for mainnet
@@ -113,11 +121,7 @@ func obtainGethWallets(chainID *big.Int) ([]accounts.Wallet, error) {
 	} else if chainID.Cmp(params.GoerliChainConfig.ChainID) == 0 {
 		keydir = filepath.Join(keydir, "goerli")
 	}
-	keydir = filepath.Join(keydir, "keystore")
-	backends := []accounts.Backend{keystore.NewKeyStore(keydir, keystore.StandardScryptN, keystore.StandardScryptP)}
-	accountManager := accounts.NewManager(nil, backends...)
-	defer accountManager.Close()
-	return accountManager.Wallets(), nil
+	return filepath.Join(keydir, "keystore")
 }
 
 func obtainParityWallet(chainID *big.Int, address common.Address) (accounts.Wallet, error) {
@@ -20,9 +20,9 @@ import (
 // accountCmd represents the account command
 var accountCmd = &cobra.Command{
 	Use:     "account",
-	Aliases: []string{"acc"},
+	Aliases: []string{"acc", "address"},
 	Short:   "Manage accounts",
-	Long:    `Obtain information about Ethereum accounts.`,
+	Long:    `Obtain information about Ethereum accounts.  These commands are also available as "address", for example "ethereal address transfers".`,
 }
 
 func init() {
@@ -0,0 +1,78 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	string2eth "github.com/wealdtech/go-string2eth"
+)
+
+var accountInfoAddress string
+
+// accountInfoCmd represents the account info command
+var accountInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Obtain information about an account",
+	Long: `Obtain information about an account, including its balance, nonce and ENS name.  For example:
+
+    ethereal account info --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4
+
+In quiet mode this will return 0 if the account information can be obtained, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(accountInfoAddress != "", quiet, "--address is required")
+		address, err := util.ResolveAddress(client, accountInfoAddress)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain address of %s", accountInfoAddress))
+
+		ctx, cancel := localContext()
+		defer cancel()
+		balance, err := client.BalanceAt(ctx, address, nil)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain balance for %s", accountInfoAddress))
+		nonce, err := client.NonceAt(ctx, address, nil)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain nonce for %s", accountInfoAddress))
+		pendingNonce, err := client.PendingNonceAt(ctx, address)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain pending nonce for %s", accountInfoAddress))
+		code, err := client.CodeAt(ctx, address, nil)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain code for %s", accountInfoAddress))
+
+		if quiet {
+			os.Exit(_exit_success)
+		}
+
+		fmt.Printf("Address:\t%s\n", address.Hex())
+		name, err := util.ENSReverseResolve(client, address)
+		if err == nil && name != "" {
+			fmt.Printf("ENS name:\t%s\n", name)
+		}
+		fmt.Printf("Balance:\t%s\n", string2eth.WeiToString(balance, true))
+		fmt.Printf("Nonce:\t\t%d\n", nonce)
+		if pendingNonce != nonce {
+			fmt.Printf("Pending nonce:\t%d\n", pendingNonce)
+		}
+		if len(code) > 0 {
+			fmt.Printf("Contract:\tyes (%d bytes of code)\n", len(code))
+		} else {
+			fmt.Printf("Contract:\tno\n")
+		}
+	},
+}
+
+func init() {
+	accountCmd.AddCommand(accountInfoCmd)
+	accountInfoCmd.Flags().StringVar(&accountInfoAddress, "address", "", "Address of the account for which to obtain information")
+}
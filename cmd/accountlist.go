@@ -19,7 +19,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
-	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/ethereal/util"
 	string2eth "github.com/wealdtech/go-string2eth"
 )
 
@@ -46,7 +46,7 @@ In quiet mode this will return 0 if any accounts are found, otherwise 1.`,
 							fmt.Printf("Location:\t%s\n", account.URL)
 							fmt.Printf("Address:\t%s\n", account.Address.Hex())
 							if !offline {
-								name, err := ens.ReverseResolve(client, account.Address)
+								name, err := util.ENSReverseResolve(client, account.Address)
 								if err == nil {
 									fmt.Printf("Name:\t\t%s\n", name)
 								}
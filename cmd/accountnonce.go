@@ -14,39 +14,62 @@
 package cmd
 
 import (
-	"context"
 	"fmt"
+	"os"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 	"github.com/wealdtech/ethereal/cli"
-	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/ethereal/util"
 )
 
 var accountNonceAddress string
+var accountNonceJSON bool
 
 // accountNonceCmd represents the account nonce command
 var accountNonceCmd = &cobra.Command{
 	Use:   "nonce",
 	Short: "Obtain the current nonce for an account",
-	Long: `Obtain the current nonce for an account, taking in to account pending transctions.  For example:
+	Long: `Obtain the confirmed and pending nonces for an account.  For example:
 
     ethereal account nonce --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4
 
-In quiet mode this will return 0 if the nonce can be obtained, otherwise 1.`,
+This outputs both the confirmed nonce, that of the latest block, and the pending nonce, which also takes in to account transactions in the transaction pool and is the nonce for the next transaction from the account.  If they differ then the number of pending transactions is shown, which can be useful when diagnosing stuck or queued transactions.
+
+The --json flag outputs the nonces as JSON.
+
+In quiet mode this will return 0 if the account has no pending transactions, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(accountNonceAddress != "", quiet, "--address is required")
-		address, err := ens.Resolve(client, accountNonceAddress)
+		address, err := util.ResolveAddress(client, accountNonceAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain address of %s", accountNonceAddress))
 
-		ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("timeout"))
+		ctx, cancel := localContext()
 		defer cancel()
 
-		nonce, err := client.PendingNonceAt(ctx, address)
+		nonce, err := client.NonceAt(ctx, address, nil)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain nonce for %s", accountNonceAddress))
+		pendingNonce, err := client.PendingNonceAt(ctx, address)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain pending nonce for %s", accountNonceAddress))
+
+		if quiet {
+			if pendingNonce != nonce {
+				os.Exit(_exit_failure)
+			}
+			os.Exit(_exit_success)
+		}
+
+		data := newJSONNonce(address, nonce, pendingNonce)
+		if accountNonceJSON {
+			cli.ErrCheck(outputJSON(cmd, data), quiet, "Failed to output JSON")
+			return
+		}
+		cli.WarnCheck(recordJSON(cmd, data), quiet, "Failed to record JSON output")
 
-		if !quiet {
-			fmt.Println(nonce)
+		fmt.Printf("Nonce:\t\t%d\n", nonce)
+		if pendingNonce > nonce {
+			fmt.Printf("Pending nonce:\t%d (%d pending transaction(s))\n", pendingNonce, pendingNonce-nonce)
+		} else {
+			fmt.Printf("Pending nonce:\t%d\n", pendingNonce)
 		}
 	},
 }
@@ -54,4 +77,6 @@ In quiet mode this will return 0 if the nonce can be obtained, otherwise 1.`,
 func init() {
 	accountCmd.AddCommand(accountNonceCmd)
 	accountNonceCmd.Flags().StringVar(&accountNonceAddress, "address", "", "Address of the account for which to obtain the nonce")
+	accountNonceCmd.Flags().BoolVar(&accountNonceJSON, "json", false, "Display output as JSON")
+	jsonoutCmds["account:nonce"] = true
 }
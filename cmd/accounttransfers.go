@@ -0,0 +1,108 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	string2eth "github.com/wealdtech/go-string2eth"
+)
+
+var accountTransfersAddress string
+var accountTransfersFromBlock int64
+var accountTransfersToBlock int64
+var accountTransfersSince time.Duration
+var accountTransfersMaxBlocks uint64
+
+// accountTransfersCmd represents the account transfers command
+var accountTransfersCmd = &cobra.Command{
+	Use:   "transfers",
+	Short: "Obtain internal Ether transfers for an account",
+	Long: `Obtain the internal Ether transfers to and from an address, that is transfers made by contracts rather than directly by transactions.  For example:
+
+    ethereal address transfers --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --fromblock=10000000 --toblock=10001000
+
+Alternatively the range can start from a period of time ago, for example --since=24h.  The starting block is found from the timestamps of blocks.
+
+If --toblock is not supplied then transfers up to the latest block are obtained.
+
+This requires a connection that supports trace_filter.  If it does not then each block is traced individually with debug_traceBlockByNumber, which is considerably slower and requires an archive node for historical blocks; in this case the range is limited to --maxblocks blocks (default 1000), and progress is shown with --verbose.  Other failures of trace_filter, such as timeouts, are reported rather than falling back to tracing blocks.
+
+In quiet mode this will return 0 if any transfers are found, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(accountTransfersAddress != "", quiet, "--address is required")
+		address, err := util.ResolveAddress(client, accountTransfersAddress)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain address of %s", accountTransfersAddress))
+		cli.Assert(accountTransfersFromBlock >= 0 || accountTransfersSince > 0, quiet, "--fromblock or --since is required")
+		cli.Assert(accountTransfersFromBlock < 0 || accountTransfersSince == 0, quiet, "only one of --fromblock and --since can be supplied")
+
+		toBlock := uint64(accountTransfersToBlock)
+		if accountTransfersToBlock < 0 {
+			toBlock = latestHeader().Number.Uint64()
+		}
+		fromBlock := uint64(accountTransfersFromBlock)
+		if accountTransfersSince > 0 {
+			fromBlock = sinceBlock(accountTransfersSince)
+		}
+		cli.Assert(fromBlock <= toBlock, quiet, "--fromblock must not be after --toblock")
+
+		ctx, cancel := localContext()
+		transfers, err := util.InternalTransfersFromTraceFilter(ctx, rpcClient, address, fromBlock, toBlock)
+		cancel()
+		if err != nil {
+			cli.Assert(util.IsMethodNotFound(err), quiet, fmt.Sprintf("Failed to obtain traces with trace_filter: %v", err))
+			blocks := toBlock - fromBlock + 1
+			cli.Assert(blocks <= accountTransfersMaxBlocks, quiet, fmt.Sprintf("Connection does not support trace_filter, and tracing %d blocks individually exceeds --maxblocks; reduce the range or increase --maxblocks", blocks))
+			outputIf(verbose, fmt.Sprintf("Connection does not support trace_filter; tracing %d blocks individually", blocks))
+			transfers = make([]*util.InternalTransfer, 0)
+			for block := fromBlock; block <= toBlock; block++ {
+				ctx, cancel := localContext()
+				blockTransfers, err := util.InternalTransfersFromBlockTrace(ctx, rpcClient, address, block)
+				cancel()
+				cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to trace block %d; the connection must support either trace_filter or debug_traceBlockByNumber", block))
+				outputIf(verbose, fmt.Sprintf("Traced block %d (%d of %d)", block, block-fromBlock+1, blocks))
+				transfers = append(transfers, blockTransfers...)
+			}
+		}
+
+		if quiet {
+			if len(transfers) == 0 {
+				os.Exit(_exit_failure)
+			}
+			os.Exit(_exit_success)
+		}
+
+		for _, transfer := range transfers {
+			if transfer.To == address {
+				fmt.Printf("%d\t%s\t%s\tfrom %s\n", transfer.BlockNumber, transfer.TxHash.Hex(), string2eth.WeiToString(transfer.Value, true), util.ENSFormat(client, transfer.From))
+			} else {
+				fmt.Printf("%d\t%s\t-%s\tto %s\n", transfer.BlockNumber, transfer.TxHash.Hex(), string2eth.WeiToString(transfer.Value, true), util.ENSFormat(client, transfer.To))
+			}
+		}
+	},
+}
+
+func init() {
+	accountCmd.AddCommand(accountTransfersCmd)
+	accountTransfersCmd.Flags().StringVar(&accountTransfersAddress, "address", "", "Address of the account for which to obtain transfers")
+	accountTransfersCmd.Flags().Int64Var(&accountTransfersFromBlock, "fromblock", -1, "Block from which to obtain transfers")
+	accountTransfersCmd.Flags().DurationVar(&accountTransfersSince, "since", 0, "Time before now from which to obtain transfers, for example 24h (instead of --fromblock)")
+	accountTransfersCmd.Flags().Int64Var(&accountTransfersToBlock, "toblock", -1, "Block up to which to obtain transfers (defaults to latest)")
+	accountTransfersCmd.Flags().Uint64Var(&accountTransfersMaxBlocks, "maxblocks", 1000, "Maximum number of blocks to trace individually if the connection does not support trace_filter")
+}
@@ -0,0 +1,156 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+)
+
+var accountVanityPrefix string
+var accountVanitySuffix string
+var accountVanityWorkers int
+var accountVanityChecksum bool
+var accountVanityPassphrase string
+var accountVanityShowPrivateKey bool
+
+var accountVanityHexRe = regexp.MustCompile("^[0-9a-fA-F]*$")
+
+// accountVanityCmd represents the account vanity command
+var accountVanityCmd = &cobra.Command{
+	Use:   "vanity",
+	Short: "Generate an account with a vanity address",
+	Long: `Generate an account whose address starts and/or ends with given hex characters.  For example:
+
+    ethereal account vanity --prefix=dead --suffix=beef --passphrase=secret
+
+The account will be stored in the local keystore, encrypted with the supplied passphrase.  Alternatively the private key can be printed by supplying --showprivatekey, although this is not recommended as the key could be exposed.
+
+Matching is case-insensitive unless --checksum is supplied, in which case the prefix and suffix must match the checksummed address exactly.  Each additional character increases the time required by a factor of around 16, or more with --checksum.
+
+In quiet mode this will return 0 if an account is generated, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(accountVanityPrefix != "" || accountVanitySuffix != "", quiet, "at least one of --prefix and --suffix is required")
+		cli.Assert(accountVanityHexRe.MatchString(accountVanityPrefix), quiet, "--prefix must contain only hex characters")
+		cli.Assert(accountVanityHexRe.MatchString(accountVanitySuffix), quiet, "--suffix must contain only hex characters")
+		cli.Assert(len(accountVanityPrefix)+len(accountVanitySuffix) <= 40, quiet, "--prefix and --suffix are too long")
+		cli.Assert(accountVanityPassphrase != "" || accountVanityShowPrivateKey, quiet, "one of --passphrase or --showprivatekey is required")
+		cli.Assert(accountVanityWorkers > 0, quiet, "--workers must be at least 1")
+
+		prefix := accountVanityPrefix
+		suffix := accountVanitySuffix
+		if !accountVanityChecksum {
+			prefix = strings.ToLower(prefix)
+			suffix = strings.ToLower(suffix)
+		}
+
+		// Expected number of attempts is 16 per character, plus 2 per letter if checksummed
+		expected := math.Pow(16, float64(len(prefix)+len(suffix)))
+		if accountVanityChecksum {
+			letters := len(regexp.MustCompile("[a-fA-F]").FindAllString(prefix+suffix, -1))
+			expected *= math.Pow(2, float64(letters))
+		}
+
+		var attempts uint64
+		found := make(chan *ecdsa.PrivateKey, 1)
+		done := make(chan struct{})
+		for i := 0; i < accountVanityWorkers; i++ {
+			go accountVanityWorker(prefix, suffix, &attempts, found, done)
+		}
+
+		start := time.Now()
+		ticker := time.NewTicker(10 * time.Second)
+		defer ticker.Stop()
+		var key *ecdsa.PrivateKey
+		for key == nil {
+			select {
+			case key = <-found:
+			case <-ticker.C:
+				if verbose {
+					tried := atomic.LoadUint64(&attempts)
+					rate := float64(tried) / time.Since(start).Seconds()
+					remaining := time.Duration(math.Max(expected-float64(tried), 0)/rate) * time.Second
+					fmt.Printf("Tried %d addresses at %.0f addresses/second; estimated time remaining %v\n", tried, rate, remaining)
+				}
+			}
+		}
+		close(done)
+		outputIf(verbose, fmt.Sprintf("Found address after %d attempts in %v", atomic.LoadUint64(&attempts), time.Since(start).Round(time.Second)))
+
+		address := crypto.PubkeyToAddress(key.PublicKey)
+		if accountVanityPassphrase != "" {
+			ks := keystore.NewKeyStore(cli.GethKeystoreDir(chainID), keystore.StandardScryptN, keystore.StandardScryptP)
+			_, err := ks.ImportECDSA(key, accountVanityPassphrase)
+			cli.ErrCheck(err, quiet, "Failed to store account")
+		}
+
+		if quiet {
+			os.Exit(_exit_success)
+		}
+		fmt.Printf("Address:\t%s\n", address.Hex())
+		if accountVanityShowPrivateKey {
+			fmt.Printf("Private key:\t0x%x\n", crypto.FromECDSA(key))
+		}
+	},
+}
+
+// accountVanityWorker generates keys until one matches or it is told to stop.
+func accountVanityWorker(prefix string, suffix string, attempts *uint64, found chan<- *ecdsa.PrivateKey, done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			continue
+		}
+		atomic.AddUint64(attempts, 1)
+		address := crypto.PubkeyToAddress(key.PublicKey).Hex()[2:]
+		if !accountVanityChecksum {
+			address = strings.ToLower(address)
+		}
+		if strings.HasPrefix(address, prefix) && strings.HasSuffix(address, suffix) {
+			select {
+			case found <- key:
+			default:
+			}
+			return
+		}
+	}
+}
+
+func init() {
+	offlineCmds["account:vanity"] = true
+	accountCmd.AddCommand(accountVanityCmd)
+	accountVanityCmd.Flags().StringVar(&accountVanityPrefix, "prefix", "", "hex characters with which the address should start")
+	accountVanityCmd.Flags().StringVar(&accountVanitySuffix, "suffix", "", "hex characters with which the address should end")
+	accountVanityCmd.Flags().IntVar(&accountVanityWorkers, "workers", runtime.NumCPU(), "number of parallel workers")
+	accountVanityCmd.Flags().BoolVar(&accountVanityChecksum, "checksum", false, "require the address to match the case of the prefix and suffix (EIP-55)")
+	accountVanityCmd.Flags().StringVar(&accountVanityPassphrase, "passphrase", "", "passphrase with which to store the account in the keystore")
+	accountVanityCmd.Flags().BoolVar(&accountVanityShowPrivateKey, "showprivatekey", false, "show the private key of the account (this is a security risk)")
+}
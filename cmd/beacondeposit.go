@@ -33,7 +33,6 @@ import (
 	"github.com/wealdtech/ethereal/cli"
 	"github.com/wealdtech/ethereal/util"
 	"github.com/wealdtech/ethereal/util/contracts"
-	ens "github.com/wealdtech/go-ens/v3"
 	string2eth "github.com/wealdtech/go-string2eth"
 )
 
@@ -161,7 +160,7 @@ If you are *completely sure* you know what you are doing, you can use the --allo
 		}
 
 		cli.Assert(beaconDepositFrom != "", quiet, "--from is required")
-		fromAddress, err := ens.Resolve(client, beaconDepositFrom)
+		fromAddress, err := util.ResolveAddress(client, beaconDepositFrom)
 		cli.ErrCheck(err, quiet, "Failed to obtain address for --from")
 
 		if offline {
@@ -215,10 +214,7 @@ func sendOffline(deposits []*util.DepositInfo, contractDetails *beaconDepositCon
 		value := new(big.Int).Mul(new(big.Int).SetUint64(deposit.Amount), big.NewInt(1000000000))
 		signedTx, err := createSignedTransaction(fromAddress, &address, value, 500000, dataBytes)
 		cli.ErrCheck(err, quiet, "Failed to create signed transaction")
-		buf := new(bytes.Buffer)
-		err = signedTx.EncodeRLP(buf)
-		cli.ErrCheck(err, quiet, "Failed to encode signed transaction")
-		fmt.Printf("%#x\n", buf.Bytes())
+		outputOfflineTransaction(signedTx)
 	}
 }
 
@@ -14,11 +14,22 @@
 package cmd
 
 import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 )
 
 var blockStr string
 
+// latestBlockHeader caches the latest block header for the duration of the command.
+var latestBlockHeader *types.Header
+
 // blockCmd represents the block command
 var blockCmd = &cobra.Command{
 	Use:   "block",
@@ -29,6 +40,57 @@ var blockCmd = &cobra.Command{
 func init() {
 	RootCmd.AddCommand(blockCmd)
 }
+
+// parseBlockNumber obtains the number of a block given its number, hash or
+// one of the tags "latest", "finalized" or "safe".  An
+// empty input returns nil, which signifies the latest block.
+func parseBlockNumber(input string) *big.Int {
+	if input == "" || input == "latest" {
+		return nil
+	}
+	if input == "finalized" || input == "safe" {
+		ctx, cancel := localContext()
+		defer cancel()
+		number, err := util.BlockNumberForTag(ctx, rpcClient, input)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain %s block; the connection must support post-merge block tags", input))
+		return number
+	}
+	if blockInfoNumberRegexp.MatchString(input) {
+		number, succeeded := big.NewInt(0).SetString(input, 10)
+		cli.Assert(succeeded, quiet, fmt.Sprintf("Failed to parse block number %s", input))
+		return number
+	}
+	ctx, cancel := localContext()
+	defer cancel()
+	block, err := client.BlockByHash(ctx, common.HexToHash(input))
+	cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain block %s", input))
+	return block.Number()
+}
+
 func blockFlags(cmd *cobra.Command) {
-	cmd.Flags().StringVar(&blockStr, "block", "", "block hash or number, or 'latest'")
+	cmd.Flags().StringVar(&blockStr, "block", "", "block hash or number, or 'latest', 'finalized' or 'safe'")
+}
+
+// latestHeader obtains the header of the latest block.  It is obtained once
+// and cached for the duration of the command.
+func latestHeader() *types.Header {
+	if latestBlockHeader == nil {
+		ctx, cancel := localContext()
+		defer cancel()
+		header, err := client.HeaderByNumber(ctx, nil)
+		cli.ErrCheck(err, quiet, "Failed to obtain latest block")
+		latestBlockHeader = header
+	}
+	return latestBlockHeader
+}
+
+// sinceBlock obtains the number of the first block produced within the
+// given duration of now.
+func sinceBlock(since time.Duration) uint64 {
+	ctx, cancel := localContext()
+	defer cancel()
+	number, err := util.BlockSince(ctx, client, latestHeader(), since)
+	cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain block for %v ago", since))
+	outputIf(verbose, fmt.Sprintf("Starting from block %d, the first block within the last %v", number, since))
+	return number
 }
@@ -20,14 +20,15 @@ import (
 	"regexp"
 	"time"
 
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
-	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/ethereal/util"
+	string2eth "github.com/wealdtech/go-string2eth"
 )
 
 var blockInfoTransactions bool
+var blockInfoJSON bool
 
 var blockInfoNumberRegexp = regexp.MustCompile("^[0-9]+$")
 
@@ -39,41 +40,63 @@ var blockInfoCmd = &cobra.Command{
 
     ethereal block info --block=0xfdf173c82f1e3e393166719ddc580c161b622fa504fa4b2ddd55f174af554fb7
 
+The block can also be a number, "latest", "pending", or "finalized" or "safe" for clients that support post-merge block tags.
+
+The --json flag outputs the information as JSON.
+
 In quiet mode this will return 0 if the block exists, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(blockStr != "", quiet, "--block is required")
-		var block *types.Block
 		ctx, cancel := localContext()
 		defer cancel()
-		if blockStr == "latest" {
-			block, err = client.BlockByNumber(ctx, nil)
-		} else if blockInfoNumberRegexp.MatchString(blockStr) {
-			blockNum, succeeded := big.NewInt(0).SetString(blockStr, 10)
-			cli.Assert(succeeded, quiet, fmt.Sprintf("Failed to parse block number %s", blockStr))
-			block, err = client.BlockByNumber(ctx, blockNum)
-		} else {
-			blockHash := common.HexToHash(blockStr)
-			block, err = client.BlockByHash(ctx, blockHash)
-		}
+		summary, err := util.BlockSummaryFor(ctx, rpcClient, blockStr)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain block %s", blockStr))
 
 		if quiet {
 			os.Exit(_exit_success)
 		}
 
-		fmt.Printf("Number:\t\t\t%v\n", block.Number())
-		fmt.Printf("Hash:\t\t\t%v\n", block.Hash().Hex())
-		fmt.Printf("Block time:\t\t%v (%v)\n", block.Time(), time.Unix(int64(block.Time()), 0))
-		if verbose {
-			coinbase := block.Coinbase()
-			fmt.Printf("Mined by:\t\t%s\n", ens.Format(client, coinbase))
+		data := newJSONBlock(summary, blockInfoTransactions)
+		if blockInfoJSON {
+			cli.ErrCheck(outputJSON(cmd, data), quiet, "Failed to output JSON")
+			os.Exit(_exit_success)
+		}
+		cli.WarnCheck(recordJSON(cmd, data), quiet, "Failed to record JSON output")
+
+		var block *types.Block
+		if verbose && summary.Hash != nil {
+			// Additional information requires the full block, which the connection may not have
+			ctx, cancel := localContext()
+			defer cancel()
+			block, err = client.BlockByHash(ctx, *summary.Hash)
+			cli.WarnCheck(err, quiet, "Failed to obtain full block; some information is unavailable")
+		}
+
+		fmt.Printf("Number:\t\t\t%v\n", summary.Number.ToInt())
+		if summary.Hash == nil {
+			fmt.Printf("Hash:\t\t\tPending\n")
+		} else {
+			fmt.Printf("Hash:\t\t\t%v\n", summary.Hash.Hex())
+		}
+		fmt.Printf("Parent hash:\t\t%v\n", summary.ParentHash.Hex())
+		fmt.Printf("Block time:\t\t%v (%v)\n", uint64(summary.Timestamp), time.Unix(int64(summary.Timestamp), 0))
+		if summary.Miner != nil {
+			fmt.Printf("Mined by:\t\t%s\n", util.ENSFormat(client, *summary.Miner))
+		}
+		if block != nil {
+			fmt.Printf("Extra:\t\t\t%s\n", block.Extra())
+			fmt.Printf("Difficulty:\t\t%v\n", block.Difficulty())
+		}
+		fmt.Printf("Gas limit:\t\t%v\n", uint64(summary.GasLimit))
+		gasPct := float64(0)
+		if summary.GasLimit > 0 {
+			gasPct = 100 * float64(summary.GasUsed) / float64(summary.GasLimit)
+		}
+		fmt.Printf("Gas used:\t\t%v (%.2f%%)\n", uint64(summary.GasUsed), gasPct)
+		if summary.BaseFee != nil {
+			fmt.Printf("Base fee:\t\t%s\n", string2eth.WeiToString(summary.BaseFee.ToInt(), true))
 		}
-		outputIf(verbose, fmt.Sprintf("Extra:\t\t\t%s", block.Extra()))
-		outputIf(verbose, fmt.Sprintf("Difficulty:\t\t%v", block.Difficulty()))
-		fmt.Printf("Gas limit:\t\t%v\n", block.GasLimit())
-		gasPct := big.NewFloat(0).Quo(big.NewFloat(0).Mul(big.NewFloat(100), big.NewFloat(0).SetInt(big.NewInt(int64(block.GasUsed())))), big.NewFloat(0).SetInt(big.NewInt(int64(block.GasLimit()))))
-		fmt.Printf("Gas used:\t\t%v (%s%%)\n", block.GasUsed(), gasPct.Text('f', 2))
-		if verbose {
+		if block != nil {
 			if len(block.Uncles()) > 0 {
 				fmt.Println("Uncles:")
 				for i, uncle := range block.Uncles() {
@@ -82,17 +105,17 @@ In quiet mode this will return 0 if the block exists, otherwise 1.`,
 				}
 			}
 		} else {
-			fmt.Printf("Uncles:\t\t\t%v\n", len(block.Uncles()))
+			fmt.Printf("Uncles:\t\t\t%v\n", len(summary.Uncles))
 		}
 		if blockInfoTransactions {
-			if block.Transactions().Len() > 0 {
+			if len(summary.Transactions) > 0 {
 				fmt.Println("Transactions:")
-				for i, tx := range block.Transactions() {
-					fmt.Printf("\t%4d: %v\n", i, tx.Hash().Hex())
+				for i, txHash := range summary.Transactions {
+					fmt.Printf("\t%4d: %v\n", i, txHash.Hex())
 				}
 			}
 		} else {
-			fmt.Printf("Transactions:\t\t%v\n", block.Transactions().Len())
+			fmt.Printf("Transactions:\t\t%v\n", len(summary.Transactions))
 		}
 	},
 }
@@ -100,5 +123,7 @@ In quiet mode this will return 0 if the block exists, otherwise 1.`,
 func init() {
 	blockCmd.AddCommand(blockInfoCmd)
 	blockInfoCmd.Flags().BoolVar(&blockInfoTransactions, "transactions", false, "Display hashes of all block transactions")
+	blockInfoCmd.Flags().BoolVar(&blockInfoJSON, "json", false, "Display output as JSON")
 	blockFlags(blockInfoCmd)
+	jsonoutCmds["block:info"] = true
 }
@@ -20,7 +20,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
-	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/ethereal/util"
 )
 
 var blockOverviewBlocks int64
@@ -56,7 +56,7 @@ In quiet mode this will return 0 if the blocks exist, otherwise 1.`,
 					fmt.Printf("%v", gap)
 				}
 				coinbase := block.Coinbase()
-				fmt.Printf("\t%s\n", ens.Format(client, coinbase))
+				fmt.Printf("\t%s\n", util.ENSFormat(client, coinbase))
 				lastBlockTime = &blockTime
 			}
 			blockNumber = blockNumber.Sub(blockNumber, big.NewInt(1))
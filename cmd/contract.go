@@ -17,6 +17,8 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"math/big"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -26,9 +28,10 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/wealdtech/ethereal/cli"
 	"github.com/wealdtech/ethereal/util"
-	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/ethereal/util/funcparser"
 )
 
 var contractStr string
@@ -36,6 +39,9 @@ var contractAbi string
 var contractFunction string
 var contractJSON string
 var contractName string
+var contractABISource string
+var contractABISourceKey string
+var contractEnumMap string
 
 // contractCmd represents the contract command
 var contractCmd = &cobra.Command{
@@ -54,6 +60,11 @@ func contractFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&contractFunction, "function", "", "Signature of function")
 	cmd.Flags().StringVar(&contractJSON, "json", "", "JSON, or path to JSON, for the contract as output by solc --combined-json=bin,abi")
 	cmd.Flags().StringVar(&contractName, "name", "", "Name of the contract (required when using json)")
+	cmd.Flags().StringVar(&contractABISource, "abisource", "", "Etherscan-compatible API from which to obtain the ABI if it is not otherwise available (e.g. https://api.etherscan.io/v2/api)")
+	cmd.Flags().StringVar(&contractABISourceKey, "abisourcekey", "", "API key for --abisource")
+	cmd.Flags().Bool("refreshabi", false, "Obtain the ABI afresh rather than from the cache")
+	cmd.Flags().Bool("padbytes", false, "Right-pad values for fixed-size byte arguments (e.g. bytes32) that are too short, rather than rejecting them")
+	cmd.Flags().StringVar(&contractEnumMap, "enummap", "", "Members of the contract's enums, allowing them to be used as arguments in the form Status.Active (e.g. \"Status=Pending,Active,Closed;Role=User,Admin\")")
 }
 
 // parse contract given the information from various flags
@@ -91,13 +102,116 @@ func parseContract(binStr string) *util.Contract {
 			contract.Abi = *abi
 		}
 	}
+
+	if contractEnumMap != "" {
+		contract.Enums, err = funcparser.ParseEnumMap(contractEnumMap)
+		cli.ErrCheck(err, quiet, "Failed to parse enum map")
+	}
 	return contract
 }
 
+// contractResolveABI obtains the ABI of a contract if it was not supplied
+// with --abi, --json or --function.  A cached ABI is used if available,
+// unless --refreshabi is supplied.  Otherwise the ABI is taken from the
+// contract's ENS name if it publishes one, else from the source supplied in
+// --abisource, and cached.  If no ABI is found the contract is left
+// unchanged.
+func contractResolveABI(contract *util.Contract, address common.Address, chainID *big.Int) error {
+	if len(contract.Abi.Methods) > 0 || len(contract.Abi.Events) > 0 {
+		return nil
+	}
+	if contractABISource != "" {
+		viper.Set("abisource", contractABISource)
+	}
+	if contractABISourceKey != "" {
+		viper.Set("abisourcekey", contractABISourceKey)
+	}
+
+	var abiStr string
+	fromENS := false
+	if !viper.GetBool("refreshabi") {
+		abiStr = util.CachedABI(address, chainID)
+		if abiStr != "" {
+			outputIf(verbose, "Obtained ABI from cache")
+		}
+	}
+	if abiStr == "" && client != nil {
+		abiStr = contractENSABI(address)
+		if abiStr != "" {
+			outputIf(verbose, "Obtained ABI from ENS")
+			fromENS = true
+		}
+	}
+	if abiStr == "" && viper.GetString("abisource") != "" {
+		var err error
+		abiStr, err = util.LookupABI(address, chainID)
+		if err != nil {
+			return err
+		}
+		outputIf(verbose, "Obtained ABI from ABI source")
+	}
+	if abiStr == "" {
+		return nil
+	}
+	contractABI, err := abi.JSON(strings.NewReader(abiStr))
+	if err != nil {
+		return fmt.Errorf("invalid ABI: %v", err)
+	}
+	if fromENS {
+		util.CacheABI(address, chainID, abiStr)
+	}
+	contract.Abi = contractABI
+	return nil
+}
+
+// contractENSABI obtains the ABI published in ENS for a contract, either as
+// an ABI record (EIP-205) or failing that as an "ABI" text record (EIP-634).
+// The ENS name is the contract as supplied, or if that is an address then
+// its reverse resolution.
+func contractENSABI(address common.Address) string {
+	name := contractStr
+	if name == "" || strings.HasPrefix(name, "0x") {
+		var err error
+		name, err = util.ENSReverseResolve(client, address)
+		if err != nil {
+			return ""
+		}
+	}
+	resolver, err := util.ENSResolver(client, name)
+	if err != nil {
+		return ""
+	}
+	abiStr, err := resolver.ABI(name)
+	if err == nil && abiStr != "" {
+		return abiStr
+	}
+	abiStr, err = resolver.Text("ABI")
+	if err != nil {
+		return ""
+	}
+	return abiStr
+}
+
+// contractParseCall parses a method call.  If an arguments file is supplied
+// then the call is just the name of the method, and its arguments are read
+// from the file.
+func contractParseCall(contract *util.Contract, call string, argsFile string) (*abi.Method, string, []interface{}) {
+	if argsFile == "" {
+		method, signature, args, err := funcparser.PreviewCall(client, contract, call)
+		cli.ErrCheck(err, quiet, "Failed to parse call")
+		return method, signature, args
+	}
+	data, err := ioutil.ReadFile(argsFile)
+	cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to read arguments file %s", argsFile))
+	method, args, err := funcparser.ParseArgsFile(client, contract, call, data)
+	cli.ErrCheck(err, quiet, "Failed to parse arguments file")
+	return method, method.Sig, args
+}
+
 func contractParseAbi(input string) (output abi.ABI, err error) {
 	var reader io.Reader
 
-	if strings.HasPrefix(contractAbi, "[") {
+	if strings.HasPrefix(input, "[") {
 		// ABI is direct
 		reader = strings.NewReader(input)
 	} else {
@@ -161,12 +275,8 @@ func contractParseFunction(input string) (*abi.ABI, error) {
 		}
 	}
 
-	method := abi.Method{
-		Name:    methodName,
-		RawName: methodName,
-		Inputs:  methodArgs,
-		Outputs: methodOutputs,
-	}
+	// Use the constructor to ensure that the method's signature and ID are populated
+	method := abi.NewMethod(methodName, methodName, abi.Function, "", false, false, methodArgs, methodOutputs)
 
 	res := &abi.ABI{
 		Methods: make(map[string]abi.Method),
@@ -213,7 +323,7 @@ func contractValueToString(argType abi.Type, val interface{}) (string, error) {
 		return "[" + strings.Join(res, ",") + "]", nil
 	case abi.AddressTy:
 		addr := val.(common.Address)
-		return ens.Format(client, addr), nil
+		return util.ENSFormat(client, addr), nil
 	case abi.FixedBytesTy:
 		arrayVal := reflect.ValueOf(val)
 		castVal := make([]byte, arrayVal.Len())
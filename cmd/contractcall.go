@@ -14,21 +14,36 @@
 package cmd
 
 import (
+	"encoding/csv"
 	"encoding/hex"
 	"fmt"
+	"math/big"
 	"os"
+	"reflect"
+	"strconv"
 	"strings"
 
 	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	"github.com/wealdtech/ethereal/util/contracts"
 	"github.com/wealdtech/ethereal/util/funcparser"
-	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/ethereal/util/txdata"
+	string2eth "github.com/wealdtech/go-string2eth"
 )
 
 var contractCallFromAddress string
 var contractCallCall string
+var contractCallArgsFile string
 var contractCallData string
+var contractCallDecimals string
+var contractCallDecimalsOutputs string
+var contractCallBlock string
+var contractCallTrace bool
+var contractCallExpect string
 
 // contractCallCmd represents the contract call command
 var contractCallCmd = &cobra.Command{
@@ -38,18 +53,40 @@ var contractCallCmd = &cobra.Command{
 
    ethereal contract call --contract=0xd26114cd6EE289AccF82350c8d8487fedB8A0C07 --abi="./erc20.abi" --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --call="totalSupply()"
 
-   ethereal contract call --contract=0xd26114cd6EE289AccF82350c8d8487fedB8A0C07 --signature="balanceOf(address)" --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --call="balanceOf(@wealdtech.eth)"
+   ethereal contract call --contract=0xd26114cd6EE289AccF82350c8d8487fedB8A0C07 --function="balanceOf(address)" --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --call="balanceOf(@wealdtech.eth)"
 
-In quiet mode this will return 0 if the contract is successfully called, otherwise 1.`,
+If none of --abi, --json or --function is supplied then the ABI is obtained from the contract's ENS ABI record or "ABI" text record if present, otherwise from the Etherscan-compatible API supplied with --abisource (with an API key in --abisourcekey if required).  ABIs obtained this way are cached in the user configuration directory, so once cached a contract can be referenced by its ENS name alone; --refreshabi obtains the ABI afresh.
+
+Arguments can also be read from a JSON file with --argsfile, in which case --call is just the name of the method.  The file contains either an array of arguments in order, or an object mapping argument names or positions (starting at 0) to values.  For example:
+
+   ethereal contract call --contract=0xd26114cd6EE289AccF82350c8d8487fedB8A0C07 --abi="./erc20.abi" --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --call=balanceOf --argsfile=args.json
+
+Numbers can be supplied as JSON numbers or strings, byte values as hex strings, arrays as JSON arrays, and tuples as JSON arrays or objects keyed by component name.
+
+The call can be made against the state at a historical block with --block, which requires an archive node.  If --trace is supplied then the execution trace of the call is output prior to the result, which can help to understand why a call reverted.  Tracing requires a node that supports debug_traceCall.
+
+Unsigned integer outputs can be displayed as decimal values by supplying the number of decimals, or "auto" to use the value returned by the contract's decimals() method.  By default all unsigned integer outputs are scaled; to scale only some of them supply their positions (starting at 0) or names.  For example:
+
+   ethereal contract call --contract=0xd26114cd6EE289AccF82350c8d8487fedB8A0C07 --function="balanceOf(address)" --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --call="balanceOf(@wealdtech.eth)" --decimals=auto
+
+The result of the call can be checked against an expected value with --expect, for example:
+
+   ethereal contract call --contract=0xd26114cd6EE289AccF82350c8d8487fedB8A0C07 --function="owner() returns (address)" --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --call="owner()" --expect=wealdtech.eth
+
+If the call returns multiple values then the expected values are supplied separated by commas.  Values are compared according to their type, and are not scaled by --decimals.
+
+In quiet mode this will return 0 if the contract is successfully called (and its result matches the expected value if --expect is supplied), otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(contractCallFromAddress != "", quiet, "--from is required")
-		fromAddress, err := ens.Resolve(client, contractCallFromAddress)
+		fromAddress, err := util.ResolveAddress(client, contractCallFromAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", contractCallFromAddress))
 
 		cli.Assert(contractStr != "", quiet, "--contract is required")
-		contractAddress, err := ens.Resolve(client, contractStr)
+		contractAddress, err := util.ResolveAddress(client, contractStr)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve contract address %s", contractStr))
 
+		blockNumber := parseBlockNumber(contractCallBlock)
+
 		if contractCallData != "" {
 			// Raw data in and out
 			data, err := hex.DecodeString(strings.TrimPrefix(contractCallData, "0x"))
@@ -60,9 +97,12 @@ In quiet mode this will return 0 if the contract is successfully called, otherwi
 				To:   &contractAddress,
 				Data: data,
 			}
+			if contractCallTrace {
+				contractCallOutputTrace(msg, blockNumber)
+			}
 			ctx, cancel := localContext()
 			defer cancel()
-			result, err := client.CallContract(ctx, msg, nil)
+			result, err := client.CallContract(ctx, msg, blockNumber)
 			cli.ErrCheck(err, quiet, "Call failed")
 			outputIf(!quiet, fmt.Sprintf("%x", []byte(result)))
 			os.Exit(_exit_success)
@@ -72,8 +112,11 @@ In quiet mode this will return 0 if the contract is successfully called, otherwi
 		cli.Assert(contractCallCall != "", quiet, "--call is required")
 
 		contract := parseContract("")
-		method, methodArgs, err := funcparser.ParseCall(client, contract, contractCallCall)
-		cli.ErrCheck(err, quiet, "Failed to parse call")
+		err = contractResolveABI(contract, contractAddress, chainID)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain ABI for %s", contractStr))
+		method, signature, methodArgs := contractParseCall(contract, contractCallCall, contractCallArgsFile)
+		outputIf(verbose, fmt.Sprintf("Method is %s", signature))
+		outputIf(verbose, fmt.Sprintf("About to call %s", funcparser.FormatCall(method, methodArgs)))
 		data, err := contract.Abi.Pack(method.Name, methodArgs...)
 		cli.ErrCheck(err, quiet, "Failed to convert arguments")
 
@@ -85,9 +128,12 @@ In quiet mode this will return 0 if the contract is successfully called, otherwi
 			To:   &contractAddress,
 			Data: data,
 		}
+		if contractCallTrace {
+			contractCallOutputTrace(msg, blockNumber)
+		}
 		ctx, cancel := localContext()
 		defer cancel()
-		result, err := client.CallContract(ctx, msg, nil)
+		result, err := client.CallContract(ctx, msg, blockNumber)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to call %s", method.Name))
 		if len(method.Outputs) == 0 {
 			// No output
@@ -95,7 +141,7 @@ In quiet mode this will return 0 if the contract is successfully called, otherwi
 		}
 		cli.Assert(len(result) > 0, quiet, fmt.Sprintf("Call to %s did not return expected data", method.Name))
 
-		if quiet {
+		if quiet && contractCallExpect == "" {
 			os.Exit(0)
 		}
 
@@ -114,8 +160,31 @@ In quiet mode this will return 0 if the contract is successfully called, otherwi
 			}
 		}
 
+		matched := true
+		if contractCallExpect != "" {
+			matched = contractCallMatchesExpected(method.Outputs, outputs)
+			if quiet {
+				if matched {
+					os.Exit(_exit_success)
+				}
+				os.Exit(_exit_failure)
+			}
+		}
+
+		scaled := contractCallScaledOutputs(method.Outputs)
+		var decimals uint8
+		if len(scaled) > 0 {
+			decimals = contractCallObtainDecimals(contractAddress)
+		}
+
 		results := []string{}
 		for i := range outputs {
+			if scaled[i] {
+				// Smaller integers are returned as native types, so go via their string representation
+				value, _ := new(big.Int).SetString(fmt.Sprintf("%v", outputs[i]), 10)
+				results = append(results, util.TokenValueToString(value, decimals, false))
+				continue
+			}
 			val, err := contractValueToString(method.Outputs[i].Type, outputs[i])
 			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to turn value %v in to suitable output", outputs[i]))
 			results = append(results, val)
@@ -123,13 +192,155 @@ In quiet mode this will return 0 if the contract is successfully called, otherwi
 
 		// Output the result
 		fmt.Printf("%s\n", strings.Join(results, ","))
+		if !matched {
+			fmt.Println("Result does not match expected value")
+			os.Exit(_exit_failure)
+		}
 	},
 }
 
+// contractCallMatchesExpected returns true if the outputs match the expected values.
+func contractCallMatchesExpected(outputs abi.Arguments, values []interface{}) bool {
+	var expected []string
+	if len(outputs) == 1 {
+		expected = []string{contractCallExpect}
+	} else {
+		parser := csv.NewReader(strings.NewReader(contractCallExpect))
+		parser.TrimLeadingSpace = true
+		var err error
+		expected, err = parser.Read()
+		cli.ErrCheck(err, quiet, "Failed to parse expected value")
+	}
+	cli.Assert(len(expected) == len(outputs), quiet, fmt.Sprintf("Expected %d values but call returns %d", len(expected), len(outputs)))
+
+	for i := range outputs {
+		outputType := outputs[i].Type
+		switch outputType.T {
+		case abi.IntTy, abi.UintTy:
+			// Smaller integers are returned as native types, so compare via their string representation
+			expectedValue, success := new(big.Int).SetString(strings.TrimSpace(expected[i]), 10)
+			cli.Assert(success, quiet, fmt.Sprintf("Invalid expected value %s", expected[i]))
+			value, _ := new(big.Int).SetString(fmt.Sprintf("%v", values[i]), 10)
+			if value.Cmp(expectedValue) != 0 {
+				return false
+			}
+		case abi.AddressTy:
+			expectedValue, err := util.ResolveAddress(client, strings.TrimPrefix(strings.TrimSpace(expected[i]), "@"))
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Invalid expected value %s", expected[i]))
+			if values[i].(common.Address) != expectedValue {
+				return false
+			}
+		default:
+			expectedValue, err := funcparser.StrTo(&outputType, expected[i])
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Invalid expected value %s", expected[i]))
+			if !reflect.DeepEqual(values[i], expectedValue) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// contractCallScaledOutputs returns the outputs that should be scaled by decimals.
+func contractCallScaledOutputs(outputs abi.Arguments) map[int]bool {
+	res := make(map[int]bool)
+	if contractCallDecimals == "" {
+		return res
+	}
+	if contractCallDecimalsOutputs == "" {
+		for i := range outputs {
+			if outputs[i].Type.T == abi.UintTy {
+				res[i] = true
+			}
+		}
+		return res
+	}
+	for _, item := range strings.Split(contractCallDecimalsOutputs, ",") {
+		item = strings.TrimSpace(item)
+		index, err := strconv.Atoi(item)
+		if err != nil {
+			// Not a position, so look for a name
+			index = -1
+			for i := range outputs {
+				if outputs[i].Name == item {
+					index = i
+					break
+				}
+			}
+			cli.Assert(index != -1, quiet, fmt.Sprintf("Unknown output %s", item))
+		}
+		cli.Assert(index >= 0 && index < len(outputs), quiet, fmt.Sprintf("Output %d out of range", index))
+		cli.Assert(outputs[index].Type.T == abi.UintTy, quiet, fmt.Sprintf("Output %s is not an unsigned integer", item))
+		res[index] = true
+	}
+	return res
+}
+
+// contractCallObtainDecimals obtains the number of decimals with which to scale outputs.
+func contractCallObtainDecimals(contractAddress common.Address) uint8 {
+	if contractCallDecimals == "auto" {
+		token, err := contracts.NewERC20(contractAddress, client)
+		cli.ErrCheck(err, quiet, "Failed to obtain contract")
+		decimals, err := token.Decimals(nil)
+		cli.ErrCheck(err, quiet, "Failed to obtain decimals from contract")
+		outputIf(verbose, fmt.Sprintf("Contract decimals is %d", decimals))
+		return decimals
+	}
+	decimals, err := strconv.ParseUint(contractCallDecimals, 10, 8)
+	cli.ErrCheck(err, quiet, "Invalid value for --decimals")
+	return uint8(decimals)
+}
+
+// contractCallOutputTrace traces the call and outputs the trace.
+func contractCallOutputTrace(msg ethereum.CallMsg, blockNumber *big.Int) {
+	ctx, cancel := localContext()
+	defer cancel()
+	trace, err := util.TraceCall(ctx, rpcClient, msg, blockNumber)
+	cli.ErrCheck(err, quiet, "Failed to trace call; the connection must support debug_traceCall")
+	if quiet {
+		return
+	}
+	txdata.InitFunctionMap()
+	fmt.Println("Trace:")
+	contractCallOutputCallFrame(trace, 1)
+}
+
+// contractCallOutputCallFrame outputs a call frame and its subcalls.
+func contractCallOutputCallFrame(frame *util.CallFrame, depth int) {
+	indent := strings.Repeat("  ", depth)
+	description := txdata.DataToString(client, frame.Input)
+	if description == "" && len(frame.Input) > 0 {
+		description = fmt.Sprintf("%x", []byte(frame.Input))
+	}
+	fmt.Printf("%s%s %s %s\n", indent, frame.Type, util.ENSFormat(client, frame.To), description)
+	if frame.Value != nil && frame.Value.ToInt().Sign() != 0 {
+		fmt.Printf("%s  Value: %s\n", indent, string2eth.WeiToString(frame.Value.ToInt(), true))
+	}
+	fmt.Printf("%s  Gas used: %d\n", indent, frame.GasUsed)
+	if frame.Error != "" {
+		if frame.RevertReason != "" {
+			fmt.Printf("%s  Error: %s (%s)\n", indent, frame.Error, frame.RevertReason)
+		} else {
+			fmt.Printf("%s  Error: %s\n", indent, frame.Error)
+		}
+	} else if len(frame.Output) > 0 {
+		fmt.Printf("%s  Output: %x\n", indent, []byte(frame.Output))
+	}
+	for _, call := range frame.Calls {
+		contractCallOutputCallFrame(call, depth+1)
+	}
+}
+
 func init() {
 	contractCmd.AddCommand(contractCallCmd)
 	contractFlags(contractCallCmd)
 	contractCallCmd.Flags().StringVar(&contractCallFromAddress, "from", "", "Address from which to call the contract method")
 	contractCallCmd.Flags().StringVar(&contractCallData, "data", "", "Raw hex data to use in the call")
 	contractCallCmd.Flags().StringVar(&contractCallCall, "call", "", "Contract method to call")
+	contractCallCmd.Flags().StringVar(&contractCallArgsFile, "argsfile", "", "JSON file containing the arguments for the method (--call is then just the method name)")
+	contractCallCmd.Flags().StringVar(&contractCallDecimals, "decimals", "", "Number of decimals with which to display unsigned integer outputs, or \"auto\" to obtain them from the contract")
+	contractCallCmd.Flags().StringVar(&contractCallBlock, "block", "", "Block hash or number at which to make the call (must be run against an archive node)")
+	contractCallCmd.Flags().BoolVar(&contractCallTrace, "trace", false, "Output the execution trace of the call")
+	contractCallCmd.Flags().StringVar(&contractCallExpect, "expect", "", "Expected result of the call")
+	contractCallCmd.Flags().StringVar(&contractCallDecimalsOutputs, "decimalsoutputs", "", "Comma-separated positions or names of the outputs to display with decimals (defaults to all unsigned integer outputs)")
 }
@@ -0,0 +1,87 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+)
+
+var contractCreate2AddressDeployer string
+var contractCreate2AddressSalt string
+var contractCreate2AddressConstructor string
+var contractCreate2AddressData string
+var contractCreate2AddressInitHash string
+
+// contractCreate2AddressCmd represents the contract create2address command
+var contractCreate2AddressCmd = &cobra.Command{
+	Use:     "create2address",
+	Aliases: []string{"create2-address"},
+	Short:   "Obtain the address of a contract created with CREATE2",
+	Long: `Obtain the address at which a contract will be created by the CREATE2 opcode, as per EIP-1014.  For example:
+
+   ethereal contract create2address --deployer=0x4e59b44847b379578588920cA78FbF26c0B4956C --salt=0x01 --data=0x606060...430029
+
+where deployer is the address of the contract that executes CREATE2, salt is a hex string of up to 32 bytes or a decimal number, and data is the hex string of the contract binary or the path to a file containing it.  Salts shorter than 32 bytes are left-padded with zeros.  If the contract constructor requires arguments then both the ABI and the constructor are required, for example:
+
+   ethereal contract create2address --deployer=0x4e59b44847b379578588920cA78FbF26c0B4956C --salt=0x01 --json='./MyContract.json' --constructor='constructor(1,2,3)'
+
+Alternatively, the hash of the init code can be supplied directly with --inithash.
+
+In quiet mode this will return 0 if the address is calculated, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(contractCreate2AddressDeployer != "", quiet, "--deployer is required")
+		deployer := resolveAddressOffline(contractCreate2AddressDeployer)
+		cli.Assert(contractCreate2AddressSalt != "", quiet, "--salt is required")
+		salt, err := util.ParseSalt(contractCreate2AddressSalt)
+		cli.ErrCheck(err, quiet, "Invalid salt")
+
+		var initHash common.Hash
+		if contractCreate2AddressInitHash != "" {
+			cli.Assert(contractCreate2AddressData == "" && contractJSON == "", quiet, "only one of --inithash and --data or --json can be supplied")
+			initHashBytes, err := hex.DecodeString(strings.TrimPrefix(contractCreate2AddressInitHash, "0x"))
+			cli.ErrCheck(err, quiet, "Invalid init code hash")
+			cli.Assert(len(initHashBytes) == 32, quiet, "--inithash must be 32 bytes")
+			initHash = common.BytesToHash(initHashBytes)
+		} else {
+			initHash = crypto.Keccak256Hash(contractInitCode(contractCreate2AddressData, contractCreate2AddressConstructor))
+		}
+		outputIf(verbose, fmt.Sprintf("Init code hash is %s", initHash.Hex()))
+
+		address := util.Create2Address(deployer, salt, initHash)
+		if quiet {
+			os.Exit(_exit_success)
+		}
+		fmt.Printf("%s\n", address.Hex())
+	},
+}
+
+func init() {
+	offlineCmds["contract:create2address"] = true
+	contractCmd.AddCommand(contractCreate2AddressCmd)
+	contractFlags(contractCreate2AddressCmd)
+	contractCreate2AddressCmd.Flags().StringVar(&contractCreate2AddressDeployer, "deployer", "", "Address of the contract that executes CREATE2")
+	contractCreate2AddressCmd.Flags().StringVar(&contractCreate2AddressSalt, "salt", "", "Salt (as a hex string or decimal number)")
+	contractCreate2AddressCmd.Flags().StringVar(&contractCreate2AddressConstructor, "constructor", "", "Constructor invocation (if required)")
+	contractCreate2AddressCmd.Flags().StringVar(&contractCreate2AddressData, "data", "", "Contract data (as a hex string, or path to a file containing a hex string)")
+	contractCreate2AddressCmd.Flags().StringVar(&contractCreate2AddressInitHash, "inithash", "", "Hash of the contract's init code (alternative to --data or --json)")
+}
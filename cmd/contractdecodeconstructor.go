@@ -0,0 +1,99 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+)
+
+var contractDecodeConstructorBytecode string
+var contractDecodeConstructorData string
+
+// contractDecodeConstructorCmd represents the contract decodeconstructor command
+var contractDecodeConstructorCmd = &cobra.Command{
+	Use:   "decodeconstructor",
+	Short: "Decode the constructor arguments of a contract deployment",
+	Long: `Decode the constructor arguments from the data of a contract deployment transaction.  For example:
+
+   ethereal contract decodeconstructor --bytecode=0x606060...430029 --abi='./MyContract.abi' --data=0x606060...4300290000...0001
+
+where bytecode is the hex string of the contract binary, or the path to a file containing it, and data is the hex string of the deployment transaction's data.  The combined JSON output of solc can also be used:
+
+   ethereal contract decodeconstructor --json='./MyContract.json' --data=0x606060...4300290000...0001
+
+In quiet mode this will return 0 if the constructor arguments are decoded, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(contractDecodeConstructorBytecode != "" || contractJSON != "", quiet, "either --bytecode or --json is required")
+		cli.Assert(contractDecodeConstructorData != "", quiet, "--data is required")
+
+		bytecode := contractDecodeConstructorBytecode
+		if bytecode != "" && !strings.HasPrefix(bytecode, "0x") {
+			// Bytecode might be a path
+			fileData, err := ioutil.ReadFile(bytecode)
+			if err == nil {
+				bytecode = strings.TrimSpace(string(fileData))
+			}
+		}
+
+		contract := parseContract(bytecode)
+		cli.Assert(len(contract.Binary) > 0, quiet, "failed to obtain contract binary data")
+
+		data, err := hex.DecodeString(strings.TrimPrefix(contractDecodeConstructorData, "0x"))
+		cli.ErrCheck(err, quiet, "Failed to decode data")
+		cli.Assert(bytes.HasPrefix(data, contract.Binary), quiet, "Data does not start with the contract bytecode")
+		argData := data[len(contract.Binary):]
+		outputIf(verbose, fmt.Sprintf("Constructor data is %x", argData))
+
+		constructor := contract.Abi.Constructor
+		if len(constructor.Inputs) == 0 {
+			cli.Assert(len(argData) == 0, quiet, "Constructor takes no arguments but data is present")
+			os.Exit(_exit_success)
+		}
+
+		values, err := constructor.Inputs.UnpackValues(argData)
+		cli.ErrCheck(err, quiet, "Failed to decode constructor arguments")
+
+		if quiet {
+			os.Exit(_exit_success)
+		}
+
+		results := []string{}
+		for i := range values {
+			val, err := contractValueToString(constructor.Inputs[i].Type, values[i])
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to turn value %v in to suitable output", values[i]))
+			if verbose && constructor.Inputs[i].Name != "" {
+				val = fmt.Sprintf("%s=%s", constructor.Inputs[i].Name, val)
+			}
+			results = append(results, val)
+		}
+
+		fmt.Printf("%s\n", strings.Join(results, ","))
+	},
+}
+
+func init() {
+	offlineCmds["contract:decodeconstructor"] = true
+	contractCmd.AddCommand(contractDecodeConstructorCmd)
+	contractFlags(contractDecodeConstructorCmd)
+	contractDecodeConstructorCmd.Flags().StringVar(&contractDecodeConstructorBytecode, "bytecode", "", "Contract creation bytecode (as a hex string, or path to a file containing a hex string)")
+	contractDecodeConstructorCmd.Flags().StringVar(&contractDecodeConstructorData, "data", "", "Data of the deployment transaction (as a hex string)")
+}
@@ -14,19 +14,18 @@
 package cmd
 
 import (
-	"bytes"
-	"encoding/hex"
 	"fmt"
 	"math/big"
 	"os"
 
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	"github.com/wealdtech/ethereal/util/funcparser"
-	ens "github.com/wealdtech/go-ens/v3"
-	string2eth "github.com/wealdtech/go-string2eth"
 )
 
 var contractDeployFromAddress string
@@ -51,10 +50,14 @@ Usually the easiest way to deploy a contract is to use the combined JSON output
 
    ethereal contract deploy --json='./MyContract.json' --constructor='constructor(1,2,3') --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --passphrase=secret
 
+If --showcalldata is supplied then the hex-encoded deployment data, including any constructor arguments, is output prior to the transaction hash.
+
+If --verbose is supplied then the address of the contract, as calculated from the sending address and nonce, is output prior to the transaction hash.  If --wait is supplied then the address at which the contract was deployed is output once the transaction has been mined.
+
 This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(contractDeployFromAddress != "", quiet, "--from is required")
-		fromAddress, err := ens.Resolve(client, contractDeployFromAddress)
+		fromAddress, err := util.ResolveAddress(client, contractDeployFromAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", contractDeployFromAddress))
 		cli.Assert(contractDeployData != "" || contractJSON != "", quiet, "either --data or --json is required")
 
@@ -69,10 +72,11 @@ This will return an exit status of 0 if the transaction is successfully submitte
 			outputIf(verbose, fmt.Sprintf("Constructor data is %x", argData))
 			contract.Binary = append(contract.Binary, argData...)
 		}
+		outputCalldata(contract.Binary)
 
 		amount := big.NewInt(0)
 		if contractDeployAmount != "" {
-			amount, err = string2eth.StringToWei(contractDeployAmount)
+			amount, err = parseAmount(contractDeployAmount)
 			cli.ErrCheck(err, quiet, fmt.Sprintf("Invalid amount %s", contractDeployAmount))
 		}
 
@@ -83,12 +87,11 @@ This will return an exit status of 0 if the transaction is successfully submitte
 			cli.ErrCheck(err, quiet, "Failed to create contract deployment transaction")
 			outputIf(verbose, fmt.Sprintf("Transaction data is %x", signedTx.Data()))
 			outputIf(verbose, fmt.Sprintf("Transaction data size is %d", len(signedTx.Data())))
+			outputIf(verbose, fmt.Sprintf("Contract address will be %s", crypto.CreateAddress(fromAddress, signedTx.Nonce()).Hex()))
 
 			if offline {
 				if !quiet {
-					buf := new(bytes.Buffer)
-					signedTx.EncodeRLP(buf)
-					fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+					outputOfflineTransaction(signedTx)
 				}
 				os.Exit(_exit_success)
 			}
@@ -105,17 +108,33 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		}
 
 		// Wait for the last transaction if requested
-		handleSubmittedTransaction(signedTx, nil, false)
+		mined := handleSubmittedTransaction(signedTx, nil, false)
+		if !viper.GetBool("wait") {
+			os.Exit(_exit_success)
+		}
+		ctx, cancel := localContext()
+		defer cancel()
+		receipt, err := client.TransactionReceipt(ctx, signedTx.Hash())
+		if err != nil {
+			os.Exit(_exit_not_mined)
+		}
+		if !mined {
+			// Reverted, and the reason has been output; there is no contract
+			os.Exit(_exit_success)
+		}
+		outputIf(!quiet, fmt.Sprintf("Contract deployed at %s", receipt.ContractAddress.Hex()))
+		os.Exit(_exit_success)
 	},
 }
 
 func init() {
 	contractCmd.AddCommand(contractDeployCmd)
 	contractFlags(contractDeployCmd)
-	contractDeployCmd.Flags().StringVar(&contractDeployAmount, "amount", "", "Amount of Ether to send with the contract deployment")
+	contractDeployCmd.Flags().StringVar(&contractDeployAmount, "amount", "", "Amount of Ether to send with the contract deployment, with a unit (e.g. \"1.5 ether\")")
 	contractDeployCmd.Flags().StringVar(&contractDeployConstructor, "constructor", "", "Constructor invocation (if required)")
 	contractDeployCmd.Flags().StringVar(&contractDeployData, "data", "", "Contract data (as a hex string)")
 	contractDeployCmd.Flags().StringVar(&contractDeployFromAddress, "from", "", "Address from which to deploy the contract")
+	addShowCalldataFlag(contractDeployCmd, "deployment data for the contract")
 	contractDeployCmd.Flags().IntVar(&contractDeployRepeat, "repeat", 1, "Number of times to repeat sending the transaction (incrementing the nonce each time)")
 	addTransactionFlags(contractDeployCmd, "Passphrase for the address from which to deploy the conract")
 }
@@ -0,0 +1,127 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+)
+
+var (
+	contractDomainSeparatorSelector = crypto.Keccak256([]byte("DOMAIN_SEPARATOR()"))[:4]
+	contractEIP712DomainSelector    = crypto.Keccak256([]byte("eip712Domain()"))[:4]
+)
+
+// contractDomainSeparatorCmd represents the contract domainseparator command
+var contractDomainSeparatorCmd = &cobra.Command{
+	Use:   "domainseparator",
+	Short: "Obtain the EIP-712 domain separator of a contract",
+	Long: `Obtain the EIP-712 domain separator of a contract.  For example:
+
+   ethereal contract domainseparator --contract=0x6B175474E89094C44Da98b954EedeAC495271d0F
+
+The domain separator is obtained from the contract's DOMAIN_SEPARATOR() function if present, otherwise it is calculated from the fields returned by the contract's EIP-5267 eip712Domain() function.  If the contract supports both then the values are checked against each other.
+
+In quiet mode this will return 0 if the domain separator is obtained, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(contractStr != "", quiet, "--contract is required")
+		contractAddress, err := util.ResolveAddress(client, contractStr)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve contract address %s", contractStr))
+
+		var separator *common.Hash
+		res, err := contractDomainSeparatorCall(contractAddress, contractDomainSeparatorSelector)
+		if err == nil && len(res) == 32 {
+			hash := common.BytesToHash(res)
+			separator = &hash
+			outputIf(verbose, fmt.Sprintf("DOMAIN_SEPARATOR() returned %s", hash.Hex()))
+		} else {
+			outputIf(verbose, "Contract does not support DOMAIN_SEPARATOR()")
+		}
+
+		var calculated *common.Hash
+		res, err = contractDomainSeparatorCall(contractAddress, contractEIP712DomainSelector)
+		if err == nil && len(res) > 0 {
+			domain, err := contractDomainSeparatorDecodeDomain(res)
+			cli.ErrCheck(err, quiet, "Failed to decode result of eip712Domain()")
+			outputIf(verbose, fmt.Sprintf("Domain fields are 0x%02x", domain.Fields))
+			outputIf(verbose && domain.Fields&0x01 != 0, fmt.Sprintf("Name is %q", domain.Name))
+			outputIf(verbose && domain.Fields&0x02 != 0, fmt.Sprintf("Version is %q", domain.Version))
+			outputIf(verbose && domain.Fields&0x04 != 0, fmt.Sprintf("Chain ID is %v", domain.ChainID))
+			outputIf(verbose && domain.Fields&0x08 != 0, fmt.Sprintf("Verifying contract is %s", domain.VerifyingContract.Hex()))
+			outputIf(verbose && domain.Fields&0x10 != 0, fmt.Sprintf("Salt is %#x", domain.Salt))
+			hash := domain.Separator()
+			calculated = &hash
+			outputIf(verbose, fmt.Sprintf("Domain separator calculated from eip712Domain() is %s", hash.Hex()))
+		} else {
+			outputIf(verbose, "Contract does not support eip712Domain()")
+		}
+
+		cli.Assert(separator != nil || calculated != nil, quiet, "Contract supports neither DOMAIN_SEPARATOR() nor eip712Domain()")
+		if separator == nil {
+			separator = calculated
+		} else if calculated != nil && *calculated != *separator {
+			cli.Err(quiet, fmt.Sprintf("DOMAIN_SEPARATOR() returned %s but eip712Domain() gives %s", separator.Hex(), calculated.Hex()))
+		}
+
+		if quiet {
+			os.Exit(_exit_success)
+		}
+		fmt.Println(separator.Hex())
+	},
+}
+
+// contractDomainSeparatorCall calls a function without arguments on the contract.
+func contractDomainSeparatorCall(contractAddress common.Address, selector []byte) ([]byte, error) {
+	ctx, cancel := localContext()
+	defer cancel()
+	return client.CallContract(ctx, ethereum.CallMsg{To: &contractAddress, Data: selector}, nil)
+}
+
+// contractDomainSeparatorDecodeDomain decodes the result of eip712Domain().
+func contractDomainSeparatorDecodeDomain(data []byte) (*util.EIP712Domain, error) {
+	args := abi.Arguments{}
+	for _, argType := range []string{"bytes1", "string", "string", "uint256", "address", "bytes32", "uint256[]"} {
+		t, err := abi.NewType(argType, "", nil)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, abi.Argument{Type: t})
+	}
+	values, err := args.UnpackValues(data)
+	if err != nil {
+		return nil, err
+	}
+	return &util.EIP712Domain{
+		Fields:            values[0].([1]byte)[0],
+		Name:              values[1].(string),
+		Version:           values[2].(string),
+		ChainID:           values[3].(*big.Int),
+		VerifyingContract: values[4].(common.Address),
+		Salt:              values[5].([32]byte),
+	}, nil
+}
+
+func init() {
+	contractCmd.AddCommand(contractDomainSeparatorCmd)
+	contractDomainSeparatorCmd.Flags().StringVar(&contractStr, "contract", "", "address of the contract")
+}
@@ -0,0 +1,94 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util/funcparser"
+)
+
+var contractInitHashConstructor string
+var contractInitHashData string
+
+// contractInitHashCmd represents the contract inithash command
+var contractInitHashCmd = &cobra.Command{
+	Use:   "inithash",
+	Short: "Obtain the init code hash of a contract",
+	Long: `Obtain the hash of a contract's init code, as used when calculating CREATE2 addresses.  For example:
+
+   ethereal contract inithash --data=0x606060...430029
+
+where data is the hex string of the contract binary, or the path to a file containing it.  If the contract constructor requires arguments then both the ABI and the constructor are required, for example:
+
+   ethereal contract inithash --data=0x606060...430029 --abi='./MyContract.abi' --constructor='constructor(1,2,3)'
+
+The combined JSON output of solc can also be used:
+
+   ethereal contract inithash --json='./MyContract.json' --constructor='constructor(1,2,3)'
+
+In quiet mode this will return 0 if the hash is calculated, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		initCode := contractInitCode(contractInitHashData, contractInitHashConstructor)
+
+		hash := crypto.Keccak256Hash(initCode)
+		if quiet {
+			os.Exit(_exit_success)
+		}
+		fmt.Printf("%s\n", hash.Hex())
+	},
+}
+
+// contractInitCode obtains the init code of a contract from its binary, as
+// supplied directly or in a file with --data or in the solc JSON supplied
+// with --json, and its constructor invocation if supplied.
+func contractInitCode(data string, constructor string) []byte {
+	cli.Assert(data != "" || contractJSON != "", quiet, "either --data or --json is required")
+
+	if data != "" && !strings.HasPrefix(data, "0x") {
+		// Data might be a path
+		fileData, err := ioutil.ReadFile(data)
+		if err == nil {
+			data = strings.TrimSpace(string(fileData))
+		}
+	}
+
+	contract := parseContract(data)
+	cli.Assert(len(contract.Binary) > 0, quiet, "failed to obtain contract binary data")
+	if constructor != "" {
+		_, constructorArgs, err := funcparser.ParseCall(client, contract, constructor)
+		cli.ErrCheck(err, quiet, "Failed to parse constructor")
+
+		argData, err := contract.Abi.Pack("", constructorArgs...)
+		cli.ErrCheck(err, quiet, "Failed to convert arguments")
+		outputIf(verbose, fmt.Sprintf("Constructor data is %x", argData))
+		contract.Binary = append(contract.Binary, argData...)
+	}
+	outputIf(verbose, fmt.Sprintf("Init code is %x", contract.Binary))
+	return contract.Binary
+}
+
+func init() {
+	offlineCmds["contract:inithash"] = true
+	contractCmd.AddCommand(contractInitHashCmd)
+	contractFlags(contractInitHashCmd)
+	contractInitHashCmd.Flags().StringVar(&contractInitHashConstructor, "constructor", "", "Constructor invocation (if required)")
+	contractInitHashCmd.Flags().StringVar(&contractInitHashData, "data", "", "Contract data (as a hex string, or path to a file containing a hex string)")
+}
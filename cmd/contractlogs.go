@@ -0,0 +1,227 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	"github.com/wealdtech/ethereal/util/funcparser"
+	"github.com/wealdtech/ethereal/util/output"
+)
+
+var contractLogsEvent string
+var contractLogsArgs []string
+var contractLogsFromBlock int64
+var contractLogsToBlock int64
+var contractLogsSince time.Duration
+var contractLogsChunkSize uint64
+
+// contractLogsCmd represents the contract logs command
+var contractLogsCmd = &cobra.Command{
+	Use:   "logs",
+	Short: "Obtain and decode the logs of a contract event",
+	Long: `Obtain the logs emitted by a contract for an event over a range of blocks, decoding their values.  For example:
+
+    ethereal contract logs --contract=0x6B175474E89094C44Da98b954EedeAC495271d0F --abi=./erc20.abi --event=Transfer --fromblock=17000000 --toblock=17001000
+
+The ABI is obtained in the same way as for "contract call", and the event is supplied either as its name or its full signature, for example Transfer(address,address,uint256).
+
+Logs can be filtered by the values of indexed arguments with --arg, in the form name=value or position=value, for example --arg=from=wealdtech.eth.  --arg can be repeated; supplying multiple values for the same argument matches any of them.  Indexed strings and bytes are stored as a hash of their value, so are matched by their full value and shown as the hash.
+
+Alternatively the range can start from a period of time ago, for example --since=24h.  If --toblock is not supplied then logs up to the latest block are obtained.  The range is queried in chunks of --chunksize blocks, to stay within the limits that nodes place on the number of logs returned by a single query.
+
+The --output flag selects the format of the output: text (the default), json or csv.
+
+In quiet mode this will return 0 if any logs are found, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(contractStr != "", quiet, "--contract is required")
+		cli.Assert(contractLogsEvent != "", quiet, "--event is required")
+		cli.Assert(contractLogsFromBlock >= 0 || contractLogsSince > 0, quiet, "--fromblock or --since is required")
+		cli.Assert(contractLogsFromBlock < 0 || contractLogsSince == 0, quiet, "only one of --fromblock and --since can be supplied")
+		cli.Assert(contractLogsChunkSize > 0, quiet, "--chunksize must be greater than 0")
+		formatter, err := output.New(outputFormat)
+		cli.ErrCheck(err, quiet, "Invalid output format")
+		formatter.SetNameResolver(func(address common.Address) string { return util.ENSFormat(client, address) })
+		// Always output an array, regardless of the number of logs.
+		formatter.SetArray(true)
+
+		contractAddress, err := util.ResolveAddress(client, contractStr)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve contract address %s", contractStr))
+		contract := parseContract("")
+		err = contractResolveABI(contract, contractAddress, chainID)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain ABI for %s", contractStr))
+		event := contractLogsFindEvent(contract)
+		outputIf(verbose, fmt.Sprintf("Event is %s with topic %s", event.Sig, event.ID.Hex()))
+
+		topics, err := util.EventTopics(event, contractLogsFilters(event))
+		cli.ErrCheck(err, quiet, "Failed to create filter")
+
+		toBlock := uint64(contractLogsToBlock)
+		if contractLogsToBlock < 0 {
+			toBlock = latestHeader().Number.Uint64()
+		}
+		fromBlock := uint64(contractLogsFromBlock)
+		if contractLogsSince > 0 {
+			fromBlock = sinceBlock(contractLogsSince)
+		}
+		cli.Assert(fromBlock <= toBlock, quiet, "--fromblock must not be after --toblock")
+
+		records := make([]output.Record, 0)
+		for _, blocks := range util.BlockRanges(fromBlock, toBlock, contractLogsChunkSize) {
+			outputIf(verbose, fmt.Sprintf("Obtaining logs for blocks %d to %d", blocks.From, blocks.To))
+			ctx, cancel := localContext()
+			logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+				FromBlock: new(big.Int).SetUint64(blocks.From),
+				ToBlock:   new(big.Int).SetUint64(blocks.To),
+				Addresses: []common.Address{contractAddress},
+				Topics:    topics,
+			})
+			cancel()
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain logs for blocks %d to %d; try a lower --chunksize", blocks.From, blocks.To))
+			for i := range logs {
+				if logs[i].Removed {
+					continue
+				}
+				record, err := contractLogsRecord(event, &logs[i])
+				if err != nil {
+					// Anonymous events cannot be told apart by topic so may
+					// not match the ABI; ignore those that fail to decode.
+					cli.Assert(event.Anonymous, quiet, fmt.Sprintf("Failed to decode log %d in transaction %s: %v", logs[i].Index, logs[i].TxHash.Hex(), err))
+					outputIf(verbose, fmt.Sprintf("Ignoring log %d in transaction %s: %v", logs[i].Index, logs[i].TxHash.Hex(), err))
+					continue
+				}
+				records = append(records, record)
+			}
+		}
+
+		if quiet {
+			if len(records) == 0 {
+				os.Exit(_exit_failure)
+			}
+			os.Exit(_exit_success)
+		}
+
+		cli.ErrCheck(outputRecords(cmd, formatter, records...), quiet, "Failed to output logs")
+	},
+}
+
+// contractLogsFindEvent finds the event in the contract's ABI by name or
+// signature.
+func contractLogsFindEvent(contract *util.Contract) *abi.Event {
+	signature := strings.Replace(contractLogsEvent, " ", "", -1)
+	names := make([]string, 0, len(contract.Abi.Events))
+	for name := range contract.Abi.Events {
+		event := contract.Abi.Events[name]
+		if name == contractLogsEvent || event.Sig == signature {
+			return &event
+		}
+		names = append(names, event.Sig)
+	}
+	cli.Assert(len(names) > 0, quiet, "ABI has no events; supply it with --abi or --json")
+	sort.Strings(names)
+	cli.Err(quiet, fmt.Sprintf("Unknown event %s; events in the ABI are %s", contractLogsEvent, strings.Join(names, ", ")))
+	return nil
+}
+
+// contractLogsFilters parses the values supplied with --arg, keyed by the
+// position of their input.
+func contractLogsFilters(event *abi.Event) map[int][]interface{} {
+	filters := make(map[int][]interface{})
+	for _, arg := range contractLogsArgs {
+		parts := strings.SplitN(arg, "=", 2)
+		cli.Assert(len(parts) == 2, quiet, fmt.Sprintf("Invalid --arg %s; must be in the form name=value", arg))
+		position := -1
+		for i, input := range event.Inputs {
+			if input.Name == parts[0] || fmt.Sprintf("%d", i) == parts[0] {
+				position = i
+				break
+			}
+		}
+		cli.Assert(position != -1, quiet, fmt.Sprintf("Unknown argument %s for event %s", parts[0], event.Name))
+		input := event.Inputs[position]
+
+		var value interface{}
+		var err error
+		switch input.Type.T {
+		case abi.AddressTy:
+			value, err = util.ResolveAddress(client, parts[1])
+		case abi.StringTy:
+			value = parts[1]
+		default:
+			value, err = funcparser.StrTo(&input.Type, parts[1])
+		}
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Invalid value %s for argument %s", parts[1], parts[0]))
+		filters[position] = append(filters[position], value)
+	}
+	return filters
+}
+
+// contractLogsRecord decodes a log in to a record.
+func contractLogsRecord(event *abi.Event, log *types.Log) (output.Record, error) {
+	values, err := util.DecodeLog(event, log)
+	if err != nil {
+		return nil, err
+	}
+	args := make(output.Record, len(values))
+	for i, input := range event.Inputs {
+		args[i].Name = input.Name
+		if args[i].Name == "" {
+			args[i].Name = fmt.Sprintf("%d", i)
+		}
+		switch value := values[i].(type) {
+		case common.Address, common.Hash:
+			// Addresses are named in text output; hashes include indexed
+			// values of dynamic types.
+			args[i].Value = value
+		default:
+			args[i].Value, err = contractValueToString(input.Type, value)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return output.Record{
+		{Name: "block", Value: log.BlockNumber},
+		{Name: "transaction", Value: log.TxHash},
+		{Name: "index", Value: log.Index},
+		{Name: "event", Value: event.Name},
+		{Name: "args", Value: args},
+	}, nil
+}
+
+func init() {
+	contractCmd.AddCommand(contractLogsCmd)
+	contractFlags(contractLogsCmd)
+	contractLogsCmd.Flags().StringVar(&contractStr, "address", "", "address of the contract (alias for --contract)")
+	contractLogsCmd.Flags().StringVar(&contractLogsEvent, "event", "", "Name or signature of the event")
+	contractLogsCmd.Flags().StringArrayVar(&contractLogsArgs, "arg", nil, "Value of an indexed argument on which to filter, in the form name=value; can be repeated")
+	contractLogsCmd.Flags().Int64Var(&contractLogsFromBlock, "fromblock", -1, "Block from which to obtain logs")
+	contractLogsCmd.Flags().DurationVar(&contractLogsSince, "since", 0, "Time before now from which to obtain logs, for example 24h (instead of --fromblock)")
+	contractLogsCmd.Flags().Int64Var(&contractLogsToBlock, "toblock", -1, "Block up to which to obtain logs (defaults to latest)")
+	contractLogsCmd.Flags().Uint64Var(&contractLogsChunkSize, "chunksize", 10000, "Maximum number of blocks to query for logs at a time")
+	outputFlags(contractLogsCmd)
+	jsonoutCmds["contract:logs"] = true
+}
@@ -14,8 +14,6 @@
 package cmd
 
 import (
-	"bytes"
-	"encoding/hex"
 	"fmt"
 	"math/big"
 	"os"
@@ -23,14 +21,15 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	"github.com/wealdtech/ethereal/util/funcparser"
-	ens "github.com/wealdtech/go-ens/v3"
-	string2eth "github.com/wealdtech/go-string2eth"
 )
 
 var contractSendAmount string
 var contractSendFromAddress string
 var contractSendCall string
+var contractSendArgsFile string
+var contractSendStructured bool
 
 // contractSendCmd represents the contract call command
 var contractSendCmd = &cobra.Command{
@@ -40,33 +39,45 @@ var contractSendCmd = &cobra.Command{
 
    ethereal contract send --contract=0xd26114cd6EE289AccF82350c8d8487fedB8A0C07 --abi="./erc20.abi" --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --call="transfer(0x5FfC014343cd971B7eb70732021E26C35B744cc4, 10)" --passphrase=secret
 
-   ethereal contract send --contract=0xd26114cd6EE289AccF82350c8d8487fedB8A0C07 --signature="transfer(address,uint256)" --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --call="transfer(0x5FfC014343cd971B7eb70732021E26C35B744cc4, 10)" --passphrase=secret
+   ethereal contract send --contract=0xd26114cd6EE289AccF82350c8d8487fedB8A0C07 --function="transfer(address,uint256)" --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --call="transfer(0x5FfC014343cd971B7eb70732021E26C35B744cc4, 10)" --passphrase=secret
+
+If none of --abi, --json or --function is supplied then the ABI is obtained from the contract's ENS ABI record or "ABI" text record if present, otherwise from the Etherscan-compatible API supplied with --abisource (with an API key in --abisourcekey if required).  ABIs obtained this way are cached in the user configuration directory, so once cached a contract can be referenced by its ENS name alone; --refreshabi obtains the ABI afresh.
+
+Arguments can also be read from a JSON file with --argsfile, in which case --call is just the name of the function.  The format of the file is as per "ethereal contract call".
+
+If --showcalldata is supplied then the hex-encoded calldata for the method is output prior to the transaction hash.
+
+If --structured is supplied then a JSON representation of the transaction, with the contract call decoded into its function and named parameters, is output prior to the transaction hash.  Tuple parameters are output as a list of their named components.  This allows the transaction to be checked by a reviewer or clear-signing tool rather than as an opaque hash.  In offline mode the JSON includes the signed transaction and replaces the usual hex output.
 
 This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
 	Aliases: []string{"transaction", "transmit"},
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(contractSendFromAddress != "", quiet, "--from is required")
-		fromAddress, err := ens.Resolve(client, contractSendFromAddress)
+		fromAddress, err := util.ResolveAddress(client, contractSendFromAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", contractSendFromAddress))
 
+		cli.Assert(contractStr != "", quiet, "--contract is required")
+		contractAddress, err := util.ResolveAddress(client, contractStr)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve contract address %s", contractStr))
+
 		// We need to have 'call'
 		cli.Assert(contractSendCall != "", quiet, "--call is required")
 
 		contract := parseContract("")
-		method, methodArgs, err := funcparser.ParseCall(client, contract, contractSendCall)
-		cli.ErrCheck(err, quiet, "Failed to parse call")
+		err = contractResolveABI(contract, contractAddress, chainID)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain ABI for %s", contractStr))
+		method, signature, methodArgs := contractParseCall(contract, contractSendCall, contractSendArgsFile)
+		outputIf(verbose, fmt.Sprintf("Method is %s", signature))
+		outputIf(verbose, fmt.Sprintf("About to call %s", funcparser.FormatCall(method, methodArgs)))
 
 		data, err := contract.Abi.Pack(method.Name, methodArgs...)
 		cli.ErrCheck(err, quiet, "Failed to convert arguments")
 		outputIf(verbose, fmt.Sprintf("Data is %x", data))
-
-		cli.Assert(contractStr != "", quiet, "--contract is required")
-		contractAddress, err := ens.Resolve(client, contractStr)
-		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve contract address %s", contractStr))
+		outputCalldata(data)
 
 		amount := big.NewInt(0)
 		if contractSendAmount != "" {
-			amount, err = string2eth.StringToWei(contractSendAmount)
+			amount, err = parseAmount(contractSendAmount)
 			cli.ErrCheck(err, quiet, fmt.Sprintf("Invalid amount %s", contractSendAmount))
 		}
 
@@ -74,11 +85,18 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		signedTx, err := createSignedTransaction(fromAddress, &contractAddress, amount, gasLimit, data)
 		cli.ErrCheck(err, quiet, "Failed to create contract method transaction")
 
+		if contractSendStructured && !quiet {
+			structured, err := newStructuredTransaction(fromAddress, signedTx, method, methodArgs, offline)
+			cli.ErrCheck(err, quiet, "Failed to create structured transaction")
+			cli.ErrCheck(outputJSON(cmd, structured), quiet, "Failed to output structured transaction")
+			if offline {
+				os.Exit(_exit_success)
+			}
+		}
+
 		if offline {
 			if !quiet {
-				buf := new(bytes.Buffer)
-				signedTx.EncodeRLP(buf)
-				fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+				outputOfflineTransaction(signedTx)
 			}
 			os.Exit(_exit_success)
 		}
@@ -98,8 +116,11 @@ This will return an exit status of 0 if the transaction is successfully submitte
 func init() {
 	contractCmd.AddCommand(contractSendCmd)
 	contractFlags(contractSendCmd)
-	contractSendCmd.Flags().StringVar(&contractSendAmount, "amount", "", "Amount of Ether to send with the contract method")
+	contractSendCmd.Flags().StringVar(&contractSendAmount, "amount", "", "Amount of Ether to send with the contract method, with a unit (e.g. \"1.5 ether\")")
 	contractSendCmd.Flags().StringVar(&contractSendFromAddress, "from", "", "Address from which to call the contract function")
 	contractSendCmd.Flags().StringVar(&contractSendCall, "call", "", "Contract function to call")
+	contractSendCmd.Flags().StringVar(&contractSendArgsFile, "argsfile", "", "JSON file containing the arguments for the function (--call is then just the function name)")
+	addShowCalldataFlag(contractSendCmd, "calldata for the contract function")
+	contractSendCmd.Flags().BoolVar(&contractSendStructured, "structured", false, "Output a structured JSON representation of the transaction with the contract call decoded")
 	addTransactionFlags(contractSendCmd, "Passphrase for the address from which to send the contract transaction")
 }
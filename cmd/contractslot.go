@@ -0,0 +1,94 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+)
+
+var contractSlotType string
+var contractSlotBase string
+var contractSlotKey string
+var contractSlotKeyType string
+var contractSlotIndex string
+
+// contractSlotCmd represents the contract slot command
+var contractSlotCmd = &cobra.Command{
+	Use:   "slot",
+	Short: "Calculate the storage slot of a mapping or array element",
+	Long: `Calculate the storage slot of an element of a mapping or dynamic array, for use with 'contract storage'.  For example:
+
+   ethereal contract slot --type=mapping --base=5 --keytype=address --key=0x5FfC014343cd971B7eb70732021E26C35B744cc4
+
+For mappings of mappings supply comma-separated keys and key types, outermost first:
+
+   ethereal contract slot --type=mapping --base=5 --keytype=address,uint256 --key=0x5FfC014343cd971B7eb70732021E26C35B744cc4,12
+
+For dynamic arrays supply the index of the element:
+
+   ethereal contract slot --type=array --base=5 --index=3
+
+The base is the slot of the mapping or array itself, and can be supplied as a decimal or hex value.
+
+In quiet mode this will return 0 if the slot is calculated, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(contractSlotBase != "", quiet, "--base is required")
+		slot, err := util.StringToSlot(contractSlotBase)
+		cli.ErrCheck(err, quiet, "Invalid base slot")
+
+		switch contractSlotType {
+		case "mapping":
+			cli.Assert(contractSlotKey != "", quiet, "--key is required for mappings")
+			cli.Assert(contractSlotKeyType != "", quiet, "--keytype is required for mappings")
+			keys := strings.Split(contractSlotKey, ",")
+			keyTypes := strings.Split(contractSlotKeyType, ",")
+			cli.Assert(len(keys) == len(keyTypes), quiet, "--key and --keytype must have the same number of values")
+			for i := range keys {
+				key, err := util.EncodeMappingKey(strings.TrimSpace(keyTypes[i]), strings.TrimSpace(keys[i]))
+				cli.ErrCheck(err, quiet, fmt.Sprintf("Invalid key %s", keys[i]))
+				outputIf(verbose, fmt.Sprintf("Encoded key is %x", key))
+				slot = util.MappingSlot(slot, key)
+			}
+		case "array":
+			cli.Assert(contractSlotIndex != "", quiet, "--index is required for arrays")
+			index, success := new(big.Int).SetString(contractSlotIndex, 10)
+			cli.Assert(success && index.Sign() >= 0, quiet, fmt.Sprintf("Invalid index %s", contractSlotIndex))
+			slot = util.ArraySlot(slot, index)
+		default:
+			cli.Err(quiet, "--type must be one of mapping or array")
+		}
+
+		if quiet {
+			os.Exit(_exit_success)
+		}
+		fmt.Printf("%s\n", slot.Hex())
+	},
+}
+
+func init() {
+	offlineCmds["contract:slot"] = true
+	contractCmd.AddCommand(contractSlotCmd)
+	contractSlotCmd.Flags().StringVar(&contractSlotType, "type", "mapping", "Type of the storage item (mapping or array)")
+	contractSlotCmd.Flags().StringVar(&contractSlotBase, "base", "", "Slot of the mapping or array")
+	contractSlotCmd.Flags().StringVar(&contractSlotKey, "key", "", "Key (or comma-separated keys for nested mappings) of the mapping element")
+	contractSlotCmd.Flags().StringVar(&contractSlotKeyType, "keytype", "", "Solidity type (or comma-separated types for nested mappings) of the mapping key")
+	contractSlotCmd.Flags().StringVar(&contractSlotIndex, "index", "", "Index of the array element")
+}
@@ -21,7 +21,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
-	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/ethereal/util"
 )
 
 var contractStorageFromAddress string
@@ -40,7 +40,7 @@ var contractStorageCmd = &cobra.Command{
 In quiet mode this will return 0 if the storage contains a non-zero value, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(contractStr != "", quiet, "--contract is required")
-		contractAddress, err := ens.Resolve(client, contractStr)
+		contractAddress, err := util.ResolveAddress(client, contractStr)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve contract address %s", contractStr))
 
 		cli.Assert(contractStorageKey != "", quiet, "--key is required")
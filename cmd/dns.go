@@ -14,13 +14,20 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/miekg/dns"
 	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	ens "github.com/wealdtech/go-ens/v3"
 )
 
 var dnsDomain string
 var dnsResource string
 var dnsName string
+var dnsNoSOABump bool
+var dnsSOASerial uint32
 
 // dnsCmd represents the dns command
 var dnsCmd = &cobra.Command{
@@ -48,3 +55,44 @@ func dnsFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&dnsResource, "resource", "", "The resource (A, NS, CNAME etc.)")
 	cmd.Flags().StringVar(&dnsName, "name", "", "The name for the resource (end with \".\" for fully-qualified domain, otherwise domain will be added)")
 }
+
+// dnsSOAFlags adds the flags that control updating of the SOA serial.
+func dnsSOAFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&dnsNoSOABump, "nosoabump", false, "Do not update the serial of the zone's SOA record")
+	cmd.Flags().Uint32Var(&dnsSOASerial, "soaserial", 0, "Serial for the zone's SOA record (default is to increment the current serial)")
+}
+
+// dnsCurrentSOA obtains the current SOA record for a domain, returning nil
+// if there is no SOA record.
+func dnsCurrentSOA(resolver *ens.DNSResolver, domain string) *dns.SOA {
+	data, err := resolver.Record(domain, dns.TypeSOA)
+	cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain SOA resource for %s", domain))
+	if len(data) == 0 {
+		return nil
+	}
+	rr, _, err := dns.UnpackRR(data, 0)
+	cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to unpack SOA resource for %s", domain))
+	soa, isSOA := rr.(*dns.SOA)
+	cli.Assert(isSOA, quiet, fmt.Sprintf("Invalid SOA resource for %s", domain))
+	outputIf(verbose, fmt.Sprintf("Current SOA record is %v", soa))
+	return soa
+}
+
+// dnsNextSOASerial returns the serial for an updated SOA record.  This is
+// the serial supplied with --soaserial if present, otherwise the current
+// serial incremented as per RFC 1912.
+func dnsNextSOASerial(current uint32) uint32 {
+	if dnsSOASerial != 0 {
+		return dnsSOASerial
+	}
+	return util.IncrementSerial(current)
+}
+
+// dnsPackSOA packs an SOA record.
+func dnsPackSOA(soa *dns.SOA) []byte {
+	outputIf(verbose, fmt.Sprintf("New SOA record is %v", soa))
+	data := make([]byte, 16384)
+	offset, err := dns.PackRR(soa, data, 0, nil, false)
+	cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to pack resource record %v", soa))
+	return data[0:offset]
+}
@@ -15,15 +15,17 @@ package cmd
 
 import (
 	"bytes"
-	"encoding/hex"
 	"fmt"
 	"os"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/go-ens/v3/contracts/dnsresolver"
 )
 
 // dnsClearCmd represents the dns clear command
@@ -34,6 +36,8 @@ var dnsClearCmd = &cobra.Command{
 
     ethereal dns clear --domain=wealdtech.eth --passphrase=secret
 
+If --simulate is supplied then the transaction is first run as a call against the pending block, and is not sent if it would fail.
+
 This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(dnsDomain != "", quiet, "--domain is required")
@@ -50,7 +54,7 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		outputIf(verbose, fmt.Sprintf("ENS domain hash is 0x%x", domainHash))
 
 		// Obtain the registry contract
-		registry, err := ens.NewRegistry(client)
+		registry, err := util.ENSRegistry(client)
 		cli.ErrCheck(err, quiet, "Cannot obtain ENS registry contract")
 
 		// Obtain owner for the domain
@@ -58,12 +62,20 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		cli.ErrCheck(err, quiet, "Cannot obtain owner")
 
 		cli.Assert(bytes.Compare(domainOwner.Bytes(), ens.UnknownAddress.Bytes()) != 0, quiet, "Owner is not set")
-		outputIf(verbose, fmt.Sprintf("Domain owner is %s", ens.Format(client, domainOwner)))
+		outputIf(verbose, fmt.Sprintf("Domain owner is %s", util.ENSFormat(client, domainOwner)))
 
 		// Obtain resolver for the domain
-		resolver, err := ens.NewDNSResolver(client, ensDomain)
+		resolver, err := util.ENSDNSResolver(client, ensDomain)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain resolver contract for %s", dnsDomain))
 
+		if simulate {
+			resolverABI, err := abi.JSON(strings.NewReader(dnsresolver.ContractABI))
+			cli.ErrCheck(err, quiet, "Failed to parse resolver ABI")
+			data, err := resolverABI.Pack("clearDNSZone", domainHash)
+			cli.ErrCheck(err, quiet, "Failed to create transaction data")
+			simulateTransaction(domainOwner, &resolver.ContractAddr, nil, data)
+		}
+
 		// Build the transaction
 		opts, err := generateTxOpts(domainOwner)
 		cli.ErrCheck(err, quiet, "Failed to generate transaction options")
@@ -71,9 +83,7 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		cli.ErrCheck(err, quiet, "Failed to create transaction")
 		if offline {
 			if !quiet {
-				buf := new(bytes.Buffer)
-				signedTx.EncodeRLP(buf)
-				fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+				outputOfflineTransaction(signedTx)
 			}
 			os.Exit(_exit_success)
 		}
@@ -90,4 +100,5 @@ func init() {
 	dnsCmd.AddCommand(dnsClearCmd)
 	dnsFlags(dnsClearCmd)
 	addTransactionFlags(dnsClearCmd, "the owner of the domain")
+	addSimulateFlag(dnsClearCmd)
 }
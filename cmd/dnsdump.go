@@ -0,0 +1,166 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/miekg/dns"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+var dnsDumpConcurrency int
+
+// dnsDumpQueryTypes are types that only exist in queries and so are never
+// stored.
+var dnsDumpQueryTypes = map[uint16]bool{
+	dns.TypeNone:     true,
+	dns.TypeANY:      true,
+	dns.TypeAXFR:     true,
+	dns.TypeIXFR:     true,
+	dns.TypeMAILA:    true,
+	dns.TypeMAILB:    true,
+	dns.TypeReserved: true,
+}
+
+// dnsDumpCmd represents the dns dump command
+var dnsDumpCmd = &cobra.Command{
+	Use:   "dump",
+	Short: "Obtain all DNS records for a name",
+	Long: `Obtain all DNS resource records for a name, regardless of type.  For example:
+
+    ethereal dns dump --domain=wealdtech.eth --name=www
+
+If no name is supplied then the records for the domain itself are obtained.
+
+Each resource type is queried separately, with up to --concurrency queries in flight at a time.  Increasing this can speed up dumps against remote nodes with high latency.
+
+In quiet mode this will return 0 if any resources exist, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
+
+		cli.Assert(dnsDomain != "", quiet, "--domain is required")
+		cli.Assert(dnsDumpConcurrency > 0, quiet, "--concurrency must be at least 1")
+		if !strings.HasSuffix(dnsDomain, ".") {
+			dnsDomain = dnsDomain + "."
+		}
+		var err error
+		dnsDomain, err = ens.NormaliseDomain(dnsDomain)
+		cli.ErrCheck(err, quiet, "Failed to normalise ENS domain")
+		outputIf(verbose, fmt.Sprintf("DNS domain is %s", dnsDomain))
+		ensDomain := strings.TrimSuffix(dnsDomain, ".")
+		outputIf(verbose, fmt.Sprintf("ENS domain is %s", ensDomain))
+
+		dnsName = strings.ToLower(dnsName)
+		if dnsName == "" || dnsName == "@" {
+			dnsName = dnsDomain
+		} else {
+			if !strings.HasSuffix(dnsName, ".") {
+				dnsName = dnsName + "." + dnsDomain
+			}
+		}
+		outputIf(verbose, fmt.Sprintf("DNS name is %s", dnsName))
+
+		// Obtain DNS resolver for the domain
+		resolver, err := util.ENSDNSResolver(client, ensDomain)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain resolver contract for %s", dnsDomain))
+
+		// Avoid querying each type if there are no records at all
+		hasRecords, err := resolver.HasRecords(dnsName)
+		if err != nil {
+			outputIf(verbose, fmt.Sprintf("Resolver does not support checking for records: %v", err))
+		} else {
+			cli.Assert(hasRecords, quiet, fmt.Sprintf("No resources for %s", dnsName))
+		}
+
+		resourceNums := make([]int, 0, len(stringToType))
+		for _, resourceNum := range stringToType {
+			if !dnsDumpQueryTypes[resourceNum] {
+				resourceNums = append(resourceNums, int(resourceNum))
+			}
+		}
+		sort.Ints(resourceNums)
+
+		// Fetch the records in parallel, as there are many types to query
+		domainHash, err := ens.NameHash(ensDomain)
+		cli.ErrCheck(err, quiet, "Failed to obtain name hash of ENS domain")
+		nameHash := util.DNSWireFormatDomainHash(dnsName)
+		results := make([][]byte, len(resourceNums))
+		errs := make([]error, len(resourceNums))
+		indexCh := make(chan int)
+		var wg sync.WaitGroup
+		for i := 0; i < dnsDumpConcurrency; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for index := range indexCh {
+					results[index], errs[index] = resolver.Contract.DnsRecord(nil, domainHash, nameHash, uint16(resourceNums[index]))
+				}
+			}()
+		}
+		for i := range resourceNums {
+			indexCh <- i
+		}
+		close(indexCh)
+		wg.Wait()
+
+		failed := make([]string, 0)
+		for i := range errs {
+			if errs[i] != nil {
+				outputIf(verbose, fmt.Sprintf("Failed to obtain %s resource: %v", dns.TypeToString[uint16(resourceNums[i])], errs[i]))
+				failed = append(failed, dns.TypeToString[uint16(resourceNums[i])])
+			}
+		}
+		cli.Assert(len(failed) == 0, quiet, fmt.Sprintf("Failed to obtain %s resource(s) %s for %s", strings.Join(failed, ", "), dnsName, dnsDomain))
+
+		seen := make(map[string]bool)
+		records := make([]dns.RR, 0)
+		for i, data := range results {
+			offset := 0
+			var result dns.RR
+			for offset < len(data) {
+				result, offset, err = dns.UnpackRR(data, offset)
+				if err != nil {
+					outputIf(verbose, fmt.Sprintf("Failed to unpack %s resource: %v", dns.TypeToString[uint16(resourceNums[i])], err))
+					break
+				}
+				if !seen[result.String()] {
+					seen[result.String()] = true
+					records = append(records, result)
+				}
+			}
+		}
+		cli.Assert(len(records) > 0, quiet, fmt.Sprintf("No resources for %s", dnsName))
+
+		if quiet {
+			os.Exit(_exit_success)
+		}
+		for _, record := range records {
+			fmt.Println(record)
+		}
+	},
+}
+
+func init() {
+	dnsCmd.AddCommand(dnsDumpCmd)
+	dnsFlags(dnsDumpCmd)
+	dnsDumpCmd.Flags().IntVar(&dnsDumpConcurrency, "concurrency", 4, "Number of resource types to query concurrently")
+}
@@ -19,9 +19,9 @@ import (
 	"os"
 	"strings"
 
-	"github.com/miekg/dns"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	ens "github.com/wealdtech/go-ens/v3"
 )
 
@@ -35,7 +35,15 @@ var dnsGetCmd = &cobra.Command{
 
     ethereal dns get --domain=wealdtech.eth --name=www --resource=A
 
-In quiet mode this will return 0 if the resource exists, otherwise 1.`,
+Multiple resources can be obtained at once by supplying a comma-separated list, for example:
+
+    ethereal dns get --domain=wealdtech.eth --name=www --resource=A,AAAA,MX
+
+in which case the records for each resource are output under a header.  The resources are obtained in a single batch request where the connection supports it.
+
+DNSSEC records such as RRSIG, NSEC, DNSKEY and DS are followed by a comment line describing their key fields, for example the type covered, algorithm, key tag and validity period of a signature.
+
+In quiet mode this will return 0 if all of the requested resources exist, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
 
@@ -59,40 +67,97 @@ In quiet mode this will return 0 if the resource exists, otherwise 1.`,
 		}
 		outputIf(verbose, fmt.Sprintf("DNS name is %s", dnsName))
 
+		resources, resourceNums := dnsGetResources(dnsResource)
+
 		// Obtain DNS resolver for the domain
-		resolver, err := ens.NewDNSResolver(client, ensDomain)
+		resolver, err := util.ENSDNSResolver(client, ensDomain)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain resolver contract for %s", dnsDomain))
 
-		var data []byte
-		// Attempt to fetch record
-		dnsResource := strings.ToUpper(dnsResource)
-		resourceNum, exists := stringToType[dnsResource]
-		cli.Assert(exists, quiet, fmt.Sprintf("Unknown resource %s", dnsResource))
-		outputIf(verbose, fmt.Sprintf("Resource record is %s (%d)", dnsResource, resourceNum))
-		data, err = resolver.Record(dnsName, resourceNum)
-		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain %s resource %s for %s", dnsResource, dnsName, dnsDomain))
-		cli.Assert(len(data) > 0, quiet, fmt.Sprintf("No value of %s resource %s for %s", dnsResource, dnsName, dnsDomain))
+		// Hashes are the same for all resources so compute them once
+		domainHash, err := ens.NameHash(ensDomain)
+		cli.ErrCheck(err, quiet, "Failed to obtain name hash of ENS domain")
+		nameHash := util.DNSWireFormatDomainHash(dnsName)
+
+		for i := range resources {
+			outputIf(verbose, fmt.Sprintf("Resource record is %s (%d)", resources[i], resourceNums[i]))
+		}
+		// Obtain all of the resources with a single batch request
+		ctx, cancel := localContext()
+		defer cancel()
+		records, errs, err := util.DNSRecords(ctx, rpcClient, resolver.ContractAddr, domainHash, nameHash, resourceNums)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain resources %s for %s", dnsName, dnsDomain))
+		missing := make([]string, 0)
+		for i := range resources {
+			cli.ErrCheck(errs[i], quiet, fmt.Sprintf("Failed to obtain %s resource %s for %s", resources[i], dnsName, dnsDomain))
+			if len(records[i]) == 0 {
+				missing = append(missing, resources[i])
+			}
+		}
+		if len(resources) == 1 {
+			cli.Assert(len(missing) == 0, quiet, fmt.Sprintf("No value of %s resource %s for %s", resources[0], dnsName, dnsDomain))
+		}
 
 		if quiet {
+			if len(missing) > 0 {
+				os.Exit(_exit_failure)
+			}
 			os.Exit(_exit_success)
 		}
 
-		if dnsGetWire {
-			fmt.Println(hex.EncodeToString(data))
-		} else {
-			// Decode the data resource record(s)
-			offset := 0
-			var result dns.RR
-			for offset < len(data) {
-				result, offset, err = dns.UnpackRR(data, offset)
-				if err == nil {
-					fmt.Println(result)
+		for i := range resources {
+			if len(resources) > 1 {
+				if i > 0 {
+					fmt.Println()
+				}
+				fmt.Printf(";; %s\n", resources[i])
+			}
+			if len(records[i]) == 0 {
+				fmt.Printf(";; No value of %s resource %s for %s\n", resources[i], dnsName, dnsDomain)
+				continue
+			}
+			if dnsGetWire {
+				fmt.Println(hex.EncodeToString(records[i]))
+			} else {
+				// Decode the data resource record(s)
+				rrs, err := util.DNSUnpackRRs(records[i])
+				for _, rr := range rrs {
+					fmt.Println(rr)
+					if details := util.DNSSECDetails(rr); details != "" {
+						fmt.Printf(";; %s\n", details)
+					}
+				}
+				if err != nil {
+					fmt.Printf(";; Failed to decode %s resource: %v\n", resources[i], err)
 				}
 			}
 		}
+		if len(missing) > 0 {
+			os.Exit(_exit_failure)
+		}
 	},
 }
 
+// dnsGetResources parses a comma-separated list of resource types,
+// returning their names and numeric values.
+func dnsGetResources(input string) ([]string, []uint16) {
+	cli.Assert(input != "", quiet, "--resource is required")
+	resources := make([]string, 0)
+	resourceNums := make([]uint16, 0)
+	unknown := make([]string, 0)
+	for _, resource := range strings.Split(input, ",") {
+		resource = strings.ToUpper(strings.TrimSpace(resource))
+		resourceNum, exists := stringToType[resource]
+		if !exists {
+			unknown = append(unknown, resource)
+			continue
+		}
+		resources = append(resources, resource)
+		resourceNums = append(resourceNums, resourceNum)
+	}
+	cli.Assert(len(unknown) == 0, quiet, fmt.Sprintf("Unknown resource(s) %s", strings.Join(unknown, ", ")))
+	return resources, resourceNums
+}
+
 func init() {
 	dnsCmd.AddCommand(dnsGetCmd)
 	dnsFlags(dnsGetCmd)
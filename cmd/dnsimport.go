@@ -0,0 +1,199 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/miekg/dns"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+var dnsImportFile string
+
+// dnsImportRRSet is a resource record set within an imported zone.
+type dnsImportRRSet struct {
+	name   string
+	rrType uint16
+	rrs    []dns.RR
+}
+
+// dnsImportCmd represents the dns import command
+var dnsImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import records for a DNS zone from a zone file",
+	Long: `Import all records in a standard (RFC 1035) zone file in to a DNS zone.  For example to import the records in wealdtech.zone to wealdtech.eth:
+
+    ethereal dns import --domain=wealdtech.eth --file=wealdtech.zone --passphrase=secret
+
+Records are grouped in to resource record sets and set in a single transaction.  The domain is used as the initial origin for the zone file; $ORIGIN and $TTL directives are honoured.  All records must be within the domain.
+
+The serial of the zone's SOA record is updated so that caching resolvers pick up the change.  If the zone file does not contain an SOA record then the current SOA record is added with its serial incremented using the date-based format YYYYMMDDnn as per RFC 1912.  If the zone file contains an SOA record with a serial that is not higher than the current serial then the current serial is incremented instead.  The serial can be set explicitly with --soaserial, and updating it can be disabled with --nosoabump.
+
+This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(dnsDomain != "", quiet, "--domain is required")
+		cli.Assert(dnsImportFile != "", quiet, "--file is required")
+		if !strings.HasSuffix(dnsDomain, ".") {
+			dnsDomain = dnsDomain + "."
+		}
+		var err error
+		dnsDomain, err = ens.NormaliseDomain(dnsDomain)
+		cli.ErrCheck(err, quiet, "Failed to normalise ENS domain")
+		outputIf(verbose, fmt.Sprintf("DNS domain is %s", dnsDomain))
+		ensDomain := strings.TrimSuffix(dnsDomain, ".")
+		outputIf(verbose, fmt.Sprintf("ENS domain is %s", ensDomain))
+
+		rrSets, err := dnsImportParseZone(dnsImportFile, dnsDomain)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to parse zone file %s", dnsImportFile))
+		cli.Assert(len(rrSets) > 0, quiet, fmt.Sprintf("No records found in %s", dnsImportFile))
+
+		// Obtain the registry contract
+		registry, err := util.ENSRegistry(client)
+		cli.ErrCheck(err, quiet, "Cannot obtain ENS registry contract")
+
+		// Obtain owner for the domain
+		domainOwner, err := registry.Owner(ensDomain)
+		cli.ErrCheck(err, quiet, "Cannot obtain owner")
+
+		cli.Assert(bytes.Compare(domainOwner.Bytes(), ens.UnknownAddress.Bytes()) != 0, quiet, "Owner is not set")
+		outputIf(verbose, fmt.Sprintf("Domain owner is %s", util.ENSFormat(client, domainOwner)))
+
+		// Obtain DNS resolver for the domain
+		resolver, err := util.ENSDNSResolver(client, ensDomain)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain resolver contract for %s", dnsDomain))
+
+		if !dnsNoSOABump {
+			rrSets = dnsImportUpdateSOA(resolver, dnsDomain, rrSets)
+		}
+
+		// Create the data for all resource record sets
+		data := make([]byte, 0)
+		for _, rrSet := range rrSets {
+			rrSetData := make([]byte, 65535)
+			offset := 0
+			for _, rr := range rrSet.rrs {
+				offset, err = dns.PackRR(rr, rrSetData, offset, nil, false)
+				cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to pack resource record %v", rr))
+			}
+			data = append(data, rrSetData[0:offset]...)
+			outputIf(verbose, fmt.Sprintf("Packed %d %s record(s) for %s", len(rrSet.rrs), dns.TypeToString[rrSet.rrType], rrSet.name))
+		}
+		outputIf(verbose, fmt.Sprintf("DNS data is %x", data))
+
+		// Build the transaction
+		opts, err := generateTxOpts(domainOwner)
+		cli.ErrCheck(err, quiet, "Failed to generate transaction options")
+		signedTx, err := resolver.SetRecords(opts, data)
+		cli.ErrCheck(err, quiet, "Failed to create transaction")
+		if offline {
+			if !quiet {
+				outputOfflineTransaction(signedTx)
+			}
+			os.Exit(_exit_success)
+		}
+
+		handleSubmittedTransaction(signedTx, log.Fields{
+			"group":     "dns",
+			"command":   "import",
+			"dnsdomain": dnsDomain,
+			"dnsfile":   dnsImportFile,
+			"dnsrrsets": len(rrSets),
+		}, true)
+	},
+}
+
+// dnsImportUpdateSOA ensures that the serial of the zone's SOA record is
+// updated by the import, so that caching resolvers pick up the change.  If
+// the zone file does not contain an SOA record then the current SOA record,
+// if any, is added with an updated serial.
+func dnsImportUpdateSOA(resolver *ens.DNSResolver, domain string, rrSets []*dnsImportRRSet) []*dnsImportRRSet {
+	current := dnsCurrentSOA(resolver, domain)
+	var soa *dns.SOA
+	for _, rrSet := range rrSets {
+		if rrSet.name == domain && rrSet.rrType == dns.TypeSOA {
+			soa = rrSet.rrs[0].(*dns.SOA)
+		}
+	}
+	switch {
+	case soa == nil && current != nil:
+		current.Serial = dnsNextSOASerial(current.Serial)
+		rrSets = append(rrSets, &dnsImportRRSet{
+			name:   domain,
+			rrType: dns.TypeSOA,
+			rrs:    []dns.RR{current},
+		})
+		outputIf(verbose, fmt.Sprintf("New SOA record is %v", current))
+	case soa != nil && dnsSOASerial != 0:
+		soa.Serial = dnsSOASerial
+		outputIf(verbose, fmt.Sprintf("New SOA record is %v", soa))
+	case soa != nil && current != nil && soa.Serial <= current.Serial:
+		// The zone file's serial would not be seen as a change
+		soa.Serial = util.IncrementSerial(current.Serial)
+		outputIf(verbose, fmt.Sprintf("New SOA record is %v", soa))
+	}
+	return rrSets
+}
+
+// dnsImportParseZone parses a zone file, returning its records grouped in to
+// resource record sets in the order in which they are first seen.
+func dnsImportParseZone(path string, domain string) ([]*dnsImportRRSet, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	rrSets := make([]*dnsImportRRSet, 0)
+	rrSetMap := make(map[string]*dnsImportRRSet)
+	zp := dns.NewZoneParser(file, domain, path)
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		header := rr.Header()
+		header.Name = strings.ToLower(header.Name)
+		if !dns.IsSubDomain(domain, header.Name) {
+			return nil, fmt.Errorf("record %s is outside of domain %s", header.Name, domain)
+		}
+		key := fmt.Sprintf("%s/%d", header.Name, header.Rrtype)
+		rrSet, exists := rrSetMap[key]
+		if !exists {
+			rrSet = &dnsImportRRSet{
+				name:   header.Name,
+				rrType: header.Rrtype,
+				rrs:    make([]dns.RR, 0),
+			}
+			rrSetMap[key] = rrSet
+			rrSets = append(rrSets, rrSet)
+		}
+		rrSet.rrs = append(rrSet.rrs, rr)
+	}
+	if err := zp.Err(); err != nil {
+		return nil, err
+	}
+	return rrSets, nil
+}
+
+func init() {
+	dnsCmd.AddCommand(dnsImportCmd)
+	dnsFlags(dnsImportCmd)
+	dnsImportCmd.Flags().StringVar(&dnsImportFile, "file", "", "The zone file from which to import records")
+	dnsSOAFlags(dnsImportCmd)
+	addTransactionFlags(dnsImportCmd, "the owner of the domain")
+}
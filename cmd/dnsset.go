@@ -15,7 +15,6 @@ package cmd
 
 import (
 	"bytes"
-	"encoding/hex"
 	"fmt"
 	"os"
 	"strings"
@@ -42,7 +41,9 @@ var dnsSetCmd = &cobra.Command{
 
     ethereal dns set --domain=wealdtech.eth --ttl=3600 --resource=A --name=www --record=193.62.81.1 --passphrase=secret
 
-In This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
+Unless the SOA record itself is being set, the serial of the zone's SOA record is updated in the same transaction so that caching resolvers pick up the change.  The serial is incremented using the date-based format YYYYMMDDnn as per RFC 1912, unless it is already higher than can be represented in that format in which case it is incremented by 1.  The serial can be set explicitly with --soaserial, and updating it can be disabled with --nosoabump.
+
+This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(dnsDomain != "", quiet, "--domain is required")
 		if !strings.HasSuffix(dnsDomain, ".") {
@@ -58,7 +59,7 @@ In This will return an exit status of 0 if the transaction is successfully submi
 		outputIf(verbose, fmt.Sprintf("ENS domain hash is 0x%x", domainHash))
 
 		// Obtain the registry contract
-		registry, err := ens.NewRegistry(client)
+		registry, err := util.ENSRegistry(client)
 		cli.ErrCheck(err, quiet, "Cannot obtain ENS registry contract")
 
 		// Obtain owner for the domain
@@ -66,10 +67,10 @@ In This will return an exit status of 0 if the transaction is successfully submi
 		cli.ErrCheck(err, quiet, "Cannot obtain owner")
 
 		cli.Assert(bytes.Compare(domainOwner.Bytes(), ens.UnknownAddress.Bytes()) != 0, quiet, "Owner is not set")
-		outputIf(verbose, fmt.Sprintf("Domain owner is %s", ens.Format(client, domainOwner)))
+		outputIf(verbose, fmt.Sprintf("Domain owner is %s", util.ENSFormat(client, domainOwner)))
 
 		// Obtain DNS resolver for the domain
-		resolver, err := ens.NewDNSResolver(client, ensDomain)
+		resolver, err := util.ENSDNSResolver(client, ensDomain)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain resolver contract for %s", dnsDomain))
 
 		var signedTx *types.Transaction
@@ -106,23 +107,11 @@ In This will return an exit status of 0 if the transaction is successfully submi
 		}
 		data = data[0:offset]
 
-		if dnsResource != "SOA" && !dnsSetNoSoa {
-			// Obtain the current SOA
-			curSoaData, err := resolver.Record(dnsDomain, dns.TypeSOA)
-			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain SOA resource for %s", dnsDomain))
-			if len(curSoaData) > 0 {
-				// We have an SOA so increment the serial as per RFC 1912
-				soaRr, _, err := dns.UnpackRR(curSoaData, 0)
-				cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to unpack SOA resource for %s", dnsDomain))
-				outputIf(verbose, fmt.Sprintf("Current SOA record is %v", soaRr))
-				soaRr.(*dns.SOA).Serial = util.IncrementSerial(soaRr.(*dns.SOA).Serial)
-				soaRr.(*dns.SOA).Serial++
-				outputIf(verbose, fmt.Sprintf("New SOA record is %v", soaRr))
-				soaData := make([]byte, 16384)
-				offset, err := dns.PackRR(soaRr, soaData, 0, nil, false)
-				cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to pack resource record %v", soaRr))
-				soaData = soaData[0:offset]
-				data = append(data, soaData...)
+		if dnsResource != "SOA" && !dnsNoSOABump && !dnsSetNoSoa {
+			// Update the serial of the current SOA so that resolvers pick up the change
+			if soa := dnsCurrentSOA(resolver, dnsDomain); soa != nil {
+				soa.Serial = dnsNextSOASerial(soa.Serial)
+				data = append(data, dnsPackSOA(soa)...)
 			}
 		}
 		outputIf(verbose, fmt.Sprintf("DNS data is %x", data))
@@ -134,9 +123,7 @@ In This will return an exit status of 0 if the transaction is successfully submi
 		cli.ErrCheck(err, quiet, "Failed to create transaction")
 		if offline {
 			if !quiet {
-				buf := new(bytes.Buffer)
-				signedTx.EncodeRLP(buf)
-				fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+				outputOfflineTransaction(signedTx)
 			}
 			os.Exit(_exit_success)
 		}
@@ -158,6 +145,8 @@ func init() {
 	dnsFlags(dnsSetCmd)
 	dnsSetCmd.Flags().DurationVar(&dnsSetTTL, "ttl", time.Duration(0), "The time-to-live for the record")
 	dnsSetCmd.Flags().StringVar(&dnsSetRecord, "record", "", "The record for the resource (separate multiple items with &&)")
+	dnsSOAFlags(dnsSetCmd)
 	dnsSetCmd.Flags().BoolVar(&dnsSetNoSoa, "nosoa", false, "Do not update the zone's SOA record")
+	dnsSetCmd.Flags().MarkDeprecated("nosoa", "use --nosoabump instead")
 	addTransactionFlags(dnsSetCmd, "the owner of the domain")
 }
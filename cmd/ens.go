@@ -14,7 +14,14 @@
 package cmd
 
 import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
 )
 
 var ensDomain string
@@ -33,3 +40,62 @@ func init() {
 func ensFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&ensDomain, "domain", "", "Domain against which to operate (e.g. wealdtech.eth)")
 }
+
+// ensResolverSender works out the resolver for a domain and the account that
+// will send a transaction to it.  The sender defaults to the owner of the
+// domain; any other sender must be authorised by the owner with the resolver.
+// In offline mode neither can be looked up, so both must be supplied.
+func ensResolverSender(domain string, node [32]byte, fromStr string, resolverStr string) (common.Address, common.Address) {
+	if offline {
+		cli.Assert(fromStr != "", quiet, "--from is required in offline mode")
+		cli.Assert(resolverStr != "", quiet, "--resolver is required in offline mode")
+		cli.Assert(gasLimit != 0, quiet, "--gaslimit is required in offline mode")
+		return resolveAddressOffline(fromStr), resolveAddressOffline(resolverStr)
+	}
+
+	registry, err := util.ENSRegistry(client)
+	cli.ErrCheck(err, quiet, "Cannot obtain ENS registry contract")
+	owner, err := registry.Owner(domain)
+	cli.ErrCheck(err, quiet, "Cannot obtain owner")
+	cli.Assert(owner != ens.UnknownAddress, quiet, fmt.Sprintf("owner of %s is not set", domain))
+	resolverAddress, err := registry.ResolverAddress(domain)
+	cli.ErrCheck(err, quiet, "Cannot obtain resolver")
+	cli.Assert(resolverAddress != ens.UnknownAddress, quiet, "No resolver for that name")
+	if resolverStr != "" {
+		cli.Assert(resolveAddressOffline(resolverStr) == resolverAddress, quiet, fmt.Sprintf("%s is not the resolver for %s", resolverStr, domain))
+	}
+
+	from := owner
+	if fromStr != "" {
+		from = resolveAddressOffline(fromStr)
+		if from != owner {
+			resolverContract, err := resolver.NewContract(resolverAddress, client)
+			cli.ErrCheck(err, quiet, "Failed to obtain resolver contract")
+			authorised, err := resolverContract.Authorisations(nil, node, owner, from)
+			cli.ErrCheck(err, quiet, "Failed to obtain authorisation")
+			cli.Assert(authorised, quiet, fmt.Sprintf("%s is neither the owner of %s nor authorised by the owner", fromStr, domain))
+		}
+	}
+	return from, resolverAddress
+}
+
+// ensOwnerSender works out the account that will send a transaction to the
+// registry for a domain, which must be the owner of the domain.  In offline
+// mode the owner cannot be looked up, so must be supplied.
+func ensOwnerSender(domain string, fromStr string) common.Address {
+	if offline {
+		cli.Assert(fromStr != "", quiet, "--from is required in offline mode")
+		cli.Assert(gasLimit != 0, quiet, "--gaslimit is required in offline mode")
+		return resolveAddressOffline(fromStr)
+	}
+
+	registry, err := util.ENSRegistry(client)
+	cli.ErrCheck(err, quiet, "Cannot obtain ENS registry contract")
+	owner, err := registry.Owner(domain)
+	cli.ErrCheck(err, quiet, "Cannot obtain owner")
+	cli.Assert(owner != ens.UnknownAddress, quiet, fmt.Sprintf("owner of %s is not set", domain))
+	if fromStr != "" {
+		cli.Assert(resolveAddressOffline(fromStr) == owner, quiet, fmt.Sprintf("%s is not the owner of %s", fromStr, domain))
+	}
+	return owner
+}
@@ -20,6 +20,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	ens "github.com/wealdtech/go-ens/v3"
 )
 
@@ -38,7 +39,7 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
 		cli.Assert(ensDomain != "", quiet, "--domain is required")
 
-		registry, err := ens.NewRegistry(client)
+		registry, err := util.ENSRegistry(client)
 		cli.ErrCheck(err, quiet, "cannot obtain ENS registry contract")
 
 		// Fetch the owner of the name
@@ -47,7 +48,7 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		cli.Assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, quiet, fmt.Sprintf("owner of %s is not set", ensDomain))
 
 		// Obtain the resolver for this name
-		resolver, err := ens.NewResolver(client, ensDomain)
+		resolver, err := util.ENSResolver(client, ensDomain)
 		cli.ErrCheck(err, quiet, "No resolver for that name")
 
 		opts, err := generateTxOpts(owner)
@@ -20,7 +20,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
-	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/ethereal/util"
 )
 
 // ensAddressGetCmd represents the address get command
@@ -36,7 +36,7 @@ In quiet mode this will return 0 if the name has an address, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(ensDomain != "", quiet, "--domain is required")
 
-		resolver, err := ens.NewResolver(client, ensDomain)
+		resolver, err := util.ENSResolver(client, ensDomain)
 		cli.ErrCheck(err, quiet, "failed to obtain resolver")
 
 		bytes, err := resolver.MultiAddress(ensAddressCoinType)
@@ -22,6 +22,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	ens "github.com/wealdtech/go-ens/v3"
 )
 
@@ -42,20 +43,20 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
 		cli.Assert(ensDomain != "", quiet, "--domain is required")
 
-		registry, err := ens.NewRegistry(client)
+		registry, err := util.ENSRegistry(client)
 		cli.ErrCheck(err, quiet, "Cannot obtain ENS registry contract")
 
 		// Fetch the owner of the name
 		owner, err := registry.Owner(ensDomain)
 		cli.ErrCheck(err, quiet, "Cannot obtain owner")
 		cli.Assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, quiet, fmt.Sprintf("owner of %s is not set", ensDomain))
-		outputIf(verbose, fmt.Sprintf("Domain is owned by %s", ens.Format(client, owner)))
+		outputIf(verbose, fmt.Sprintf("Domain is owned by %s", util.ENSFormat(client, owner)))
 
 		// Obtain the address: could be an ENS name or a number
 		var data []byte
 		if strings.Contains(ensAddressSetAddressStr, ".") {
 			// Assume ENS address
-			address, err := ens.Resolve(client, ensAddressSetAddressStr)
+			address, err := util.ResolveAddress(client, ensAddressSetAddressStr)
 			cli.Assert(bytes.Compare(address.Bytes(), ens.UnknownAddress.Bytes()) != 0, quiet, "Invalid address; if you are trying to clear an existing address use \"ens address clear\"")
 			cli.ErrCheck(err, quiet, fmt.Sprintf("Invalid name/address %s", ensAddressSetAddressStr))
 			data = address.Bytes()
@@ -66,9 +67,9 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		}
 
 		// Obtain the resolver for this name
-		resolver, err := ens.NewResolver(client, ensDomain)
+		resolver, err := util.ENSResolver(client, ensDomain)
 		cli.ErrCheck(err, quiet, "No resolver for that name")
-		outputIf(verbose, fmt.Sprintf("Resolver is %s", ens.Format(client, resolver.ContractAddr)))
+		outputIf(verbose, fmt.Sprintf("Resolver is %s", util.ENSFormat(client, resolver.ContractAddr)))
 
 		opts, err := generateTxOpts(owner)
 		cli.ErrCheck(err, quiet, "Failed to generate transaction options")
@@ -0,0 +1,107 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	"github.com/wealdtech/ethereal/util/contracts"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+var ensAvatarIPFSGateway string
+
+// ensAvatarCmd represents the ens avatar command
+var ensAvatarCmd = &cobra.Command{
+	Use:   "avatar",
+	Short: "Obtain the avatar image of an ENS domain",
+	Long: `Obtain the URL of the avatar image of a domain registered with the Ethereum Name Service (ENS).  For example:
+
+    ethereal ens avatar --domain=enstest.eth
+
+The avatar is taken from the domain's "avatar" text record, which can be an https URL, an ipfs:// URI or a reference to an ERC-721 NFT in the form eip155:1/erc721:0x.../1234.  ipfs:// URIs are returned through a gateway, which can be changed with --ipfsgateway.
+
+For an NFT reference the NFT must be on the connected chain, and must be owned by the address to which the domain resolves, otherwise the avatar is rejected.  The image is then taken from the NFT's metadata.
+
+In quiet mode this will return 0 if the domain has a valid avatar, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
+		cli.Assert(ensDomain != "", quiet, "--domain is required")
+		if ensAvatarIPFSGateway != "" {
+			viper.Set("ipfsgateway", ensAvatarIPFSGateway)
+		}
+
+		resolver, err := util.ENSResolver(client, ensDomain)
+		cli.ErrCheck(err, quiet, "No resolver for that name")
+		record, err := resolver.Text("avatar")
+		cli.ErrCheck(err, quiet, "Failed to obtain avatar record")
+		cli.Assert(record != "", quiet, fmt.Sprintf("%s has no avatar", ensDomain))
+		outputIf(verbose, fmt.Sprintf("Avatar record is %s", record))
+
+		image := record
+		if strings.HasPrefix(strings.ToLower(record), "eip155:") {
+			image = ensAvatarNFTImage(resolver, record)
+		}
+
+		imageURL := image
+		if !strings.HasPrefix(image, "data:") {
+			imageURL, err = util.TokenURIToURL(image)
+			cli.ErrCheck(err, quiet, "Unsupported avatar")
+		}
+		outputIf(!quiet, imageURL)
+		os.Exit(_exit_success)
+	},
+}
+
+// ensAvatarNFTImage checks that an NFT referenced by an avatar record is
+// owned by the address of the domain, and returns the image of the NFT.
+func ensAvatarNFTImage(resolver *ens.Resolver, record string) string {
+	nft, err := util.ParseAvatarNFT(record)
+	cli.ErrCheck(err, quiet, "Invalid avatar NFT reference")
+	cli.Assert(nft.ChainID.Cmp(chainID) == 0, quiet, fmt.Sprintf("Avatar NFT is on chain %v but connection is to chain %v", nft.ChainID, chainID))
+
+	address, err := resolver.Address()
+	cli.ErrCheck(err, quiet, "Failed to obtain address of domain")
+	cli.Assert(address != ens.UnknownAddress, quiet, fmt.Sprintf("%s has no address so cannot own the avatar NFT", ensDomain))
+
+	token, err := contracts.NewERC721(nft.Contract, client)
+	cli.ErrCheck(err, quiet, "Failed to obtain token contract")
+	owner, err := token.OwnerOf(nil, nft.TokenID)
+	cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain owner of avatar NFT %s", nft.TokenID))
+	cli.Assert(owner == address, quiet, fmt.Sprintf("Avatar NFT is owned by %s, not by %s", util.ENSFormat(client, owner), address.Hex()))
+	outputIf(verbose, fmt.Sprintf("Avatar NFT %s of %s is owned by %s", nft.TokenID, nft.Contract.Hex(), address.Hex()))
+
+	uri, err := token.TokenURI(nil, nft.TokenID)
+	cli.ErrCheck(err, quiet, "Failed to obtain URI of avatar NFT")
+	cli.Assert(uri != "", quiet, "Avatar NFT has no URI")
+	uri = util.SubstituteTokenID(uri, nft.TokenID)
+	outputIf(verbose, fmt.Sprintf("Avatar NFT URI is %s", uri))
+	metadata, err := util.FetchTokenURI(uri)
+	cli.ErrCheck(err, quiet, "Failed to obtain avatar NFT metadata")
+	image, err := util.MetadataImage(metadata)
+	cli.ErrCheck(err, quiet, "Failed to obtain avatar NFT image")
+	return image
+}
+
+func init() {
+	ensCmd.AddCommand(ensAvatarCmd)
+	ensFlags(ensAvatarCmd)
+	ensAvatarCmd.Flags().StringVar(&ensAvatarIPFSGateway, "ipfsgateway", "", "Gateway through which to return ipfs:// URIs (defaults to https://ipfs.io/ipfs/)")
+}
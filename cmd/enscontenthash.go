@@ -14,9 +14,33 @@
 package cmd
 
 import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
 	"github.com/spf13/cobra"
+	ens "github.com/wealdtech/go-ens/v3"
 )
 
+// ensContenthashSchemes maps EIP-1577 text namespaces to URL schemes.
+var ensContenthashSchemes = map[string]string{
+	"ipfs":   "ipfs",
+	"ipns":   "ipns",
+	"swarm":  "bzz",
+	"onion":  "onion",
+	"onion3": "onion3",
+}
+
+// ensContenthashCodecs are the multicodec values of the namespaces in ensContenthashSchemes.
+var ensContenthashCodecs = map[uint64]bool{
+	0xe3:  true, // ipfs-ns
+	0xe4:  true, // swarm-ns
+	0xe5:  true, // ipns-ns
+	0x1bc: true, // onion
+	0x1bd: true, // onion3
+}
+
 // ensContenthashCmd represents the ens contenthash command
 var ensContenthashCmd = &cobra.Command{
 	Use:   "contenthash",
@@ -31,3 +55,45 @@ func init() {
 func ensContenthashFlags(cmd *cobra.Command) {
 	ensFlags(cmd)
 }
+
+// ensContenthashToURL turns EIP-1577 binary format in to a URL, for example
+// ipfs://QmdTEBPdNxJFFsH1wRE3YeWHREWDiSex8xhgTnqknyxWgu.  Content hashes with
+// codecs that are not understood are described by their codec number rather
+// than failing.
+func ensContenthashToURL(data []byte) (string, error) {
+	text, err := ens.ContenthashToString(data)
+	if err != nil {
+		codec, offset := binary.Uvarint(data)
+		if offset <= 0 {
+			return "", errors.New("invalid content hash")
+		}
+		if ensContenthashCodecs[codec] {
+			// Known codec with bad data
+			return "", err
+		}
+		return fmt.Sprintf("unknown codec 0x%x with data 0x%x", codec, data[offset:]), nil
+	}
+	bits := strings.SplitN(text, "/", 3)
+	if len(bits) != 3 || ensContenthashSchemes[bits[1]] == "" {
+		return "", fmt.Errorf("unexpected content hash %s", text)
+	}
+	return fmt.Sprintf("%s://%s", ensContenthashSchemes[bits[1]], bits[2]), nil
+}
+
+// ensContenthashFromURL turns a URL, or EIP-1577 text format, in to EIP-1577
+// binary format.
+func ensContenthashFromURL(input string) ([]byte, error) {
+	text := input
+	if parts := strings.SplitN(input, "://", 2); len(parts) == 2 {
+		text = ""
+		for namespace, scheme := range ensContenthashSchemes {
+			if scheme == parts[0] {
+				text = fmt.Sprintf("/%s/%s", namespace, strings.TrimSuffix(parts[1], "/"))
+			}
+		}
+		if text == "" {
+			return nil, fmt.Errorf("unsupported scheme %s", parts[0])
+		}
+	}
+	return ens.StringToContenthash(text)
+}
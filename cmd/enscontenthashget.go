@@ -14,15 +14,20 @@
 package cmd
 
 import (
+	"bytes"
 	"fmt"
+	"math/big"
 	"os"
 
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	ens "github.com/wealdtech/go-ens/v3"
 )
 
-var ensContenthashGetRaw bool
+var ensContenthashGetWire bool
 
 // ensContenthashGetCmd represents the content hash get command
 var ensContenthashGetCmd = &cobra.Command{
@@ -32,39 +37,106 @@ var ensContenthashGetCmd = &cobra.Command{
 
     ethereal ens contenthash get --domain=enstest.eth
 
+The content hash is printed as a URL, for example ipfs://QmdTEBPdNxJFFsH1wRE3YeWHREWDiSex8xhgTnqknyxWgu or bzz://d1de9994b4d039f6548d191eb26786769f580809256b4685ef316805265ea162.  Content hashes using codecs that are not understood are reported with their codec number.  If --wire is supplied then the raw content hash is printed as a hex string.
+
+If the domain does not have a content hash but has a value in the legacy content or multihash fields used by older resolvers then that value will be returned instead, with a note that it is in legacy format.
+
 In quiet mode this will return 0 if the name has a valid content hash, otherwise 1.`,
 
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(ensDomain != "", quiet, "--domain is required")
 
 		// Obtain resolver for the domain
-		resolver, err := ens.NewResolver(client, ensDomain)
+		resolver, err := util.ENSResolver(client, ensDomain)
 		cli.ErrCheck(err, quiet, "No resolver for that name")
 
-		bytes, err := resolver.Contenthash()
+		data, err := resolver.Contenthash()
+		legacy := ""
+		if err != nil || len(data) == 0 {
+			// Try the legacy fields
+			var legacyData []byte
+			legacyData, legacy = ensContenthashGetLegacy(resolver.ContractAddr)
+			if legacyData != nil {
+				data = legacyData
+				err = nil
+			}
+		}
 		cli.ErrCheck(err, quiet, "Failed to obtain content hash for that domain")
-		cli.Assert(len(bytes) > 0, quiet, "No content hash for that domain")
+		cli.Assert(len(data) > 0, quiet, "No content hash for that domain")
 
-		if ensContenthashGetRaw {
+		if ensContenthashGetWire {
 			if !quiet {
-				fmt.Printf("%x\n", bytes)
+				fmt.Printf("%x\n", data)
 			}
 			os.Exit(_exit_success)
 		}
-		outputIf(debug, fmt.Sprintf("data is %x", bytes))
+		outputIf(debug, fmt.Sprintf("data is %x", data))
 
-		res, err := ens.ContenthashToString(bytes)
+		res, err := ensContenthashToURL(data)
 		cli.ErrCheck(err, quiet, "Invalid content hash data")
 
 		if !quiet {
-			fmt.Printf("%s\n", res)
+			if legacy != "" {
+				fmt.Printf("%s (from legacy %s field)\n", res, legacy)
+			} else {
+				fmt.Printf("%s\n", res)
+			}
 		}
 		os.Exit(_exit_success)
 	},
 }
 
+var (
+	// ensLegacyContentSelector is the selector for the legacy content(bytes32) function.
+	ensLegacyContentSelector = []byte{0x2d, 0xff, 0x69, 0x41}
+	// ensLegacyMultihashSelector is the selector for the legacy multihash(bytes32) function.
+	ensLegacyMultihashSelector = []byte{0xe8, 0x94, 0x01, 0xa1}
+)
+
+// ensContenthashGetLegacy obtains the content hash from the legacy fields of
+// older resolvers, converting it to EIP-1577 format.  It returns the content
+// hash and the name of the field from which it was obtained, or nil if there
+// is no legacy value.
+func ensContenthashGetLegacy(resolverAddress common.Address) ([]byte, string) {
+	nameHash, err := ens.NameHash(ensDomain)
+	if err != nil {
+		return nil, ""
+	}
+
+	// The multihash field holds a multihash, which is assumed to be an IPFS
+	// hash
+	res, err := ensContenthashCallLegacy(resolverAddress, ensLegacyMultihashSelector, nameHash)
+	if err == nil && len(res) >= 64 {
+		length := new(big.Int).SetBytes(res[32:64]).Uint64()
+		if length > 0 && uint64(len(res)) >= 64+length {
+			return append([]byte{0xe3, 0x01, 0x01, 0x70}, res[64:64+length]...), "multihash"
+		}
+	}
+
+	// The content field holds a 32-byte hash, which is assumed to be a Swarm
+	// hash
+	res, err = ensContenthashCallLegacy(resolverAddress, ensLegacyContentSelector, nameHash)
+	if err == nil && len(res) == 32 && !bytes.Equal(res, make([]byte, 32)) {
+		return append([]byte{0xe4, 0x01, 0x01, 0xfa, 0x01, 0x1b, 0x20}, res...), "content"
+	}
+
+	return nil, ""
+}
+
+// ensContenthashCallLegacy calls a legacy resolver function.
+func ensContenthashCallLegacy(resolverAddress common.Address, selector []byte, nameHash [32]byte) ([]byte, error) {
+	ctx, cancel := localContext()
+	defer cancel()
+	return client.CallContract(ctx, ethereum.CallMsg{
+		To:   &resolverAddress,
+		Data: append(append([]byte{}, selector...), nameHash[:]...),
+	}, nil)
+}
+
 func init() {
 	ensContenthashFlags(ensContenthashGetCmd)
-	ensContenthashGetCmd.Flags().BoolVar(&ensContenthashGetRaw, "raw", false, "output raw content hash bytes")
+	ensContenthashGetCmd.Flags().BoolVar(&ensContenthashGetWire, "wire", false, "output content hash in wire format")
+	ensContenthashGetCmd.Flags().BoolVar(&ensContenthashGetWire, "raw", false, "output content hash in wire format")
+	ensContenthashGetCmd.Flags().MarkDeprecated("raw", "use --wire instead")
 	ensContenthashCmd.AddCommand(ensContenthashGetCmd)
 }
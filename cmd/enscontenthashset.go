@@ -16,14 +16,21 @@ package cmd
 import (
 	"bytes"
 	"fmt"
+	"math/big"
+	"os"
+	"strings"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
 	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
 )
 
 var ensContenthashSetContentStr string
+var ensContenthashSetFromStr string
+var ensContenthashSetResolverStr string
 
 // ensContenthashSetCmd represents the ens content hash set command
 var ensContenthashSetCmd = &cobra.Command{
@@ -31,43 +38,60 @@ var ensContenthashSetCmd = &cobra.Command{
 	Short: "Set the content hash of an ENS domain",
 	Long: `Set the content hash of a name registered with the Ethereum Name Service (ENS).  For example:
 
-    ethereal ens contenthash set --domain=enstest.eth --content=/swarm/d1de9994b4d039f6548d191eb26786769f580809256b4685ef316805265ea162 --passphrase="my secret passphrase"
+    ethereal ens contenthash set --domain=enstest.eth --content=ipfs://QmdTEBPdNxJFFsH1wRE3YeWHREWDiSex8xhgTnqknyxWgu --passphrase="my secret passphrase"
 
-The keystore for the account that owns the name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+The content can be supplied as a URL with a scheme of ipfs, ipns, bzz, onion or onion3, or in EIP-1577 text format (e.g. /swarm/d1de9994b4d039f6548d191eb26786769f580809256b4685ef316805265ea162).  The content is checked to be well-formed before the transaction is sent.
+
+By default the transaction is sent from the owner of the name.  An account that the owner has authorised with the resolver can be supplied with --from instead.  The keystore for the account must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.  In offline mode --from, --resolver and --gaslimit must all be supplied.
 
 This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
 		cli.Assert(ensDomain != "", quiet, "--domain is required")
-
-		registry, err := ens.NewRegistry(client)
-		cli.ErrCheck(err, quiet, "Cannot obtain ENS registry contract")
-
-		// Fetch the owner of the name
-		owner, err := registry.Owner(ensDomain)
-		cli.ErrCheck(err, quiet, "Cannot obtain owner")
-		cli.Assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, quiet, fmt.Sprintf("owner of %s is not set", ensDomain))
-
 		cli.Assert(ensContenthashSetContentStr != "", quiet, "--content is required")
-		data, err := ens.StringToContenthash(ensContenthashSetContentStr)
-		cli.ErrCheck(err, quiet, "Unknown content")
-		outputIf(verbose, fmt.Sprintf("Content hash is 0x%x", data))
-
-		// Obtain the resolver for this name
-		resolver, err := ens.NewResolver(client, ensDomain)
-		cli.ErrCheck(err, quiet, "No resolver for that name")
 
-		opts, err := generateTxOpts(owner)
-		cli.ErrCheck(err, quiet, "failed to generate transaction options")
+		domain, err := ens.NormaliseDomain(ensDomain)
+		cli.ErrCheck(err, quiet, "Failed to normalise ENS domain")
+		node, err := ens.NameHash(domain)
+		cli.ErrCheck(err, quiet, "Failed to obtain name hash of ENS domain")
+
+		data, err := ensContenthashFromURL(ensContenthashSetContentStr)
+		cli.ErrCheck(err, quiet, "Invalid content")
+		// Ensure that the content hash can be read back as it was set
+		contentURL, err := ensContenthashToURL(data)
+		cli.ErrCheck(err, quiet, "Invalid content")
+		roundTrip, err := ensContenthashFromURL(contentURL)
+		cli.Assert(err == nil && bytes.Equal(roundTrip, data), quiet, "Invalid content; content hash cannot be read back")
+		outputIf(verbose, fmt.Sprintf("Content is %s", contentURL))
+		outputIf(verbose, fmt.Sprintf("Content hash is 0x%x", data))
 
-		signedTx, err := resolver.SetContenthash(opts, data)
-		cli.ErrCheck(err, quiet, "failed to send transaction")
+		// Work out the resolver and the account that will send the transaction
+		from, resolverAddress := ensResolverSender(domain, node, ensContenthashSetFromStr, ensContenthashSetResolverStr)
+		outputIf(verbose, fmt.Sprintf("Sending from %s", from.Hex()))
+
+		resolverAbi, err := abi.JSON(strings.NewReader(resolver.ContractABI))
+		cli.ErrCheck(err, quiet, "Failed to parse resolver ABI")
+		txData, err := resolverAbi.Pack("setContenthash", node, data)
+		cli.ErrCheck(err, quiet, "Failed to create setContenthash data")
+
+		signedTx, err := createSignedTransaction(from, &resolverAddress, big.NewInt(0), gasLimit, txData)
+		cli.ErrCheck(err, quiet, "Failed to create transaction")
+		if offline {
+			if !quiet {
+				outputOfflineTransaction(signedTx)
+			}
+			os.Exit(_exit_success)
+		}
+
+		ctx, cancel := localContext()
+		defer cancel()
+		err = client.SendTransaction(ctx, signedTx)
+		cli.ErrCheck(err, quiet, "Failed to send transaction")
 
 		handleSubmittedTransaction(signedTx, log.Fields{
 			"group":       "ens/contenthash",
 			"command":     "set",
-			"ensdomain":   ensDomain,
-			"contenthash": ensContenthashSetContentStr,
+			"ensdomain":   domain,
+			"contenthash": contentURL,
 		}, true)
 	},
 }
@@ -75,6 +99,8 @@ This will return an exit status of 0 if the transaction is successfully submitte
 func init() {
 	ensContenthashCmd.AddCommand(ensContenthashSetCmd)
 	ensContenthashFlags(ensContenthashSetCmd)
-	ensContenthashSetCmd.Flags().StringVar(&ensContenthashSetContentStr, "content", "", "The address to set e.g. /ipfs/QmdTEBPdNxJFFsH1wRE3YeWHREWDiSex8xhgTnqknyxWgu")
+	ensContenthashSetCmd.Flags().StringVar(&ensContenthashSetContentStr, "content", "", "The content to set e.g. ipfs://QmdTEBPdNxJFFsH1wRE3YeWHREWDiSex8xhgTnqknyxWgu")
+	ensContenthashSetCmd.Flags().StringVar(&ensContenthashSetFromStr, "from", "", "The account sending the transaction, if not the owner of the name (required in offline mode)")
+	ensContenthashSetCmd.Flags().StringVar(&ensContenthashSetResolverStr, "resolver", "", "The resolver of the name (required in offline mode)")
 	addTransactionFlags(ensContenthashSetCmd, "passphrase for the account that owns the domain")
 }
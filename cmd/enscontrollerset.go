@@ -20,6 +20,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	ens "github.com/wealdtech/go-ens/v3"
 )
 
@@ -40,7 +41,7 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
 		cli.Assert(ensDomain != "", quiet, "--domain is required")
 
-		registry, err := ens.NewRegistry(client)
+		registry, err := util.ENSRegistry(client)
 		cli.ErrCheck(err, quiet, "Cannot obtain ENS registry contract")
 
 		// Fetch the controller of the name
@@ -49,7 +50,7 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		cli.Assert(bytes.Compare(controller.Bytes(), ens.UnknownAddress.Bytes()) != 0, quiet, fmt.Sprintf("%s has no controller", ensDomain))
 
 		cli.Assert(ensControllerSetControllerStr != "", quiet, "--controller is required")
-		newControllerAddress, err := ens.Resolve(client, ensControllerSetControllerStr)
+		newControllerAddress, err := util.ResolveAddress(client, ensControllerSetControllerStr)
 		cli.Assert(bytes.Compare(newControllerAddress.Bytes(), ens.UnknownAddress.Bytes()) != 0, quiet, "Attempt to set controller to 0x00 disallowed")
 		cli.ErrCheck(err, quiet, "Failed to obtain new controller address")
 
@@ -17,7 +17,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
-	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/ethereal/util"
 )
 
 var ensDomainClearAddress string
@@ -37,11 +37,11 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
 
 		cli.Assert(ensDomainClearAddress != "", quiet, "--address is required")
-		address, err := ens.Resolve(client, ensDomainClearAddress)
+		address, err := util.ResolveAddress(client, ensDomainClearAddress)
 		cli.ErrCheck(err, quiet, "Failed to obtain address to clear domain")
 
 		// Obtain the reverse registrar
-		registrar, err := ens.NewReverseRegistrar(client)
+		registrar, err := util.ENSReverseRegistrar(client)
 		cli.ErrCheck(err, quiet, "Failed to obtain reverse registrar")
 
 		opts, err := generateTxOpts(address)
@@ -17,6 +17,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	ens "github.com/wealdtech/go-ens/v3"
 )
 
@@ -38,13 +39,13 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
 
 		cli.Assert(ensDomainSetAddress != "", quiet, "--address is required")
-		address, err := ens.Resolve(client, ensDomainSetAddress)
+		address, err := util.ResolveAddress(client, ensDomainSetAddress)
 		cli.ErrCheck(err, quiet, "Failed to obtain address to set domain; to clear the domain use \"ens domain clear\"")
 
 		cli.Assert(ensDomainSetDomain != "", quiet, "--domain is required")
 
 		// Obtain the reverse registrar
-		registrar, err := ens.NewReverseRegistrar(client)
+		registrar, err := util.ENSReverseRegistrar(client)
 		cli.ErrCheck(err, quiet, "Failed to obtain reverse registrar")
 
 		opts, err := generateTxOpts(address)
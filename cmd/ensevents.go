@@ -0,0 +1,211 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	"github.com/wealdtech/ethereal/util/output"
+	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/go-ens/v3/contracts/registry"
+)
+
+var ensEventsFromBlock int64
+var ensEventsToBlock int64
+var ensEventsSince time.Duration
+var ensEventsChunkSize uint64
+var ensEventsJSON bool
+
+// ensEventsCmd represents the ens events command
+var ensEventsCmd = &cobra.Command{
+	Use:   "events",
+	Short: "Obtain the registry events for an ENS domain",
+	Long: `Obtain the events emitted by the Ethereum Name Service (ENS) registry for a domain over a range of blocks.  For example:
+
+    ethereal ens events --domain=enstest.eth --fromblock=9380380 --toblock=9400000
+
+Alternatively the range can start from a period of time ago, for example --since=24h.  The starting block is found from the timestamps of blocks.
+
+If --toblock is not supplied then events up to the latest block are obtained.  The range is queried in chunks of --chunksize blocks, to stay within the limits that nodes place on the number of logs returned by a single query.
+
+Events shown are changes of owner (NewOwner and Transfer), resolver (NewResolver) and TTL (NewTTL).
+
+The --output flag selects the format of the output: text (the default), json or csv.  --json is equivalent to --output=json.  JSON output holds the events as an array in its results field.
+
+In quiet mode this will return 0 if any events are found, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if ensEventsJSON {
+			outputFormat = output.JSON
+		}
+		formatter, err := output.New(outputFormat)
+		cli.ErrCheck(err, quiet, "Invalid output format")
+		formatter.SetNameResolver(func(address common.Address) string { return util.ENSFormat(client, address) })
+		formatter.SetArray(true)
+
+		cli.Assert(ensDomain != "", quiet, "--domain is required")
+		cli.Assert(ensEventsFromBlock >= 0 || ensEventsSince > 0, quiet, "--fromblock or --since is required")
+		cli.Assert(ensEventsFromBlock < 0 || ensEventsSince == 0, quiet, "only one of --fromblock and --since can be supplied")
+		cli.Assert(ensEventsChunkSize > 0, quiet, "--chunksize must be greater than 0")
+
+		domain, err := ens.NormaliseDomain(ensDomain)
+		cli.ErrCheck(err, quiet, "Failed to normalise ENS domain")
+		nodeHash, err := ens.NameHash(domain)
+		cli.ErrCheck(err, quiet, "Failed to obtain name hash of ENS domain")
+		node := common.Hash(nodeHash)
+		parentNodeHash, err := ens.NameHash(ens.Domain(domain))
+		cli.ErrCheck(err, quiet, "Failed to obtain name hash of parent domain")
+		parentNode := common.Hash(parentNodeHash)
+		label, err := ens.DomainPart(domain, 1)
+		cli.ErrCheck(err, quiet, "Failed to obtain label of ENS domain")
+		labelHash, err := ens.LabelHash(label)
+		cli.ErrCheck(err, quiet, "Failed to obtain label hash of ENS domain")
+
+		toBlock := uint64(ensEventsToBlock)
+		if ensEventsToBlock < 0 {
+			toBlock = latestHeader().Number.Uint64()
+		}
+		fromBlock := uint64(ensEventsFromBlock)
+		if ensEventsSince > 0 {
+			fromBlock = sinceBlock(ensEventsSince)
+		}
+		cli.Assert(fromBlock <= toBlock, quiet, "--fromblock must not be after --toblock")
+
+		registryAddress, err := util.ENSRegistryAddress(client)
+		cli.ErrCheck(err, quiet, "Failed to obtain ENS registry address")
+		filterer, err := registry.NewContractFilterer(registryAddress, client)
+		cli.ErrCheck(err, quiet, "Failed to obtain ENS registry contract")
+		outputIf(verbose, fmt.Sprintf("Obtaining events from registry %s", util.ENSFormat(client, registryAddress)))
+
+		records := make([]output.Record, 0)
+		for _, blocks := range util.BlockRanges(fromBlock, toBlock, ensEventsChunkSize) {
+			start, end := blocks.From, blocks.To
+			outputIf(verbose, fmt.Sprintf("Obtaining events for blocks %d to %d", start, end))
+			ctx, cancel := localContext()
+			logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+				FromBlock: new(big.Int).SetUint64(start),
+				ToBlock:   new(big.Int).SetUint64(end),
+				Addresses: []common.Address{registryAddress},
+				Topics: [][]common.Hash{
+					{ensWatchNewOwnerTopic, ensWatchTransferTopic, ensWatchNewResolverTopic, ensWatchNewTTLTopic},
+					{node, parentNode},
+				},
+			})
+			cancel()
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain events for blocks %d to %d; try a lower --chunksize", start, end))
+			for _, log := range logs {
+				record, err := ensEventsDecode(filterer, log, node, parentNode, common.Hash(labelHash))
+				cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to decode event in transaction %s", log.TxHash.Hex()))
+				if record != nil {
+					records = append(records, record)
+				}
+			}
+		}
+
+		if quiet {
+			if len(records) == 0 {
+				os.Exit(_exit_failure)
+			}
+			os.Exit(_exit_success)
+		}
+
+		cli.ErrCheck(outputRecords(cmd, formatter, records...), quiet, "Failed to output events")
+	},
+}
+
+// ensEventsDecode decodes a registry log with the registry ABI, returning
+// the output record for the event.  It returns nil if the log does not refer
+// to the domain, for example a NewOwner event for a sibling domain.
+func ensEventsDecode(filterer *registry.ContractFilterer, log types.Log, node common.Hash, parentNode common.Hash, labelHash common.Hash) (output.Record, error) {
+	if log.Removed || len(log.Topics) < 2 {
+		return nil, nil
+	}
+	record := output.Record{
+		{Name: "block", Value: log.BlockNumber, Text: fmt.Sprintf("block %d", log.BlockNumber)},
+		{Name: "transaction", Value: log.TxHash},
+	}
+	switch log.Topics[0] {
+	case ensWatchNewOwnerTopic:
+		newOwner, err := filterer.ParseNewOwner(log)
+		if err != nil {
+			return nil, err
+		}
+		if common.Hash(newOwner.Node) != parentNode || common.Hash(newOwner.Label) != labelHash {
+			return nil, nil
+		}
+		record = append(record,
+			output.Field{Name: "event", Value: "NewOwner", Text: "owner set to"},
+			output.Field{Name: "owner", Value: newOwner.Owner},
+		)
+	case ensWatchTransferTopic:
+		transfer, err := filterer.ParseTransfer(log)
+		if err != nil {
+			return nil, err
+		}
+		if common.Hash(transfer.Node) != node {
+			return nil, nil
+		}
+		record = append(record,
+			output.Field{Name: "event", Value: "Transfer", Text: "owner transferred to"},
+			output.Field{Name: "owner", Value: transfer.Owner},
+		)
+	case ensWatchNewResolverTopic:
+		newResolver, err := filterer.ParseNewResolver(log)
+		if err != nil {
+			return nil, err
+		}
+		if common.Hash(newResolver.Node) != node {
+			return nil, nil
+		}
+		record = append(record,
+			output.Field{Name: "event", Value: "NewResolver", Text: "resolver set to"},
+			output.Field{Name: "resolver", Value: newResolver.Resolver},
+		)
+	case ensWatchNewTTLTopic:
+		newTTL, err := filterer.ParseNewTTL(log)
+		if err != nil {
+			return nil, err
+		}
+		if common.Hash(newTTL.Node) != node {
+			return nil, nil
+		}
+		record = append(record,
+			output.Field{Name: "event", Value: "NewTTL", Text: "TTL set to"},
+			output.Field{Name: "ttl", Value: newTTL.Ttl},
+		)
+	default:
+		return nil, nil
+	}
+	return record, nil
+}
+
+func init() {
+	ensCmd.AddCommand(ensEventsCmd)
+	ensFlags(ensEventsCmd)
+	ensEventsCmd.Flags().Int64Var(&ensEventsFromBlock, "fromblock", -1, "Block from which to obtain events")
+	ensEventsCmd.Flags().DurationVar(&ensEventsSince, "since", 0, "Time before now from which to obtain events, for example 24h (instead of --fromblock)")
+	ensEventsCmd.Flags().Int64Var(&ensEventsToBlock, "toblock", -1, "Block up to which to obtain events (defaults to latest)")
+	ensEventsCmd.Flags().Uint64Var(&ensEventsChunkSize, "chunksize", 10000, "Maximum number of blocks to query for events at a time")
+	ensEventsCmd.Flags().BoolVar(&ensEventsJSON, "json", false, "Display output as JSON (equivalent to --output=json)")
+	outputFlags(ensEventsCmd)
+	jsonoutCmds["ens:events"] = true
+}
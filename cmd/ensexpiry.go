@@ -20,6 +20,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	ens "github.com/wealdtech/go-ens/v3"
 )
 
@@ -41,7 +42,7 @@ In quiet mode this will return 0 if the domain has an expiry date in the future,
 		ensDomain, err := ens.NormaliseDomain(ensDomain)
 		cli.ErrCheck(err, quiet, "Failed to normalise ENS domain")
 
-		registrar, err := ens.NewBaseRegistrar(client, ens.Tld(ensDomain))
+		registrar, err := util.ENSRegistrarFor(client, ens.Tld(ensDomain))
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain ENS registrar contract for %s", ens.Tld(ensDomain)))
 
 		expiryTS, err := registrar.Expiry(ensDomain)
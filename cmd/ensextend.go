@@ -24,8 +24,8 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	ens "github.com/wealdtech/go-ens/v3"
-	string2eth "github.com/wealdtech/go-string2eth"
 )
 
 var ensExtendDomains string
@@ -61,7 +61,7 @@ This will return an exit status of 0 if the transactions are successfully submit
 
 		cli.Assert(viper.GetString("value") != "", quiet, "--value is required")
 
-		value, err := string2eth.StringToWei(viper.GetString("value"))
+		value, err := parseAmount(viper.GetString("value"))
 		cli.ErrCheck(err, quiet, "Could not understand value")
 		// Extend loop
 		var lastTx *types.Transaction
@@ -70,16 +70,16 @@ This will return an exit status of 0 if the transactions are successfully submit
 			cli.ErrCheck(err, quiet, "Failed to normalise ENS domain")
 
 			// Ensure the domain is owned
-			registry, err := ens.NewRegistry(client)
+			registry, err := util.ENSRegistry(client)
 			cli.ErrCheck(err, quiet, "Failed to obtain ENS registry")
 			owner, err := registry.Owner(domain)
 			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain owner for %s", domain))
 			cli.Assert(owner != ens.UnknownAddress, quiet, fmt.Sprintf("%s is not registered", domain))
 
-			controller, err := ens.NewETHController(client, ens.Domain(domain))
+			controller, err := util.ENSETHController(client, ens.Domain(domain))
 			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain %s controller", ens.Domain(domain)))
 
-			registrar, err := ens.NewBaseRegistrar(client, ens.Domain(domain))
+			registrar, err := util.ENSRegistrarFor(client, ens.Domain(domain))
 			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain %s registrar", ens.Domain(domain)))
 
 			// Obtain current expiry
@@ -95,7 +95,9 @@ This will return an exit status of 0 if the transactions are successfully submit
 
 			opts, err := generateTxOpts(owner)
 			cli.ErrCheck(err, quiet, "failed to generate transaction options")
-			lastTx, err = controller.Renew(opts, domain)
+			name, err := ens.UnqualifiedName(domain, ens.Domain(domain))
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain name of %s", domain))
+			lastTx, err = controller.Contract.Renew(opts, name, duration)
 			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to submit extend transaction for %s", domain))
 			logTransaction(lastTx, log.Fields{
 				"group":     "ens",
@@ -23,12 +23,21 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	ens "github.com/wealdtech/go-ens/v3"
 	string2eth "github.com/wealdtech/go-string2eth"
 )
 
 var zero = big.NewInt(0)
 
+// ensGracePeriod is the time after expiry of a .eth name during which it can
+// be renewed by its registrant.
+const ensGracePeriod = 90 * 24 * time.Hour
+
+// ensPremiumPeriod is the time after the grace period during which
+// registration of a .eth name attracts a premium.
+const ensPremiumPeriod = 21 * 24 * time.Hour
+
 // ensInfoCmd represents the ens info command
 var ensInfoCmd = &cobra.Command{
 	Use:   "info",
@@ -37,7 +46,9 @@ var ensInfoCmd = &cobra.Command{
 
     ens info --domain=enstest.eth
 
-In quiet mode this will return 0 if the domain is owned, otherwise 1.`,
+This shows the registrant and expiry of .eth names, and the owner, resolver and resolved address of all names.  Addresses are shown with their reverse-resolved names where available.  Expired .eth names are reported as being in their grace period or premium auction as appropriate.
+
+In quiet mode this will return 0 if the domain is registered and, for .eth names, not expired, otherwise 1.`,
 
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(ensDomain != "", quiet, "--domain is required")
@@ -60,38 +71,47 @@ In quiet mode this will return 0 if the domain is owned, otherwise 1.`,
 
 		if ens.DomainLevel(ensDomain) == 1 && ens.Tld(ensDomain) == "eth" {
 			// Work out if this is on the old or new .eth registrar and act accordingly
-			registrar, err := ens.NewBaseRegistrar(client, ens.Tld(ensDomain))
+			registrar, err := util.ENSRegistrarFor(client, ens.Tld(ensDomain))
 			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain ENS registrar contract for %s", ens.Tld(ensDomain)))
 			outputIf(debug, fmt.Sprintf("Registrar address is %#x", registrar.ContractAddr))
 
 			domain, err := ens.DomainPart(ensDomain, 1)
-			registrant, err := registrar.Owner(domain)
-			if err != nil {
-				if err.Error() == "abi: attempting to unmarshall an empty string while arguments are expected" {
+			cli.ErrCheck(err, quiet, "Failed to obtain domain")
+			expiryTS, err := registrar.Expiry(domain)
+			cli.ErrCheck(err, quiet, "Failed to obtain expiry")
+			if expiryTS.Sign() == 0 {
+				if !quiet {
 					fmt.Println("Name not recognised by registrar")
-					os.Exit(_exit_failure)
-				} else {
-					cli.ErrCheck(err, quiet, "Failed to obtain registrant")
+					unregisteredResolverCheck(ensDomain)
 				}
-			}
-			if registrant == ens.UnknownAddress {
-				fmt.Println("Name not recognised by registrar")
-				unregisteredResolverCheck(ensDomain)
 				os.Exit(_exit_failure)
 			}
+			expiry := time.Unix(int64(expiryTS.Uint64()), 0)
+			expired := !time.Now().Before(expiry)
+			if quiet {
+				if expired {
+					os.Exit(_exit_failure)
+				}
+				os.Exit(_exit_success)
+			}
 
-			outputIf(verbose, fmt.Sprintf("Registrar is %s", ens.Format(client, registrar.ContractAddr)))
-			registrantName, _ := ens.ReverseResolve(client, registrant)
-			if registrantName == "" {
-				fmt.Printf("Registrant is %s\n", registrant.Hex())
+			outputIf(verbose, fmt.Sprintf("Registrar is %s", util.ENSFormat(client, registrar.ContractAddr)))
+			// The registrar does not provide the registrant of an expired name
+			if expired {
+				fmt.Println("Registrant not available as the registration has expired")
 			} else {
-				fmt.Printf("Registrant is %s (%s)\n", registrantName, registrant.Hex())
+				registrant, err := registrar.Owner(domain)
+				cli.ErrCheck(err, quiet, "Failed to obtain registrant")
+				registrantName, _ := util.ENSReverseResolve(client, registrant)
+				if registrantName == "" {
+					fmt.Printf("Registrant is %s\n", registrant.Hex())
+				} else {
+					fmt.Printf("Registrant is %s (%s)\n", registrantName, registrant.Hex())
+				}
 			}
-			expiry, err := registrar.Expiry(domain)
-			cli.ErrCheck(err, quiet, "Failed to obtain expiry")
-			fmt.Printf("Registration expires at %v\n", time.Unix(int64(expiry.Uint64()), 0))
+			fmt.Println(ensExpiryStatus(expiry, time.Now()))
 
-			controller, err := ens.NewETHController(client, ens.Domain(ensDomain))
+			controller, err := util.ENSETHController(client, ens.Domain(ensDomain))
 			cli.ErrCheck(err, quiet, "Failed to obtain controller")
 			rentPerSec, err := controller.RentCost(ensDomain)
 			if err == nil {
@@ -112,10 +132,44 @@ In quiet mode this will return 0 if the domain is owned, otherwise 1.`,
 				fmt.Printf("Deed value is %s; release with 'ethereal ens release'\n", string2eth.WeiToString(entry.Value, true))
 			}
 			genericInfo(ensDomain)
+			return
+		}
+
+		if quiet {
+			registry, err := util.ENSRegistry(client)
+			cli.ErrCheck(err, quiet, "Failed to obtain registry contract")
+			owner, err := registry.Owner(ensDomain)
+			cli.ErrCheck(err, quiet, "Failed to obtain owner")
+			if owner == ens.UnknownAddress {
+				os.Exit(_exit_failure)
+			}
+			os.Exit(_exit_success)
+		}
+		if !genericInfo(ensDomain) {
+			os.Exit(_exit_failure)
 		}
 	},
 }
 
+// ensExpiryStatus describes the state of a .eth registration given its expiry.
+// After expiry there is a grace period during which only the registrant can
+// renew the name, followed by a period during which anyone can register it
+// for a premium that decays to zero.
+func ensExpiryStatus(expiry time.Time, now time.Time) string {
+	graceEnd := expiry.Add(ensGracePeriod)
+	premiumEnd := graceEnd.Add(ensPremiumPeriod)
+	switch {
+	case now.Before(expiry):
+		return fmt.Sprintf("Registration expires at %v", expiry)
+	case now.Before(graceEnd):
+		return fmt.Sprintf("Registration expired at %v; in grace period until %v, during which only the registrant can renew", expiry, graceEnd)
+	case now.Before(premiumEnd):
+		return fmt.Sprintf("Registration expired at %v; available for registration with a decaying premium until %v", expiry, premiumEnd)
+	default:
+		return fmt.Sprintf("Registration expired at %v; available for registration", expiry)
+	}
+}
+
 func init() {
 	ensCmd.AddCommand(ensInfoCmd)
 	ensFlags(ensInfoCmd)
@@ -167,7 +221,7 @@ func wonInfo(registrar *ens.AuctionRegistrar, name string) {
 	// Registrant
 	registrant, err := deed.Owner()
 	cli.ErrCheck(err, quiet, "Failed to obtain registrant")
-	registrantName, _ := ens.ReverseResolve(client, registrant)
+	registrantName, _ := util.ENSReverseResolve(client, registrant)
 	if registrantName == "" {
 		fmt.Println("Registrant is", registrant.Hex())
 	} else {
@@ -188,7 +242,7 @@ func ownedInfo(registrar *ens.AuctionRegistrar, name string) {
 		// Registrant
 		registrant, err := deed.Owner()
 		cli.ErrCheck(err, quiet, "Failed to obtain registrant")
-		registrantName, _ := ens.ReverseResolve(client, registrant)
+		registrantName, _ := util.ENSReverseResolve(client, registrant)
 		if registrantName == "" {
 			fmt.Println("Registrant is", registrant.Hex())
 		} else {
@@ -197,7 +251,7 @@ func ownedInfo(registrar *ens.AuctionRegistrar, name string) {
 		previousRegistrant, err := deed.PreviousOwner()
 		cli.ErrCheck(err, quiet, "Failed to obtain previous registrant")
 		if bytes.Compare(previousRegistrant.Bytes(), ens.UnknownAddress.Bytes()) != 0 {
-			previousRegistrantName, _ := ens.ReverseResolve(client, previousRegistrant)
+			previousRegistrantName, _ := util.ENSReverseResolve(client, previousRegistrant)
 			if previousRegistrantName == "" {
 				fmt.Println("Previous registrant is", previousRegistrant.Hex())
 			} else {
@@ -209,7 +263,7 @@ func ownedInfo(registrar *ens.AuctionRegistrar, name string) {
 
 // It is possible for an unregistered domain to have a resolver; report if this is the case
 func unregisteredResolverCheck(domain string) {
-	registry, err := ens.NewRegistry(client)
+	registry, err := util.ENSRegistry(client)
 	cli.ErrCheck(err, quiet, "Failed to obtain registry contract")
 	resolverAddress, err := registry.ResolverAddress(domain)
 	if err != nil {
@@ -229,15 +283,15 @@ a malicious part could register the domain and change the resolution.`)
 // genericInfo prints generic info about any ENS domain.
 // It returns true if the domain exists, otherwise false
 func genericInfo(name string) bool {
-	registry, err := ens.NewRegistry(client)
+	registry, err := util.ENSRegistry(client)
 	cli.ErrCheck(err, quiet, "Failed to obtain registry contract")
-	controllerAddress, err := registry.Owner(ensDomain)
+	controllerAddress, err := registry.Owner(name)
 	cli.ErrCheck(err, quiet, "Failed to obtain controller")
 	if controllerAddress == ens.UnknownAddress {
 		fmt.Println("Owner not set")
 		return false
 	}
-	controllerName, _ := ens.ReverseResolve(client, controllerAddress)
+	controllerName, _ := util.ENSReverseResolve(client, controllerAddress)
 	if controllerName == "" {
 		fmt.Printf("Controller is %s\n", controllerAddress.Hex())
 	} else {
@@ -250,7 +304,7 @@ func genericInfo(name string) bool {
 		fmt.Println("Resolver not configured")
 		return true
 	}
-	resolverName, _ := ens.ReverseResolve(client, resolverAddress)
+	resolverName, _ := util.ENSReverseResolve(client, resolverAddress)
 	if resolverName == "" {
 		fmt.Printf("Resolver is %s\n", resolverAddress.Hex())
 	} else {
@@ -258,11 +312,11 @@ func genericInfo(name string) bool {
 	}
 
 	// Address
-	address, err := ens.Resolve(client, name)
+	address, err := util.ResolveAddress(client, name)
 	if err == nil && address != ens.UnknownAddress {
 		fmt.Printf("Domain resolves to %s\n", address.Hex())
 		// Reverse resolution
-		reverseDomain, err := ens.ReverseResolve(client, address)
+		reverseDomain, err := util.ENSReverseResolve(client, address)
 		if err == nil && reverseDomain != "" {
 			fmt.Printf("Address resolves to %s\n", reverseDomain)
 		}
@@ -20,6 +20,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	ens "github.com/wealdtech/go-ens/v3"
 )
 
@@ -53,7 +54,7 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		}
 
 		// Obtain the required registrars
-		registrar, err := ens.NewBaseRegistrar(client, ens.Tld(domains[0]))
+		registrar, err := util.ENSRegistrarFor(client, ens.Tld(domains[0]))
 		cli.ErrCheck(err, quiet, "Cannot obtain ENS base registrar contract")
 		auctionRegistrar, err := registrar.PriorAuctionContract()
 		cli.ErrCheck(err, quiet, "Cannot obtain ENS auction registrar contract")
@@ -83,7 +84,7 @@ This will return an exit status of 0 if the transaction is successfully submitte
 			owner, err := auctionRegistrar.Owner(name)
 			cli.ErrCheck(err, quiet, "Failed to obtain domain owner")
 
-			outputIf(verbose, fmt.Sprintf("Domain %s owner is %s", domain, ens.Format(client, owner)))
+			outputIf(verbose, fmt.Sprintf("Domain %s owner is %s", domain, util.ENSFormat(client, owner)))
 
 			opts, err := generateTxOpts(owner)
 			cli.ErrCheck(err, quiet, "Failed to generate transaction options")
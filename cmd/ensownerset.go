@@ -0,0 +1,132 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/go-ens/v3/contracts/registry"
+)
+
+var ensOwnerSetFromStr string
+var ensOwnerSetOwnerStr string
+
+// ensOwnerSetCmd represents the ens owner set command
+var ensOwnerSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set the owner of an ENS domain",
+	Long: `Set the owner of a domain in the Ethereum Name Service (ENS) registry, also known as the controller.  For example:
+
+    ethereal ens owner set --domain=enstest.eth --owner=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --passphrase="my secret passphrase"
+
+The keystore for the account that currently owns the domain must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.  In offline mode the current owner must be supplied with --from.
+
+This only changes the owner in the registry.  For .eth names the registrant, which holds the name itself, is unchanged and can reclaim ownership at any time; to transfer the name use 'ethereal ens transfer'.  Setting the owner to the zero address gives up control of the domain permanently, so requires --force.
+
+This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(ensDomain != "", quiet, "--domain is required")
+		cli.Assert(ensOwnerSetOwnerStr != "", quiet, "--owner is required")
+		node, err := ens.NameHash(ensDomain)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Invalid domain %s", ensDomain))
+
+		registryAddress, err := util.ENSRegistryAddress(client)
+		cli.ErrCheck(err, quiet, "Cannot obtain ENS registry address")
+
+		from := ensOwnerSender(ensDomain, ensOwnerSetFromStr)
+		outputIf(verbose, fmt.Sprintf("Current owner is %s", from.Hex()))
+
+		owner := resolveAddressOffline(ensOwnerSetOwnerStr)
+		if owner == ens.UnknownAddress {
+			cli.Assert(viper.GetBool("force"), quiet, fmt.Sprintf("Setting the owner to the zero address gives up control of %s permanently; use --force to continue regardless", ensDomain))
+			cli.Warn(quiet, fmt.Sprintf("WARNING: setting the owner of %s to the zero address; control of the domain will be lost permanently", ensDomain))
+		} else {
+			cli.Assert(owner != from, quiet, fmt.Sprintf("%s is already the owner of %s", ensOwnerSetOwnerStr, ensDomain))
+		}
+		ensOwnerSetRegistrantCheck(from)
+
+		registryAbi, err := abi.JSON(strings.NewReader(registry.ContractABI))
+		cli.ErrCheck(err, quiet, "Failed to parse ENS registry ABI")
+		data, err := registryAbi.Pack("setOwner", node, owner)
+		cli.ErrCheck(err, quiet, "Failed to create setOwner data")
+
+		signedTx, err := createSignedTransaction(from, &registryAddress, big.NewInt(0), gasLimit, data)
+		cli.ErrCheck(err, quiet, "Failed to create transaction")
+
+		if offline {
+			if !quiet {
+				outputOfflineTransaction(signedTx)
+			}
+			os.Exit(_exit_success)
+		}
+
+		ctx, cancel := localContext()
+		defer cancel()
+		err = client.SendTransaction(ctx, signedTx)
+		cli.ErrCheck(err, quiet, "Failed to send transaction")
+
+		handleSubmittedTransaction(signedTx, log.Fields{
+			"group":     "ens/owner",
+			"command":   "set",
+			"ensdomain": ensDomain,
+			"ensowner":  owner.Hex(),
+		}, true)
+	},
+}
+
+// ensOwnerSetRegistrantCheck warns if the domain is a .eth name whose owner
+// is also its registrant, as in this case the user probably wants to
+// transfer the name rather than just its ownership in the registry.
+func ensOwnerSetRegistrantCheck(owner common.Address) {
+	if ens.Tld(ensDomain) != "eth" || len(strings.Split(ensDomain, ".")) != 2 {
+		return
+	}
+	if offline {
+		cli.Warn(quiet, fmt.Sprintf("This changes the owner of %s in the registry but not its registrant; to transfer the name use 'ethereal ens transfer'", ensDomain))
+		return
+	}
+
+	registrar, err := util.ENSRegistrarFor(client, ens.Tld(ensDomain))
+	if err != nil {
+		return
+	}
+	domain, err := ens.DomainPart(ensDomain, 1)
+	if err != nil {
+		return
+	}
+	registrant, err := registrar.Owner(domain)
+	if err != nil || registrant != owner {
+		return
+	}
+	cli.Warn(quiet, fmt.Sprintf("%s is the registrant of %s and can reclaim ownership at any time, so this does not transfer the name; to do so use 'ethereal ens transfer'", util.ENSFormat(client, registrant), ensDomain))
+}
+
+func init() {
+	ensOwnerCmd.AddCommand(ensOwnerSetCmd)
+	ensOwnerFlags(ensOwnerSetCmd)
+	ensOwnerSetCmd.Flags().StringVar(&ensOwnerSetFromStr, "from", "", "The current owner of the domain (required in offline mode)")
+	ensOwnerSetCmd.Flags().StringVar(&ensOwnerSetOwnerStr, "owner", "", "The new owner's name or address")
+	addTransactionFlags(ensOwnerSetCmd, "passphrase for the account that owns the domain")
+}
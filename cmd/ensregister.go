@@ -29,7 +29,6 @@ import (
 	"github.com/wealdtech/ethereal/cli"
 	"github.com/wealdtech/ethereal/util"
 	ens "github.com/wealdtech/go-ens/v3"
-	string2eth "github.com/wealdtech/go-string2eth"
 )
 
 var ensRegisterDomains string
@@ -55,12 +54,12 @@ This will return an exit status of 0 if the transactions are successfully submit
 		cli.Assert(ensDomain != "" || ensRegisterDomains != "", quiet, "--domain or --domains is required")
 
 		cli.Assert(ensRegisterOwnerStr != "", quiet, "--owner is required")
-		owner, err := ens.Resolve(client, ensRegisterOwnerStr)
+		owner, err := util.ResolveAddress(client, ensRegisterOwnerStr)
 		cli.ErrCheck(err, quiet, "Failed to obtain new owner address")
 		cli.Assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, quiet, "Unknown owner")
 
 		cli.Assert(viper.GetString("value") != "", quiet, "--value is required")
-		value, err := string2eth.StringToWei(viper.GetString("value"))
+		value, err := parseAmount(viper.GetString("value"))
 		cli.ErrCheck(err, quiet, "Could not understand value")
 
 		var domains []string
@@ -71,7 +70,7 @@ This will return an exit status of 0 if the transactions are successfully submit
 			domains[0] = ensDomain
 		}
 
-		controller, err := ens.NewETHController(client, ens.Domain(domains[0]))
+		controller, err := util.ENSETHController(client, ens.Domain(domains[0]))
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain %s controller", ens.Domain(domains[0])))
 
 		tmp, err := controller.MinCommitmentInterval()
@@ -0,0 +1,62 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+var ensRegistryNodeStr string
+
+// ensRegistryCmd represents the ens registry command
+var ensRegistryCmd = &cobra.Command{
+	Use:   "registry",
+	Short: "Manage ENS registry entries",
+	Long:  `Set and obtain Ethereum Name Service registry entries directly`,
+}
+
+func init() {
+	ensCmd.AddCommand(ensRegistryCmd)
+}
+
+func ensRegistryFlags(cmd *cobra.Command) {
+	ensFlags(cmd)
+	cmd.Flags().StringVar(&ensRegistryNodeStr, "node", "", "Node hash against which to operate (alternative to domain)")
+}
+
+// ensRegistryNode obtains the node hash given either the --domain or --node flag.
+func ensRegistryNode() [32]byte {
+	cli.Assert(ensDomain != "" || ensRegistryNodeStr != "", quiet, "either --domain or --node is required")
+	cli.Assert(ensDomain == "" || ensRegistryNodeStr == "", quiet, "only one of --domain and --node can be supplied")
+
+	var node [32]byte
+	if ensDomain != "" {
+		var err error
+		node, err = ens.NameHash(ensDomain)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Invalid domain %s", ensDomain))
+		return node
+	}
+
+	nodeBytes, err := hex.DecodeString(strings.TrimPrefix(ensRegistryNodeStr, "0x"))
+	cli.ErrCheck(err, quiet, "Invalid node")
+	cli.Assert(len(nodeBytes) == 32, quiet, "Node must be 32 bytes")
+	copy(node[:], nodeBytes)
+	return node
+}
@@ -0,0 +1,70 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+// ensRegistryGetCmd represents the ens registry get command
+var ensRegistryGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Obtain the registry entry of an ENS node",
+	Long: `Obtain the owner, resolver and TTL held in the Ethereum Name Service (ENS) registry for a node.  For example:
+
+    ethereal ens registry get --domain=enstest.eth
+
+The node hash can be supplied directly instead of the domain, for example:
+
+    ethereal ens registry get --node=0x4e34d3a81dc3a20f71bbdf2160492ddaa17ee7e5523757d47153379c13cb46df
+
+In quiet mode this will return 0 if the node has an owner, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		node := ensRegistryNode()
+
+		registry, err := util.ENSRegistry(client)
+		cli.ErrCheck(err, quiet, "Cannot obtain ENS registry contract")
+
+		owner, err := registry.Contract.Owner(nil, node)
+		cli.ErrCheck(err, quiet, "Cannot obtain owner")
+		if quiet {
+			if bytes.Equal(owner.Bytes(), ens.UnknownAddress.Bytes()) {
+				os.Exit(_exit_failure)
+			}
+			os.Exit(_exit_success)
+		}
+
+		resolver, err := registry.Contract.Resolver(nil, node)
+		cli.ErrCheck(err, quiet, "Cannot obtain resolver")
+		ttl, err := registry.Contract.Ttl(nil, node)
+		cli.ErrCheck(err, quiet, "Cannot obtain TTL")
+
+		fmt.Printf("Node: 0x%x\n", node)
+		fmt.Printf("Owner: %s\n", util.ENSFormat(client, owner))
+		fmt.Printf("Resolver: %s\n", util.ENSFormat(client, resolver))
+		fmt.Printf("TTL: %d\n", ttl)
+	},
+}
+
+func init() {
+	ensRegistryCmd.AddCommand(ensRegistryGetCmd)
+	ensRegistryFlags(ensRegistryGetCmd)
+}
@@ -0,0 +1,149 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/go-ens/v3/contracts/registry"
+)
+
+var ensRegistrySetFromStr string
+var ensRegistrySetOwnerStr string
+var ensRegistrySetResolverStr string
+var ensRegistrySetTTL int64
+
+// ensRegistrySetCmd represents the ens registry set command
+var ensRegistrySetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Set the registry entry of an ENS node",
+	Long: `Set the owner, resolver and/or TTL held in the Ethereum Name Service (ENS) registry for a node.  For example:
+
+    ethereal ens registry set --domain=enstest.eth --owner=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --resolver=0x1da022710dF5002339274AaDEe8D58218e9D6AB5 --ttl=3600 --passphrase="my secret passphrase"
+
+The node hash can be supplied directly instead of the domain.  Each of owner, resolver and TTL is optional; a separate transaction is sent for each item supplied, with the owner changed last.
+
+The keystore for the account that owns the node must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.  In offline mode the current owner of the node must be supplied with --from.
+
+This will return an exit status of 0 if the transactions are successfully submitted (and mined if --wait is supplied), 1 if the transactions are not successfully submitted, and 2 if the transactions are successfully submitted but not mined within the supplied time limit.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		node := ensRegistryNode()
+		cli.Assert(ensRegistrySetOwnerStr != "" || ensRegistrySetResolverStr != "" || ensRegistrySetTTL != -1, quiet, "at least one of --owner, --resolver and --ttl is required")
+
+		registryAddress, err := util.ENSRegistryAddress(client)
+		cli.ErrCheck(err, quiet, "Cannot obtain ENS registry address")
+
+		// Work out the account that will send the transactions
+		var from common.Address
+		if offline {
+			cli.Assert(ensRegistrySetFromStr != "", quiet, "--from is required in offline mode")
+			cli.Assert(gasLimit != 0, quiet, "--gaslimit is required in offline mode")
+			from = resolveAddressOffline(ensRegistrySetFromStr)
+		} else {
+			ensRegistry, err := ens.NewRegistryAt(client, registryAddress)
+			cli.ErrCheck(err, quiet, "Cannot obtain ENS registry contract")
+			from, err = ensRegistry.Contract.Owner(nil, node)
+			cli.ErrCheck(err, quiet, "Cannot obtain owner")
+			cli.Assert(!bytes.Equal(from.Bytes(), ens.UnknownAddress.Bytes()), quiet, "Owner of node is not set")
+			if ensRegistrySetFromStr != "" {
+				fromAddress, err := util.ResolveAddress(client, ensRegistrySetFromStr)
+				cli.ErrCheck(err, quiet, fmt.Sprintf("Invalid name/address %s", ensRegistrySetFromStr))
+				cli.Assert(bytes.Equal(from.Bytes(), fromAddress.Bytes()), quiet, fmt.Sprintf("%s is not the owner of the node", ensRegistrySetFromStr))
+			}
+		}
+		outputIf(debug, fmt.Sprintf("Owner is %s", from.Hex()))
+
+		registryAbi, err := abi.JSON(strings.NewReader(registry.ContractABI))
+		cli.ErrCheck(err, quiet, "Failed to parse ENS registry ABI")
+
+		// Build up the data for each transaction.  Owner goes last, as once it
+		// has changed the sender is no longer authorised to make changes
+		txData := make([][]byte, 0)
+		logFields := log.Fields{
+			"group":   "ens/registry",
+			"command": "set",
+			"ensnode": fmt.Sprintf("0x%x", node),
+		}
+		if ensRegistrySetResolverStr != "" {
+			resolverAddress := resolveAddressOffline(ensRegistrySetResolverStr)
+			data, err := registryAbi.Pack("setResolver", node, resolverAddress)
+			cli.ErrCheck(err, quiet, "Failed to create setResolver data")
+			txData = append(txData, data)
+			logFields["ensresolver"] = resolverAddress.Hex()
+		}
+		if ensRegistrySetTTL != -1 {
+			cli.Assert(ensRegistrySetTTL >= 0, quiet, "--ttl cannot be negative")
+			data, err := registryAbi.Pack("setTTL", node, uint64(ensRegistrySetTTL))
+			cli.ErrCheck(err, quiet, "Failed to create setTTL data")
+			txData = append(txData, data)
+			logFields["ensttl"] = ensRegistrySetTTL
+		}
+		if ensRegistrySetOwnerStr != "" {
+			ownerAddress := resolveAddressOffline(ensRegistrySetOwnerStr)
+			data, err := registryAbi.Pack("setOwner", node, ownerAddress)
+			cli.ErrCheck(err, quiet, "Failed to create setOwner data")
+			txData = append(txData, data)
+			logFields["ensowner"] = ownerAddress.Hex()
+		}
+
+		var signedTx *types.Transaction
+		for i := range txData {
+			signedTx, err = createSignedTransaction(from, &registryAddress, big.NewInt(0), gasLimit, txData[i])
+			cli.ErrCheck(err, quiet, "Failed to create transaction")
+
+			if offline {
+				if !quiet {
+					outputOfflineTransaction(signedTx)
+				}
+				continue
+			}
+
+			ctx, cancel := localContext()
+			defer cancel()
+			err = client.SendTransaction(ctx, signedTx)
+			cli.ErrCheck(err, quiet, "Failed to send transaction")
+
+			if i < len(txData)-1 {
+				handleSubmittedTransaction(signedTx, logFields, false)
+			}
+		}
+		if offline {
+			os.Exit(_exit_success)
+		}
+
+		handleSubmittedTransaction(signedTx, logFields, true)
+	},
+}
+
+func init() {
+	ensRegistryCmd.AddCommand(ensRegistrySetCmd)
+	ensRegistryFlags(ensRegistrySetCmd)
+	ensRegistrySetCmd.Flags().StringVar(&ensRegistrySetFromStr, "from", "", "The current owner of the node (required in offline mode)")
+	ensRegistrySetCmd.Flags().StringVar(&ensRegistrySetOwnerStr, "owner", "", "The new owner's name or address")
+	ensRegistrySetCmd.Flags().StringVar(&ensRegistrySetResolverStr, "resolver", "", "The new resolver's name or address")
+	ensRegistrySetCmd.Flags().Int64Var(&ensRegistrySetTTL, "ttl", -1, "The new TTL, in seconds")
+	addTransactionFlags(ensRegistrySetCmd, "passphrase for the account that owns the node")
+}
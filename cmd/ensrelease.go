@@ -21,6 +21,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	ens "github.com/wealdtech/go-ens/v3"
 )
 
@@ -68,7 +69,7 @@ This will return an exit status of 0 if the transactions are successfully submit
 			owner, err := auctionRegistrar.Owner(domain)
 			cli.ErrCheck(err, quiet, "Failed to obtain domain owner")
 
-			outputIf(verbose, fmt.Sprintf("Domain %s owner is %s", domain, ens.Format(client, owner)))
+			outputIf(verbose, fmt.Sprintf("Domain %s owner is %s", domain, util.ENSFormat(client, owner)))
 
 			opts, err := generateTxOpts(owner)
 			cli.ErrCheck(err, quiet, "Failed to generate transaction options")
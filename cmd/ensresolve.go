@@ -0,0 +1,232 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+var ensResolveFile string
+var ensResolveWorkers int
+var ensResolveRate int
+var ensResolveJSON bool
+var ensResolveCSV bool
+var ensResolveContenthash bool
+
+// ensResolveResult is the result of resolving a single name.
+type ensResolveResult struct {
+	Name        string `json:"name"`
+	Address     string `json:"address,omitempty"`
+	Contenthash string `json:"contenthash,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// value returns the resolved value of the result.
+func (r *ensResolveResult) value() string {
+	if ensResolveContenthash {
+		return r.Contenthash
+	}
+	return r.Address
+}
+
+// ensResolveCmd represents the ens resolve command
+var ensResolveCmd = &cobra.Command{
+	Use:   "resolve",
+	Short: "Resolve ENS names to addresses",
+	Long: `Resolve one or more names registered with the Ethereum Name Service (ENS) to addresses.  For example:
+
+    ethereal ens resolve --domain=enstest.eth
+
+    ethereal ens resolve --file=names.txt
+
+where the file contains one name per line.  Names are normalised before they are resolved.  If --contenthash is supplied then names are resolved to their content hashes rather than their addresses.  Names are resolved in parallel; the number of parallel resolutions can be set with --workers and the maximum number of resolutions per second with --rate.  Failures are reported against the relevant name and do not stop the remaining names being resolved.  Output is tab-separated by default, or can be CSV with --csv or JSON with --json.
+
+In quiet mode this will return 0 if all names resolve, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(ensDomain != "" || ensResolveFile != "", quiet, "--domain or --file is required")
+		cli.Assert(!(ensResolveJSON && ensResolveCSV), quiet, "--json and --csv are mutually exclusive")
+		cli.Assert(ensResolveWorkers > 0, quiet, "--workers must be greater than 0")
+
+		var names []string
+		if ensResolveFile != "" {
+			f, err := os.Open(ensResolveFile)
+			cli.ErrCheck(err, quiet, "Failed to open names file")
+			scanner := bufio.NewScanner(f)
+			for scanner.Scan() {
+				name := strings.TrimSpace(scanner.Text())
+				if name == "" || strings.HasPrefix(name, "#") {
+					continue
+				}
+				names = append(names, name)
+			}
+			f.Close()
+			cli.ErrCheck(scanner.Err(), quiet, "Failed to read names file")
+		} else {
+			names = []string{ensDomain}
+		}
+
+		results := ensResolveNames(names)
+
+		failed := false
+		for _, result := range results {
+			if result.Error != "" {
+				failed = true
+			}
+		}
+		if quiet {
+			if failed {
+				os.Exit(_exit_failure)
+			}
+			os.Exit(_exit_success)
+		}
+
+		if !ensResolveJSON {
+			cli.WarnCheck(recordJSON(cmd, results), quiet, "Failed to record JSON output")
+		}
+		switch {
+		case ensResolveJSON:
+			cli.ErrCheck(outputJSON(cmd, results), quiet, "Failed to output JSON")
+		case ensResolveCSV:
+			writer := csv.NewWriter(os.Stdout)
+			if ensResolveContenthash {
+				writer.Write([]string{"name", "contenthash", "error"})
+			} else {
+				writer.Write([]string{"name", "address", "error"})
+			}
+			for _, result := range results {
+				writer.Write([]string{result.Name, result.value(), result.Error})
+			}
+			writer.Flush()
+		default:
+			for _, result := range results {
+				if result.Error != "" {
+					fmt.Printf("%s\terror: %s\n", result.Name, result.Error)
+				} else {
+					fmt.Printf("%s\t%s\n", result.Name, result.value())
+				}
+			}
+		}
+		if failed {
+			os.Exit(_exit_failure)
+		}
+	},
+}
+
+// ensResolveNames resolves names in parallel, returning the results in the
+// same order as the names.  Duplicate names are only resolved once.
+func ensResolveNames(names []string) []*ensResolveResult {
+	var throttle <-chan time.Time
+	if ensResolveRate > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(ensResolveRate))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	cache := make(map[string]*ensResolveResult)
+	unique := make([]string, 0, len(names))
+	for _, name := range names {
+		if _, exists := cache[name]; !exists {
+			cache[name] = nil
+			unique = append(unique, name)
+		}
+	}
+	nameCh := make(chan string)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < ensResolveWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range nameCh {
+				result := ensResolveName(name)
+				mu.Lock()
+				cache[name] = result
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, name := range unique {
+		if throttle != nil {
+			<-throttle
+		}
+		nameCh <- name
+	}
+	close(nameCh)
+	wg.Wait()
+
+	results := make([]*ensResolveResult, len(names))
+	for i, name := range names {
+		results[i] = cache[name]
+	}
+	return results
+}
+
+// ensResolveName resolves a single name.
+func ensResolveName(name string) *ensResolveResult {
+	result := &ensResolveResult{Name: name}
+	normalised, err := ens.NormaliseDomain(name)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if ensResolveContenthash {
+		resolver, err := util.ENSResolver(client, normalised)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		data, err := resolver.Contenthash()
+		if err == nil && len(data) == 0 {
+			err = errors.New("no content hash")
+		}
+		if err == nil {
+			result.Contenthash, err = ensContenthashToURL(data)
+		}
+		if err != nil {
+			result.Error = err.Error()
+		}
+		return result
+	}
+	address, err := util.ResolveAddress(client, normalised)
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Address = address.Hex()
+	}
+	return result
+}
+
+func init() {
+	ensCmd.AddCommand(ensResolveCmd)
+	ensFlags(ensResolveCmd)
+	ensResolveCmd.Flags().StringVar(&ensResolveFile, "file", "", "File containing names to resolve, one per line")
+	ensResolveCmd.Flags().IntVar(&ensResolveWorkers, "workers", 8, "Number of names to resolve in parallel")
+	ensResolveCmd.Flags().IntVar(&ensResolveRate, "rate", 0, "Maximum number of names to resolve per second (0 for no limit)")
+	ensResolveCmd.Flags().BoolVar(&ensResolveJSON, "json", false, "Output results as JSON")
+	ensResolveCmd.Flags().BoolVar(&ensResolveCSV, "csv", false, "Output results as CSV")
+	ensResolveCmd.Flags().BoolVar(&ensResolveContenthash, "contenthash", false, "Resolve names to content hashes rather than addresses")
+	jsonoutCmds["ens:resolve"] = true
+}
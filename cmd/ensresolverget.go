@@ -19,7 +19,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
-	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/ethereal/util"
 )
 
 // ensResolverGetCmd represents the resolver get command
@@ -35,12 +35,12 @@ In quiet mode this will return 0 if the name has a resolver, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(ensDomain != "", quiet, "--domain is required")
 
-		registry, err := ens.NewRegistry(client)
+		registry, err := util.ENSRegistry(client)
 		cli.ErrCheck(err, quiet, "Failed to obtain registry contract")
 		resolver, err := registry.ResolverAddress(ensDomain)
 		cli.ErrCheck(err, quiet, "No resolver for that name")
 		if !quiet {
-			fmt.Printf("%s\n", ens.Format(client, resolver))
+			fmt.Printf("%s\n", util.ENSFormat(client, resolver))
 		}
 		os.Exit(_exit_success)
 	},
@@ -14,18 +14,30 @@
 package cmd
 
 import (
-	"bytes"
 	"fmt"
+	"math/big"
+	"os"
+	"strings"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/go-ens/v3/contracts/registry"
 )
 
+var ensResolverSetFromStr string
 var ensResolverSetResolverStr string
 
+// ensPublicResolvers are the addresses of the ENS public resolver, by chain ID.
+var ensPublicResolvers = map[int64]common.Address{
+	1:        common.HexToAddress("0x231b0Ee14048e9dCcD1d247744d114a4EB5E8E63"),
+	11155111: common.HexToAddress("0x8FADE66B79cC9f707aB26799354482EB93a5B7dD"),
+}
+
 // ensResolverSetCmd represents the ens resolver set command
 var ensResolverSetCmd = &cobra.Command{
 	Use:   "set",
@@ -34,37 +46,57 @@ var ensResolverSetCmd = &cobra.Command{
 
     ethereal ens resolver set --domain=enstest.eth --resolver=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --passphrase="my secret passphrase"
 
-If the resolver is not supplied then the public resolver for the network will be used.
+If the resolver is not supplied, or is "public", then the public resolver for the network will be used.  A warning is given if there is no contract at the resolver's address.
 
-The keystore for the account that owns the name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+The keystore for the account that owns the name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.  In offline mode the owner of the name must be supplied with --from.
 
 This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
 		cli.Assert(ensDomain != "", quiet, "--domain is required")
+		node, err := ens.NameHash(ensDomain)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Invalid domain %s", ensDomain))
 
-		registry, err := ens.NewRegistry(client)
-		cli.ErrCheck(err, quiet, "Cannot obtain ENS registry contract")
-
-		// Fetch the owner of the name
-		owner, err := registry.Owner(ensDomain)
-		cli.ErrCheck(err, quiet, "Cannot obtain owner")
-		cli.Assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, quiet, fmt.Sprintf("owner of %s is not set", ensDomain))
+		from := ensOwnerSender(ensDomain, ensResolverSetFromStr)
 
-		// Set the resolver from either command-line or default
 		var resolverAddress common.Address
-		if ensResolverSetResolverStr == "" {
-			resolverAddress, err = ens.PublicResolverAddress(client)
-			cli.ErrCheck(err, quiet, fmt.Sprintf("No public resolver for network id %v", chainID))
+		if ensResolverSetResolverStr == "" || ensResolverSetResolverStr == "public" {
+			resolverAddress = ensPublicResolver()
 		} else {
-			resolverAddress, err = ens.Resolve(client, ensResolverSetResolverStr)
-			cli.Assert(bytes.Compare(resolverAddress.Bytes(), ens.UnknownAddress.Bytes()) != 0, quiet, "Invalid resolver; if you are trying to clear an existing resolver use \"ens resolver clear\"")
-			cli.ErrCheck(err, quiet, fmt.Sprintf("Invalid name/address %s", ensAddressSetAddressStr))
+			resolverAddress = resolveAddressOffline(ensResolverSetResolverStr)
+			cli.Assert(resolverAddress != ens.UnknownAddress, quiet, "Invalid resolver; if you are trying to clear an existing resolver use \"ens resolver clear\"")
 		}
+		outputIf(verbose, fmt.Sprintf("Resolver is %s", resolverAddress.Hex()))
+
+		if !offline {
+			ctx, cancel := localContext()
+			defer cancel()
+			code, err := client.CodeAt(ctx, resolverAddress, nil)
+			cli.ErrCheck(err, quiet, "Failed to obtain code of resolver")
+			if len(code) == 0 {
+				cli.Warn(quiet, fmt.Sprintf("There is no contract at %s, so it cannot act as a resolver", resolverAddress.Hex()))
+			}
+		}
+
+		registryAddress, err := util.ENSRegistryAddress(client)
+		cli.ErrCheck(err, quiet, "Cannot obtain ENS registry address")
+		registryAbi, err := abi.JSON(strings.NewReader(registry.ContractABI))
+		cli.ErrCheck(err, quiet, "Failed to parse ENS registry ABI")
+		data, err := registryAbi.Pack("setResolver", node, resolverAddress)
+		cli.ErrCheck(err, quiet, "Failed to create setResolver data")
 
-		opts, err := generateTxOpts(owner)
-		cli.ErrCheck(err, quiet, "Failed to generate transaction options")
-		signedTx, err := registry.SetResolver(opts, ensDomain, resolverAddress)
+		signedTx, err := createSignedTransaction(from, &registryAddress, big.NewInt(0), gasLimit, data)
+		cli.ErrCheck(err, quiet, "Failed to create transaction")
+
+		if offline {
+			if !quiet {
+				outputOfflineTransaction(signedTx)
+			}
+			os.Exit(_exit_success)
+		}
+
+		ctx, cancel := localContext()
+		defer cancel()
+		err = client.SendTransaction(ctx, signedTx)
 		cli.ErrCheck(err, quiet, "Failed to send transaction")
 
 		handleSubmittedTransaction(signedTx, log.Fields{
@@ -76,9 +108,22 @@ This will return an exit status of 0 if the transaction is successfully submitte
 	},
 }
 
+// ensPublicResolver obtains the address of the public resolver for the
+// chain, either from the list of known public resolvers or from ENS itself.
+func ensPublicResolver() common.Address {
+	if address, exists := ensPublicResolvers[chainID.Int64()]; exists {
+		return address
+	}
+	cli.Assert(!offline, quiet, fmt.Sprintf("No known public resolver for chain ID %v; please supply --resolver", chainID))
+	address, err := util.ENSPublicResolverAddress(client)
+	cli.ErrCheck(err, quiet, fmt.Sprintf("No public resolver for chain ID %v", chainID))
+	return address
+}
+
 func init() {
 	ensResolverCmd.AddCommand(ensResolverSetCmd)
 	ensResolverFlags(ensResolverSetCmd)
-	ensResolverSetCmd.Flags().StringVar(&ensResolverSetResolverStr, "resolver", "", "The resolver's name or address")
+	ensResolverSetCmd.Flags().StringVar(&ensResolverSetFromStr, "from", "", "The owner of the domain (required in offline mode)")
+	ensResolverSetCmd.Flags().StringVar(&ensResolverSetResolverStr, "resolver", "", "The resolver's name or address, or \"public\" for the network's public resolver (defaults to the public resolver)")
 	addTransactionFlags(ensResolverSetCmd, "passphrase for the account that owns the domain")
 }
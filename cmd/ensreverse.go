@@ -0,0 +1,65 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+var ensReverseAddress string
+
+// ensReverseCmd represents the ens reverse command
+var ensReverseCmd = &cobra.Command{
+	Use:   "reverse",
+	Short: "Obtain the primary ENS name of an address",
+	Long: `Obtain the primary Ethereum Name Service (ENS) name of an address.  For example:
+
+    ethereal ens reverse --address=0x217d2707d6CDA43C4807F343a5f5d93a57d86321
+
+The name is obtained by reverse resolution, and is only returned if it resolves back to the address.
+
+In quiet mode this will return 0 if the address has a primary name, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(ensReverseAddress != "", quiet, "--address is required")
+		address, err := util.ResolveAddress(client, ensReverseAddress)
+		cli.ErrCheck(err, quiet, "Failed to obtain address for lookup")
+
+		name, err := util.ENSReverseResolve(client, address)
+		cli.ErrCheck(err, quiet, "Failed to obtain reverse resolution")
+		outputIf(verbose, fmt.Sprintf("Reverse resolution is %s", name))
+
+		// A name is only primary if it resolves back to the address
+		normalised, err := ens.NormaliseDomain(name)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Invalid name %s", name))
+		forward, err := util.ResolveAddress(client, normalised)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve %s", name))
+		cli.Assert(forward == address, quiet, fmt.Sprintf("%s resolves to %s rather than %s", name, forward.Hex(), address.Hex()))
+
+		if quiet {
+			os.Exit(_exit_success)
+		}
+		fmt.Println(name)
+	},
+}
+
+func init() {
+	ensCmd.AddCommand(ensReverseCmd)
+	ensReverseCmd.Flags().StringVar(&ensReverseAddress, "address", "", "Address for which to obtain the primary name")
+}
@@ -16,17 +16,26 @@ package cmd
 import (
 	"bytes"
 	"fmt"
+	"math/big"
+	"os"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	ens "github.com/wealdtech/go-ens/v3"
 )
 
+// ensSubdomainCreateABI contains the registry functions used to create subdomains.
+const ensSubdomainCreateABI = `[{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"label","type":"bytes32"},{"name":"owner","type":"address"}],"name":"setSubnodeOwner","outputs":[{"name":"","type":"bytes32"}],"payable":false,"stateMutability":"nonpayable","type":"function"},{"constant":false,"inputs":[{"name":"node","type":"bytes32"},{"name":"label","type":"bytes32"},{"name":"owner","type":"address"},{"name":"resolver","type":"address"},{"name":"ttl","type":"uint64"}],"name":"setSubnodeRecord","outputs":[],"payable":false,"stateMutability":"nonpayable","type":"function"}]`
+
 var ensSubdomainCreateSubdomain string
 var ensSubdomainCreateOwnerStr string
+var ensSubdomainCreateResolverStr string
+var ensSubdomainCreateFromStr string
 
 // ensSubdomainCreateCmd represents the ens subdomain create command
 var ensSubdomainCreateCmd = &cobra.Command{
@@ -36,49 +45,91 @@ var ensSubdomainCreateCmd = &cobra.Command{
 
     ethereal ens subdomain create --domain=enstest.eth --subdomain=sub --passphrase="my secret passphrase"
 
-The keystore for the account that owns the name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+The owner of the subdomain defaults to the owner of the domain, and can be set with --owner.  If --resolver is supplied then the resolver of the subdomain is set in the same transaction.
+
+The keystore for the account that owns the name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.  In offline mode the owner of the domain must be supplied with --from.
 
 This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
 		cli.Assert(ensDomain != "", quiet, "--domain is required")
+		domain, err := ens.NormaliseDomain(ensDomain)
+		cli.ErrCheck(err, quiet, "Failed to normalise ENS domain")
 
 		cli.Assert(ensSubdomainCreateSubdomain != "", quiet, "--subdomain is required")
 		cli.Assert(!strings.Contains(ensSubdomainCreateSubdomain, "."), quiet, "subdomain should not contain the '.' character")
+		label, err := ens.NormaliseDomain(ensSubdomainCreateSubdomain)
+		cli.ErrCheck(err, quiet, "Failed to normalise subdomain")
 
-		registry, err := ens.NewRegistry(client)
-		cli.ErrCheck(err, quiet, "cannot obtain ENS registry contract")
+		node, err := ens.NameHash(domain)
+		cli.ErrCheck(err, quiet, "Failed to obtain name hash of ENS domain")
+		labelHash, err := ens.LabelHash(label)
+		cli.ErrCheck(err, quiet, "Failed to obtain label hash of subdomain")
 
-		// Fetch the controller of the name.
-		controller, err := registry.Owner(ensDomain)
-		cli.ErrCheck(err, quiet, "cannot obtain owner")
-		cli.Assert(bytes.Compare(controller.Bytes(), ens.UnknownAddress.Bytes()) != 0, quiet, fmt.Sprintf("controller of %s is not set", ensDomain))
+		registryAddress, err := util.ENSRegistryAddress(client)
+		cli.ErrCheck(err, quiet, "Cannot obtain ENS registry address")
+
+		// The controller of the domain sends the transaction
+		var controller common.Address
+		if offline {
+			cli.Assert(ensSubdomainCreateFromStr != "", quiet, "--from is required in offline mode")
+			cli.Assert(gasLimit != 0, quiet, "--gaslimit is required in offline mode")
+			controller = resolveAddressOffline(ensSubdomainCreateFromStr)
+		} else {
+			registry, err := ens.NewRegistryAt(client, registryAddress)
+			cli.ErrCheck(err, quiet, "Cannot obtain ENS registry contract")
+			controller, err = registry.Owner(domain)
+			cli.ErrCheck(err, quiet, "Cannot obtain owner")
+			cli.Assert(!bytes.Equal(controller.Bytes(), ens.UnknownAddress.Bytes()), quiet, fmt.Sprintf("Controller of %s is not set", domain))
+			if ensSubdomainCreateFromStr != "" {
+				fromAddress := resolveAddressOffline(ensSubdomainCreateFromStr)
+				cli.Assert(bytes.Equal(controller.Bytes(), fromAddress.Bytes()), quiet, fmt.Sprintf("%s is not the controller of %s", ensSubdomainCreateFromStr, domain))
+			}
+		}
 		outputIf(debug, fmt.Sprintf("Controller is %s", controller.Hex()))
 
 		// Work out the owner of the subdomain.
-		var subdomainOwner common.Address
-		if ensSubdomainCreateOwnerStr == "" {
-			// Subdomain owner == controller.
-			subdomainOwner = controller
-		} else {
-			subdomainOwner, err = ens.Resolve(client, ensSubdomainCreateOwnerStr)
-			cli.ErrCheck(err, quiet, fmt.Sprintf("invalid subdomain name/address %s", ensSubdomainCreateOwnerStr))
+		subdomainOwner := controller
+		if ensSubdomainCreateOwnerStr != "" {
+			subdomainOwner = resolveAddressOffline(ensSubdomainCreateOwnerStr)
 		}
 		outputIf(debug, fmt.Sprintf("Controller of subdomain will be %s", subdomainOwner.Hex()))
 
-		// Create the subdomain
-		opts, err := generateTxOpts(controller)
-		cli.ErrCheck(err, quiet, "failed to generate transaction options")
-		signedTx, err := registry.SetSubdomainOwner(opts, ensDomain, ensSubdomainCreateSubdomain, subdomainOwner)
-		cli.ErrCheck(err, quiet, "failed to broadcast transaction")
-
-		handleSubmittedTransaction(signedTx, log.Fields{
+		registryAbi, err := abi.JSON(strings.NewReader(ensSubdomainCreateABI))
+		cli.ErrCheck(err, quiet, "Failed to parse ENS registry ABI")
+		logFields := log.Fields{
 			"group":             "ens/subdomain",
 			"command":           "create",
-			"ensdomain":         ensDomain,
-			"enssubdomain":      ensSubdomainCreateSubdomain,
+			"ensdomain":         domain,
+			"enssubdomain":      label,
 			"enssubdomainowner": subdomainOwner.Hex(),
-		}, true)
+		}
+		var data []byte
+		if ensSubdomainCreateResolverStr != "" {
+			resolverAddress := resolveAddressOffline(ensSubdomainCreateResolverStr)
+			outputIf(debug, fmt.Sprintf("Resolver of subdomain will be %s", resolverAddress.Hex()))
+			data, err = registryAbi.Pack("setSubnodeRecord", node, labelHash, subdomainOwner, resolverAddress, uint64(0))
+			logFields["enssubdomainresolver"] = resolverAddress.Hex()
+		} else {
+			data, err = registryAbi.Pack("setSubnodeOwner", node, labelHash, subdomainOwner)
+		}
+		cli.ErrCheck(err, quiet, "Failed to create transaction data")
+
+		signedTx, err := createSignedTransaction(controller, &registryAddress, big.NewInt(0), gasLimit, data)
+		cli.ErrCheck(err, quiet, "Failed to create transaction")
+
+		if offline {
+			if !quiet {
+				outputOfflineTransaction(signedTx)
+			}
+			os.Exit(_exit_success)
+		}
+
+		ctx, cancel := localContext()
+		defer cancel()
+		err = client.SendTransaction(ctx, signedTx)
+		cli.ErrCheck(err, quiet, "Failed to send transaction")
+
+		handleSubmittedTransaction(signedTx, logFields, true)
 	},
 }
 
@@ -86,6 +137,9 @@ func init() {
 	ensSubdomainCmd.AddCommand(ensSubdomainCreateCmd)
 	ensSubdomainFlags(ensSubdomainCreateCmd)
 	ensSubdomainCreateCmd.Flags().StringVar(&ensSubdomainCreateSubdomain, "subdomain", "", "The name of the subdomain")
+	ensSubdomainCreateCmd.Flags().StringVar(&ensSubdomainCreateSubdomain, "name", "", "The name of the subdomain (alternative to --subdomain)")
 	ensSubdomainCreateCmd.Flags().StringVar(&ensSubdomainCreateOwnerStr, "owner", "", "The owner of the subdomain (defaults to the owner of the domain)")
+	ensSubdomainCreateCmd.Flags().StringVar(&ensSubdomainCreateResolverStr, "resolver", "", "The resolver of the subdomain")
+	ensSubdomainCreateCmd.Flags().StringVar(&ensSubdomainCreateFromStr, "from", "", "The owner of the domain (required in offline mode)")
 	addTransactionFlags(ensSubdomainCreateCmd, "passphrase for the account that owns the domain")
 }
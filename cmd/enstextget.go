@@ -19,7 +19,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
-	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/ethereal/util"
 )
 
 var ensTextGetRaw bool
@@ -30,15 +30,16 @@ var ensTextGetCmd = &cobra.Command{
 	Short: "Obtain the text of an ENS domain",
 	Long: `Obtain the text of a domain registered with the Ethereum Name Service (ENS).  For example:
 
-    ethereal ens text get --domain=enstest.eth --enskey="My key"
+    ethereal ens text get --domain=enstest.eth --key=url
 
 In quiet mode this will return 0 if the key has text, otherwise 1.`,
 
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(ensDomain != "", quiet, "--domain is required")
+		cli.Assert(ensTextKey != "", quiet, "--key is required")
 
 		// Obtain resolver for the domain
-		resolver, err := ens.NewResolver(client, ensDomain)
+		resolver, err := util.ENSResolver(client, ensDomain)
 		cli.ErrCheck(err, quiet, "No resolver for that name")
 
 		value, err := resolver.Text(ensTextKey)
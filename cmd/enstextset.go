@@ -14,16 +14,22 @@
 package cmd
 
 import (
-	"bytes"
 	"fmt"
+	"math/big"
+	"os"
+	"strings"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
 	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
 )
 
 var ensTextSetText string
+var ensTextSetFromStr string
+var ensTextSetResolverStr string
 
 // ensTextSetCmd represents the ens text set command
 var ensTextSetCmd = &cobra.Command{
@@ -31,40 +37,50 @@ var ensTextSetCmd = &cobra.Command{
 	Short: "Set the text of an ENS domain",
 	Long: `Set the text of a name registered with the Ethereum Name Service (ENS) for a given name.  For example:
 
-    ethereal ens text set --domain=enstest.eth --key="my name" --text="my value" --passphrase="my secret passphrase"
+    ethereal ens text set --domain=enstest.eth --key="url" --text="https://www.example.com/" --passphrase="my secret passphrase"
 
-The keystore for the account that owns the name must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.
+Common keys include avatar, description, email, url and com.twitter; any key can be used.
+
+By default the transaction is sent from the owner of the name.  An account that the owner has authorised with the resolver can be supplied with --from instead.  The keystore for the account must be local (i.e. listed with 'get accounts list') and unlockable with the supplied passphrase.  In offline mode --from, --resolver and --gaslimit must all be supplied.
 
 This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
 		cli.Assert(ensDomain != "", quiet, "--domain is required")
-
 		cli.Assert(ensTextKey != "", quiet, "--key is required")
 		cli.Assert(ensTextSetText != "", quiet, "--text is required; to clear the value use \"ens text clear\"")
 
-		registry, err := ens.NewRegistry(client)
-		cli.ErrCheck(err, quiet, "Cannot obtain ENS registry contract")
-
-		// Fetch the owner of the name
-		owner, err := registry.Owner(ensDomain)
-		cli.ErrCheck(err, quiet, "Cannot obtain owner")
-		cli.Assert(bytes.Compare(owner.Bytes(), ens.UnknownAddress.Bytes()) != 0, quiet, fmt.Sprintf("owner of %s is not set", ensDomain))
-
-		// Obtain the resolver for this name
-		resolver, err := ens.NewResolver(client, ensDomain)
-		cli.ErrCheck(err, quiet, "No resolver for that name")
-
-		opts, err := generateTxOpts(owner)
-		cli.ErrCheck(err, quiet, "failed to generate transaction options")
-
-		signedTx, err := resolver.SetText(opts, ensTextKey, ensTextSetText)
-		cli.ErrCheck(err, quiet, "failed to send transaction")
+		domain, err := ens.NormaliseDomain(ensDomain)
+		cli.ErrCheck(err, quiet, "Failed to normalise ENS domain")
+		node, err := ens.NameHash(domain)
+		cli.ErrCheck(err, quiet, "Failed to obtain name hash of ENS domain")
+
+		// Work out the resolver and the account that will send the transaction
+		from, resolverAddress := ensResolverSender(domain, node, ensTextSetFromStr, ensTextSetResolverStr)
+		outputIf(verbose, fmt.Sprintf("Sending from %s", from.Hex()))
+
+		resolverAbi, err := abi.JSON(strings.NewReader(resolver.ContractABI))
+		cli.ErrCheck(err, quiet, "Failed to parse resolver ABI")
+		txData, err := resolverAbi.Pack("setText", node, ensTextKey, ensTextSetText)
+		cli.ErrCheck(err, quiet, "Failed to create setText data")
+
+		signedTx, err := createSignedTransaction(from, &resolverAddress, big.NewInt(0), gasLimit, txData)
+		cli.ErrCheck(err, quiet, "Failed to create transaction")
+		if offline {
+			if !quiet {
+				outputOfflineTransaction(signedTx)
+			}
+			os.Exit(_exit_success)
+		}
+
+		ctx, cancel := localContext()
+		defer cancel()
+		err = client.SendTransaction(ctx, signedTx)
+		cli.ErrCheck(err, quiet, "Failed to send transaction")
 
 		handleSubmittedTransaction(signedTx, log.Fields{
 			"group":     "ens/text",
 			"command":   "set",
-			"ensdomain": ensDomain,
+			"ensdomain": domain,
 			"key":       ensTextKey,
 			"text":      ensTextSetText,
 		}, true)
@@ -75,5 +91,7 @@ func init() {
 	ensTextCmd.AddCommand(ensTextSetCmd)
 	ensTextFlags(ensTextSetCmd)
 	ensTextSetCmd.Flags().StringVar(&ensTextSetText, "text", "", "The text to set")
+	ensTextSetCmd.Flags().StringVar(&ensTextSetFromStr, "from", "", "The account sending the transaction, if not the owner of the name (required in offline mode)")
+	ensTextSetCmd.Flags().StringVar(&ensTextSetResolverStr, "resolver", "", "The resolver of the name (required in offline mode)")
 	addTransactionFlags(ensTextSetCmd, "passphrase for the account that owns the domain")
 }
@@ -23,6 +23,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	ens "github.com/wealdtech/go-ens/v3"
 )
 
@@ -46,7 +47,7 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		cli.Assert(len(ensDomain) > 10, quiet, "Domain must be at least 7 characters long")
 		cli.Assert(len(strings.Split(ensDomain, ".")) == 2, quiet, "Name must not contain . (except for ending in .eth)")
 
-		registrar, err := ens.NewBaseRegistrar(client, ens.Tld(ensDomain))
+		registrar, err := util.ENSRegistrarFor(client, ens.Tld(ensDomain))
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain ENS registrar contract for %s", ens.Tld(ensDomain)))
 
 		// Obtain the registrant
@@ -73,10 +74,10 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		}
 		cli.Assert(registrant != ens.UnknownAddress, quiet, "Failed to obtain registrant")
 
-		outputIf(verbose, fmt.Sprintf("Current registrant is %s", ens.Format(client, registrant)))
+		outputIf(verbose, fmt.Sprintf("Current registrant is %s", util.ENSFormat(client, registrant)))
 
 		// Transfer the registration
-		newRegistrantAddress, err := ens.Resolve(client, ensTransferNewRegistrantStr)
+		newRegistrantAddress, err := util.ResolveAddress(client, ensTransferNewRegistrantStr)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("unknown new registrant %s", ensTransferNewRegistrantStr))
 		opts, err := generateTxOpts(registrant)
 		cli.ErrCheck(err, quiet, "failed to generate transaction options")
@@ -0,0 +1,163 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	"github.com/wealdtech/ethereal/util/txdata"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+var (
+	ensWatchNewOwnerTopic    = crypto.Keccak256Hash([]byte("NewOwner(bytes32,bytes32,address)"))
+	ensWatchTransferTopic    = crypto.Keccak256Hash([]byte("Transfer(bytes32,address)"))
+	ensWatchNewResolverTopic = crypto.Keccak256Hash([]byte("NewResolver(bytes32,address)"))
+	ensWatchNewTTLTopic      = crypto.Keccak256Hash([]byte("NewTTL(bytes32,uint64)"))
+	ensWatchAddrChangedTopic = crypto.Keccak256Hash([]byte("AddrChanged(bytes32,address)"))
+)
+
+// ensWatchCmd represents the ens watch command
+var ensWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watch an ENS domain for changes",
+	Long: `Watch a domain registered with the Ethereum Name Service (ENS) for changes to its owner, resolver or records.  For example:
+
+    ethereal ens watch --domain=enstest.eth
+
+Changes are printed as they are mined.  This requires a connection that supports subscriptions, such as websocket or IPC.
+
+In quiet mode this will return 1 if the watch fails.  It does not otherwise return.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(ensDomain != "", quiet, "--domain is required")
+
+		domain, err := ens.NormaliseDomain(ensDomain)
+		cli.ErrCheck(err, quiet, "Failed to normalise ENS domain")
+		nodeHash, err := ens.NameHash(domain)
+		cli.ErrCheck(err, quiet, "Failed to obtain name hash of ENS domain")
+		node := common.Hash(nodeHash)
+		parentNodeHash, err := ens.NameHash(ens.Domain(domain))
+		cli.ErrCheck(err, quiet, "Failed to obtain name hash of parent domain")
+		parentNode := common.Hash(parentNodeHash)
+		label, err := ens.DomainPart(domain, 1)
+		cli.ErrCheck(err, quiet, "Failed to obtain label of ENS domain")
+		labelHash, err := ens.LabelHash(label)
+		cli.ErrCheck(err, quiet, "Failed to obtain label hash of ENS domain")
+
+		registryAddress, err := util.ENSRegistryAddress(client)
+		cli.ErrCheck(err, quiet, "Failed to obtain ENS registry address")
+		registry, err := ens.NewRegistryAt(client, registryAddress)
+		cli.ErrCheck(err, quiet, "Failed to obtain ENS registry contract")
+		resolverAddress, err := registry.ResolverAddress(domain)
+		cli.ErrCheck(err, quiet, "Failed to obtain resolver address")
+		outputIf(verbose, fmt.Sprintf("Watching registry %s", util.ENSFormat(client, registryAddress)))
+		if resolverAddress != ens.UnknownAddress {
+			outputIf(verbose, fmt.Sprintf("Watching resolver %s", util.ENSFormat(client, resolverAddress)))
+		}
+
+		txdata.InitFunctionMap()
+
+		logs := make(chan types.Log)
+		sub := ensWatchSubscribe(logs, registryAddress, resolverAddress, node, parentNode)
+		for {
+			select {
+			case err := <-sub.Err():
+				cli.ErrCheck(err, quiet, "Subscription failed")
+			case log := <-logs:
+				if len(log.Topics) < 2 {
+					continue
+				}
+				prefix := fmt.Sprintf("%s block %d transaction %s:", time.Now().Format(time.RFC3339), log.BlockNumber, log.TxHash.Hex())
+				if log.Removed {
+					outputIf(!quiet, fmt.Sprintf("%s change removed due to chain reorganisation", prefix))
+					continue
+				}
+				if log.Address == registryAddress {
+					switch log.Topics[0] {
+					case ensWatchNewOwnerTopic:
+						if len(log.Topics) < 3 || log.Topics[1] != parentNode || log.Topics[2] != common.Hash(labelHash) {
+							// Change to a sibling domain
+							continue
+						}
+						outputIf(!quiet, fmt.Sprintf("%s owner set to %s", prefix, util.ENSFormat(client, common.BytesToAddress(log.Data))))
+					case ensWatchTransferTopic:
+						if log.Topics[1] != node {
+							continue
+						}
+						outputIf(!quiet, fmt.Sprintf("%s owner transferred to %s", prefix, util.ENSFormat(client, common.BytesToAddress(log.Data))))
+					case ensWatchNewResolverTopic:
+						if log.Topics[1] != node {
+							continue
+						}
+						resolverAddress = common.BytesToAddress(log.Data)
+						outputIf(!quiet, fmt.Sprintf("%s resolver set to %s", prefix, util.ENSFormat(client, resolverAddress)))
+						// Watch the new resolver rather than the old one
+						sub.Unsubscribe()
+						sub = ensWatchSubscribe(logs, registryAddress, resolverAddress, node, parentNode)
+					case ensWatchNewTTLTopic:
+						if log.Topics[1] != node {
+							continue
+						}
+						outputIf(!quiet, fmt.Sprintf("%s TTL set to %d", prefix, common.BytesToHash(log.Data).Big()))
+					}
+					continue
+				}
+				if log.Address != resolverAddress || log.Topics[1] != node {
+					continue
+				}
+				if log.Topics[0] == ensWatchAddrChangedTopic {
+					outputIf(!quiet, fmt.Sprintf("%s address set to %s", prefix, util.ENSFormat(client, common.BytesToAddress(log.Data))))
+					continue
+				}
+				decoded := txdata.EventToString(client, &log)
+				if decoded == "" {
+					decoded = fmt.Sprintf("unknown event %s", log.Topics[0].Hex())
+				}
+				outputIf(!quiet, fmt.Sprintf("%s resolver record changed: %s", prefix, decoded))
+			}
+		}
+	},
+}
+
+// ensWatchSubscribe subscribes to logs from the registry and resolver that refer to the given nodes.
+func ensWatchSubscribe(logs chan types.Log, registryAddress common.Address, resolverAddress common.Address, node common.Hash, parentNode common.Hash) ethereum.Subscription {
+	addresses := []common.Address{registryAddress}
+	if resolverAddress != ens.UnknownAddress {
+		addresses = append(addresses, resolverAddress)
+	}
+	query := ethereum.FilterQuery{
+		Addresses: addresses,
+		Topics:    [][]common.Hash{{}, {node, parentNode}},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("timeout"))
+	defer cancel()
+	sub, err := client.SubscribeFilterLogs(ctx, query, logs)
+	cli.ErrCheck(err, quiet, "Failed to subscribe to logs; a websocket or IPC connection is required")
+	return sub
+}
+
+func init() {
+	ensCmd.AddCommand(ensWatchCmd)
+	ensFlags(ensWatchCmd)
+}
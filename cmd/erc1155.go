@@ -0,0 +1,57 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var erc1155TokenStr string
+var erc1155HolderStr string
+
+// erc1155Cmd represents the erc1155 command
+var erc1155Cmd = &cobra.Command{
+	Use:   "erc1155",
+	Short: "Manage ERC-1155 multi-tokens",
+	Long:  `Obtain information about ERC-1155 multi-tokens.`,
+}
+
+// erc1155TokenIDs parses a comma-separated list of token IDs.
+func erc1155TokenIDs(input string) ([]*big.Int, error) {
+	ids := make([]*big.Int, 0)
+	for _, idStr := range strings.Split(input, ",") {
+		idStr = strings.TrimSpace(idStr)
+		if idStr == "" {
+			continue
+		}
+		id, err := nftTokenID(idStr)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func init() {
+	RootCmd.AddCommand(erc1155Cmd)
+}
+
+func erc1155Flags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&erc1155TokenStr, "token", "", "Name or address of the token contract")
+	cmd.Flags().StringVar(&erc1155HolderStr, "holder", "", "Name or address of the holder of the tokens")
+}
@@ -0,0 +1,69 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	"github.com/wealdtech/ethereal/util/contracts"
+)
+
+var erc1155BalanceIDStr string
+
+// erc1155BalanceCmd represents the erc1155 balance command
+var erc1155BalanceCmd = &cobra.Command{
+	Use:   "balance",
+	Short: "Obtain the balance of an ERC-1155 token",
+	Long: `Obtain the balance of a single ERC-1155 token held by an address.  For example:
+
+    ethereal erc1155 balance --token=0x76BE3b62873462d2142405439777e971754E8E77 --holder=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --id=10147
+
+In quiet mode this will return 0 if the balance is greater than 0, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(erc1155TokenStr != "", quiet, "--token is required")
+		tokenAddress, err := util.ResolveAddress(client, erc1155TokenStr)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve token address %s", erc1155TokenStr))
+		token, err := contracts.NewERC1155(tokenAddress, client)
+		cli.ErrCheck(err, quiet, "Failed to obtain token contract")
+
+		cli.Assert(erc1155HolderStr != "", quiet, "--holder is required")
+		holder, err := util.ResolveAddress(client, erc1155HolderStr)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve holder address %s", erc1155HolderStr))
+
+		cli.Assert(erc1155BalanceIDStr != "", quiet, "--id is required")
+		id, err := nftTokenID(erc1155BalanceIDStr)
+		cli.ErrCheck(err, quiet, "Invalid token ID")
+
+		balance, err := token.BalanceOf(nil, holder, id)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain balance of token %s", id))
+
+		if quiet {
+			if balance.Sign() == 0 {
+				os.Exit(_exit_failure)
+			}
+			os.Exit(_exit_success)
+		}
+		fmt.Printf("%s\n", balance)
+	},
+}
+
+func init() {
+	erc1155Cmd.AddCommand(erc1155BalanceCmd)
+	erc1155Flags(erc1155BalanceCmd)
+	erc1155BalanceCmd.Flags().StringVar(&erc1155BalanceIDStr, "id", "", "ID of the token")
+}
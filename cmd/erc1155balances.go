@@ -0,0 +1,109 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	"github.com/wealdtech/ethereal/util/contracts"
+	"github.com/wealdtech/ethereal/util/output"
+)
+
+var erc1155BalancesIDsStr string
+var erc1155BalancesJSON bool
+
+// erc1155BalancesCmd represents the erc1155 balances command
+var erc1155BalancesCmd = &cobra.Command{
+	Use:   "balances",
+	Short: "Obtain the balances of multiple ERC-1155 tokens",
+	Long: `Obtain the balances of multiple ERC-1155 tokens held by an address.  For example:
+
+    ethereal erc1155 balances --token=0x76BE3b62873462d2142405439777e971754E8E77 --holder=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --ids=10147,10148,10149
+
+The balances are obtained in a single call to balanceOfBatch().  If the contract does not support this then each balance is obtained individually.
+
+The --output flag selects the format of the output: text (the default), json or csv.  --json is equivalent to --output=json.  JSON output holds the balances as an array in its results field.
+
+In quiet mode this will return 0 if any balance is greater than 0, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if erc1155BalancesJSON {
+			outputFormat = output.JSON
+		}
+		formatter, err := output.New(outputFormat)
+		cli.ErrCheck(err, quiet, "Invalid output format")
+		formatter.SetArray(true)
+
+		cli.Assert(erc1155TokenStr != "", quiet, "--token is required")
+		tokenAddress, err := util.ResolveAddress(client, erc1155TokenStr)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve token address %s", erc1155TokenStr))
+		token, err := contracts.NewERC1155(tokenAddress, client)
+		cli.ErrCheck(err, quiet, "Failed to obtain token contract")
+
+		cli.Assert(erc1155HolderStr != "", quiet, "--holder is required")
+		holder, err := util.ResolveAddress(client, erc1155HolderStr)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve holder address %s", erc1155HolderStr))
+
+		cli.Assert(erc1155BalancesIDsStr != "", quiet, "--ids is required")
+		ids, err := erc1155TokenIDs(erc1155BalancesIDsStr)
+		cli.ErrCheck(err, quiet, "Invalid token IDs")
+		cli.Assert(len(ids) > 0, quiet, "--ids is required")
+
+		holders := make([]common.Address, len(ids))
+		for i := range holders {
+			holders[i] = holder
+		}
+		balances, err := token.BalanceOfBatch(nil, holders, ids)
+		if err != nil || len(balances) != len(ids) {
+			outputIf(verbose, "Failed to obtain balances in a single call; obtaining them individually")
+			balances = make([]*big.Int, len(ids))
+			for i := range ids {
+				balances[i], err = token.BalanceOf(nil, holder, ids[i])
+				cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain balance of token %s", ids[i]))
+			}
+		}
+
+		if quiet {
+			for i := range balances {
+				if balances[i].Sign() > 0 {
+					os.Exit(_exit_success)
+				}
+			}
+			os.Exit(_exit_failure)
+		}
+
+		records := make([]output.Record, len(ids))
+		for i := range ids {
+			records[i] = output.Record{
+				{Name: "id", Value: ids[i]},
+				{Name: "balance", Value: balances[i]},
+			}
+		}
+		cli.ErrCheck(outputRecords(cmd, formatter, records...), quiet, "Failed to output balances")
+	},
+}
+
+func init() {
+	erc1155Cmd.AddCommand(erc1155BalancesCmd)
+	erc1155Flags(erc1155BalancesCmd)
+	erc1155BalancesCmd.Flags().StringVar(&erc1155BalancesIDsStr, "ids", "", "Comma-separated list of IDs of the tokens")
+	erc1155BalancesCmd.Flags().BoolVar(&erc1155BalancesJSON, "json", false, "Display output as JSON (equivalent to --output=json)")
+	outputFlags(erc1155BalancesCmd)
+	jsonoutCmds["erc1155:balances"] = true
+}
@@ -21,14 +21,17 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/wealdtech/ethereal/cli"
-	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/ethereal/util"
+	"github.com/wealdtech/ethereal/util/output"
 	string2eth "github.com/wealdtech/go-string2eth"
 )
 
-var etherBalanceAddress string
+var etherBalanceAddresses []string
 var etherBalanceBlock string
 var etherBalanceWei bool
+var etherBalanceJSON bool
 
 // etherBalanceCmd represents the ether balance command
 var etherBalanceCmd = &cobra.Command{
@@ -38,53 +41,149 @@ var etherBalanceCmd = &cobra.Command{
 
     ethereal ether balance --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4
 
-In quiet mode this will return 0 if the balance is greater than 0, otherwise 1.`,
+The --output flag selects the format of the output: text (the default), json or csv.  JSON and CSV output include the number of the block at which the balance was obtained.  --json is equivalent to --output=json.
+
+Multiple addresses can be supplied separated by commas or by repeating --address, in which case the balances are obtained in a single batch request where the connection supports it.  A failure to obtain the balance for one address does not stop the balances of the others from being shown.  With JSON output the balances are output in the results field.
+
+In quiet mode this will return 0 if the balance is greater than 0, otherwise 1.  If multiple addresses are supplied this will return 0 if all of the balances are greater than 0, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(etherBalanceAddress != "", quiet, "--address is required")
-		address, err := ens.Resolve(client, etherBalanceAddress)
-		cli.ErrCheck(err, quiet, "Failed to obtain address")
+		cli.Assert(len(etherBalanceAddresses) > 0, quiet, "--address is required")
 
-		var blockNumber *big.Int
-		if etherBalanceBlock != "" {
-			if blockInfoNumberRegexp.MatchString(etherBalanceBlock) {
-				var succeeded bool
-				blockNumber, succeeded = big.NewInt(0).SetString(etherBalanceBlock, 10)
-				cli.Assert(succeeded, quiet, fmt.Sprintf("Failed to parse block number %s", etherBalanceBlock))
-			} else {
-				blockHash := common.HexToHash(etherBalanceBlock)
-				ctx, cancel := localContext()
-				defer cancel()
-				block, err := client.BlockByHash(ctx, blockHash)
-				cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain block %s", etherBalanceBlock))
-				blockNumber = block.Number()
-			}
+		if etherBalanceJSON {
+			outputFormat = output.JSON
+		}
+		formatter, err := output.New(outputFormat)
+		cli.ErrCheck(err, quiet, "Invalid output format")
+
+		blockNumber := parseBlockNumber(etherBalanceBlock)
+		if (formatter.Format() != output.Text || viper.GetString("jsonout") != "") && blockNumber == nil {
+			// Fix the block so that it can be reported alongside the balance
+			ctx, cancel := localContext()
+			header, err := client.HeaderByNumber(ctx, nil)
+			cancel()
+			cli.ErrCheck(err, quiet, "Failed to obtain latest block")
+			blockNumber = header.Number
+		}
+
+		if len(etherBalanceAddresses) > 1 {
+			etherBalanceMultiple(cmd, formatter, blockNumber)
 		}
 
+		address, err := util.ResolveAddress(client, etherBalanceAddresses[0])
+		cli.ErrCheck(err, quiet, "Failed to obtain address")
+
 		ctx, cancel := localContext()
 		defer cancel()
 		balance, err := client.BalanceAt(ctx, address, blockNumber)
 		cli.Assert(err == nil || !strings.HasPrefix(err.Error(), "missing trie node"), quiet, "Connection does not have information on that block, please change the connection parameter to point to a full synced node")
 		cli.ErrCheck(err, quiet, "Failed to obtain balance")
 
+		if !quiet {
+			record := etherBalanceRecord(address, balance, blockNumber)
+			// Only the balance is shown for a single address
+			record[0].Hidden = true
+			cli.ErrCheck(outputRecords(cmd, formatter, record), quiet, "Failed to output balance")
+		}
 		if balance.Cmp(big.NewInt(0)) == 0 {
-			outputIf(!quiet, "0")
 			os.Exit(_exit_failure)
+		}
+		os.Exit(_exit_success)
+	},
+}
+
+// etherBalanceRecord creates the record for the balance of an address.
+func etherBalanceRecord(address common.Address, balance *big.Int, blockNumber *big.Int) output.Record {
+	text := "0"
+	if balance.Cmp(big.NewInt(0)) != 0 {
+		if etherBalanceWei {
+			text = balance.String()
 		} else {
-			if !quiet {
-				if etherBalanceWei {
-					fmt.Printf("%s\n", balance.String())
-				} else {
-					fmt.Printf("%s\n", string2eth.WeiToString(balance, true))
+			text = string2eth.WeiToString(balance, true)
+		}
+	}
+	var block interface{}
+	if blockNumber != nil {
+		block = blockNumber.Uint64()
+	}
+	return output.Record{
+		{Name: "address", Value: address},
+		{Name: "balance", Value: balance, Text: text},
+		{Name: "balanceEth", Value: util.TokenValueToString(balance, 18, false), Hidden: true},
+		{Name: "block", Value: block, Hidden: true},
+	}
+}
+
+// etherBalanceErrorRecord creates the record for an address whose balance
+// could not be obtained.
+func etherBalanceErrorRecord(address string, err string) output.Record {
+	return output.Record{
+		{Name: "address", Value: address},
+		{Name: "error", Value: err, Text: fmt.Sprintf("error: %s", err)},
+	}
+}
+
+// etherBalanceMultiple obtains and displays the balances of multiple addresses.
+func etherBalanceMultiple(cmd *cobra.Command, formatter *output.Formatter, blockNumber *big.Int) {
+	records := make([]output.Record, len(etherBalanceAddresses))
+	addresses := make([]common.Address, 0, len(etherBalanceAddresses))
+	indices := make([]int, 0, len(etherBalanceAddresses))
+	allPositive := true
+	for i := range etherBalanceAddresses {
+		name := strings.TrimSpace(etherBalanceAddresses[i])
+		address, err := util.ResolveAddress(client, name)
+		if err != nil {
+			if quiet {
+				os.Exit(_exit_failure)
+			}
+			records[i] = etherBalanceErrorRecord(name, fmt.Sprintf("failed to obtain address: %v", err))
+			allPositive = false
+			continue
+		}
+		addresses = append(addresses, address)
+		indices = append(indices, i)
+	}
+
+	if len(addresses) > 0 {
+		ctx, cancel := localContext()
+		defer cancel()
+		balances, errs, err := util.BalancesAt(ctx, rpcClient, addresses, blockNumber)
+		cli.ErrCheck(err, quiet, "Failed to obtain balances")
+		for i := range balances {
+			cli.Assert(errs[i] == nil || !strings.HasPrefix(errs[i].Error(), "missing trie node"), quiet, "Connection does not have information on that block, please change the connection parameter to point to a full synced node")
+			if errs[i] != nil {
+				if quiet {
+					os.Exit(_exit_failure)
 				}
+				records[indices[i]] = etherBalanceErrorRecord(addresses[i].Hex(), fmt.Sprintf("failed to obtain balance: %v", errs[i]))
+				allPositive = false
+				continue
+			}
+			records[indices[i]] = etherBalanceRecord(addresses[i], balances[i], blockNumber)
+			if balances[i].Sign() == 0 {
+				allPositive = false
 			}
-			os.Exit(_exit_success)
 		}
-	},
+	}
+
+	if !quiet {
+		// Text output shows addresses as supplied
+		for i := range records {
+			records[i][0].Text = strings.TrimSpace(etherBalanceAddresses[i])
+		}
+		cli.ErrCheck(outputRecords(cmd, formatter, records...), quiet, "Failed to output balances")
+	}
+	if allPositive {
+		os.Exit(_exit_success)
+	}
+	os.Exit(_exit_failure)
 }
 
 func init() {
 	etherCmd.AddCommand(etherBalanceCmd)
 	etherBalanceCmd.Flags().BoolVar(&etherBalanceWei, "wei", false, "Display output in number of Wei")
-	etherBalanceCmd.Flags().StringVar(&etherBalanceAddress, "address", "", "Address to show Ether balance")
+	etherBalanceCmd.Flags().BoolVar(&etherBalanceJSON, "json", false, "Display output as JSON (equivalent to --output=json)")
+	outputFlags(etherBalanceCmd)
+	etherBalanceCmd.Flags().StringSliceVar(&etherBalanceAddresses, "address", nil, "Address (or comma-separated addresses) to show Ether balance; can be repeated")
 	etherBalanceCmd.Flags().StringVar(&etherBalanceBlock, "block", "", "block hash or number at which to show Ether balance (must be run against an archive node)")
+	jsonoutCmds["ether:balance"] = true
 }
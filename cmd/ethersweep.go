@@ -14,8 +14,6 @@
 package cmd
 
 import (
-	"bytes"
-	"encoding/hex"
 	"fmt"
 	"math/big"
 	"os"
@@ -23,7 +21,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
-	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/ethereal/util"
 	string2eth "github.com/wealdtech/go-string2eth"
 )
 
@@ -41,11 +39,11 @@ var etherSweepCmd = &cobra.Command{
 This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(etherSweepFromAddress != "", quiet, "--from is required")
-		fromAddress, err := ens.Resolve(client, etherSweepFromAddress)
+		fromAddress, err := util.ResolveAddress(client, etherSweepFromAddress)
 		cli.ErrCheck(err, quiet, "Failed to obtain from address for sweep")
 
 		cli.Assert(etherSweepToAddress != "", quiet, "--to is required")
-		toAddress, err := ens.Resolve(client, etherSweepToAddress)
+		toAddress, err := util.ResolveAddress(client, etherSweepToAddress)
 		cli.ErrCheck(err, quiet, "Failed to obtain to address for sweep")
 
 		// Obtain the balance of the address
@@ -53,7 +51,7 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		defer cancel()
 		balance, err := client.BalanceAt(ctx, fromAddress, nil)
 		cli.ErrCheck(err, quiet, "Failed to obtain balance of address from which to send funds")
-		cli.Assert(balance.Cmp(big.NewInt(0)) > 0, quiet, fmt.Sprintf("Balance of %s is 0; nothing to sweep", ens.Format(client, fromAddress)))
+		cli.Assert(balance.Cmp(big.NewInt(0)) > 0, quiet, fmt.Sprintf("Balance of %s is 0; nothing to sweep", util.ENSFormat(client, fromAddress)))
 
 		// Obtain the amount of gas required to send the transaction, and calculate the amount to send
 		gas, err := estimateGas(fromAddress, &toAddress, balance, nil)
@@ -70,9 +68,7 @@ This will return an exit status of 0 if the transaction is successfully submitte
 
 		if offline {
 			if !quiet {
-				buf := new(bytes.Buffer)
-				signedTx.EncodeRLP(buf)
-				fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+				outputOfflineTransaction(signedTx)
 			}
 			os.Exit(_exit_success)
 		}
@@ -14,16 +14,18 @@
 package cmd
 
 import (
-	"bytes"
 	"encoding/hex"
 	"fmt"
+	"math/big"
 	"os"
 	"strings"
 
+	"github.com/ethereum/go-ethereum/common"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/wealdtech/ethereal/cli"
-	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/ethereal/util"
 	string2eth "github.com/wealdtech/go-string2eth"
 )
 
@@ -31,6 +33,7 @@ var etherTransferAmount string
 var etherTransferFromAddress string
 var etherTransferToAddress string
 var etherTransferData string
+var etherTransferDryRun bool
 
 // etherTransferCmd represents the ether transfer command
 var etherTransferCmd = &cobra.Command{
@@ -40,36 +43,57 @@ var etherTransferCmd = &cobra.Command{
 
     ethereal ether transfer --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --to=0x52f1A3027d3aA514F17E454C93ae1F79b3B12d5d --amount=1.5ether --passphrase=secret
 
-This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
+Data can be sent with the transfer using --data, for example to call a contract's payable fallback or receive function.  A warning is given if data is sent to an address that is not a contract.
+
+The --dryrun flag shows the estimated gas, gas price and fee for the transfer without sending it.  In this case the exit status is 0 if the balance of the sender is sufficient to cover the amount and fee, otherwise 1.  When offline the balance is not checked, and --gaslimit must be supplied.
+
+Otherwise this will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
 	Aliases: []string{"send"},
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(etherTransferFromAddress != "", quiet, "--from is required")
-		fromAddress, err := ens.Resolve(client, etherTransferFromAddress)
+		fromAddress, err := util.ResolveAddress(client, etherTransferFromAddress)
 		cli.ErrCheck(err, quiet, "Failed to obtain from address for transfer")
 
 		cli.Assert(etherTransferToAddress != "", quiet, "--to is required")
-		toAddress, err := ens.Resolve(client, etherTransferToAddress)
+		toAddress, err := util.ResolveAddress(client, etherTransferToAddress)
 		cli.ErrCheck(err, quiet, "Failed to obtain to address for transfer")
 
 		cli.Assert(etherTransferAmount != "", quiet, "--amount is required")
-		amount, err := string2eth.StringToWei(etherTransferAmount)
+		amount, err := parseAmount(etherTransferAmount)
 		cli.ErrCheck(err, quiet, "Invalid amount")
 
-		// Obtain the balance of the address
-		ctx, cancel := localContext()
-		defer cancel()
-		balance, err := client.BalanceAt(ctx, fromAddress, nil)
-		cli.ErrCheck(err, quiet, "Failed to obtain balance of address from which to send funds")
-		cli.Assert(balance.Cmp(amount) > 0, quiet, fmt.Sprintf("Balance of %s insufficient for transfer", string2eth.WeiToString(balance, true)))
-
-		// Turn the data string in to hex
-		etherTransferData = strings.TrimPrefix(etherTransferData, "0x")
-		if len(etherTransferData)%2 == 1 {
-			// Doesn't like odd numbers
-			etherTransferData = "0" + etherTransferData
+		var data []byte
+		if etherTransferData != "" {
+			data, err = hex.DecodeString(strings.TrimPrefix(etherTransferData, "0x"))
+			cli.ErrCheck(err, quiet, "Invalid data; it must be a hex string with an even number of characters")
+		}
+
+		var balance *big.Int
+		if !offline {
+			if len(data) > 0 {
+				ctx, cancel := localContext()
+				defer cancel()
+				code, err := client.CodeAt(ctx, toAddress, nil)
+				cli.ErrCheck(err, quiet, "Failed to obtain code of address to which to send funds")
+				if len(code) == 0 {
+					cli.Warn(quiet, fmt.Sprintf("%s is not a contract; the data will be recorded in the transaction but not acted upon", etherTransferToAddress))
+				}
+			}
+
+			// Obtain the balance of the address
+			ctx, cancel := localContext()
+			defer cancel()
+			balance, err = client.BalanceAt(ctx, fromAddress, nil)
+			cli.ErrCheck(err, quiet, "Failed to obtain balance of address from which to send funds")
+		}
+
+		if etherTransferDryRun {
+			etherTransferPreview(fromAddress, &toAddress, amount, data, balance)
+		}
+
+		if balance != nil {
+			cli.Assert(balance.Cmp(amount) > 0, quiet, fmt.Sprintf("Balance of %s insufficient for transfer", string2eth.WeiToString(balance, true)))
 		}
-		data, err := hex.DecodeString(etherTransferData)
-		cli.ErrCheck(err, quiet, "Failed to parse data")
 
 		// Create and sign the transaction
 		signedTx, err := createSignedTransaction(fromAddress, &toAddress, amount, gasLimit, data)
@@ -77,14 +101,12 @@ This will return an exit status of 0 if the transaction is successfully submitte
 
 		if offline {
 			if !quiet {
-				buf := new(bytes.Buffer)
-				signedTx.EncodeRLP(buf)
-				fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+				outputOfflineTransaction(signedTx)
 			}
 			os.Exit(_exit_success)
 		}
 
-		ctx, cancel = localContext()
+		ctx, cancel := localContext()
 		defer cancel()
 		err = client.SendTransaction(ctx, signedTx)
 		cli.ErrCheck(err, quiet, "Failed to send transaction")
@@ -96,11 +118,52 @@ This will return an exit status of 0 if the transaction is successfully submitte
 	},
 }
 
+// etherTransferPreview displays the expected cost of a transfer, then exits.
+// balance is nil when offline, in which case it is not checked.
+func etherTransferPreview(fromAddress common.Address, toAddress *common.Address, amount *big.Int, data []byte, balance *big.Int) {
+	gas := gasLimit
+	if gas == 0 {
+		cli.Assert(!offline, quiet, "--gaslimit is required for a dry run when offline")
+		var err error
+		gas, err = estimateGas(fromAddress, toAddress, amount, data)
+		cli.ErrCheck(err, quiet, "Failed to estimate gas")
+	}
+
+	price := gasPrice
+	if viper.GetString("gasprice") == "" && !offline {
+		// No explicit gas price so use the node's suggestion
+		ctx, cancel := localContext()
+		defer cancel()
+		var err error
+		price, err = client.SuggestGasPrice(ctx)
+		cli.ErrCheck(err, quiet, "Failed to obtain suggested gas price")
+	}
+
+	fee := new(big.Int).Mul(new(big.Int).SetUint64(gas), price)
+	total := new(big.Int).Add(amount, fee)
+	sufficient := balance == nil || balance.Cmp(total) >= 0
+
+	if !quiet {
+		fmt.Printf("Gas:\t\t%d\n", gas)
+		fmt.Printf("Gas price:\t%s\n", string2eth.WeiToString(price, true))
+		fmt.Printf("Fee:\t\t%s\n", string2eth.WeiToString(fee, true))
+		fmt.Printf("Total cost:\t%s\n", string2eth.WeiToString(total, true))
+		if !sufficient {
+			fmt.Printf("Balance of %s is insufficient to cover the total cost\n", string2eth.WeiToString(balance, true))
+		}
+	}
+	if sufficient {
+		os.Exit(_exit_success)
+	}
+	os.Exit(_exit_failure)
+}
+
 func init() {
 	etherCmd.AddCommand(etherTransferCmd)
-	etherTransferCmd.Flags().StringVar(&etherTransferAmount, "amount", "", "Amount of Ether to transfer")
+	etherTransferCmd.Flags().StringVar(&etherTransferAmount, "amount", "", "Amount of Ether to transfer, with a unit (e.g. \"1.5 ether\")")
 	etherTransferCmd.Flags().StringVar(&etherTransferFromAddress, "from", "", "Address from which to transfer Ether")
 	etherTransferCmd.Flags().StringVar(&etherTransferToAddress, "to", "", "Address to which to transfer Ether")
-	etherTransferCmd.Flags().StringVar(&etherTransferData, "data", "", "data to send with transaction (as a hex string)")
+	etherTransferCmd.Flags().StringVar(&etherTransferData, "data", "", "Data to send with the transfer (as a hex string)")
+	etherTransferCmd.Flags().BoolVar(&etherTransferDryRun, "dryrun", false, "Show the expected cost of the transfer without sending it")
 	addTransactionFlags(etherTransferCmd, "the address from which to transfer Ether")
 }
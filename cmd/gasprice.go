@@ -18,6 +18,7 @@ import (
 	"math/big"
 	"os"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -29,6 +30,7 @@ import (
 var gasPriceBlocks int64
 var gasPriceWei bool
 var gasPriceLowest bool
+var gasPriceOracle string
 var gas uint64
 
 // gasPriceCmd represents the gas price command
@@ -43,8 +45,18 @@ The per-block expected inclusion price is based on the average of the gas price
 
 If the optional --gas parameter is supplied the price will be based on a transaction with the given supplied gas rather than the 9th decile transactions.  This should provide a more accurate indication of gas price for transactions that require the supplied gas.
 
-In quiet mode this will return 0 if it can calculate a gas price, otherwise 1.`,
+Alternatively the gas price can be obtained from an oracle with the --oracle parameter.  "node" uses the price suggested by the connected node, "gasstation" uses ETH Gas Station, and any other value is treated as the URL of an ETH Gas Station-compatible endpoint.  Gas stations supply slow, standard and fast prices, for example:
+
+    ethereal gas price --oracle=gasstation
+
+When using an oracle the base fee of the latest block and the priority fee suggested by the node are also shown, on chains that support them.
+
+In quiet mode this will return 0 if it can calculate a gas price, otherwise 1.  When using an oracle it will always return 0.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if gasPriceOracle != "blocks" {
+			gasPriceFromOracle()
+			os.Exit(_exit_success)
+		}
 		cli.Assert(gasPriceBlocks > 0, quiet, "--blocks must be greater than 0")
 
 		lowestGasPrice := big.NewInt(0)
@@ -132,18 +144,71 @@ In quiet mode this will return 0 if it can calculate a gas price, otherwise 1.`,
 			os.Exit(_exit_success)
 		}
 
-		if gasPriceWei {
-			fmt.Printf("%s\n", finalGasPrice.String())
-		} else {
-			fmt.Printf("%s\n", string2eth.WeiToString(finalGasPrice, true))
-		}
+		fmt.Printf("%s\n", gasPriceString(finalGasPrice))
 	},
 }
 
+// gasPriceFromOracle outputs the gas price as suggested by the chosen oracle.
+func gasPriceFromOracle() {
+	if quiet {
+		return
+	}
+
+	switch gasPriceOracle {
+	case "node":
+		ctx, cancel := localContext()
+		defer cancel()
+		price, err := client.SuggestGasPrice(ctx)
+		cli.ErrCheck(err, quiet, "Failed to obtain gas price from node")
+		fmt.Printf("Gas price:\t%s\n", gasPriceString(price))
+	default:
+		endpoint := gasPriceOracle
+		if endpoint == "gasstation" {
+			endpoint = util.DefaultGasStationEndpoint
+		}
+		cli.Assert(strings.HasPrefix(endpoint, "http://") || strings.HasPrefix(endpoint, "https://"), quiet, fmt.Sprintf("Unknown oracle %s", gasPriceOracle))
+		tiers, err := util.GasStationPrices(endpoint)
+		cli.ErrCheck(err, quiet, "Failed to obtain gas price from gas station")
+		fmt.Printf("Slow:\t\t%s\n", gasPriceString(tiers.Slow))
+		fmt.Printf("Standard:\t%s\n", gasPriceString(tiers.Standard))
+		fmt.Printf("Fast:\t\t%s\n", gasPriceString(tiers.Fast))
+	}
+	gasPriceFees()
+}
+
+// gasPriceFees outputs the base fee of the latest block and the priority fee
+// suggested by the node.  Either is omitted if the node cannot supply it, for
+// example on chains that do not support EIP-1559.
+func gasPriceFees() {
+	ctx, cancel := localContext()
+	defer cancel()
+	summary, err := util.BlockSummaryFor(ctx, rpcClient, "latest")
+	if err != nil {
+		outputIf(verbose, fmt.Sprintf("Failed to obtain base fee: %v", err))
+	} else if summary.BaseFee != nil {
+		fmt.Printf("Base fee:\t%s\n", gasPriceString(summary.BaseFee.ToInt()))
+	}
+	fee, err := util.SuggestPriorityFee(ctx, rpcClient)
+	if err != nil {
+		outputIf(verbose, fmt.Sprintf("Failed to obtain priority fee: %v", err))
+	} else {
+		fmt.Printf("Priority fee:\t%s\n", gasPriceString(fee))
+	}
+}
+
+// gasPriceString formats a gas price according to the output options.
+func gasPriceString(price *big.Int) string {
+	if gasPriceWei {
+		return price.String()
+	}
+	return string2eth.WeiToString(price, true)
+}
+
 func init() {
 	gasCmd.AddCommand(gasPriceCmd)
 	gasPriceCmd.Flags().BoolVar(&gasPriceWei, "wei", false, "Display output in number of Wei")
 	gasPriceCmd.Flags().Int64Var(&gasPriceBlocks, "blocks", 5, "Number of blocks to go back to average gas price")
 	gasPriceCmd.Flags().Uint64Var(&gas, "gas", 0, "Provide gas price based on the amount of gas used by the transaction")
 	gasPriceCmd.Flags().BoolVar(&gasPriceLowest, "lowest", false, "Lowest inclusion price over the blocks")
+	gasPriceCmd.Flags().StringVar(&gasPriceOracle, "oracle", "blocks", "Source of the gas price: blocks, node, gasstation or the URL of a gas station")
 }
@@ -17,15 +17,11 @@ import (
 	"encoding/hex"
 	"fmt"
 	"os"
-	"strconv"
-	"strings"
 
-	bip32 "github.com/FactomProject/go-bip32"
-	bip39 "github.com/FactomProject/go-bip39"
-	bip44 "github.com/FactomProject/go-bip44"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 )
 
 var hdKeysPath string
@@ -44,32 +40,8 @@ In quiet mode this will return 0 if the keys were successfully obtained, otherwi
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(hdKeysSeed != "", quiet, "seed is required")
 
-		components := strings.Split(hdKeysPath, "/")
-		cli.Assert(len(components) == 6, quiet, "Invalid path")
-		path := make([]uint32, 4)
-		for i := 0; i < 4; i++ {
-			if strings.HasSuffix(components[i+2], "'") {
-				unhardened, err := strconv.ParseInt(components[i+2][:len(components[i+2])-1], 10, 0)
-				cli.ErrCheck(err, quiet, fmt.Sprintf("Invalid path component %v", components[i+2]))
-				path[i] = 0x80000000 + uint32(unhardened)
-			} else {
-				unhardened, err := strconv.ParseInt(components[i+2], 10, 0)
-				cli.ErrCheck(err, quiet, fmt.Sprintf("Invalid path component %v", components[i+2]))
-				path[i] = uint32(unhardened)
-			}
-		}
-
-		seed, err := bip39.NewSeedWithErrorChecking(hdKeysSeed, hdKeysSecret)
-		cli.ErrCheck(err, quiet, "Failed to obtain seed from passphrase")
-
-		masterKey, err := bip32.NewMasterKey(seed)
-		cli.ErrCheck(err, quiet, "Failed to obtain master key from seed")
-
-		childKey, err := bip44.NewKeyFromMasterKey(masterKey, path[0], path[1], path[2], path[3])
-		cli.ErrCheck(err, quiet, "Failed to obtain child key from master key")
-
-		key, err := crypto.ToECDSA(childKey.Key)
-		cli.ErrCheck(err, quiet, "Failed to obtain private key from master key")
+		key, err := util.MnemonicPrivateKey(hdKeysSeed, hdKeysSecret, hdKeysPath)
+		cli.ErrCheck(err, quiet, "Failed to obtain keys")
 
 		outputIf(!quiet, fmt.Sprintf("Private key:\t\t0x%032x", key.D))
 		outputIf(!quiet, fmt.Sprintf("Public key:\t\t0x%s", hex.EncodeToString(crypto.FromECDSAPub(&key.PublicKey))))
@@ -103,5 +75,5 @@ func init() {
 	hdCmd.AddCommand(hdKeysCmd)
 	hdKeysCmd.Flags().StringVar(&hdKeysSeed, "seed", "", "12- or 24-word BIP-39 seed phrase")
 	hdKeysCmd.Flags().StringVar(&hdKeysSecret, "secret", "", "optional secret to add to seed")
-	hdKeysCmd.Flags().StringVar(&hdKeysPath, "path", "", "path for keys (e.g. m/44'/60'/0'/0/0)")
+	hdKeysCmd.Flags().StringVar(&hdKeysPath, "path", util.DefaultHDPath, "path for keys")
 }
@@ -0,0 +1,388 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+)
+
+// jsonMetadata is added to all JSON output to allow consumers to detect
+// changes in format between versions.
+type jsonMetadata struct {
+	Version string `json:"version"`
+	Command string `json:"command"`
+}
+
+// jsonBlock is the JSON representation of a block.  Hash and Miner are
+// empty for pending blocks, and BaseFee is empty for blocks prior to
+// EIP-1559.
+type jsonBlock struct {
+	Number           uint64   `json:"number"`
+	Hash             string   `json:"hash,omitempty"`
+	ParentHash       string   `json:"parentHash"`
+	Timestamp        uint64   `json:"timestamp"`
+	Miner            string   `json:"miner,omitempty"`
+	GasLimit         uint64   `json:"gasLimit"`
+	GasUsed          uint64   `json:"gasUsed"`
+	BaseFee          string   `json:"baseFee,omitempty"`
+	TransactionCount int      `json:"transactionCount"`
+	Transactions     []string `json:"transactions,omitempty"`
+}
+
+// newJSONBlock creates the JSON representation of a block, optionally
+// including the hashes of its transactions.
+func newJSONBlock(summary *util.BlockSummary, transactions bool) *jsonBlock {
+	res := &jsonBlock{
+		Number:           summary.Number.ToInt().Uint64(),
+		ParentHash:       summary.ParentHash.Hex(),
+		Timestamp:        uint64(summary.Timestamp),
+		GasLimit:         uint64(summary.GasLimit),
+		GasUsed:          uint64(summary.GasUsed),
+		TransactionCount: len(summary.Transactions),
+	}
+	if summary.Hash != nil {
+		res.Hash = summary.Hash.Hex()
+	}
+	if summary.Miner != nil {
+		res.Miner = summary.Miner.Hex()
+	}
+	if summary.BaseFee != nil {
+		res.BaseFee = summary.BaseFee.ToInt().String()
+	}
+	if transactions {
+		res.Transactions = make([]string, len(summary.Transactions))
+		for i := range summary.Transactions {
+			res.Transactions[i] = summary.Transactions[i].Hex()
+		}
+	}
+	return res
+}
+
+// jsonNonce is the JSON representation of the confirmed and pending nonces
+// of an address.
+type jsonNonce struct {
+	Address      string `json:"address"`
+	Nonce        uint64 `json:"nonce"`
+	PendingNonce uint64 `json:"pendingNonce"`
+}
+
+// newJSONNonce creates the JSON representation of the nonces of an address.
+func newJSONNonce(address common.Address, nonce uint64, pendingNonce uint64) *jsonNonce {
+	return &jsonNonce{
+		Address:      address.Hex(),
+		Nonce:        nonce,
+		PendingNonce: pendingNonce,
+	}
+}
+
+// jsonTokenBalance is the JSON representation of the token balance of an
+// address.  Balance is in the token's base unit, and BalanceTokens is scaled
+// by the token's decimals.
+type jsonTokenBalance struct {
+	Token         string `json:"token"`
+	Symbol        string `json:"symbol"`
+	Decimals      uint8  `json:"decimals"`
+	Holder        string `json:"holder"`
+	Balance       string `json:"balance"`
+	BalanceTokens string `json:"balanceTokens"`
+}
+
+// newJSONTokenBalance creates the JSON representation of a token balance.
+func newJSONTokenBalance(token common.Address, symbol string, decimals uint8, holder common.Address, balance *big.Int) *jsonTokenBalance {
+	return &jsonTokenBalance{
+		Token:         token.Hex(),
+		Symbol:        symbol,
+		Decimals:      decimals,
+		Holder:        holder.Hex(),
+		Balance:       balance.String(),
+		BalanceTokens: util.TokenValueToString(balance, decimals, false),
+	}
+}
+
+// jsonTokenAllowance is the JSON representation of the amount of a token
+// that a spender can transfer on behalf of a holder.  Allowance is in the
+// token's base unit, and AllowanceTokens is scaled by the token's decimals.
+type jsonTokenAllowance struct {
+	Token           string `json:"token"`
+	Symbol          string `json:"symbol"`
+	Decimals        uint8  `json:"decimals"`
+	Holder          string `json:"holder"`
+	Spender         string `json:"spender"`
+	Allowance       string `json:"allowance"`
+	AllowanceTokens string `json:"allowanceTokens"`
+}
+
+// newJSONTokenAllowance creates the JSON representation of a token allowance.
+func newJSONTokenAllowance(token common.Address, symbol string, decimals uint8, holder common.Address, spender common.Address, allowance *big.Int) *jsonTokenAllowance {
+	return &jsonTokenAllowance{
+		Token:           token.Hex(),
+		Symbol:          symbol,
+		Decimals:        decimals,
+		Holder:          holder.Hex(),
+		Spender:         spender.Hex(),
+		Allowance:       allowance.String(),
+		AllowanceTokens: util.TokenValueToString(allowance, decimals, false),
+	}
+}
+
+// jsonSignature is the JSON representation of a signature, with V being the
+// recovery ID offset by 27 as expected by ecrecover().
+type jsonSignature struct {
+	R string `json:"r"`
+	S string `json:"s"`
+	V uint8  `json:"v"`
+}
+
+// newJSONSignature creates the JSON representation of a 65-byte signature
+// with a recovery ID of 0 or 1.
+func newJSONSignature(signature []byte) *jsonSignature {
+	return &jsonSignature{
+		R: fmt.Sprintf("0x%x", signature[0:32]),
+		S: fmt.Sprintf("0x%x", signature[32:64]),
+		V: signature[64] + 27,
+	}
+}
+
+// jsonTransaction is the JSON representation of a signed transaction.  Type
+// is the EIP-2718 transaction type; only legacy (type 0) transactions are
+// supported.  ChainID is empty for transactions that are not
+// replay-protected.
+type jsonTransaction struct {
+	Type     uint8  `json:"type"`
+	ChainID  string `json:"chainId,omitempty"`
+	From     string `json:"from"`
+	To       string `json:"to,omitempty"`
+	Value    string `json:"value"`
+	Nonce    uint64 `json:"nonce"`
+	Gas      uint64 `json:"gas"`
+	GasPrice string `json:"gasPrice"`
+	Data     string `json:"data"`
+	V        string `json:"v"`
+	R        string `json:"r"`
+	S        string `json:"s"`
+	Hash     string `json:"hash"`
+}
+
+// legacyTransactionRLP is the RLP layout of a signed legacy transaction.
+type legacyTransactionRLP struct {
+	Nonce    uint64
+	GasPrice *big.Int
+	Gas      uint64
+	To       *common.Address `rlp:"nil"`
+	Value    *big.Int
+	Data     []byte
+	V        *big.Int
+	R        *big.Int
+	S        *big.Int
+}
+
+// newJSONTransaction creates the JSON representation of a signed transaction.
+func newJSONTransaction(tx *types.Transaction) (*jsonTransaction, error) {
+	v, r, s := tx.RawSignatureValues()
+	from, err := types.Sender(deriveSigner(v), tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain sender: %v", err)
+	}
+	res := &jsonTransaction{
+		From:     from.Hex(),
+		Value:    tx.Value().String(),
+		Nonce:    tx.Nonce(),
+		Gas:      tx.Gas(),
+		GasPrice: tx.GasPrice().String(),
+		Data:     fmt.Sprintf("0x%x", tx.Data()),
+		V:        fmt.Sprintf("%#x", v),
+		R:        fmt.Sprintf("%#x", r),
+		S:        fmt.Sprintf("%#x", s),
+		Hash:     tx.Hash().Hex(),
+	}
+	if tx.Protected() {
+		res.ChainID = tx.ChainId().String()
+	}
+	if tx.To() != nil {
+		res.To = tx.To().Hex()
+	}
+	return res, nil
+}
+
+// transaction recreates the signed transaction from its JSON representation.
+// The hash and sender must match those of the recreated transaction, to
+// catch fields that have been altered after signing.
+func (j *jsonTransaction) transaction() (*types.Transaction, error) {
+	if j.Type != 0 {
+		return nil, fmt.Errorf("unsupported transaction type %d", j.Type)
+	}
+	fields := &legacyTransactionRLP{
+		Nonce: j.Nonce,
+		Gas:   j.Gas,
+	}
+	var ok bool
+	bigFields := []struct {
+		name  string
+		input string
+		base  int
+		dst   **big.Int
+	}{
+		{"gasPrice", j.GasPrice, 10, &fields.GasPrice},
+		{"value", j.Value, 10, &fields.Value},
+		{"v", strings.TrimPrefix(j.V, "0x"), 16, &fields.V},
+		{"r", strings.TrimPrefix(j.R, "0x"), 16, &fields.R},
+		{"s", strings.TrimPrefix(j.S, "0x"), 16, &fields.S},
+	}
+	for _, field := range bigFields {
+		*field.dst, ok = new(big.Int).SetString(field.input, field.base)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s %q", field.name, field.input)
+		}
+	}
+	if j.To != "" {
+		to, err := util.HexAddress(j.To)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to %q: %v", j.To, err)
+		}
+		fields.To = &to
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(j.Data, "0x"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid data: %v", err)
+	}
+	fields.Data = data
+
+	encoded, err := rlp.EncodeToBytes(fields)
+	if err != nil {
+		return nil, err
+	}
+	tx := &types.Transaction{}
+	if err := rlp.DecodeBytes(encoded, tx); err != nil {
+		return nil, err
+	}
+
+	if j.Hash != "" && tx.Hash() != common.HexToHash(j.Hash) {
+		return nil, fmt.Errorf("hash %s does not match transaction hash %s", j.Hash, tx.Hash().Hex())
+	}
+	from, err := types.Sender(deriveSigner(fields.V), tx)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %v", err)
+	}
+	if j.From != "" {
+		expected, err := util.HexAddress(j.From)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from %q: %v", j.From, err)
+		}
+		if from != expected {
+			return nil, fmt.Errorf("from %s does not match transaction signer %s", j.From, from.Hex())
+		}
+	}
+	return tx, nil
+}
+
+// outputJSON outputs the supplied JSON object with an additional top-level
+// "ethereal" field containing metadata about the output.  data can be either
+// a structure to be marshalled or pre-marshalled JSON.  JSON arrays are output
+// in the "results" field of an object, so that all output has the same shape.
+// The output is also recorded in the file supplied in --jsonout, if any.
+func outputJSON(cmd *cobra.Command, data interface{}) error {
+	output, err := jsonEnvelope(cmd, data)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s\n", string(output))
+	cli.WarnCheck(recordJSON(cmd, data), quiet, "Failed to record JSON output")
+	return nil
+}
+
+// recordJSON appends the supplied JSON object, with metadata, to the file
+// supplied in --jsonout.  This allows a machine-readable record of results to
+// be kept alongside the regular output.  If --jsonout is not set this does
+// nothing.
+//
+// Commands that output JSON record it through outputJSON; commands that
+// output text should call this with the equivalent JSON, and be listed in
+// jsonoutCmds so that --jsonout is accepted.
+func recordJSON(cmd *cobra.Command, data interface{}) error {
+	if viper.GetString("jsonout") == "" || cmd == nil {
+		return nil
+	}
+	output, err := jsonEnvelope(cmd, data)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(viper.GetString("jsonout"), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(output, '\n'))
+	return err
+}
+
+// jsonEnvelope adds metadata to the supplied JSON object.
+func jsonEnvelope(cmd *cobra.Command, data interface{}) ([]byte, error) {
+	var raw []byte
+	switch v := data.(type) {
+	case []byte:
+		raw = v
+	case json.RawMessage:
+		raw = v
+	default:
+		var err error
+		raw, err = json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+		// Arrays are held in an object so that they can carry the metadata.
+		raw = append(append([]byte(`{"results":`), trimmed...), '}')
+	}
+
+	raw = bytes.TrimSpace(raw)
+	if len(raw) < 2 || raw[0] != '{' || !json.Valid(raw) {
+		return nil, errors.New("JSON output is not an object")
+	}
+	metadata, err := json.Marshal(&jsonMetadata{
+		Version: ReleaseVersion,
+		Command: strings.Replace(cmdPath(cmd), ":", " ", -1),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Add the metadata as the final field of the object.  This is done
+	// directly rather than through a map to keep the order of the fields.
+	output := make([]byte, 0, len(raw)+len(metadata)+12)
+	output = append(output, bytes.TrimSpace(raw[:len(raw)-1])...)
+	if len(output) > 1 {
+		output = append(output, ',')
+	}
+	output = append(output, []byte(`"ethereal":`)...)
+	output = append(output, metadata...)
+	output = append(output, '}')
+	return output, nil
+}
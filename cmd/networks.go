@@ -0,0 +1,130 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util/output"
+)
+
+var networksJSON bool
+
+// network is a network known to Ethereal.
+type network struct {
+	Name       string
+	ChainID    int64
+	Connection string
+}
+
+const infuraProjectID = "831a5442dc2e4536a9f8dee4ea1707a6"
+
+// knownNetworks are the networks that can be selected with --network.
+// Networks without a connection require --connection to access them.
+var knownNetworks = []*network{
+	{Name: "mainnet", ChainID: 1, Connection: "https://mainnet.infura.io/v3/" + infuraProjectID},
+	{Name: "optimism", ChainID: 10, Connection: "https://optimism-mainnet.infura.io/v3/" + infuraProjectID},
+	{Name: "gnosis", ChainID: 100},
+	{Name: "polygon", ChainID: 137, Connection: "https://polygon-mainnet.infura.io/v3/" + infuraProjectID},
+	{Name: "base", ChainID: 8453},
+	{Name: "holesky", ChainID: 17000, Connection: "https://holesky.infura.io/v3/" + infuraProjectID},
+	{Name: "arbitrum", ChainID: 42161, Connection: "https://arbitrum-mainnet.infura.io/v3/" + infuraProjectID},
+	{Name: "sepolia", ChainID: 11155111, Connection: "https://sepolia.infura.io/v3/" + infuraProjectID},
+}
+
+// networkByName returns the known network with the given name, or nil if
+// there is no such network.
+func networkByName(name string) *network {
+	name = strings.ToLower(name)
+	for _, network := range knownNetworks {
+		if network.Name == name {
+			return network
+		}
+	}
+	return nil
+}
+
+// networksCmd represents the networks command
+var networksCmd = &cobra.Command{
+	Use:   "networks",
+	Short: "List known networks",
+	Long: `List the networks known to Ethereal, with their chain IDs and default connections.  For example:
+
+    ethereal networks
+
+The network to which the current connection belongs is marked with an asterisk.  With --offline the table is listed without contacting a node.
+
+The --output flag selects the format of the output: text (the default), json or csv.  --json is equivalent to --output=json.
+
+In quiet mode this will return 0 if the current connection belongs to a known network, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if networksJSON {
+			outputFormat = output.JSON
+		}
+		formatter, err := output.New(outputFormat)
+		cli.ErrCheck(err, quiet, "Invalid output format")
+
+		found := false
+		records := make([]output.Record, len(knownNetworks))
+		for i, network := range knownNetworks {
+			current := !offline && chainID != nil && chainID.IsInt64() && chainID.Int64() == network.ChainID
+			if current {
+				found = true
+			}
+			records[i] = networksRecord(network, current)
+		}
+		if quiet {
+			if found {
+				os.Exit(_exit_success)
+			}
+			os.Exit(_exit_failure)
+		}
+
+		cli.ErrCheck(outputRecords(cmd, formatter, records...), quiet, "Failed to output networks")
+		if !offline && !found {
+			outputIf(verbose, fmt.Sprintf("Connection is to unknown chain ID %v", chainID))
+		}
+		os.Exit(_exit_success)
+	},
+}
+
+// networksRecord creates the output record for a network.  Networks without
+// a default connection have no connection field.
+func networksRecord(network *network, current bool) output.Record {
+	marker := " "
+	if current {
+		marker = "*"
+	}
+	var connection interface{}
+	if network.Connection != "" {
+		connection = network.Connection
+	}
+	return output.Record{
+		{Name: "current", Value: current, Text: marker},
+		{Name: "name", Value: network.Name},
+		{Name: "chainId", Value: network.ChainID},
+		{Name: "connection", Value: connection},
+	}
+}
+
+func init() {
+	RootCmd.AddCommand(networksCmd)
+	networksCmd.Flags().BoolVar(&networksJSON, "json", false, "Display output as JSON (equivalent to --output=json)")
+	outputFlags(networksCmd)
+	jsonoutCmds["networks"] = true
+}
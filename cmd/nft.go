@@ -0,0 +1,60 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/util/funcparser"
+)
+
+var nftTokenStr string
+var nftIDStr string
+
+// nftCmd represents the nft command
+var nftCmd = &cobra.Command{
+	Use:   "nft",
+	Short: "Manage non-fungible tokens",
+	Long:  `Obtain information about and transfer ERC-721 non-fungible tokens.`,
+}
+
+// nftTokenID parses a token ID.  Token IDs are uint256 so can be larger
+// than will fit in an int64.
+func nftTokenID(input string) (*big.Int, error) {
+	idType, err := abi.NewType("uint256", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	id, err := funcparser.StrToUint(&idType, input)
+	if err != nil {
+		return nil, err
+	}
+	res, isBigInt := id.(*big.Int)
+	if !isBigInt {
+		return nil, fmt.Errorf("unexpected type %T for token ID", id)
+	}
+	return res, nil
+}
+
+func init() {
+	RootCmd.AddCommand(nftCmd)
+}
+
+func nftFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&nftTokenStr, "token", "", "Name or address of the token contract")
+	cmd.Flags().StringVar(&nftIDStr, "id", "", "ID of the token")
+}
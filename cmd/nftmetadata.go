@@ -0,0 +1,99 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	"github.com/wealdtech/ethereal/util/contracts"
+)
+
+var nftMetadataFetch bool
+var nftMetadataIPFSGateway string
+var nftMetadataArweaveGateway string
+
+// nftMetadataCmd represents the nft metadata command
+var nftMetadataCmd = &cobra.Command{
+	Use:   "metadata",
+	Short: "Obtain the metadata of a non-fungible token",
+	Long: `Obtain the metadata of an ERC-721 non-fungible token from its token URI.  For example:
+
+    ethereal nft metadata --token=0x06012c8cf97BEaD5deAe237070F9587f8E7A266d --id=1 --fetch
+
+Metadata held in data: URIs is decoded directly.  Metadata held elsewhere is only fetched if --fetch is supplied, otherwise the token URI is printed.  ipfs:// and ar:// URIs are fetched through gateways, which can be changed with --ipfsgateway and --arweavegateway.  An {id} placeholder in the token URI is replaced with the token ID as 64 hex characters.
+
+In quiet mode this will return 0 if the token URI is obtained (and the metadata fetched if --fetch is supplied), otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
+
+		cli.Assert(nftTokenStr != "", quiet, "--token is required")
+		tokenAddress, err := util.ResolveAddress(client, nftTokenStr)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve token address %s", nftTokenStr))
+		token, err := contracts.NewERC721(tokenAddress, client)
+		cli.ErrCheck(err, quiet, "Failed to obtain token contract")
+
+		cli.Assert(nftIDStr != "", quiet, "--id is required")
+		id, err := nftTokenID(nftIDStr)
+		cli.ErrCheck(err, quiet, "Invalid token ID")
+
+		uri, err := token.TokenURI(nil, id)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain URI of token %s", id))
+		cli.Assert(uri != "", quiet, fmt.Sprintf("Token %s has no URI", id))
+		uri = util.SubstituteTokenID(uri, id)
+
+		if !strings.HasPrefix(uri, "data:") && !nftMetadataFetch {
+			outputIf(!quiet, uri)
+			os.Exit(_exit_success)
+		}
+
+		if nftMetadataIPFSGateway != "" {
+			viper.Set("ipfsgateway", nftMetadataIPFSGateway)
+		}
+		if nftMetadataArweaveGateway != "" {
+			viper.Set("arweavegateway", nftMetadataArweaveGateway)
+		}
+		outputIf(verbose, fmt.Sprintf("Token URI is %s", uri))
+		metadata, err := util.FetchTokenURI(uri)
+		cli.ErrCheck(err, quiet, "Failed to obtain metadata")
+
+		if quiet {
+			os.Exit(_exit_success)
+		}
+
+		buf := new(bytes.Buffer)
+		if err := json.Indent(buf, bytes.TrimSpace(metadata), "", "  "); err != nil {
+			// Not JSON, so print as-is
+			cli.Warn(quiet, "Metadata is not JSON")
+			fmt.Println(string(metadata))
+			os.Exit(_exit_success)
+		}
+		fmt.Println(buf.String())
+	},
+}
+
+func init() {
+	nftCmd.AddCommand(nftMetadataCmd)
+	nftFlags(nftMetadataCmd)
+	nftMetadataCmd.Flags().BoolVar(&nftMetadataFetch, "fetch", false, "Fetch metadata from the network rather than printing the token URI")
+	nftMetadataCmd.Flags().StringVar(&nftMetadataIPFSGateway, "ipfsgateway", "", "Gateway through which to fetch ipfs:// URIs (defaults to https://ipfs.io/ipfs/)")
+	nftMetadataCmd.Flags().StringVar(&nftMetadataArweaveGateway, "arweavegateway", "", "Gateway through which to fetch ar:// URIs (defaults to https://arweave.net/)")
+}
@@ -0,0 +1,64 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	"github.com/wealdtech/ethereal/util/contracts"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+// nftOwnerCmd represents the nft owner command
+var nftOwnerCmd = &cobra.Command{
+	Use:   "owner",
+	Short: "Obtain the owner of a non-fungible token",
+	Long: `Obtain the owner of an ERC-721 non-fungible token.  For example:
+
+    ethereal nft owner --token=0x06012c8cf97BEaD5deAe237070F9587f8E7A266d --id=1
+
+The owner is shown as an ENS name if it has reverse resolution set, otherwise as an address.
+
+In quiet mode this will return 0 if the token has an owner, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(nftTokenStr != "", quiet, "--token is required")
+		tokenAddress, err := util.ResolveAddress(client, nftTokenStr)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve token address %s", nftTokenStr))
+		token, err := contracts.NewERC721(tokenAddress, client)
+		cli.ErrCheck(err, quiet, "Failed to obtain token contract")
+
+		cli.Assert(nftIDStr != "", quiet, "--id is required")
+		id, err := nftTokenID(nftIDStr)
+		cli.ErrCheck(err, quiet, "Invalid token ID")
+
+		owner, err := token.OwnerOf(nil, id)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain owner of token %s", id))
+		if owner == ens.UnknownAddress {
+			outputIf(!quiet, "Token has no owner")
+			os.Exit(_exit_failure)
+		}
+
+		outputIf(!quiet, util.ENSFormat(client, owner))
+		os.Exit(_exit_success)
+	},
+}
+
+func init() {
+	nftCmd.AddCommand(nftOwnerCmd)
+	nftFlags(nftOwnerCmd)
+}
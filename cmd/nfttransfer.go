@@ -0,0 +1,130 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	"github.com/wealdtech/ethereal/util/contracts"
+	"github.com/wealdtech/ethereal/util/funcparser"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+var nftTransferFromAddress string
+var nftTransferToAddress string
+
+// nftTransferCmd represents the nft transfer command
+var nftTransferCmd = &cobra.Command{
+	Use:   "transfer",
+	Short: "Transfer a non-fungible token to a given address",
+	Long: `Transfer an ERC-721 non-fungible token to another address using safeTransferFrom.  For example:
+
+    ethereal nft transfer --token=0x06012c8cf97BEaD5deAe237070F9587f8E7A266d --id=1 --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --to=0x52f1A3027d3aA514F17E454C93ae1F79b3B12d5d --passphrase=secret
+
+The transaction is signed by the address supplied in --from, which must be the owner of the token, approved for the token, or an approved operator for the owner.  This is checked prior to sending the transaction.  If --offline is supplied then the address supplied in --from is assumed to be the owner of the token.
+
+If --showcalldata is supplied then the hex-encoded calldata for the transfer is output prior to the transaction hash.
+
+This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(nftTransferFromAddress != "", quiet, "--from is required")
+		fromAddress, err := util.ResolveAddress(client, nftTransferFromAddress)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", nftTransferFromAddress))
+
+		cli.Assert(nftTransferToAddress != "", quiet, "--to is required")
+		toAddress, err := util.ResolveAddress(client, nftTransferToAddress)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve to address %s", nftTransferToAddress))
+
+		cli.Assert(nftTokenStr != "", quiet, "--token is required")
+		tokenAddress, err := util.ResolveAddress(client, nftTokenStr)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve token address %s", nftTokenStr))
+
+		cli.Assert(nftIDStr != "", quiet, "--id is required")
+		id, err := nftTokenID(nftIDStr)
+		cli.ErrCheck(err, quiet, "Invalid token ID")
+
+		owner := fromAddress
+		if offline {
+			cli.Assert(gasLimit != 0, quiet, "--gaslimit is required if offline")
+		} else {
+			token, err := contracts.NewERC721(tokenAddress, client)
+			cli.ErrCheck(err, quiet, "Failed to obtain token contract")
+			owner, err = token.OwnerOf(nil, id)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain owner of token %s", id))
+			cli.Assert(owner != ens.UnknownAddress, quiet, fmt.Sprintf("Token %s has no owner", id))
+			if owner != fromAddress {
+				approved, err := token.GetApproved(nil, id)
+				cli.ErrCheck(err, quiet, "Failed to obtain approved address for token")
+				if approved != fromAddress {
+					operator, err := token.IsApprovedForAll(nil, owner, fromAddress)
+					cli.ErrCheck(err, quiet, "Failed to obtain operator approval for token")
+					cli.Assert(operator, quiet, fmt.Sprintf("%s is not the owner of token %s nor approved to transfer it; the owner is %s", util.ENSFormat(client, fromAddress), id, util.ENSFormat(client, owner)))
+				}
+				outputIf(verbose, fmt.Sprintf("Transferring on behalf of owner %s", util.ENSFormat(client, owner)))
+			}
+		}
+
+		tokenABI, err := abi.JSON(strings.NewReader(contracts.ERC721ABI))
+		cli.ErrCheck(err, quiet, "Failed to parse token ABI")
+		method := tokenABI.Methods["safeTransferFrom"]
+		methodArgs := []interface{}{owner, toAddress, id}
+		outputIf(verbose, fmt.Sprintf("Method is %s", method.Sig))
+		outputIf(verbose, fmt.Sprintf("About to call %s", funcparser.FormatCall(&method, methodArgs)))
+		data, err := tokenABI.Pack(method.Name, methodArgs...)
+		cli.ErrCheck(err, quiet, "Failed to convert arguments")
+		outputCalldata(data)
+
+		signedTx, err := createSignedTransaction(fromAddress, &tokenAddress, big.NewInt(0), gasLimit, data)
+		cli.ErrCheck(err, quiet, "Failed to create transaction")
+
+		if offline {
+			if !quiet {
+				outputOfflineTransaction(signedTx)
+			}
+			os.Exit(_exit_success)
+		}
+
+		ctx, cancel := localContext()
+		defer cancel()
+		err = client.SendTransaction(ctx, signedTx)
+		cli.ErrCheck(err, quiet, "Failed to send transaction")
+
+		handleSubmittedTransaction(signedTx, log.Fields{
+			"group":          "nft",
+			"command":        "transfer",
+			"token":          tokenAddress.Hex(),
+			"tokenid":        id.String(),
+			"tokenholder":    owner.Hex(),
+			"tokensender":    fromAddress.Hex(),
+			"tokenrecipient": toAddress.Hex(),
+		}, true)
+	},
+}
+
+func init() {
+	nftCmd.AddCommand(nftTransferCmd)
+	nftFlags(nftTransferCmd)
+	nftTransferCmd.Flags().StringVar(&nftTransferFromAddress, "from", "", "Address from which to transfer the token")
+	nftTransferCmd.Flags().StringVar(&nftTransferToAddress, "to", "", "Address to which to transfer the token")
+	addTransactionFlags(nftTransferCmd, "the address from which to transfer the token")
+	addShowCalldataFlag(nftTransferCmd, "calldata for the transfer")
+}
@@ -0,0 +1,51 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util/output"
+)
+
+var outputFormat string
+
+// outputFlags adds the --output flag to commands that write their results
+// through a formatter.
+func outputFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&outputFormat, "output", output.Text, "Output format (text, json or csv)")
+}
+
+// outputRecords writes records with the given formatter.  JSON output has
+// the same metadata as other JSON output.  Regardless of the format, the
+// records are recorded as JSON in the file supplied in --jsonout, if any.
+func outputRecords(cmd *cobra.Command, formatter *output.Formatter, records ...output.Record) error {
+	jsonFormatter, err := output.New(output.JSON)
+	if err != nil {
+		return err
+	}
+	jsonFormatter.SetArray(formatter.Array())
+	buf := new(bytes.Buffer)
+	if err := jsonFormatter.Write(buf, records...); err != nil {
+		return err
+	}
+	if formatter.Format() == output.JSON {
+		return outputJSON(cmd, buf.Bytes())
+	}
+	cli.WarnCheck(recordJSON(cmd, buf.Bytes()), quiet, "Failed to record JSON output")
+	return formatter.Write(os.Stdout, records...)
+}
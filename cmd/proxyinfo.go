@@ -0,0 +1,142 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+var proxyInfoHistory bool
+var proxyInfoFromBlock int64
+var proxyInfoSince time.Duration
+var proxyInfoChunkSize uint64
+
+var (
+	// EIP-1967 storage slots
+	proxyImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+	proxyAdminSlot          = common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103")
+	proxyBeaconSlot         = common.HexToHash("0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50")
+	// Upgraded(address) event topic
+	proxyUpgradedTopic = common.HexToHash("0xbc7cd75a20ee27fd9adebab32041f755214dbc6bffa90cc0225b39da2e5c2d3b")
+	// implementation() function selector, used for beacons
+	proxyImplementationSelector = []byte{0x5c, 0x60, 0xda, 0x1b}
+)
+
+// proxyInfoCmd represents the proxy info command
+var proxyInfoCmd = &cobra.Command{
+	Use:   "info",
+	Short: "Obtain information about a proxy contract",
+	Long: `Obtain information about an EIP-1967 proxy contract.  For example:
+
+    ethereal proxy info --contract=0x5FfC014343cd971B7eb70732021E26C35B744cc4
+
+This will show the implementation, admin and beacon of the proxy where available.  If --history is supplied the previous implementations of the proxy will also be shown; the search can be limited with --fromblock, or with a period of time such as --since=720h.  The history is queried in chunks of --chunksize blocks, to stay within the limits that nodes place on the number of logs returned by a single query.
+
+In quiet mode this will return 0 if the contract is a proxy, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(proxyStr != "", quiet, "--contract is required")
+		cli.Assert(proxyInfoFromBlock == 0 || proxyInfoSince == 0, quiet, "only one of --fromblock and --since can be supplied")
+		cli.Assert(proxyInfoHistory || (proxyInfoFromBlock == 0 && proxyInfoSince == 0), quiet, "--fromblock and --since require --history")
+		cli.Assert(proxyInfoChunkSize > 0, quiet, "--chunksize must be greater than 0")
+		proxyAddress, err := util.ResolveAddress(client, proxyStr)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve contract address %s", proxyStr))
+
+		implementation := proxyInfoSlotAddress(proxyAddress, proxyImplementationSlot)
+		admin := proxyInfoSlotAddress(proxyAddress, proxyAdminSlot)
+		beacon := proxyInfoSlotAddress(proxyAddress, proxyBeaconSlot)
+		if beacon != ens.UnknownAddress {
+			// Implementation is obtained from the beacon
+			ctx, cancel := localContext()
+			defer cancel()
+			res, err := client.CallContract(ctx, ethereum.CallMsg{To: &beacon, Data: proxyImplementationSelector}, nil)
+			cli.ErrCheck(err, quiet, "Failed to obtain implementation from beacon")
+			cli.Assert(len(res) == 32, quiet, "Invalid implementation returned from beacon")
+			implementation = common.BytesToAddress(res)
+		}
+
+		if quiet {
+			if implementation == ens.UnknownAddress {
+				os.Exit(_exit_failure)
+			}
+			os.Exit(_exit_success)
+		}
+
+		if implementation == ens.UnknownAddress {
+			fmt.Println("Not an EIP-1967 proxy")
+			os.Exit(_exit_failure)
+		}
+		fmt.Printf("Implementation:\t%s\n", util.ENSFormat(client, implementation))
+		if admin != ens.UnknownAddress {
+			fmt.Printf("Admin:\t\t%s\n", util.ENSFormat(client, admin))
+		}
+		if beacon != ens.UnknownAddress {
+			fmt.Printf("Beacon:\t\t%s\n", util.ENSFormat(client, beacon))
+		}
+
+		if proxyInfoHistory {
+			toBlock := latestHeader().Number.Uint64()
+			fromBlock := uint64(proxyInfoFromBlock)
+			if proxyInfoSince > 0 {
+				fromBlock = sinceBlock(proxyInfoSince)
+			}
+			cli.Assert(fromBlock <= toBlock, quiet, "--fromblock must not be after the latest block")
+			fmt.Println("History:")
+			for _, blocks := range util.BlockRanges(fromBlock, toBlock, proxyInfoChunkSize) {
+				ctx, cancel := localContext()
+				logs, err := client.FilterLogs(ctx, ethereum.FilterQuery{
+					FromBlock: new(big.Int).SetUint64(blocks.From),
+					ToBlock:   new(big.Int).SetUint64(blocks.To),
+					Addresses: []common.Address{proxyAddress},
+					Topics:    [][]common.Hash{{proxyUpgradedTopic}},
+				})
+				cancel()
+				cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain upgrade history for blocks %d to %d; try a lower --chunksize", blocks.From, blocks.To))
+				for _, log := range logs {
+					if log.Removed || len(log.Topics) < 2 {
+						continue
+					}
+					fmt.Printf("\tBlock %d:\t%s\n", log.BlockNumber, util.ENSFormat(client, common.BytesToAddress(log.Topics[1].Bytes())))
+				}
+			}
+		}
+	},
+}
+
+// proxyInfoSlotAddress obtains an address from a storage slot of the proxy.
+func proxyInfoSlotAddress(proxyAddress common.Address, slot common.Hash) common.Address {
+	ctx, cancel := localContext()
+	defer cancel()
+	value, err := client.StorageAt(ctx, proxyAddress, slot, nil)
+	cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain storage for contract %s", proxyStr))
+	return common.BytesToAddress(value)
+}
+
+func init() {
+	proxyCmd.AddCommand(proxyInfoCmd)
+	proxyFlags(proxyInfoCmd)
+	proxyInfoCmd.Flags().BoolVar(&proxyInfoHistory, "history", false, "Show the history of upgrades to the proxy")
+	proxyInfoCmd.Flags().Int64Var(&proxyInfoFromBlock, "fromblock", 0, "Block from which to search for upgrades when showing history")
+	proxyInfoCmd.Flags().DurationVar(&proxyInfoSince, "since", 0, "Time before now from which to search for upgrades when showing history, for example 720h (instead of --fromblock)")
+	proxyInfoCmd.Flags().Uint64Var(&proxyInfoChunkSize, "chunksize", 10000, "Maximum number of blocks to query for upgrades at a time")
+}
@@ -14,9 +14,17 @@
 package cmd
 
 import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/spf13/cobra"
 )
 
+// erc1820RegistryAddress is the well-known address of the ERC-1820 registry on all networks
+var erc1820RegistryAddress = common.HexToAddress("0x1820a4B7618BdE71Dce8cdc73aAB6C95905faD24")
+
 var registryImplementerInterface string
 var registryImplementerAddressStr string
 
@@ -35,3 +43,21 @@ func registryImplementerFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&registryImplementerInterface, "interface", "", "interface against which to operate (e.g. ERC777TokensRecipient)")
 	cmd.Flags().StringVar(&registryImplementerAddressStr, "address", "", "address against which to operate (e.g. wealdtech.eth)")
 }
+
+// registryImplementerInterfaceHash obtains the ERC-1820 interface hash for an
+// interface.  The interface can be a 32-byte hash, an ERC-165 interface ID, or
+// the name of an interface (e.g. ERC777TokensRecipient) in which case the hash
+// of the name is used.
+func registryImplementerInterfaceHash(iface string) [32]byte {
+	var hash [32]byte
+	if strings.HasPrefix(iface, "0x") && (len(iface) == 66 || len(iface) == 10) {
+		bytes, err := hex.DecodeString(iface[2:])
+		if err == nil {
+			// ERC-165 interface IDs are left-aligned and zero-padded
+			copy(hash[:], bytes)
+			return hash
+		}
+	}
+	copy(hash[:], crypto.Keccak256([]byte(iface)))
+	return hash
+}
@@ -17,6 +17,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	ens "github.com/wealdtech/go-ens/v3"
 	erc1820 "github.com/wealdtech/go-erc1820"
 )
@@ -33,7 +34,7 @@ This will return an exit status of 0 if the transaction is successfully submitte
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(registryImplementerInterface != "", quiet, "--interface is required")
 
-		address, err := ens.Resolve(client, registryImplementerAddressStr)
+		address, err := util.ResolveAddress(client, registryImplementerAddressStr)
 		cli.ErrCheck(err, quiet, "failed to resolve address")
 
 		registry, err := erc1820.NewRegistry(client)
@@ -19,8 +19,9 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	ens "github.com/wealdtech/go-ens/v3"
-	erc1820 "github.com/wealdtech/go-erc1820"
+	"github.com/wealdtech/go-erc1820/contracts"
 )
 
 // registryImplementerGetCmd represents the registry implementer get command
@@ -31,25 +32,32 @@ var registryImplementerGetCmd = &cobra.Command{
 
     ethereal registry implementer get --interface=ERC777Token --address=0x1234...5678
 
+The interface can be the name of the interface, in which case its hash is used, an ERC-165 interface ID, or a 32-byte interface hash.
+
 In quiet mode this will return 0 if the implementer has an address, otherwise 1.`,
 
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(registryImplementerInterface != "", quiet, "--interface is required")
+		cli.Assert(registryImplementerAddressStr != "", quiet, "--address is required")
 
-		address, err := ens.Resolve(client, registryImplementerAddressStr)
+		address, err := util.ResolveAddress(client, registryImplementerAddressStr)
 		cli.ErrCheck(err, quiet, "failed to resolve name")
 
-		registry, err := erc1820.NewRegistry(client)
+		registry, err := contracts.NewErc1820Registry(erc1820RegistryAddress, client)
 		cli.ErrCheck(err, quiet, "failed to obtain ERC-1820 registry")
 
-		implementer, err := registry.InterfaceImplementer(registryImplementerInterface, &address)
+		interfaceHash := registryImplementerInterfaceHash(registryImplementerInterface)
+		outputIf(verbose, fmt.Sprintf("Interface hash is %#x", interfaceHash))
+
+		implementer, err := registry.GetInterfaceImplementer(nil, address, interfaceHash)
 		cli.ErrCheck(err, quiet, "failed to obtain implementer")
 
-		if *implementer == ens.UnknownAddress {
+		if implementer == ens.UnknownAddress {
+			outputIf(!quiet, "No implementer")
 			os.Exit(_exit_failure)
 		}
 		if !quiet {
-			fmt.Printf("%s\n", ens.Format(client, *implementer))
+			fmt.Printf("%s\n", util.ENSFormat(client, implementer))
 		}
 		os.Exit(_exit_success)
 	},
@@ -14,14 +14,28 @@
 package cmd
 
 import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/wealdtech/ethereal/cli"
-	ens "github.com/wealdtech/go-ens/v3"
-	erc1820 "github.com/wealdtech/go-erc1820"
+	"github.com/wealdtech/ethereal/util"
+	"github.com/wealdtech/go-erc1820/contracts"
 )
 
+// registryAcceptMagic is the value returned by canImplementInterfaceForAddress() when an implementer accepts an interface
+var registryAcceptMagic = crypto.Keccak256Hash([]byte("ERC1820_ACCEPT_MAGIC"))
+
 var registryImplementerSetImplementerStr string
+var registryImplementerSetFromStr string
 
 // registryImplementerSetCmd represents the registry implementer set command
 var registryImplementerSetCmd = &cobra.Command{
@@ -31,54 +45,117 @@ var registryImplementerSetCmd = &cobra.Command{
 
     ethereal registry implementer set --interface=ERC777Token --address=0x1234...5678 --implementer=0x9abc...def0
 
+The transaction is sent from the manager of the address, which must be local (i.e. listed with 'get accounts list') or the account of the supplied private key.  If the implementer is a contract that does not accept the interface for the address a warning is given, as the registry will reject the transaction.  In offline mode the manager must be supplied with --from, and all addresses must be supplied as addresses rather than names.
+
+If --simulate is supplied then the transaction is first run as a call against the pending block, and is not sent if it would fail.
+
 This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(registryImplementerInterface != "", quiet, "--interface is required")
-
 		cli.Assert(registryImplementerAddressStr != "", quiet, "--address is required")
-		address, err := ens.Resolve(client, registryImplementerAddressStr)
-		cli.ErrCheck(err, quiet, "failed to resolve address")
-
 		cli.Assert(registryImplementerSetImplementerStr != "", quiet, "--implementer is required")
-		implementer, err := ens.Resolve(client, registryImplementerSetImplementerStr)
-		if err != nil {
-			if err.Error() == "could not parse address" {
-				cli.Err(quiet, "Invalid implementer address; if you are trying to clear an existing entry use \"registry implementer clear\"")
+
+		interfaceHash := registryImplementerInterfaceHash(registryImplementerInterface)
+		cli.Assert(!bytes.Equal(interfaceHash[4:], make([]byte, 28)), quiet, "ERC-165 interfaces cannot be set in the registry")
+		outputIf(verbose, fmt.Sprintf("Interface hash is %#x", interfaceHash))
+
+		address := resolveAddressOffline(registryImplementerAddressStr)
+		implementer := resolveAddressOffline(registryImplementerSetImplementerStr)
+
+		// Work out the account that will send the transaction
+		var from common.Address
+		if offline {
+			cli.Assert(registryImplementerSetFromStr != "", quiet, "--from is required in offline mode")
+			cli.Assert(gasLimit != 0, quiet, "--gaslimit is required in offline mode")
+			from = resolveAddressOffline(registryImplementerSetFromStr)
+		} else {
+			registry, err := contracts.NewErc1820Registry(erc1820RegistryAddress, client)
+			cli.ErrCheck(err, quiet, "failed to obtain ERC-1820 registry")
+			from, err = registry.GetManager(nil, address)
+			cli.ErrCheck(err, quiet, "failed to obtain manager")
+			if registryImplementerSetFromStr != "" {
+				fromAddress := resolveAddressOffline(registryImplementerSetFromStr)
+				cli.Assert(fromAddress == from, quiet, fmt.Sprintf("%s is not the manager of %s; the manager is %s", fromAddress.Hex(), address.Hex(), util.ENSFormat(client, from)))
 			}
+			registryImplementerSetCheckImplementer(implementer, interfaceHash, address, from)
 		}
-		cli.ErrCheck(err, quiet, "failed to resolve implementer")
+		outputIf(verbose, fmt.Sprintf("Manager is %s", from.Hex()))
+		registryImplementerSetCheckSigner(from)
 
-		implementerContract, err := erc1820.NewImplementer(client, &implementer)
-		cli.ErrCheck(err, quiet, "failed to obtain ERC-1820 implementer contract")
-		implementsIface, err := implementerContract.ImplementsInterface(registryImplementerInterface, &address)
-		cli.Assert(implementsIface, quiet, "implementer does not implement that interface for that address")
+		registryAbi, err := abi.JSON(strings.NewReader(contracts.Erc1820RegistryABI))
+		cli.ErrCheck(err, quiet, "failed to parse ERC-1820 registry ABI")
+		data, err := registryAbi.Pack("setInterfaceImplementer", address, interfaceHash, implementer)
+		cli.ErrCheck(err, quiet, "failed to create transaction data")
 
-		registry, err := erc1820.NewRegistry(client)
-		cli.ErrCheck(err, quiet, "failed to obtain ERC-1820 registry")
+		simulateTransaction(from, &erc1820RegistryAddress, big.NewInt(0), data)
 
-		managerAddr, err := registry.Manager(&address)
-		cli.ErrCheck(err, quiet, "failed to obtain manager")
-		if *managerAddr == ens.UnknownAddress {
-			managerAddr = &address
+		signedTx, err := createSignedTransaction(from, &erc1820RegistryAddress, big.NewInt(0), gasLimit, data)
+		cli.ErrCheck(err, quiet, "failed to create transaction")
+		if offline {
+			if !quiet {
+				outputOfflineTransaction(signedTx)
+			}
+			os.Exit(_exit_success)
 		}
 
-		opts, err := generateTxOpts(*managerAddr)
-		cli.ErrCheck(err, quiet, "failed to generate transaction options")
-		signedTx, err := registry.SetInterfaceImplementer(opts, registryImplementerInterface, &address, &implementer)
+		ctx, cancel := localContext()
+		defer cancel()
+		err = client.SendTransaction(ctx, signedTx)
 		cli.ErrCheck(err, quiet, "failed to send transaction")
 
 		handleSubmittedTransaction(signedTx, log.Fields{
 			"group":               "registry/implementer",
 			"command":             "set",
 			"registryaddress":     address.Hex(),
+			"registryinterface":   fmt.Sprintf("%#x", interfaceHash),
 			"registryimplementer": implementer.Hex(),
 		}, true)
 	},
 }
 
+// registryImplementerSetCheckImplementer warns if the registry will refuse
+// the implementer for the interface.
+func registryImplementerSetCheckImplementer(implementer common.Address, interfaceHash [32]byte, address common.Address, from common.Address) {
+	if implementer == from {
+		// The registry accepts the sender as an implementer without checking
+		return
+	}
+	ctx, cancel := localContext()
+	defer cancel()
+	code, err := client.CodeAt(ctx, implementer, nil)
+	cli.ErrCheck(err, quiet, "failed to obtain implementer code")
+	if len(code) == 0 {
+		cli.Warn(quiet, fmt.Sprintf("Implementer %s is not a contract or the manager, so will be rejected by the registry", implementer.Hex()))
+		return
+	}
+	implementerContract, err := contracts.NewErc1820Implementer(implementer, client)
+	cli.ErrCheck(err, quiet, "failed to obtain ERC-1820 implementer contract")
+	result, err := implementerContract.CanImplementInterfaceForAddress(nil, interfaceHash, address)
+	if err != nil || result != registryAcceptMagic {
+		cli.Warn(quiet, fmt.Sprintf("Implementer %s does not accept the interface for %s, so will be rejected by the registry", implementer.Hex(), address.Hex()))
+	}
+}
+
+// registryImplementerSetCheckSigner ensures that the manager can sign the transaction.
+func registryImplementerSetCheckSigner(manager common.Address) {
+	if viper.GetString("passphrase") == "" {
+		key, err := transactionKey()
+		cli.ErrCheck(err, quiet, "Failed to obtain private key")
+		if key != nil {
+			keyAddress := crypto.PubkeyToAddress(key.PublicKey)
+			cli.Assert(keyAddress == manager, quiet, fmt.Sprintf("Private key is for %s but the manager is %s", keyAddress.Hex(), manager.Hex()))
+			return
+		}
+	}
+	_, err := cli.ObtainWallet(chainID, manager)
+	cli.ErrCheck(err, quiet, fmt.Sprintf("The manager %s is not a local account", manager.Hex()))
+}
+
 func init() {
 	registryImplementerFlags(registryImplementerSetCmd)
 	registryImplementerSetCmd.Flags().StringVar(&registryImplementerSetImplementerStr, "implementer", "", "address that implements the interface")
+	registryImplementerSetCmd.Flags().StringVar(&registryImplementerSetFromStr, "from", "", "the manager of the address (required in offline mode)")
 	registryImplementerCmd.AddCommand(registryImplementerSetCmd)
-	addTransactionFlags(registryImplementerSetCmd, "passphrase for the address")
+	addTransactionFlags(registryImplementerSetCmd, "passphrase for the manager of the address")
+	addSimulateFlag(registryImplementerSetCmd)
 }
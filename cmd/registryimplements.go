@@ -20,7 +20,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
-	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/ethereal/util"
 	erc1820 "github.com/wealdtech/go-erc1820"
 )
 
@@ -41,7 +41,7 @@ In quiet mode this will return 0 if the address implements the interface, otherw
 		cli.Assert(registryImplementsInterface != "", quiet, "--interface is required")
 
 		cli.Assert(registryImplementsAddressStr != "", quiet, "--address is required")
-		address, err := ens.Resolve(client, registryImplementsAddressStr)
+		address, err := util.ResolveAddress(client, registryImplementsAddressStr)
 		cli.ErrCheck(err, quiet, "failed to resolve name")
 
 		implementer, err := erc1820.NewImplementer(client, &address)
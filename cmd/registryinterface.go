@@ -0,0 +1,37 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var registryInterfaceAddressStr string
+var registryInterfaceID string
+
+// registryInterfaceCmd represents the registry interface command
+var registryInterfaceCmd = &cobra.Command{
+	Use:   "interface",
+	Short: "Manage ERC-165 interfaces",
+	Long:  `Obtain ERC-165 interface information for contracts`,
+}
+
+func init() {
+	registryCmd.AddCommand(registryInterfaceCmd)
+}
+
+func registryInterfaceFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&registryInterfaceAddressStr, "address", "", "address against which to operate (e.g. wealdtech.eth)")
+	cmd.Flags().StringVar(&registryInterfaceID, "interface", "", "interface ID (e.g. 0x01ffc9a7) or function signature (e.g. supportsInterface(bytes4))")
+}
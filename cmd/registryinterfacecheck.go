@@ -0,0 +1,122 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+)
+
+// registryInterfaceCheckABI is the ABI for ERC-165 supportsInterface()
+const registryInterfaceCheckABI = `[{"constant":true,"inputs":[{"name":"interfaceID","type":"bytes4"}],"name":"supportsInterface","outputs":[{"name":"","type":"bool"}],"payable":false,"stateMutability":"view","type":"function"}]`
+
+// registryInterfaceCheckCmd represents the registry interface check command
+var registryInterfaceCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check if a contract supports an ERC-165 interface",
+	Long: `Check if a contract supports an interface according to ERC-165.  For example:
+
+    ethereal registry interface check --interface=0x01ffc9a7 --address=0x1234...5678
+
+The interface can be supplied as a 4-byte interface ID, or as a function signature in which case its selector is used.  Contracts that revert when asked about an interface are treated as not supporting it.
+
+In quiet mode this will return 0 if the contract supports the interface, otherwise 1.`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(registryInterfaceID != "", quiet, "--interface is required")
+		cli.Assert(registryInterfaceAddressStr != "", quiet, "--address is required")
+
+		address, err := util.ResolveAddress(client, registryInterfaceAddressStr)
+		cli.ErrCheck(err, quiet, "failed to resolve name")
+
+		interfaceID, err := registryInterfaceCheckID(registryInterfaceID)
+		cli.ErrCheck(err, quiet, "invalid interface")
+		outputIf(verbose, fmt.Sprintf("Interface ID is %#x", interfaceID))
+
+		supported, err := registryInterfaceCheckSupports(address, interfaceID)
+		cli.ErrCheck(err, quiet, "failed to check interface")
+
+		if !quiet {
+			fmt.Printf("%t\n", supported)
+		}
+		if supported {
+			os.Exit(_exit_success)
+		}
+		os.Exit(_exit_failure)
+	},
+}
+
+// registryInterfaceCheckID obtains the ERC-165 interface ID from a hex
+// string or a function signature.
+func registryInterfaceCheckID(input string) ([4]byte, error) {
+	var id [4]byte
+	if strings.HasPrefix(input, "0x") {
+		bytes, err := hex.DecodeString(input[2:])
+		if err != nil {
+			return id, err
+		}
+		if len(bytes) != 4 {
+			return id, errors.New("interface ID must be 4 bytes")
+		}
+		copy(id[:], bytes)
+		return id, nil
+	}
+	copy(id[:], crypto.Keccak256([]byte(input)))
+	return id, nil
+}
+
+// registryInterfaceCheckSupports returns true if the contract at the address
+// supports the interface.
+func registryInterfaceCheckSupports(address common.Address, interfaceID [4]byte) (bool, error) {
+	contractAbi, err := abi.JSON(strings.NewReader(registryInterfaceCheckABI))
+	if err != nil {
+		return false, err
+	}
+	data, err := contractAbi.Pack("supportsInterface", interfaceID)
+	if err != nil {
+		return false, err
+	}
+	ctx, cancel := localContext()
+	defer cancel()
+	res, err := client.CallContract(ctx, ethereum.CallMsg{To: &address, Data: data}, nil)
+	if util.IsRevert(err) || (err == nil && len(res) == 0) {
+		// Reverts and empty responses (e.g. from accounts without code) do not support the interface
+		outputIf(debug, fmt.Sprintf("Call did not return a value: %v", err))
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	var supported bool
+	if err := contractAbi.Unpack(&supported, "supportsInterface", res); err != nil {
+		return false, nil
+	}
+	return supported, nil
+}
+
+func init() {
+	registryInterfaceFlags(registryInterfaceCheckCmd)
+	registryInterfaceCmd.AddCommand(registryInterfaceCheckCmd)
+}
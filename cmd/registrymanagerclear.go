@@ -17,6 +17,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	ens "github.com/wealdtech/go-ens/v3"
 	erc1820 "github.com/wealdtech/go-erc1820"
 )
@@ -31,7 +32,7 @@ var registryManagerClearCmd = &cobra.Command{
 
 This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		address, err := ens.Resolve(client, registryManagerAddressStr)
+		address, err := util.ResolveAddress(client, registryManagerAddressStr)
 		cli.ErrCheck(err, quiet, "failed to resolve address")
 
 		registry, err := erc1820.NewRegistry(client)
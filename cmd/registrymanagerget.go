@@ -19,6 +19,7 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	ens "github.com/wealdtech/go-ens/v3"
 	erc1820 "github.com/wealdtech/go-erc1820"
 )
@@ -36,7 +37,7 @@ Note that this will always return the managing address if possible.  This means
 In quiet mode this will return 0 if the manager was obtained without error, otherwise 1.`,
 
 	Run: func(cmd *cobra.Command, args []string) {
-		address, err := ens.Resolve(client, registryManagerAddressStr)
+		address, err := util.ResolveAddress(client, registryManagerAddressStr)
 		cli.ErrCheck(err, quiet, "failed to resolve address")
 
 		registry, err := erc1820.NewRegistry(client)
@@ -50,7 +51,7 @@ In quiet mode this will return 0 if the manager was obtained without error, othe
 		}
 
 		if !quiet {
-			fmt.Printf("%s\n", ens.Format(client, *manager))
+			fmt.Printf("%s\n", util.ENSFormat(client, *manager))
 		}
 		os.Exit(_exit_success)
 	},
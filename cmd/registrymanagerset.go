@@ -17,7 +17,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
-	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/ethereal/util"
 	erc1820 "github.com/wealdtech/go-erc1820"
 )
 
@@ -34,11 +34,11 @@ var registryManagerSetCmd = &cobra.Command{
 This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(registryManagerAddressStr != "", quiet, "--address is required")
-		address, err := ens.Resolve(client, registryManagerAddressStr)
+		address, err := util.ResolveAddress(client, registryManagerAddressStr)
 		cli.ErrCheck(err, quiet, "failed to resolve address")
 
 		cli.Assert(registryManagerSetManagerStr != "", quiet, "--manager is required")
-		manager, err := ens.Resolve(client, registryManagerSetManagerStr)
+		manager, err := util.ResolveAddress(client, registryManagerSetManagerStr)
 		if err != nil {
 			if err.Error() == "could not parse address" {
 				cli.Err(quiet, "Invalid manager address; if you are trying to clear an existing entry use \"registry manager clear\"")
@@ -14,11 +14,13 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"math/big"
 	"os"
 	"path/filepath"
@@ -32,6 +34,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	homedir "github.com/mitchellh/go-homedir"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -46,12 +49,18 @@ var quiet bool
 var verbose bool
 var debug bool
 var offline bool
+var simulate bool
+var showCalldata bool
 
 var client *ethclient.Client
+var rpcClient *rpc.Client
 var chainID *big.Int
 var referrer common.Address
 
 var nonce int64
+
+// nonceChecked is set once the nonce has been checked against the chain.
+var nonceChecked bool
 var wallet accounts.Wallet
 var account *accounts.Account
 
@@ -62,9 +71,16 @@ var gasLimit uint64
 
 var err error
 
+// The command being run
+var activeCmd *cobra.Command
+
 // Commands that can be run offline
 var offlineCmds = make(map[string]bool)
 
+// Commands that can record their results with --jsonout, in addition to
+// those that submit transactions
+var jsonoutCmds = make(map[string]bool)
+
 // RootCmd represents the base command when called without any subcommands
 var RootCmd = &cobra.Command{
 	Use:              "ethereal",
@@ -84,6 +100,8 @@ func persistentPreRun(cmd *cobra.Command, args []string) {
 		return
 	}
 
+	activeCmd = cmd
+
 	// We bind viper here so that we bind to the correct command
 	quiet = viper.GetBool("quiet")
 	verbose = viper.GetBool("verbose")
@@ -95,20 +113,31 @@ func persistentPreRun(cmd *cobra.Command, args []string) {
 		offline = true
 	}
 
-	switch strings.ToLower(viper.GetString("network")) {
-	case "mainnet":
-		chainID = big.NewInt(1)
-	case "ropsten":
-		chainID = big.NewInt(3)
-	case "rinkeby":
-		chainID = big.NewInt(4)
-	case "goerli", "gorli", "görli":
-		chainID = big.NewInt(5)
-	case "kovan":
-		chainID = big.NewInt(42)
-	default:
+	if viper.GetString("jsonout") != "" {
+		cli.Assert(jsonoutCmds[cmdPath(cmd)] || cmd.Flags().Lookup("wait") != nil, quiet, "--jsonout is not supported by this command")
+	}
+
+	selectedNetwork := networkByName(viper.GetString("network"))
+	if selectedNetwork == nil {
 		cli.Err(quiet, fmt.Sprintf("Unknown network name %q", viper.GetString("network")))
 	}
+	chainID = big.NewInt(selectedNetwork.ChainID)
+	// An explicit chain ID overrides that of the network, for signing offline
+	if viper.GetInt64("chainid") != 0 {
+		chainID = big.NewInt(viper.GetInt64("chainid"))
+	}
+	if cmd.Flags().Lookup("padbytes") != nil {
+		viper.BindPFlag("padbytes", cmd.Flags().Lookup("padbytes"))
+	}
+	if cmd.Flags().Lookup("refreshabi") != nil {
+		viper.BindPFlag("refreshabi", cmd.Flags().Lookup("refreshabi"))
+	}
+
+	switch viper.GetString("offlineformat") {
+	case "hex", "json":
+	default:
+		cli.Err(quiet, fmt.Sprintf("Unknown offline format %q; supported formats are hex and json", viper.GetString("offlineformat")))
+	}
 
 	if quiet && verbose {
 		cli.Err(false, "Cannot supply both quiet and verbose flags")
@@ -126,9 +155,17 @@ func persistentPreRun(cmd *cobra.Command, args []string) {
 	if cmd.Flags().Lookup("privatekey") != nil {
 		viper.BindPFlag("privatekey", cmd.Flags().Lookup("privatekey"))
 	}
+	if cmd.Flags().Lookup("mnemonic") != nil {
+		viper.BindPFlag("mnemonic", cmd.Flags().Lookup("mnemonic"))
+		viper.BindPFlag("mnemonicfile", cmd.Flags().Lookup("mnemonicfile"))
+		viper.BindPFlag("hdpath", cmd.Flags().Lookup("path"))
+	}
 	if cmd.Flags().Lookup("nonce") != nil {
 		viper.BindPFlag("nonce", cmd.Flags().Lookup("nonce"))
 	}
+	if cmd.Flags().Lookup("replace") != nil {
+		viper.BindPFlag("replace", cmd.Flags().Lookup("replace"))
+	}
 	if cmd.Flags().Lookup("value") != nil {
 		viper.BindPFlag("value", cmd.Flags().Lookup("value"))
 	}
@@ -138,6 +175,9 @@ func persistentPreRun(cmd *cobra.Command, args []string) {
 	if cmd.Flags().Lookup("limit") != nil {
 		viper.BindPFlag("limit", cmd.Flags().Lookup("limit"))
 	}
+	if cmd.Flags().Lookup("pollinterval") != nil {
+		viper.BindPFlag("pollinterval", cmd.Flags().Lookup("pollinterval"))
+	}
 	// Set up gas price if we have it
 	if cmd.Flags().Lookup("gasprice") != nil {
 		viper.BindPFlag("gasprice", cmd.Flags().Lookup("gasprice"))
@@ -157,7 +197,7 @@ func persistentPreRun(cmd *cobra.Command, args []string) {
 				// fmt.Printf("Gas price is %v\n", string2eth.WeiToString(gasPrice, true))
 				os.Exit(_exit_success)
 			} else {
-				gasPrice, err = string2eth.StringToWei(viper.GetString("gasprice"))
+				gasPrice, err = parseAmount(viper.GetString("gasprice"))
 				cli.ErrCheck(err, quiet, "Invalid gas price")
 			}
 		}
@@ -166,6 +206,7 @@ func persistentPreRun(cmd *cobra.Command, args []string) {
 
 	// Set up nonce if we have it
 	nonce = viper.GetInt64("nonce")
+	cli.Assert(nonce == -1 || !viper.GetBool("replace"), quiet, "Cannot supply both --nonce and --replace")
 
 	if cmd.Flags().Lookup("gaslimit") != nil {
 		viper.BindPFlag("gaslimit", cmd.Flags().Lookup("gaslimit"))
@@ -186,34 +227,42 @@ func connect() error {
 	var err error
 	if viper.GetString("connection") != "" {
 		outputIf(debug, fmt.Sprintf("Connecting to %s", viper.GetString("connection")))
-		client, err = ethclient.Dial(viper.GetString("connection"))
+		rpcClient, err = rpc.Dial(viper.GetString("connection"))
 	} else {
-		switch strings.ToLower(viper.GetString("network")) {
-		case "mainnet":
-			outputIf(debug, "Connecting to mainnet")
-			client, err = ethclient.Dial("https://mainnet.infura.io/v3/831a5442dc2e4536a9f8dee4ea1707a6")
-		case "ropsten":
-			outputIf(debug, "Connecting to ropsten")
-			client, err = ethclient.Dial("https://ropsten.infura.io/v3/831a5442dc2e4536a9f8dee4ea1707a6")
-		case "rinkeby":
-			outputIf(debug, "Connecting to rinkeby")
-			client, err = ethclient.Dial("https://rinkeby.infura.io/v3/831a5442dc2e4536a9f8dee4ea1707a6")
-		case "goerli", "gorli", "görli":
-			outputIf(debug, "Connecting to goerli")
-			client, err = ethclient.Dial("https://goerli.infura.io/v3/831a5442dc2e4536a9f8dee4ea1707a6")
-		case "kovan":
-			outputIf(debug, "Connecting to kovan")
-			client, err = ethclient.Dial("https://kovan.infura.io/v3/831a5442dc2e4536a9f8dee4ea1707a6")
-		default:
+		selectedNetwork := networkByName(viper.GetString("network"))
+		switch {
+		case selectedNetwork == nil:
 			cli.Err(quiet, fmt.Sprintf("Unknown network %s", viper.GetString("network")))
+		case selectedNetwork.Connection == "":
+			cli.Err(quiet, fmt.Sprintf("No default connection for network %s; please supply --connection", selectedNetwork.Name))
+		default:
+			outputIf(debug, fmt.Sprintf("Connecting to %s", selectedNetwork.Name))
+			rpcClient, err = rpc.Dial(selectedNetwork.Connection)
 		}
 	}
 	cli.ErrCheck(err, quiet, "Failed to connect to network")
+	// Keep hold of the RPC client for batch requests
+	client = ethclient.NewClient(rpcClient)
 	// Fetch the chain ID
 	ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("timeout"))
 	defer cancel()
-	chainID, err = client.NetworkID(ctx)
-	return err
+	nodeChainID, err := client.ChainID(ctx)
+	if err != nil {
+		// Older nodes do not support eth_chainId so fall back to the network ID
+		nodeChainID, err = client.NetworkID(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	if viper.GetInt64("chainid") != 0 && nodeChainID.Cmp(chainID) != 0 {
+		if !viper.GetBool("force") {
+			return fmt.Errorf("chain ID %v was supplied but the node is on chain ID %v; use --force to continue regardless", chainID, nodeChainID)
+		}
+		outputIf(verbose, fmt.Sprintf("Using chain ID %v rather than node chain ID %v", chainID, nodeChainID))
+		return nil
+	}
+	chainID = nodeChainID
+	return nil
 }
 
 // cmdPath recurses up the command information to create a path for this command through commands and subcommands
@@ -240,13 +289,21 @@ func setupLogging() {
 
 // handleSubmittedTransaction handles logging and waiting for a submitted transaction to be mined.
 // It will not log the transaction if logFields is nil.
-// If exit is true this function will exit with a suitable status.
+// If exit is true this function will exit with a suitable status.  A transaction that is mined
+// but reverts exits with 0, as it has been submitted and mined, after its revert reason is output.
 // If exit is false this function will return false if asked to wait and the transaction is not
-// mined, otherwise true.
+// mined or is reverted, otherwise true.
 func handleSubmittedTransaction(tx *types.Transaction, logFields log.Fields, exit bool) bool {
 	if logFields != nil {
 		logTransaction(tx, logFields)
 	}
+	record := log.Fields{}
+	for k, v := range logFields {
+		record[k] = v
+	}
+	record["transactionid"] = tx.Hash().Hex()
+	err := recordJSON(activeCmd, record)
+	cli.WarnCheck(err, quiet, "Failed to record JSON output")
 
 	if !viper.GetBool("wait") {
 		outputIf(!quiet, fmt.Sprintf("%s", tx.Hash().Hex()))
@@ -256,20 +313,81 @@ func handleSubmittedTransaction(tx *types.Transaction, logFields log.Fields, exi
 			return true
 		}
 	}
-	mined := util.WaitForTransaction(client, tx.Hash(), viper.GetDuration("limit"))
-	if mined {
-		outputIf(!quiet, fmt.Sprintf("%s mined", tx.Hash().Hex()))
+	interval := viper.GetDuration("pollinterval")
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	outputIf(verbose, fmt.Sprintf("Waiting for %s to be mined", tx.Hash().Hex()))
+	receipt := util.WaitForReceipt(client, tx.Hash(), viper.GetDuration("limit"), interval)
+	if receipt == nil {
+		if viper.GetDuration("limit") != 0 {
+			outputIf(!quiet, fmt.Sprintf("%s submitted but not mined within %v", tx.Hash().Hex(), viper.GetDuration("limit")))
+		} else {
+			outputIf(!quiet, fmt.Sprintf("%s submitted but not mined", tx.Hash().Hex()))
+		}
+		if exit {
+			os.Exit(_exit_not_mined)
+		}
+		return false
+	}
+	if receipt.Status == types.ReceiptStatusFailed {
+		outputIf(!quiet, fmt.Sprintf("%s mined in block %d but reverted (gas used %d): %s", tx.Hash().Hex(), receipt.BlockNumber, receipt.GasUsed, transactionRevertReason(tx, receipt)))
 		if exit {
 			os.Exit(_exit_success)
-		} else {
-			return true
 		}
+		return false
 	}
-	outputIf(!quiet, fmt.Sprintf("%s submitted but not mined", tx.Hash().Hex()))
+	outputIf(!quiet, fmt.Sprintf("%s mined in block %d (gas used %d)", tx.Hash().Hex(), receipt.BlockNumber, receipt.GasUsed))
 	if exit {
-		os.Exit(_exit_not_mined)
+		os.Exit(_exit_success)
+	}
+	return true
+}
+
+// transactionRevertReason obtains the reason a mined transaction reverted by
+// re-running it as a call at the block in which it was mined.
+func transactionRevertReason(tx *types.Transaction, receipt *types.Receipt) string {
+	from, err := types.Sender(types.NewEIP155Signer(chainID), tx)
+	if err != nil {
+		return "unknown reason"
+	}
+	msg := ethereum.CallMsg{
+		From:     from,
+		To:       tx.To(),
+		Gas:      tx.Gas(),
+		GasPrice: tx.GasPrice(),
+		Value:    tx.Value(),
+		Data:     tx.Data(),
+	}
+	ctx, cancel := localContext()
+	defer cancel()
+	_, err = client.CallContract(ctx, msg, receipt.BlockNumber)
+	if err == nil {
+		return "unknown reason"
+	}
+	return util.RevertReason(err)
+}
+
+// simulateTransaction makes a call against the pending block with the same
+// parameters as a transaction that is about to be sent, and exits with the
+// reason if the call reverts.  It does nothing unless --simulate is supplied.
+func simulateTransaction(from common.Address, to *common.Address, value *big.Int, data []byte) {
+	if !simulate {
+		return
+	}
+	cli.Assert(!offline, quiet, "Cannot simulate transactions in offline mode")
+	msg := ethereum.CallMsg{
+		From:  from,
+		To:    to,
+		Value: value,
+		Data:  data,
 	}
-	return false
+	ctx, cancel := localContext()
+	defer cancel()
+	if _, err := client.PendingCallContract(ctx, msg); err != nil {
+		cli.Err(quiet, fmt.Sprintf("Transaction would fail: %s", util.RevertReason(err)))
+	}
+	outputIf(verbose, "Simulation succeeded")
 }
 
 // logTransaction logs a transaction
@@ -318,14 +436,28 @@ func init() {
 	viper.BindPFlag("debug", RootCmd.PersistentFlags().Lookup("debug"))
 	RootCmd.PersistentFlags().String("connection", "", "the custom IPC or RPC path to an Ethereum node (overrides network option).  If you are running your own local instance of Ethereum this might be /home/user/.ethereum/geth.ipc (IPC) or http://localhost:8545/ (RPC)")
 	viper.BindPFlag("connection", RootCmd.PersistentFlags().Lookup("connection"))
-	RootCmd.PersistentFlags().String("network", "mainnet", "network to access (mainnet/ropsten/kovan/rinkeby/goerli) (overridden by connection option)")
+	RootCmd.PersistentFlags().String("network", "mainnet", "network to access (see the networks command for known networks) (overridden by connection option)")
 	viper.BindPFlag("network", RootCmd.PersistentFlags().Lookup("network"))
 	RootCmd.PersistentFlags().Duration("timeout", 30*time.Second, "the time after which a network request will be deemed to have failed.  Increase this if you are running on a error-prone, high-latency or low-bandwidth connection")
 	viper.BindPFlag("timeout", RootCmd.PersistentFlags().Lookup("timeout"))
 	RootCmd.PersistentFlags().Bool("offline", false, "print the transaction a hex string and do not send it")
 	viper.BindPFlag("offline", RootCmd.PersistentFlags().Lookup("offline"))
+	RootCmd.PersistentFlags().String("offlineformat", "hex", "format in which to print transactions in offline mode (hex or json)")
+	viper.BindPFlag("offlineformat", RootCmd.PersistentFlags().Lookup("offlineformat"))
+	RootCmd.PersistentFlags().Int64("chainid", 0, "chain ID with which to sign transactions (default is that of the network, or of the connected node).  If a node is connected and has a different chain ID the command will fail unless --force is supplied")
+	viper.BindPFlag("chainid", RootCmd.PersistentFlags().Lookup("chainid"))
+	RootCmd.PersistentFlags().Bool("force", false, "carry on regardless of safety checks, such as giving up control of an ENS name")
+	viper.BindPFlag("force", RootCmd.PersistentFlags().Lookup("force"))
 	RootCmd.PersistentFlags().Int("usbwallets", 1, "number of USB wallets to show")
 	viper.BindPFlag("usbwallets", RootCmd.PersistentFlags().Lookup("usbwallets"))
+	RootCmd.PersistentFlags().String("ensregistry", "", "address of the ENS registry, for networks where ENS is not at its well-known address")
+	viper.BindPFlag("ensregistry", RootCmd.PersistentFlags().Lookup("ensregistry"))
+	RootCmd.PersistentFlags().String("jsonout", "", "append a JSON record of the result to the named file, in addition to the regular output; supported by commands that send transactions or can output JSON")
+	viper.BindPFlag("jsonout", RootCmd.PersistentFlags().Lookup("jsonout"))
+	RootCmd.PersistentFlags().Bool("nochecksum", false, "accept hex addresses with an invalid checksum")
+	viper.BindPFlag("nochecksum", RootCmd.PersistentFlags().Lookup("nochecksum"))
+	RootCmd.PersistentFlags().Bool("allowbareamounts", false, "accept amounts of Ether without a unit, treating them as a number of Wei")
+	viper.BindPFlag("allowbareamounts", RootCmd.PersistentFlags().Lookup("allowbareamounts"))
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -372,13 +504,41 @@ func initConfig() {
 func addTransactionFlags(cmd *cobra.Command, explanation string) {
 	cmd.Flags().String("passphrase", "", fmt.Sprintf("passphrase for %s", explanation))
 	cmd.Flags().String("privatekey", "", fmt.Sprintf("private key for %s", explanation))
-	cmd.Flags().String("gasprice", "", "Gas price for the transaction")
+	addMnemonicFlags(cmd, explanation)
+	cmd.Flags().String("gasprice", "", "Gas price for the transaction, with a unit (e.g. \"30 gwei\")")
 	cmd.Flags().Bool("allowhighgasprice", false, "Allow gas prices higher than 1000GWei")
-	cmd.Flags().String("value", "", "Ether to send with the transaction")
+	cmd.Flags().String("value", "", "Ether to send with the transaction, with a unit (e.g. \"0.1 ether\")")
 	cmd.Flags().Int64("gaslimit", 0, "Gas limit for the transaction; 0 is auto-select")
 	cmd.Flags().Int64("nonce", -1, "Nonce for the transaction; -1 is auto-select")
+	cmd.Flags().Bool("replace", false, "use the nonce of the lowest pending transaction from the sender, to replace it")
 	cmd.Flags().Bool("wait", false, "wait for the transaction to be mined before returning")
 	cmd.Flags().Duration("limit", 0, "maximum time to wait for transaction to complete before failing (default forever)")
+	cmd.Flags().Duration("pollinterval", 5*time.Second, "time between checks for the transaction being mined when waiting")
+}
+
+// addMnemonicFlags adds flags to derive a private key from a mnemonic.
+func addMnemonicFlags(cmd *cobra.Command, explanation string) {
+	cmd.Flags().String("mnemonic", "", fmt.Sprintf("BIP-39 mnemonic from which to derive the private key for %s", explanation))
+	cmd.Flags().String("mnemonicfile", "", "file containing the BIP-39 mnemonic")
+	cmd.Flags().String("path", "", fmt.Sprintf("derivation path of the private key (default %s)", util.DefaultHDPath))
+}
+
+// addSimulateFlag adds the --simulate flag for commands that can simulate
+// their transaction before sending it.
+func addSimulateFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&simulate, "simulate", false, "simulate the transaction against the pending block before sending it, and do not send it if it would fail")
+}
+
+// addShowCalldataFlag adds the --showcalldata flag for commands that build
+// calldata for their transaction.
+func addShowCalldataFlag(cmd *cobra.Command, explanation string) {
+	cmd.Flags().BoolVar(&showCalldata, "showcalldata", false, fmt.Sprintf("Output the %s", explanation))
+}
+
+// outputCalldata outputs the calldata for a transaction if --showcalldata
+// is supplied.
+func outputCalldata(data []byte) {
+	outputIf(showCalldata && !quiet, fmt.Sprintf("0x%x", data))
 }
 
 // Obtain the current nonce for the given address
@@ -395,14 +555,33 @@ func currentNonce(address common.Address) (uint64, error) {
 		var tmpNonce uint64
 		ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("timeout"))
 		defer cancel()
-		tmpNonce, err = client.PendingNonceAt(ctx, address)
+		if viper.GetBool("replace") {
+			// The lowest pending transaction has the next unconfirmed nonce
+			tmpNonce, err = client.NonceAt(ctx, address, nil)
+		} else {
+			tmpNonce, err = client.PendingNonceAt(ctx, address)
+		}
 		if err != nil {
 			return 0, fmt.Errorf("failed to obtain nonce for %s: %v", address.Hex(), err)
 		}
 		currentNonce = uint64(tmpNonce)
 		nonce = int64(tmpNonce)
+		nonceChecked = true
 	} else {
 		currentNonce = uint64(nonce)
+		if !nonceChecked && client != nil {
+			// Ensure that the supplied nonce has not already been used
+			ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("timeout"))
+			defer cancel()
+			confirmedNonce, err := client.NonceAt(ctx, address, nil)
+			if err != nil {
+				return 0, fmt.Errorf("failed to obtain nonce for %s: %v", address.Hex(), err)
+			}
+			if currentNonce < confirmedNonce {
+				return 0, fmt.Errorf("nonce %d has already been used by %s and would be rejected; the lowest usable nonce is %d", currentNonce, address.Hex(), confirmedNonce)
+			}
+			nonceChecked = true
+		}
 	}
 	return currentNonce, nil
 }
@@ -493,10 +672,12 @@ func generateTxOpts(sender common.Address) (opts *bind.TransactOpts, err error)
 			return
 		}
 		signer = util.AccountSigner(chainID, &wallet, account, viper.GetString("passphrase"))
-	} else if viper.GetString("privatekey") != "" {
-		key, err := crypto.HexToECDSA(strings.TrimPrefix(viper.GetString("privatekey"), "0x"))
-		cli.ErrCheck(err, quiet, "Invalid private key")
-		signer = util.KeySigner(chainID, key)
+	} else {
+		key, err := transactionKey()
+		cli.ErrCheck(err, quiet, "Failed to obtain private key")
+		if key != nil {
+			signer = util.KeySigner(chainID, key)
+		}
 	}
 	if signer == nil {
 		err = fmt.Errorf("no signer; please supply either passphrase or private key")
@@ -505,7 +686,7 @@ func generateTxOpts(sender common.Address) (opts *bind.TransactOpts, err error)
 
 	var value *big.Int
 	if viper.GetString("value") != "" {
-		value, err = string2eth.StringToWei(viper.GetString("value"))
+		value, err = parseAmount(viper.GetString("value"))
 		cli.ErrCheck(err, quiet, "Failed to understand value")
 	}
 
@@ -540,27 +721,97 @@ func signTransaction(signer common.Address, tx *types.Transaction) (signedTx *ty
 			}
 		}
 		signedTx, err = wallet.SignTxWithPassphrase(*account, viper.GetString("passphrase"), tx, chainID)
-	} else if viper.GetString("privatekey") != "" {
+	} else {
 		var key *ecdsa.PrivateKey
-		key, err = crypto.HexToECDSA(strings.TrimPrefix(viper.GetString("privatekey"), "0x"))
-		cli.ErrCheck(err, quiet, "Invalid private key")
+		key, err = transactionKey()
+		cli.ErrCheck(err, quiet, "Failed to obtain private key")
+		if key == nil {
+			return nil, errors.New("no passphrase or private key; cannot sign")
+		}
 		keyAddr := crypto.PubkeyToAddress(key.PublicKey)
 		if signer != keyAddr {
 			return nil, errors.New("not authorized to sign this account")
 		}
 		signedTx, err = types.SignTx(tx, types.NewEIP155Signer(chainID), key)
-	} else {
-		err = errors.New("no passphrase or private key; cannot sign")
 	}
 	return
 }
 
+// transactionKey obtains the private key with which to sign transactions
+// from the transaction flags.  It returns nil if no key is supplied.
+func transactionKey() (*ecdsa.PrivateKey, error) {
+	return resolvePrivateKey(viper.GetString("privatekey"), viper.GetString("mnemonic"), viper.GetString("mnemonicfile"), viper.GetString("hdpath"))
+}
+
+// resolvePrivateKey obtains a private key, either supplied directly in hex or
+// derived from a BIP-39 mnemonic and derivation path.  To keep the mnemonic
+// out of command history it can also be read from a file, or from the
+// ETHEREAL_MNEMONIC environment variable.  It returns nil if no key is
+// supplied.
+func resolvePrivateKey(privateKey string, mnemonic string, mnemonicFile string, path string) (*ecdsa.PrivateKey, error) {
+	if privateKey != "" {
+		return crypto.HexToECDSA(strings.TrimPrefix(privateKey, "0x"))
+	}
+	if mnemonic == "" && mnemonicFile != "" {
+		data, err := ioutil.ReadFile(mnemonicFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read mnemonic file: %v", err)
+		}
+		mnemonic = string(data)
+	}
+	if mnemonic == "" {
+		mnemonic = os.Getenv("ETHEREAL_MNEMONIC")
+	}
+	if mnemonic == "" {
+		if path != "" {
+			return nil, errors.New("a mnemonic is required to derive a key from a path")
+		}
+		return nil, nil
+	}
+	if path == "" {
+		path = util.DefaultHDPath
+	}
+	return util.MnemonicPrivateKey(mnemonic, "", path)
+}
+
+// outputOfflineTransaction outputs a signed transaction that is not being
+// sent, in the format selected with --offlineformat.
+func outputOfflineTransaction(signedTx *types.Transaction) {
+	if viper.GetString("offlineformat") == "json" {
+		data, err := newJSONTransaction(signedTx)
+		cli.ErrCheck(err, quiet, "Failed to create JSON transaction")
+		cli.ErrCheck(outputJSON(activeCmd, data), quiet, "Failed to output JSON transaction")
+		return
+	}
+	buf := new(bytes.Buffer)
+	signedTx.EncodeRLP(buf)
+	fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+}
+
 func outputIf(condition bool, msg string) {
 	if condition {
 		fmt.Println(msg)
 	}
 }
 
+// parseAmount parses an amount of Ether, such as "1.5 ether" or "30 gwei".
+// Amounts without a unit are rejected unless allowbareamounts is set, in
+// which case they are a number of Wei.
+func parseAmount(input string) (*big.Int, error) {
+	return util.StringToWei(input, viper.GetBool("allowbareamounts"))
+}
+
+// resolveAddressOffline resolves a name or address, which is required to be an
+// address in offline mode as names cannot be resolved without a connection.
+func resolveAddressOffline(input string) common.Address {
+	if offline {
+		cli.Assert(util.IsHexAddressString(input), quiet, fmt.Sprintf("%s must be an address in offline mode", input))
+	}
+	address, err := util.ResolveAddress(client, input)
+	cli.ErrCheck(err, quiet, fmt.Sprintf("Invalid name/address %s", input))
+	return address
+}
+
 func localContext() (context.Context, context.CancelFunc) {
 	return context.WithTimeout(context.Background(), viper.GetDuration("timeout"))
 }
@@ -16,13 +16,16 @@ package cmd
 import (
 	"encoding/csv"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	"github.com/wealdtech/ethereal/util/funcparser"
 )
 
@@ -30,6 +33,7 @@ var signatureDataStr string
 var signatureTypes string
 var signatureNoHash bool
 var signaturePacked bool
+var signatureCanonicalize bool
 
 // signatureCmd represents the signature command
 var signatureCmd = &cobra.Command{
@@ -39,7 +43,14 @@ var signatureCmd = &cobra.Command{
 	Long:    `Sign and verify information.`,
 }
 
+// generateDataHash generates the hash to be signed for the supplied data.
 func generateDataHash() []byte {
+	return crypto.Keccak256(generateMessage())
+}
+
+// generateMessage generates the message to be hashed and signed for the
+// supplied data, prefixed with the standard Ethereum signed message header.
+func generateMessage() []byte {
 	var data []byte
 	if signatureTypes == "" {
 		// No types; might be a hex string or a non-hex string
@@ -94,11 +105,87 @@ func generateDataHash() []byte {
 		data = crypto.Keccak256(data)
 		outputIf(verbose, fmt.Sprintf("Hashed data is %x", data))
 	}
+	return personalMessage(data)
+}
+
+// personalMessage returns data prefixed with the standard Ethereum signed
+// message header.
+func personalMessage(data []byte) []byte {
 	buffer := make([]byte, 0)
 	buffer = append(buffer, []byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d", len(data)))...)
 	buffer = append(buffer, data...)
 	outputIf(verbose, fmt.Sprintf("Data to sign is %x", buffer))
-	return crypto.Keccak256(buffer)
+	return buffer
+}
+
+// personalMessageHash returns the hash of data prefixed with the standard
+// Ethereum signed message header.
+func personalMessageHash(data []byte) []byte {
+	return crypto.Keccak256(personalMessage(data))
+}
+
+// recoverSigner recovers the address that generated a signature over the
+// given hash, along with the signature in its 65-byte form with a recovery ID
+// of 0 or 1.  The signature can be either 65 bytes, with a recovery ID of
+// 0, 1, 27 or 28, or a 64-byte EIP-2098 compact signature.
+//
+// Signatures with a high S value are rejected as per EIP-2, to match
+// contracts that enforce it, unless --canonicalize is set in which case they
+// are converted to their low-S form.
+func recoverSigner(hash []byte, signatureStr string) (common.Address, []byte, error) {
+	signature, err := decodeSignature(signatureStr)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	if !util.IsLowS(signature) {
+		if !signatureCanonicalize {
+			return common.Address{}, nil, errors.New("signature has a high S value, which is invalid as per EIP-2 (use --canonicalize to accept it)")
+		}
+		signature = util.NormalizeSignature(signature)
+		outputIf(verbose, fmt.Sprintf("Canonical signature is %x", signature))
+	}
+	key, err := crypto.SigToPub(hash, signature)
+	if err != nil {
+		return common.Address{}, nil, err
+	}
+	return crypto.PubkeyToAddress(*key), signature, nil
+}
+
+// decodeSignature decodes a hex signature in to its 65-byte form with a
+// recovery ID of 0 or 1.  The signature can be either 65 bytes, with a
+// recovery ID of 0, 1, 27 or 28, or a 64-byte EIP-2098 compact signature.
+func decodeSignature(signatureStr string) ([]byte, error) {
+	signature, err := hex.DecodeString(strings.TrimPrefix(signatureStr, "0x"))
+	if err != nil {
+		return nil, errors.New("invalid hex string")
+	}
+	switch len(signature) {
+	case 64:
+		// Compact signature; recovery ID is the top bit of s
+		recoveryID := signature[32] >> 7
+		signature = append(signature, recoveryID)
+		signature[32] &= 0x7f
+	case 65:
+		// Copy to avoid altering the caller's data
+		signature = append([]byte{}, signature...)
+		if signature[64] >= 27 {
+			signature[64] -= 27
+		}
+	default:
+		return nil, fmt.Errorf("invalid signature length %d", len(signature))
+	}
+	if signature[64] > 1 {
+		return nil, fmt.Errorf("invalid recovery ID %d", signature[64])
+	}
+	return signature, nil
+}
+
+// compactSignature returns the 64-byte EIP-2098 compact form of a 65-byte
+// signature with a recovery ID of 0 or 1, which is held in the top bit of s.
+func compactSignature(signature []byte) []byte {
+	compact := append([]byte{}, signature[:64]...)
+	compact[32] |= signature[64] << 7
+	return compact
 }
 
 func argumentsAndValues(items string, types string) (abi.Arguments, []interface{}) {
@@ -140,4 +227,9 @@ func signatureFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&signatureTypes, "types", "", "Comma-separated list of data types")
 	cmd.Flags().BoolVar(&signatureNoHash, "nohash", false, "do not hash the message prior to signing")
 	cmd.Flags().BoolVar(&signaturePacked, "packed", false, "use Solidity packed encoding")
+	cmd.Flags().Bool("padbytes", false, "Right-pad values for fixed-size byte types (e.g. bytes32) that are too short, rather than rejecting them")
+}
+
+func signatureVerifyFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&signatureCanonicalize, "canonicalize", false, "accept signatures with a high S value by converting them to their low-S form, rather than rejecting them as per EIP-2")
 }
@@ -0,0 +1,124 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+)
+
+var signatureCombineSignatures []string
+var signatureCombineFile string
+var signatureCombineSigners []string
+
+// signatureCombineSignature is a signature along with its signer.
+type signatureCombineSignature struct {
+	signer    common.Address
+	signature []byte
+}
+
+// signatureCombineCmd represents the signature combine command
+var signatureCombineCmd = &cobra.Command{
+	Use:   "combine",
+	Short: "Combine multiple signatures",
+	Long: `Combine a number of signatures of the same data in to a single value, ordered by signer address as expected by multi-signature contracts such as Gnosis Safe.  For example:
+
+    ethereal signature combine --data="Hello, world" --signature=0x16f7...1500 --signature=0x8a3c...4b01
+
+Signatures can be supplied with repeated --signature arguments, or in a file given by --file with one signature per line.  Each signature can be either 65 bytes, with a recovery ID of 0, 1, 27 or 28, or a 64-byte EIP-2098 compact signature.
+
+Signatures with a high S value are rejected as per EIP-2 unless --canonicalize is supplied, in which case they are converted to their low-S form before being combined.
+
+The signer of each signature is recovered, and it is an error for the same signer to appear more than once.  If --signers is supplied then every signer must be one of the supplied addresses.
+
+The combined signature is the concatenation of the 65-byte r||s||v signatures in ascending order of signer address, where v is 27 or 28.
+
+In quiet mode this will return 0 if the signatures can be combined, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(signatureDataStr != "", quiet, "--data is required")
+
+		signatureStrs := signatureCombineSignatures
+		if signatureCombineFile != "" {
+			data, err := ioutil.ReadFile(signatureCombineFile)
+			cli.ErrCheck(err, quiet, "Failed to read signature file")
+			for _, line := range strings.Split(string(data), "\n") {
+				line = strings.TrimSpace(line)
+				if line != "" {
+					signatureStrs = append(signatureStrs, line)
+				}
+			}
+		}
+		cli.Assert(len(signatureStrs) > 0, quiet, "--signature or --file is required")
+
+		var allowed map[common.Address]bool
+		if len(signatureCombineSigners) > 0 {
+			allowed = make(map[common.Address]bool)
+			for _, signerStr := range signatureCombineSigners {
+				signer, err := signatureSignerAddress(signerStr)
+				cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve signer %s", signerStr))
+				allowed[signer] = true
+			}
+		}
+
+		dataHash := generateDataHash()
+
+		signatures := make([]*signatureCombineSignature, 0, len(signatureStrs))
+		seen := make(map[common.Address]bool)
+		for i, signatureStr := range signatureStrs {
+			signer, signature, err := recoverSigner(dataHash, signatureStr)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to recover signer of signature %d", i))
+			outputIf(verbose, fmt.Sprintf("Signature %d signed by %s", i, signer.Hex()))
+			cli.Assert(!seen[signer], quiet, fmt.Sprintf("Signature %d is from duplicate signer %s", i, signer.Hex()))
+			cli.Assert(allowed == nil || allowed[signer], quiet, fmt.Sprintf("Signature %d is from %s, which is not a supplied signer", i, signer.Hex()))
+			seen[signer] = true
+			signatures = append(signatures, &signatureCombineSignature{
+				signer:    signer,
+				signature: signature,
+			})
+		}
+
+		sort.Slice(signatures, func(i, j int) bool {
+			return bytes.Compare(signatures[i].signer.Bytes(), signatures[j].signer.Bytes()) < 0
+		})
+
+		if quiet {
+			os.Exit(_exit_success)
+		}
+
+		combined := make([]byte, 0, len(signatures)*65)
+		for _, signature := range signatures {
+			combined = append(combined, signature.signature[:64]...)
+			combined = append(combined, signature.signature[64]+27)
+		}
+		fmt.Printf("0x%x\n", combined)
+	},
+}
+
+func init() {
+	offlineCmds["signature:combine"] = true
+	signatureCmd.AddCommand(signatureCombineCmd)
+	signatureFlags(signatureCombineCmd)
+	signatureVerifyFlags(signatureCombineCmd)
+	signatureCombineCmd.Flags().StringArrayVar(&signatureCombineSignatures, "signature", nil, "Hex string signature to combine; can be repeated")
+	signatureCombineCmd.Flags().StringVar(&signatureCombineFile, "file", "", "File containing signatures to combine, one per line")
+	signatureCombineCmd.Flags().StringSliceVar(&signatureCombineSigners, "signers", nil, "Address (or comma-separated addresses) of permitted signers")
+}
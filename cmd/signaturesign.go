@@ -17,7 +17,6 @@ import (
 	"crypto/ecdsa"
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -28,7 +27,12 @@ import (
 
 var signatureSignSigner string
 var signatureSignPrivateKey string
+var signatureSignMnemonic string
+var signatureSignMnemonicFile string
+var signatureSignPath string
 var signatureSignPassphrase string
+var signatureSignShowMessage bool
+var signatureSignFormat string
 
 // signatureSignCmd represents the signature sign command
 var signatureSignCmd = &cobra.Command{
@@ -38,6 +42,18 @@ var signatureSignCmd = &cobra.Command{
 
     ethereal signature sign --data="false,2,0x5FfC014343cd971B7eb70732021E26C35B744cc4" --types="bool,uint256,address" --signer=0x1234...5678 --passphrase=secret
 
+The signer can be an address or an ENS name.  Alternatively, data can be signed directly with a private key by supplying --privatekey instead of --signer and --passphrase, or with a key derived from a BIP-39 mnemonic by supplying --mnemonic (or --mnemonicfile, or the ETHEREAL_MNEMONIC environment variable) and --path.
+
+If --showmessage is supplied then the exact message being signed, including the standard Ethereum signed message header, is output in hex along with its hash prior to the signature.  This allows the hash to be verified independently.
+
+The format of the signature is selected with --format:
+  - hex (default): the 65-byte signature r||s||v in hex, where v is the
+    recovery ID of 0 or 1
+  - eip2098: the 64-byte EIP-2098 compact signature r||vs in hex, where the
+    recovery ID is held in the top bit of s
+  - rsv-json: a JSON object with separate r, s and v fields, where v is the
+    recovery ID plus 27 (i.e. 27 or 28) as expected by ecrecover()
+
 In quiet mode this will return 0 if the data can be signed, otherwise 1.
 
 Signing data in Ethereum is complex, so details of exactly how this operates are
@@ -61,39 +77,98 @@ provided below:
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(signatureDataStr != "", quiet, "--data is required")
+		switch signatureSignFormat {
+		case "hex", "eip2098", "rsv-json":
+		default:
+			cli.Err(quiet, fmt.Sprintf("Unknown format %s; supported formats are hex, eip2098 and rsv-json", signatureSignFormat))
+		}
 
-		dataHash := generateDataHash()
+		message := generateMessage()
+		dataHash := crypto.Keccak256(message)
+		if signatureSignShowMessage && !quiet {
+			fmt.Printf("Message: 0x%x\n", message)
+			fmt.Printf("Hash: 0x%x\n", dataHash)
+		}
 
 		// Sign the hash
-		var signature []byte
-		var key *ecdsa.PrivateKey
-		var err error
-		if signatureSignPassphrase != "" {
-			signer := common.HexToAddress(signatureSignSigner)
-			key, err = util.PrivateKeyForAccount(chainID, signer, signatureSignPassphrase)
-			cli.ErrCheck(err, quiet, "Invalid account or passphrse")
-		} else if signatureSignPrivateKey != "" {
-			key, err = crypto.HexToECDSA(strings.TrimPrefix(signatureSignPrivateKey, "0x"))
-			cli.ErrCheck(err, quiet, "Invalid private key")
-		} else {
-			cli.Err(quiet, fmt.Sprintf("no passphrase or private key; cannot sign"))
-		}
-		signature, err = crypto.Sign(dataHash, key)
+		signature, err := crypto.Sign(dataHash, signatureSigningKey())
 		cli.ErrCheck(err, quiet, "Failed to sign data")
+		// Always output the low-S form, as required by EIP-2
+		signature = util.NormalizeSignature(signature)
 
 		if quiet {
 			os.Exit(_exit_success)
 		}
 
-		fmt.Printf("%x\n", signature)
+		if signatureSignFormat != "rsv-json" {
+			cli.WarnCheck(recordJSON(cmd, newJSONSignature(signature)), quiet, "Failed to record JSON output")
+		}
+		switch signatureSignFormat {
+		case "eip2098":
+			fmt.Printf("%x\n", compactSignature(signature))
+		case "rsv-json":
+			cli.ErrCheck(outputJSON(cmd, newJSONSignature(signature)), quiet, "Failed to output signature")
+		default:
+			fmt.Printf("%x\n", signature)
+		}
 	},
 }
 
+// signatureSigningKey obtains the key with which to sign from either the
+// signer and passphrase or the private key.
+func signatureSigningKey() *ecdsa.PrivateKey {
+	var key *ecdsa.PrivateKey
+	if signatureSignPassphrase != "" {
+		cli.Assert(signatureSignSigner != "", quiet, "--signer is required when signing with a passphrase")
+		signer, err := signatureSignerAddress(signatureSignSigner)
+		cli.ErrCheck(err, quiet, "Failed to resolve signer")
+		wallet, err := cli.ObtainWallet(chainID, signer)
+		cli.ErrCheck(err, quiet, "Account not found")
+		account, err := cli.ObtainAccount(&wallet, &signer, signatureSignPassphrase)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain account %s", signer.Hex()))
+		// The wallet cannot sign a pre-computed hash, so sign with the key directly
+		key, err = util.PrivateKeyForAccount(chainID, account.Address, signatureSignPassphrase)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain key for %s", signer.Hex()))
+	} else {
+		var err error
+		key, err = resolvePrivateKey(signatureSignPrivateKey, signatureSignMnemonic, signatureSignMnemonicFile, signatureSignPath)
+		cli.ErrCheck(err, quiet, "Failed to obtain private key")
+		cli.Assert(key != nil, quiet, "no passphrase or private key; cannot sign")
+	}
+	return key
+}
+
+// signatureSigningKeyFlags adds the flags that supply the key with which to sign.
+func signatureSigningKeyFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&signatureSignSigner, "signer", "", "Address of the account to sign the data")
+	cmd.Flags().StringVar(&signatureSignPassphrase, "passphrase", "", "Passphrase of the account to sign the data")
+	cmd.Flags().StringVar(&signatureSignPrivateKey, "privatekey", "", "Private key to sign the data")
+	cmd.Flags().StringVar(&signatureSignMnemonic, "mnemonic", "", "BIP-39 mnemonic from which to derive the private key to sign the data")
+	cmd.Flags().StringVar(&signatureSignMnemonicFile, "mnemonicfile", "", "File containing the BIP-39 mnemonic")
+	cmd.Flags().StringVar(&signatureSignPath, "path", "", fmt.Sprintf("Derivation path of the private key (default %s)", util.DefaultHDPath))
+}
+
+// signatureSignerAddress obtains the address of the signer, resolving it
+// through ENS if required.
+func signatureSignerAddress(input string) (common.Address, error) {
+	if util.IsHexAddressString(input) {
+		return util.HexAddress(input)
+	}
+	if client == nil {
+		// Signing is an offline command, so connect to resolve the name
+		if err := connect(); err != nil {
+			return common.Address{}, err
+		}
+	}
+	return util.ResolveAddress(client, input)
+}
+
 func init() {
 	offlineCmds["signature:sign"] = true
 	signatureCmd.AddCommand(signatureSignCmd)
 	signatureFlags(signatureSignCmd)
-	signatureSignCmd.Flags().StringVar(&signatureSignSigner, "signer", "", "Address of the account to sign the data")
-	signatureSignCmd.Flags().StringVar(&signatureSignPassphrase, "passphrase", "", "Passphrase of the account to sign the data")
-	signatureSignCmd.Flags().StringVar(&signatureSignPrivateKey, "privatekey", "", "Private key to sign the data")
+	signatureSigningKeyFlags(signatureSignCmd)
+	signatureSignCmd.Flags().StringVar(&signatureSignFormat, "format", "hex", "Format of the signature (hex, eip2098 or rsv-json)")
+	signatureSignCmd.Flags().BoolVar(&signatureSignShowMessage, "showmessage", false, "Output the message being signed and its hash")
+	jsonoutCmds["signature:sign"] = true
 }
@@ -14,15 +14,12 @@
 package cmd
 
 import (
-	"encoding/hex"
 	"fmt"
 	"os"
-	"strings"
 
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
-	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/ethereal/util"
 )
 
 var signatureSignerSignature string
@@ -41,19 +38,14 @@ In quiet mode this will return 0 if the signature provides a valid signer, other
 
 		dataHash := generateDataHash()
 
-		signature, err := hex.DecodeString(strings.TrimPrefix(signatureSignerSignature, "0x"))
+		address, _, err := recoverSigner(dataHash, signatureSignerSignature)
 		cli.ErrCheck(err, quiet, "Invalid signature")
 
-		key, err := crypto.SigToPub(dataHash, []byte(signature))
-		cli.ErrCheck(err, quiet, "Failed to signer signature")
-		cli.Assert(key != nil, quiet, "Invalid signature")
-		address := crypto.PubkeyToAddress(*key)
-
 		if quiet {
 			os.Exit(_exit_success)
 		}
 
-		fmt.Printf("%s\n", ens.Format(client, address))
+		fmt.Printf("%s\n", util.ENSFormat(client, address))
 	},
 }
 
@@ -61,5 +53,6 @@ func init() {
 	offlineCmds["signature:signer"] = true
 	signatureCmd.AddCommand(signatureSignerCmd)
 	signatureFlags(signatureSignerCmd)
+	signatureVerifyFlags(signatureSignerCmd)
 	signatureSignerCmd.Flags().StringVar(&signatureSignerSignature, "signature", "", "Hex string signature from which to obtain the signer")
 }
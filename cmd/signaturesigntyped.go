@@ -0,0 +1,80 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+)
+
+var signatureSignTypedFile string
+
+// signatureSignTypedCmd represents the signature signtyped command
+var signatureSignTypedCmd = &cobra.Command{
+	Use:   "signtyped",
+	Short: "Sign EIP-712 typed data",
+	Long: `Sign structured data as defined by EIP-712.  For example:
+
+    ethereal signature signtyped --file=typeddata.json --signer=0x1234...5678 --passphrase=secret
+
+where the file contains a standard EIP-712 JSON document with domain, types, primaryType and message fields.  The signature uses a recovery ID of 27 or 28 as expected by wallets and verifiers.
+
+In quiet mode this will return 0 if the data can be signed, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(signatureSignTypedFile != "", quiet, "--file is required")
+
+		data, err := ioutil.ReadFile(signatureSignTypedFile)
+		cli.ErrCheck(err, quiet, "Failed to read typed data file")
+		var typedData core.TypedData
+		err = json.Unmarshal(data, &typedData)
+		cli.ErrCheck(err, quiet, "Failed to parse typed data")
+		cli.Assert(typedData.PrimaryType != "", quiet, "Typed data has no primary type")
+		_, exists := typedData.Types["EIP712Domain"]
+		cli.Assert(exists, quiet, "Typed data has no EIP712Domain type")
+
+		domainSeparator, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
+		cli.ErrCheck(err, quiet, "Failed to hash domain")
+		outputIf(verbose, fmt.Sprintf("Domain separator is %#x", []byte(domainSeparator)))
+		structHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+		cli.ErrCheck(err, quiet, "Failed to hash message")
+		outputIf(verbose, fmt.Sprintf("Struct hash is %#x", []byte(structHash)))
+		hash := crypto.Keccak256([]byte{0x19, 0x01}, domainSeparator, structHash)
+		outputIf(verbose, fmt.Sprintf("Hash to sign is %#x", hash))
+
+		signature, err := crypto.Sign(hash, signatureSigningKey())
+		cli.ErrCheck(err, quiet, "Failed to sign data")
+		signature = util.NormalizeSignature(signature)
+		signature[64] += 27
+
+		if quiet {
+			os.Exit(_exit_success)
+		}
+		fmt.Printf("%#x\n", signature)
+	},
+}
+
+func init() {
+	offlineCmds["signature:signtyped"] = true
+	signatureCmd.AddCommand(signatureSignTypedCmd)
+	signatureSignTypedCmd.Flags().StringVar(&signatureSignTypedFile, "file", "", "Path to a file containing EIP-712 typed data in JSON format")
+	signatureSigningKeyFlags(signatureSignTypedCmd)
+}
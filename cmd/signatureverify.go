@@ -14,13 +14,9 @@
 package cmd
 
 import (
-	"bytes"
-	"encoding/hex"
+	"fmt"
 	"os"
-	"strings"
 
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
 )
@@ -34,33 +30,32 @@ var signatureVerifyCmd = &cobra.Command{
 	Short: "Verify a signature",
 	Long: `Verify a presented signature.  For example:
 
-    ethereal data verify --data="false,2,0x5FfC014343cd971B7eb70732021E26C35B744cc4" --types="bool,uint256,address" --signature=0xcefd09e935b867a231086f41d98644655081a6e4e87c43e05fbbf621dfda69ea305c64fcf73907e09ce242c8ab8bcb953c4b45dd78262d8e34b22a8e4309734f00 --signer=0x0x5FfC014343cd971B7eb70732021E26C35B744cc4
+    ethereal signature verify --data="false,2,0x5FfC014343cd971B7eb70732021E26C35B744cc4" --types="bool,uint256,address" --signature=0xcefd09e935b867a231086f41d98644655081a6e4e87c43e05fbbf621dfda69ea305c64fcf73907e09ce242c8ab8bcb953c4b45dd78262d8e34b22a8e4309734f00 --signer=0x5FfC014343cd971B7eb70732021E26C35B744cc4
+
+The data is hashed in the same way as for "signature sign", so --types, --packed and --nohash must match those used when signing.  The signature can be 65 bytes with a recovery ID of 0, 1, 27 or 28, or a 64-byte EIP-2098 compact signature.
+
+Signatures with a high S value are rejected as per EIP-2, as they would be by contracts that enforce it.  If --canonicalize is supplied they are instead converted to their low-S form before verification.
 
 In quiet mode this will return 0 if the signature is valid, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(signatureDataStr != "", quiet, "--data is required")
+		cli.Assert(signatureVerifySignature != "", quiet, "--signature is required")
 		cli.Assert(signatureVerifySigner != "", quiet, "--signer is required")
+		verifySigner, err := signatureSignerAddress(signatureVerifySigner)
+		cli.ErrCheck(err, quiet, "Failed to resolve signer")
 
 		dataHash := generateDataHash()
 
-		signature, err := hex.DecodeString(strings.TrimPrefix(signatureVerifySignature, "0x"))
+		signer, _, err := recoverSigner(dataHash, signatureVerifySignature)
 		cli.ErrCheck(err, quiet, "Invalid signature")
+		outputIf(verbose, fmt.Sprintf("Signer is %s", signer.Hex()))
 
-		key, err := crypto.SigToPub(dataHash, []byte(signature))
-		cli.ErrCheck(err, quiet, "Failed to signer signature")
-		cli.Assert(key != nil, quiet, "Invalid signature")
-		signer := crypto.PubkeyToAddress(*key)
-
-		verifySigner := common.HexToAddress(signatureVerifySigner)
-
-		verified := bytes.Compare(signer.Bytes(), verifySigner.Bytes()) == 0
-		if verified {
-			outputIf(!quiet, "Verified")
-			os.Exit(_exit_success)
-		} else {
-			outputIf(!quiet, "Not verified")
+		if signer != verifySigner {
+			outputIf(!quiet, fmt.Sprintf("Not verified: signed by %s rather than %s", signer.Hex(), verifySigner.Hex()))
 			os.Exit(_exit_failure)
 		}
+		outputIf(!quiet, fmt.Sprintf("Verified: signed by %s", signer.Hex()))
+		os.Exit(_exit_success)
 	},
 }
 
@@ -68,6 +63,7 @@ func init() {
 	offlineCmds["signature:verify"] = true
 	signatureCmd.AddCommand(signatureVerifyCmd)
 	signatureFlags(signatureVerifyCmd)
+	signatureVerifyFlags(signatureVerifyCmd)
 	signatureVerifyCmd.Flags().StringVar(&signatureVerifySignature, "signature", "", "Hex string signature from which to verify the signer")
-	signatureVerifyCmd.Flags().StringVar(&signatureVerifySigner, "signer", "", "Address of the signer")
+	signatureVerifyCmd.Flags().StringVar(&signatureVerifySigner, "signer", "", "Address or ENS name of the signer")
 }
@@ -0,0 +1,133 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+)
+
+var signatureVerifyBatchFile string
+var signatureVerifyBatchUnique bool
+
+// signatureVerifyBatchResult is the result of verifying a single signature.
+type signatureVerifyBatchResult struct {
+	signer   common.Address
+	verified bool
+	reason   string
+}
+
+// signatureVerifyBatchCmd represents the signature verifybatch command
+var signatureVerifyBatchCmd = &cobra.Command{
+	Use:   "verifybatch",
+	Short: "Verify a batch of signatures",
+	Long: `Verify a number of signatures of the same data.  For example:
+
+    ethereal signature verifybatch --data="false,2,0x5FfC014343cd971B7eb70732021E26C35B744cc4" --types="bool,uint256,address" --file=signatures.csv
+
+where each line of the file contains the signer's address and the signature, separated by a comma.  If --unique is supplied then signers that appear more than once are reported.
+
+In quiet mode this will return 0 if all signatures are valid, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(signatureDataStr != "", quiet, "--data is required")
+		cli.Assert(signatureVerifyBatchFile != "", quiet, "--file is required")
+
+		dataHash := generateDataHash()
+
+		f, err := os.Open(signatureVerifyBatchFile)
+		cli.ErrCheck(err, quiet, "Failed to open signature file")
+		defer f.Close()
+		reader := csv.NewReader(f)
+		reader.FieldsPerRecord = 2
+		reader.TrimLeadingSpace = true
+		rows, err := reader.ReadAll()
+		cli.ErrCheck(err, quiet, "Failed to read signature file")
+
+		// Recover the signers in parallel
+		results := make([]*signatureVerifyBatchResult, len(rows))
+		rowCh := make(chan int)
+		var wg sync.WaitGroup
+		for i := 0; i < runtime.NumCPU(); i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for row := range rowCh {
+					results[row] = signatureVerifyBatchRow(dataHash, rows[row])
+				}
+			}()
+		}
+		for i := range rows {
+			rowCh <- i
+		}
+		close(rowCh)
+		wg.Wait()
+
+		valid := 0
+		seen := make(map[common.Address]int)
+		for i, result := range results {
+			if result.verified {
+				valid++
+				if signatureVerifyBatchUnique {
+					if prev, exists := seen[result.signer]; exists {
+						outputIf(!quiet, fmt.Sprintf("%d: %s duplicate (first seen at %d)", i+1, result.signer.Hex(), prev+1))
+					} else {
+						seen[result.signer] = i
+					}
+				}
+				outputIf(verbose, fmt.Sprintf("%d: %s verified", i+1, result.signer.Hex()))
+			} else {
+				outputIf(!quiet, fmt.Sprintf("%d: %s not verified: %s", i+1, rows[i][0], result.reason))
+			}
+		}
+		outputIf(!quiet, fmt.Sprintf("%d of %d signatures verified", valid, len(rows)))
+
+		if valid != len(rows) {
+			os.Exit(_exit_failure)
+		}
+		os.Exit(_exit_success)
+	},
+}
+
+// signatureVerifyBatchRow verifies a single row of the batch.
+func signatureVerifyBatchRow(dataHash []byte, row []string) *signatureVerifyBatchResult {
+	signer, err := util.HexAddress(row[0])
+	if err != nil {
+		return &signatureVerifyBatchResult{reason: fmt.Sprintf("invalid signer address: %v", err)}
+	}
+	recovered, _, err := recoverSigner(dataHash, row[1])
+	if err != nil {
+		return &signatureVerifyBatchResult{signer: signer, reason: fmt.Sprintf("invalid signature: %v", err)}
+	}
+	if recovered != signer {
+		return &signatureVerifyBatchResult{signer: signer, reason: "signed by another address"}
+	}
+	return &signatureVerifyBatchResult{signer: signer, verified: true}
+}
+
+func init() {
+	offlineCmds["signature:verifybatch"] = true
+	signatureCmd.AddCommand(signatureVerifyBatchCmd)
+	signatureFlags(signatureVerifyBatchCmd)
+	signatureVerifyFlags(signatureVerifyBatchCmd)
+	signatureVerifyBatchCmd.Flags().StringVar(&signatureVerifyBatchFile, "file", "", "Path to a CSV file containing signer,signature pairs")
+	signatureVerifyBatchCmd.Flags().BoolVar(&signatureVerifyBatchUnique, "unique", false, "Report signers that appear more than once")
+}
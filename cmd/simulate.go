@@ -0,0 +1,177 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	"github.com/wealdtech/ethereal/util/funcparser"
+)
+
+var simulateFile string
+
+// simulateStep is a single step of a simulation.
+type simulateStep struct {
+	From     string `json:"from"`
+	Contract string `json:"contract"`
+	ABI      string `json:"abi"`
+	Function string `json:"function"`
+	Call     string `json:"call"`
+	Data     string `json:"data"`
+	Value    string `json:"value"`
+}
+
+// simulateCmd represents the simulate command
+var simulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "Simulate a sequence of transactions",
+	Long: `Simulate a sequence of transactions without sending them to the blockchain.  For example:
+
+    ethereal simulate --file=bundle.json
+
+where the file contains a JSON array of steps, for example:
+
+    [
+      {"from":"0x5FfC014343cd971B7eb70732021E26C35B744cc4","contract":"0xd26114cd6EE289AccF82350c8d8487fedB8A0C07","function":"approve(address,uint256)","call":"approve(0x52f1A3027d3aA514F17E454C93ae1F79b3B12d5d,100)"},
+      {"from":"0x52f1A3027d3aA514F17E454C93ae1F79b3B12d5d","contract":"0xd26114cd6EE289AccF82350c8d8487fedB8A0C07","function":"transferFrom(address,address,uint256)","call":"transferFrom(0x5FfC014343cd971B7eb70732021E26C35B744cc4,0x52f1A3027d3aA514F17E454C93ae1F79b3B12d5d,100)"}
+    ]
+
+Each step supplies either an ABI (or path to an ABI) or a function signature along with the call, or raw hex data.  A step can also supply an Ether value, with a unit (for example "0.1 ether").  Steps are executed in order against the latest block, with the state changes from each step applied to the following steps.  This requires a connection that supports debug_traceCall with the prestateTracer; if it does not then each step is executed against the unchanged state.
+
+In quiet mode this will return 0 if all steps succeed, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(simulateFile != "", quiet, "--file is required")
+		data, err := ioutil.ReadFile(simulateFile)
+		cli.ErrCheck(err, quiet, "Failed to read simulation file")
+		var steps []*simulateStep
+		cli.ErrCheck(json.Unmarshal(data, &steps), quiet, "Failed to parse simulation file")
+		cli.Assert(len(steps) > 0, quiet, "No steps in simulation file")
+
+		overrides := make(util.StateOverrides)
+		accumulate := true
+		failed := false
+		for i, step := range steps {
+			msg, method := simulateMessage(i, step)
+
+			var changes util.StateOverrides
+			if accumulate && i < len(steps)-1 {
+				ctx, cancel := localContext()
+				changes, err = util.CallStateChanges(ctx, rpcClient, msg, overrides)
+				cancel()
+				if err != nil {
+					outputIf(!quiet, fmt.Sprintf("Unable to obtain state changes (%v); subsequent steps will not see the results of earlier steps", err))
+					accumulate = false
+				}
+			}
+
+			ctx, cancel := localContext()
+			result, err := util.CallWithOverrides(ctx, rpcClient, msg, overrides)
+			cancel()
+			if changes != nil && err == nil {
+				overrides.Merge(changes)
+			}
+			if err != nil {
+				failed = true
+				outputIf(!quiet, fmt.Sprintf("Step %d: reverted: %s", i+1, util.RevertReason(err)))
+				continue
+			}
+			if method == nil || len(method.Outputs) == 0 {
+				outputIf(!quiet, fmt.Sprintf("Step %d: succeeded", i+1))
+				outputIf(!quiet && verbose && len(result) > 0, fmt.Sprintf("  Result is %x", result))
+				continue
+			}
+			outputIf(!quiet, fmt.Sprintf("Step %d: succeeded, returned %s", i+1, simulateResult(method, result)))
+		}
+
+		if failed {
+			os.Exit(_exit_failure)
+		}
+		os.Exit(_exit_success)
+	},
+}
+
+// simulateMessage creates the message for a simulation step.
+func simulateMessage(index int, step *simulateStep) (ethereum.CallMsg, *abi.Method) {
+	cli.Assert(step.From != "", quiet, fmt.Sprintf("Step %d: from is required", index+1))
+	from, err := util.ResolveAddress(client, step.From)
+	cli.ErrCheck(err, quiet, fmt.Sprintf("Step %d: failed to resolve from address %s", index+1, step.From))
+	cli.Assert(step.Contract != "", quiet, fmt.Sprintf("Step %d: contract is required", index+1))
+	to, err := util.ResolveAddress(client, step.Contract)
+	cli.ErrCheck(err, quiet, fmt.Sprintf("Step %d: failed to resolve contract address %s", index+1, step.Contract))
+
+	msg := ethereum.CallMsg{
+		From: from,
+		To:   &to,
+	}
+	if step.Value != "" {
+		msg.Value, err = parseAmount(step.Value)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Step %d: invalid value %s", index+1, step.Value))
+	}
+
+	if step.Data != "" {
+		msg.Data, err = hex.DecodeString(strings.TrimPrefix(step.Data, "0x"))
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Step %d: invalid data", index+1))
+		return msg, nil
+	}
+
+	cli.Assert(step.Call != "", quiet, fmt.Sprintf("Step %d: call or data is required", index+1))
+	var contractAbi *abi.ABI
+	switch {
+	case step.ABI != "":
+		parsed, err := contractParseAbi(step.ABI)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Step %d: failed to parse ABI", index+1))
+		contractAbi = &parsed
+	case step.Function != "":
+		contractAbi, err = contractParseFunction(step.Function)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Step %d: failed to parse function", index+1))
+	default:
+		cli.Err(quiet, fmt.Sprintf("Step %d: abi or function is required", index+1))
+	}
+	method, methodArgs, err := funcparser.ParseCall(client, &util.Contract{Abi: *contractAbi}, step.Call)
+	cli.ErrCheck(err, quiet, fmt.Sprintf("Step %d: failed to parse call", index+1))
+	msg.Data, err = contractAbi.Pack(method.Name, methodArgs...)
+	cli.ErrCheck(err, quiet, fmt.Sprintf("Step %d: failed to convert arguments", index+1))
+	return msg, method
+}
+
+// simulateResult decodes the result of a simulation step.
+func simulateResult(method *abi.Method, result []byte) string {
+	values, err := method.Outputs.UnpackValues(result)
+	if err != nil {
+		return fmt.Sprintf("%x (failed to decode: %v)", result, err)
+	}
+	results := make([]string, len(values))
+	for i := range values {
+		results[i], err = contractValueToString(method.Outputs[i].Type, values[i])
+		if err != nil {
+			results[i] = fmt.Sprintf("%v", values[i])
+		}
+	}
+	return strings.Join(results, ",")
+}
+
+func init() {
+	RootCmd.AddCommand(simulateCmd)
+	simulateCmd.Flags().StringVar(&simulateFile, "file", "", "JSON file containing the steps to simulate")
+}
@@ -0,0 +1,51 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+)
+
+var siweMessageStr string
+
+// siweCmd represents the siwe command
+var siweCmd = &cobra.Command{
+	Use:   "siwe",
+	Short: "Manage Sign-In with Ethereum messages",
+	Long:  `Create, sign and verify Sign-In with Ethereum (EIP-4361) messages.`,
+}
+
+func init() {
+	RootCmd.AddCommand(siweCmd)
+}
+
+func siweFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&siweMessageStr, "message", "", "The message, or path to a file containing the message")
+}
+
+// siweMessageText obtains the text of the message supplied to the command.
+func siweMessageText() string {
+	cli.Assert(siweMessageStr != "", quiet, "--message is required")
+	if _, err := os.Stat(siweMessageStr); err == nil {
+		data, err := ioutil.ReadFile(siweMessageStr)
+		cli.ErrCheck(err, quiet, "Failed to read message file")
+		return strings.TrimRight(string(data), "\r\n")
+	}
+	return siweMessageStr
+}
@@ -0,0 +1,124 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+)
+
+var siweCreateDomain string
+var siweCreateAddress string
+var siweCreateStatement string
+var siweCreateURI string
+var siweCreateNonce string
+var siweCreateExpirationTime string
+var siweCreateNotBefore string
+var siweCreateRequestID string
+var siweCreateResources string
+
+// siweCreateCmd represents the siwe create command
+var siweCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a Sign-In with Ethereum message",
+	Long: `Create a Sign-In with Ethereum (EIP-4361) message.  For example:
+
+    ethereal siwe create --domain=example.com --address=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --uri=https://example.com/login --statement="I accept the terms of service"
+
+If a nonce is not supplied then a random nonce is generated.  Expiration and not before times can be supplied either as RFC3339 times or as durations relative to the current time, for example "1h".  The chain ID is that of the selected network.
+
+In quiet mode this will return 0 if the message is created, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(siweCreateDomain != "", quiet, "--domain is required")
+		cli.Assert(siweCreateAddress != "", quiet, "--address is required")
+		address, err := util.HexAddress(siweCreateAddress)
+		cli.ErrCheck(err, quiet, "Invalid address")
+		cli.Assert(siweCreateURI != "", quiet, "--uri is required")
+
+		now := time.Now().UTC().Truncate(time.Second)
+		msg := &util.SIWEMessage{
+			Domain:    siweCreateDomain,
+			Address:   address,
+			Statement: siweCreateStatement,
+			URI:       siweCreateURI,
+			Version:   "1",
+			ChainID:   chainID.Uint64(),
+			Nonce:     siweCreateNonce,
+			IssuedAt:  now,
+			RequestID: siweCreateRequestID,
+		}
+		if msg.Nonce == "" {
+			msg.Nonce = siweNonce()
+		}
+		if siweCreateExpirationTime != "" {
+			msg.ExpirationTime = siweTime(now, siweCreateExpirationTime)
+		}
+		if siweCreateNotBefore != "" {
+			msg.NotBefore = siweTime(now, siweCreateNotBefore)
+		}
+		if siweCreateResources != "" {
+			msg.Resources = strings.Split(siweCreateResources, ",")
+		}
+		cli.ErrCheck(msg.Validate(now), quiet, "Invalid message")
+
+		if quiet {
+			os.Exit(_exit_success)
+		}
+		fmt.Println(msg.String())
+	},
+}
+
+// siweNonce generates a random alphanumeric nonce.
+func siweNonce() string {
+	const chars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	data := make([]byte, 16)
+	_, err := rand.Read(data)
+	cli.ErrCheck(err, quiet, "Failed to generate nonce")
+	for i := range data {
+		data[i] = chars[int(data[i])%len(chars)]
+	}
+	return string(data)
+}
+
+// siweTime parses a time supplied either as an RFC3339 time or as a duration from now.
+func siweTime(now time.Time, input string) *time.Time {
+	if duration, err := time.ParseDuration(input); err == nil {
+		res := now.Add(duration)
+		return &res
+	}
+	res, err := time.Parse(time.RFC3339, input)
+	cli.ErrCheck(err, quiet, fmt.Sprintf("Invalid time %s", input))
+	return &res
+}
+
+func init() {
+	offlineCmds["siwe:create"] = true
+	siweCmd.AddCommand(siweCreateCmd)
+	siweCreateCmd.Flags().StringVar(&siweCreateDomain, "domain", "", "Domain requesting the sign-in")
+	siweCreateCmd.Flags().StringVar(&siweCreateAddress, "address", "", "Address signing in")
+	siweCreateCmd.Flags().StringVar(&siweCreateStatement, "statement", "", "Human-readable statement for the user (optional)")
+	siweCreateCmd.Flags().StringVar(&siweCreateURI, "uri", "", "URI of the resource that is the subject of the sign-in")
+	siweCreateCmd.Flags().StringVar(&siweCreateNonce, "nonce", "", "Nonce (at least 8 alphanumeric characters; generated if not supplied)")
+	siweCreateCmd.Flags().StringVar(&siweCreateExpirationTime, "expirationtime", "", "Time at which the message expires (optional)")
+	siweCreateCmd.Flags().StringVar(&siweCreateNotBefore, "notbefore", "", "Time before which the message is not valid (optional)")
+	siweCreateCmd.Flags().StringVar(&siweCreateRequestID, "requestid", "", "Request ID (optional)")
+	siweCreateCmd.Flags().StringVar(&siweCreateResources, "resources", "", "Comma-separated list of resource URIs (optional)")
+}
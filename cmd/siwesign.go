@@ -0,0 +1,67 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+)
+
+// siweSignCmd represents the siwe sign command
+var siweSignCmd = &cobra.Command{
+	Use:   "sign",
+	Short: "Sign a Sign-In with Ethereum message",
+	Long: `Sign a Sign-In with Ethereum (EIP-4361) message with the account in the message.  For example:
+
+    ethereal siwe sign --message=message.txt --passphrase=secret
+
+The key can be supplied with --passphrase, --privatekey or --mnemonic (or --mnemonicfile, or the ETHEREAL_MNEMONIC environment variable) and --path, as for "signature sign", and must be that of the account in the message.  The message is signed as a personal message, and the signature uses a recovery ID of 27 or 28 as expected by wallets and verifiers.
+
+In quiet mode this will return 0 if the message can be signed, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		text := siweMessageText()
+		msg, err := util.ParseSIWEMessage(text)
+		cli.ErrCheck(err, quiet, "Failed to parse message")
+		cli.ErrCheck(msg.Validate(time.Now()), quiet, "Invalid message")
+
+		if signatureSignSigner == "" {
+			signatureSignSigner = msg.Address.Hex()
+		}
+		key := signatureSigningKey()
+		cli.Assert(crypto.PubkeyToAddress(key.PublicKey) == msg.Address, quiet, "Key does not match the address in the message")
+
+		signature, err := crypto.Sign(personalMessageHash([]byte(text)), key)
+		cli.ErrCheck(err, quiet, "Failed to sign message")
+		signature = util.NormalizeSignature(signature)
+		signature[64] += 27
+
+		if quiet {
+			os.Exit(_exit_success)
+		}
+		fmt.Printf("0x%x\n", signature)
+	},
+}
+
+func init() {
+	offlineCmds["siwe:sign"] = true
+	siweCmd.AddCommand(siweSignCmd)
+	siweFlags(siweSignCmd)
+	signatureSigningKeyFlags(siweSignCmd)
+}
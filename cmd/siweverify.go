@@ -0,0 +1,82 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+)
+
+var siweVerifySignature string
+var siweVerifyDomain string
+var siweVerifyNonce string
+
+// siweVerifyCmd represents the siwe verify command
+var siweVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify a signed Sign-In with Ethereum message",
+	Long: `Verify the signature of a Sign-In with Ethereum (EIP-4361) message.  For example:
+
+    ethereal siwe verify --message=message.txt --signature=0x8b09...1c
+
+The signature must be from the address in the message, and the message must be valid at the current time according to its expiration and not before times.  If --domain or --nonce are supplied then the message must also contain the given values.
+
+The signature can be either 65 bytes, with a recovery ID of 0, 1, 27 or 28, or a 64-byte EIP-2098 compact signature.  Signatures with a high S value are rejected as per EIP-2 unless --canonicalize is supplied.
+
+In quiet mode this will return 0 if the message is verified, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(siweVerifySignature != "", quiet, "--signature is required")
+		text := siweMessageText()
+		msg, err := util.ParseSIWEMessage(text)
+		cli.ErrCheck(err, quiet, "Failed to parse message")
+
+		signer, _, err := recoverSigner(personalMessageHash([]byte(text)), siweVerifySignature)
+		cli.ErrCheck(err, quiet, "Failed to recover signer")
+		outputIf(verbose, fmt.Sprintf("Signer is %s", signer.Hex()))
+
+		if signer != msg.Address {
+			outputIf(!quiet, fmt.Sprintf("Not verified: signed by %s rather than %s", signer.Hex(), msg.Address.Hex()))
+			os.Exit(_exit_failure)
+		}
+		if siweVerifyDomain != "" && siweVerifyDomain != msg.Domain {
+			outputIf(!quiet, fmt.Sprintf("Not verified: domain is %s", msg.Domain))
+			os.Exit(_exit_failure)
+		}
+		if siweVerifyNonce != "" && siweVerifyNonce != msg.Nonce {
+			outputIf(!quiet, fmt.Sprintf("Not verified: nonce is %s", msg.Nonce))
+			os.Exit(_exit_failure)
+		}
+		if err := msg.Validate(time.Now()); err != nil {
+			outputIf(!quiet, fmt.Sprintf("Not verified: %v", err))
+			os.Exit(_exit_failure)
+		}
+		outputIf(!quiet, "Verified")
+		os.Exit(_exit_success)
+	},
+}
+
+func init() {
+	offlineCmds["siwe:verify"] = true
+	siweCmd.AddCommand(siweVerifyCmd)
+	siweFlags(siweVerifyCmd)
+	signatureVerifyFlags(siweVerifyCmd)
+	siweVerifyCmd.Flags().StringVar(&siweVerifySignature, "signature", "", "Hex string signature of the message")
+	siweVerifyCmd.Flags().StringVar(&siweVerifyDomain, "domain", "", "Domain the message must contain (optional)")
+	siweVerifyCmd.Flags().StringVar(&siweVerifyNonce, "nonce", "", "Nonce the message must contain (optional)")
+}
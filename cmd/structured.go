@@ -0,0 +1,151 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// structuredTransaction is a representation of a transaction with its
+// contract call decoded, suitable for review by clear-signing tools.
+type structuredTransaction struct {
+	ChainID        string          `json:"chainId"`
+	From           string          `json:"from"`
+	To             string          `json:"to"`
+	Value          string          `json:"value"`
+	Nonce          uint64          `json:"nonce"`
+	Gas            uint64          `json:"gas"`
+	GasPrice       string          `json:"gasPrice"`
+	Data           string          `json:"data"`
+	Call           *structuredCall `json:"call"`
+	RawTransaction string          `json:"rawTransaction,omitempty"`
+}
+
+// structuredCall is a decoded contract call.
+type structuredCall struct {
+	Function string             `json:"function"`
+	Selector string             `json:"selector"`
+	Params   []*structuredParam `json:"params"`
+}
+
+// structuredParam is a decoded parameter of a contract call.
+type structuredParam struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// newStructuredTransaction creates a structured representation of a
+// transaction calling the given method with the given arguments.  If
+// includeRaw is set then the RLP-encoded transaction is also included.
+func newStructuredTransaction(from common.Address, tx *types.Transaction, method *abi.Method, args []interface{}, includeRaw bool) (*structuredTransaction, error) {
+	res := &structuredTransaction{
+		ChainID:  chainID.String(),
+		From:     from.Hex(),
+		Value:    tx.Value().String(),
+		Nonce:    tx.Nonce(),
+		Gas:      tx.Gas(),
+		GasPrice: tx.GasPrice().String(),
+		Data:     fmt.Sprintf("0x%x", tx.Data()),
+		Call: &structuredCall{
+			Function: method.Sig,
+			Selector: fmt.Sprintf("0x%x", method.ID),
+			Params:   make([]*structuredParam, len(method.Inputs)),
+		},
+	}
+	if tx.To() != nil {
+		res.To = tx.To().Hex()
+	}
+	for i, input := range method.Inputs {
+		value, err := structuredValue(input.Type, args[i])
+		if err != nil {
+			return nil, err
+		}
+		res.Call.Params[i] = &structuredParam{
+			Name:  input.Name,
+			Type:  input.Type.String(),
+			Value: value,
+		}
+	}
+	if includeRaw {
+		buf := new(bytes.Buffer)
+		if err := tx.EncodeRLP(buf); err != nil {
+			return nil, err
+		}
+		res.RawTransaction = fmt.Sprintf("0x%x", buf.Bytes())
+	}
+	return res, nil
+}
+
+// structuredValue converts a value to a form suitable for JSON output.
+// Integers are output as decimal strings to avoid loss of precision, and
+// tuples are output as a list of their named and typed components.
+func structuredValue(argType abi.Type, val interface{}) (interface{}, error) {
+	switch argType.T {
+	case abi.IntTy, abi.UintTy:
+		return fmt.Sprintf("%v", val), nil
+	case abi.BoolTy, abi.StringTy:
+		return val, nil
+	case abi.AddressTy:
+		return val.(common.Address).Hex(), nil
+	case abi.HashTy:
+		return val.(common.Hash).Hex(), nil
+	case abi.BytesTy:
+		return fmt.Sprintf("0x%x", val.([]byte)), nil
+	case abi.FixedBytesTy:
+		arrayVal := reflect.ValueOf(val)
+		castVal := make([]byte, arrayVal.Len())
+		for i := 0; i < arrayVal.Len(); i++ {
+			castVal[i] = byte(arrayVal.Index(i).Uint())
+		}
+		return fmt.Sprintf("0x%x", castVal), nil
+	case abi.SliceTy, abi.ArrayTy:
+		arrayVal := reflect.ValueOf(val)
+		res := make([]interface{}, arrayVal.Len())
+		for i := 0; i < arrayVal.Len(); i++ {
+			elem, err := structuredValue(*argType.Elem, arrayVal.Index(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			res[i] = elem
+		}
+		return res, nil
+	case abi.TupleTy:
+		tupleVal := reflect.Indirect(reflect.ValueOf(val))
+		if tupleVal.Kind() != reflect.Struct || tupleVal.NumField() != len(argType.TupleElems) {
+			return nil, fmt.Errorf("invalid value for %v", argType)
+		}
+		res := make([]*structuredParam, len(argType.TupleElems))
+		for i, elemType := range argType.TupleElems {
+			elem, err := structuredValue(*elemType, tupleVal.Field(i).Interface())
+			if err != nil {
+				return nil, err
+			}
+			res[i] = &structuredParam{
+				Name:  argType.TupleRawNames[i],
+				Type:  elemType.String(),
+				Value: elem,
+			}
+		}
+		return res, nil
+	default:
+		return nil, fmt.Errorf("unhandled type %v", argType)
+	}
+}
@@ -19,8 +19,9 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	"github.com/wealdtech/ethereal/util/contracts"
-	ens "github.com/wealdtech/go-ens/v3"
 )
 
 var tokenStr string
@@ -36,10 +37,10 @@ var unknownAddress = common.HexToAddress("00")
 
 func tokenContractAddress(input string) (address common.Address, err error) {
 	// Guess 1 - might be an ENS name or a hex string
-	address, err = ens.Resolve(client, input)
+	address, err = util.ResolveAddress(client, input)
 	if (address == unknownAddress || err != nil) && !strings.HasSuffix(input, ".eth") {
 		// Guess 2 - try {input}.thetoken.eth
-		address, err = ens.Resolve(client, input+".thetoken.eth")
+		address, err = util.ResolveAddress(client, input+".thetoken.eth")
 		if err != nil {
 			// Give up
 			err = fmt.Errorf("Unknown token %s", input)
@@ -57,6 +58,27 @@ func tokenContract(input string) (contract *contracts.ERC20, err error) {
 	return
 }
 
+// tokenMetadata obtains the decimals and symbol of a token.  Not all tokens
+// implement these, so if they cannot be obtained it falls back to 18 decimals
+// and the token's address as its symbol.
+func tokenMetadata(token *contracts.ERC20, address common.Address) (uint8, string) {
+	decimals, err := token.Decimals(nil)
+	if err != nil {
+		if verbose {
+			cli.Warn(quiet, fmt.Sprintf("Failed to obtain token decimals (%v); assuming 18", err))
+		}
+		decimals = 18
+	}
+	symbol, err := token.Symbol(nil)
+	if err != nil || symbol == "" {
+		if verbose {
+			cli.Warn(quiet, "Failed to obtain token symbol; using token address")
+		}
+		symbol = address.Hex()
+	}
+	return decimals, symbol
+}
+
 func init() {
 	RootCmd.AddCommand(tokenCmd)
 }
@@ -21,12 +21,13 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
 	"github.com/wealdtech/ethereal/util"
-	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/ethereal/util/contracts"
 )
 
 var tokenAllowanceRaw bool
 var tokenAllowanceHolderAddress string
 var tokenAllowanceSpenderAddress string
+var tokenAllowanceJSON bool
 
 // tokenAllowanceCmd represents the token allowance command
 var tokenAllowanceCmd = &cobra.Command{
@@ -36,23 +37,24 @@ var tokenAllowanceCmd = &cobra.Command{
 
     ethereal token allowance --token=omg --holder=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --spender=0x52f1A3027d3aA514F17E454C93ae1F79b3B12d5d
 
+The allowance is scaled by the token's decimals.  The --raw flag shows the unscaled allowance, and the --json flag outputs the allowance as JSON.
+
 In quiet mode this will return 0 if the allowance is greater than 0, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(tokenAllowanceHolderAddress != "", quiet, "--holder is required")
-		holderAddress, err := ens.Resolve(client, tokenAllowanceHolderAddress)
+		cli.Assert(tokenAllowanceHolderAddress != "", quiet, "--holder or --owner is required")
+		holderAddress, err := util.ResolveAddress(client, tokenAllowanceHolderAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve holder address %s", tokenAllowanceHolderAddress))
 
 		cli.Assert(tokenAllowanceSpenderAddress != "", quiet, "--spender is required")
-		spenderAddress, err := ens.Resolve(client, tokenAllowanceSpenderAddress)
+		spenderAddress, err := util.ResolveAddress(client, tokenAllowanceSpenderAddress)
 		cli.ErrCheck(err, quiet, "Failed to obtain spender address")
 
 		cli.Assert(tokenStr != "", quiet, "--token is required")
-		token, err := tokenContract(tokenStr)
+		tokenAddress, err := tokenContractAddress(tokenStr)
+		cli.ErrCheck(err, quiet, "Failed to obtain token address")
+		token, err := contracts.NewERC20(tokenAddress, client)
 		cli.ErrCheck(err, quiet, "Failed to obtain token contract")
 
-		decimals, err := token.Decimals(nil)
-		cli.ErrCheck(err, quiet, "Failed to obtain token decimals")
-
 		allowance, err := token.Allowance(nil, holderAddress, spenderAddress)
 		cli.ErrCheck(err, quiet, "Failed to obtain allowance")
 
@@ -64,11 +66,19 @@ In quiet mode this will return 0 if the allowance is greater than 0, otherwise 1
 			}
 		}
 
+		decimals, symbol := tokenMetadata(token, tokenAddress)
+		data := newJSONTokenAllowance(tokenAddress, symbol, decimals, holderAddress, spenderAddress, allowance)
+		if tokenAllowanceJSON {
+			cli.ErrCheck(outputJSON(cmd, data), quiet, "Failed to output JSON")
+			return
+		}
+		cli.WarnCheck(recordJSON(cmd, data), quiet, "Failed to record JSON output")
+
 		if tokenAllowanceRaw {
 			fmt.Printf("%s\n", allowance.String())
-		} else {
-			fmt.Printf("%s\n", util.TokenValueToString(allowance, decimals, false))
+			return
 		}
+		fmt.Printf("%s\n", util.TokenValueToString(allowance, decimals, false))
 	},
 }
 
@@ -77,5 +87,8 @@ func init() {
 	tokenFlags(tokenAllowanceCmd)
 	tokenAllowanceCmd.Flags().BoolVar(&tokenAllowanceRaw, "raw", false, "Display raw output (no decimals)")
 	tokenAllowanceCmd.Flags().StringVar(&tokenAllowanceHolderAddress, "holder", "", "Address that holds tokens")
+	tokenAllowanceCmd.Flags().StringVar(&tokenAllowanceHolderAddress, "owner", "", "Address that holds tokens (alternative to --holder)")
 	tokenAllowanceCmd.Flags().StringVar(&tokenAllowanceSpenderAddress, "spender", "", "Address that can spend tokens")
+	tokenAllowanceCmd.Flags().BoolVar(&tokenAllowanceJSON, "json", false, "Display output as JSON")
+	jsonoutCmds["token:allowance"] = true
 }
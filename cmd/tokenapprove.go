@@ -14,22 +14,29 @@
 package cmd
 
 import (
-	"bytes"
-	"encoding/hex"
 	"fmt"
 	"math/big"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/wealdtech/ethereal/cli"
 	"github.com/wealdtech/ethereal/util"
-	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/ethereal/util/contracts"
 )
 
 var tokenApproveAmount string
 var tokenApproveHolderAddress string
 var tokenApproveSpenderAddress string
+var tokenApproveDecimals string
+var tokenApproveReset bool
 
 // tokenApproveCmd represents the token approve command
 var tokenApproveCmd = &cobra.Command{
@@ -39,49 +46,90 @@ var tokenApproveCmd = &cobra.Command{
 
     ethereal token approve --token=omg --holder=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --spender=0x52f1A3027d3aA514F17E454C93ae1F79b3B12d5d --amount=10 --passphrase=secret
 
+Changing an existing non-zero allowance to another non-zero value allows the spender to potentially spend both the old and new allowances, so a warning is given in this situation.  The --reset flag avoids this by first setting the allowance to zero, and waiting for that transaction to be mined before setting the new allowance.
+
 This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
-
 		cli.Assert(tokenApproveHolderAddress != "", quiet, "--holder is required")
-		holderAddress, err := ens.Resolve(client, tokenApproveHolderAddress)
+		holderAddress, err := util.ResolveAddress(client, tokenApproveHolderAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve holder address %s", tokenApproveHolderAddress))
 
 		cli.Assert(tokenApproveSpenderAddress != "", quiet, "--spender is required")
-		spenderAddress, err := ens.Resolve(client, tokenApproveSpenderAddress)
+		spenderAddress, err := util.ResolveAddress(client, tokenApproveSpenderAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve spender address %s", tokenApproveSpenderAddress))
 
 		cli.Assert(tokenStr != "", quiet, "--token is required")
-		token, err := tokenContract(tokenStr)
+		tokenAddress, err := tokenContractAddress(tokenStr)
+		cli.ErrCheck(err, quiet, "Failed to obtain token address")
+		token, err := contracts.NewERC20(tokenAddress, client)
 		cli.ErrCheck(err, quiet, "Failed to obtain token contract")
 
-		decimals, err := token.Decimals(nil)
-		cli.ErrCheck(err, quiet, "Failed to obtain token decimals")
+		var decimals uint8
+		if offline {
+			cli.Assert(gasLimit != 0, quiet, "--gaslimit is required if offline")
+			cli.Assert(tokenApproveDecimals != "", quiet, "--decimals is required if offline")
+			tmpDecimals, err := strconv.Atoi(tokenApproveDecimals)
+			cli.ErrCheck(err, quiet, "Failed to obtain token decimals")
+			decimals = uint8(tmpDecimals)
+		} else {
+			decimals, err = token.Decimals(nil)
+			cli.ErrCheck(err, quiet, "Failed to obtain token decimals")
+		}
 
 		cli.Assert(tokenApproveAmount != "", quiet, "--amount is required")
 		amount, err := util.StringToTokenValue(tokenApproveAmount, decimals)
 		cli.ErrCheck(err, quiet, "Invalid amount")
 
-		allowance, err := token.Allowance(nil, holderAddress, spenderAddress)
-		cli.ErrCheck(err, quiet, "Failed to obtain allowance")
-
-		cli.Assert(allowance.Cmp(big.NewInt(0)) == 0 || amount.Cmp(big.NewInt(0)) == 0, quiet, fmt.Sprintf("Allowance is currently %s; it must be set to zero before being changed to avoid a potential double spend", util.TokenValueToString(allowance, decimals, false)))
+		reset := tokenApproveReset && amount.Sign() != 0
+		if !offline {
+			allowance, err := token.Allowance(nil, holderAddress, spenderAddress)
+			cli.ErrCheck(err, quiet, "Failed to obtain allowance")
+			if allowance.Sign() == 0 {
+				reset = false
+			} else if amount.Sign() != 0 && !reset {
+				cli.Warn(quiet, fmt.Sprintf("Allowance is currently %s; changing it to a non-zero value allows a potential double spend (use --reset to set it to zero first)", util.TokenValueToString(allowance, decimals, false)))
+			}
+		}
 
-		opts, err := generateTxOpts(holderAddress)
-		cli.ErrCheck(err, quiet, "Failed to generate transaction options")
+		tokenABI, err := abi.JSON(strings.NewReader(contracts.ERC20ABI))
+		cli.ErrCheck(err, quiet, "Failed to parse token ABI")
+
+		if reset {
+			resetTx := tokenApproveTransaction(tokenABI, holderAddress, tokenAddress, spenderAddress, big.NewInt(0))
+			if !offline {
+				outputIf(verbose, fmt.Sprintf("Resetting allowance with %s", resetTx.Hash().Hex()))
+				ctx, cancel := localContext()
+				defer cancel()
+				err = client.SendTransaction(ctx, resetTx)
+				cli.ErrCheck(err, quiet, "Failed to send allowance reset transaction")
+				logTransaction(resetTx, log.Fields{
+					"group":        "token",
+					"command":      "approve",
+					"token":        tokenStr,
+					"tokenholder":  holderAddress.Hex(),
+					"tokenspender": spenderAddress.Hex(),
+					"tokenamount":  "0",
+				})
+				tokenApproveAwaitReset(resetTx)
+			} else if !quiet {
+				outputOfflineTransaction(resetTx)
+			}
+		}
 
-		signedTx, err := token.Approve(opts, spenderAddress, amount)
-		cli.ErrCheck(err, quiet, "Failed to create transaction")
+		signedTx := tokenApproveTransaction(tokenABI, holderAddress, tokenAddress, spenderAddress, amount)
 
 		if offline {
 			if !quiet {
-				buf := new(bytes.Buffer)
-				signedTx.EncodeRLP(buf)
-				fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+				outputOfflineTransaction(signedTx)
 			}
 			os.Exit(_exit_success)
 		}
 
+		ctx, cancel := localContext()
+		defer cancel()
+		err = client.SendTransaction(ctx, signedTx)
+		cli.ErrCheck(err, quiet, "Failed to send transaction")
+
 		handleSubmittedTransaction(signedTx, log.Fields{
 			"group":        "token",
 			"command":      "approve",
@@ -93,11 +141,35 @@ This will return an exit status of 0 if the transaction is successfully submitte
 	},
 }
 
+// tokenApproveTransaction creates a signed transaction to approve the spender for the given amount.
+func tokenApproveTransaction(tokenABI abi.ABI, holderAddress common.Address, tokenAddress common.Address, spenderAddress common.Address, amount *big.Int) *types.Transaction {
+	data, err := tokenABI.Pack("approve", spenderAddress, amount)
+	cli.ErrCheck(err, quiet, "Failed to convert arguments")
+	signedTx, err := createSignedTransaction(holderAddress, &tokenAddress, big.NewInt(0), gasLimit, data)
+	cli.ErrCheck(err, quiet, "Failed to create transaction")
+	return signedTx
+}
+
+// tokenApproveAwaitReset waits for the allowance reset transaction to be
+// mined, as the new allowance must not be set until it has been.
+func tokenApproveAwaitReset(tx *types.Transaction) {
+	interval := viper.GetDuration("pollinterval")
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	outputIf(verbose, fmt.Sprintf("Waiting for %s to be mined", tx.Hash().Hex()))
+	receipt := util.WaitForReceipt(client, tx.Hash(), viper.GetDuration("limit"), interval)
+	cli.Assert(receipt != nil, quiet, fmt.Sprintf("Allowance reset transaction %s not mined", tx.Hash().Hex()))
+	cli.Assert(receipt.Status == types.ReceiptStatusSuccessful, quiet, fmt.Sprintf("Allowance reset transaction %s failed", tx.Hash().Hex()))
+}
+
 func init() {
 	tokenCmd.AddCommand(tokenApproveCmd)
 	tokenFlags(tokenApproveCmd)
 	tokenApproveCmd.Flags().StringVar(&tokenApproveAmount, "amount", "", "Amount to approve")
 	tokenApproveCmd.Flags().StringVar(&tokenApproveHolderAddress, "holder", "", "Address that holds tokens")
 	tokenApproveCmd.Flags().StringVar(&tokenApproveSpenderAddress, "spender", "", "Address that can spend tokens")
+	tokenApproveCmd.Flags().StringVar(&tokenApproveDecimals, "decimals", "18", "Number of decimals for the approval (only required if offline)")
+	tokenApproveCmd.Flags().BoolVar(&tokenApproveReset, "reset", false, "Set the allowance to zero before setting the new value")
 	addTransactionFlags(tokenApproveCmd, "the address from which to approve tokens")
 }
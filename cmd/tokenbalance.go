@@ -17,17 +17,22 @@ import (
 	"fmt"
 	"math/big"
 	"os"
+	"strings"
 
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
 	"github.com/wealdtech/ethereal/util"
-	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/ethereal/util/contracts"
 )
 
-var tokenBalanceHolderAddress string
+var tokenBalanceHolderAddresses []string
 var tokenBalanceRaw bool
+var tokenBalanceJSON bool
 
-// tokenBalanceCmd represents the ether balance command
+// tokenBalanceCmd represents the token balance command
 var tokenBalanceCmd = &cobra.Command{
 	Use:   "balance",
 	Short: "Obtain the token balance for an address",
@@ -35,18 +40,29 @@ var tokenBalanceCmd = &cobra.Command{
 
     ethereal token balance --token=omg --holder=0x5FfC014343cd971B7eb70732021E26C35B744cc4
 
-In quiet mode this will return 0 if the balance is greater than 0, otherwise 1.`,
+The balance is scaled by the token's decimals and shown with its symbol.  If the token does not supply these then 18 decimals and the token's address are used.  The --raw flag shows the unscaled balance.
+
+Multiple holders can be supplied separated by commas or by repeating --holder, in which case the balances are obtained in a single batch request where the connection supports it, and each balance is shown alongside its holder.
+
+The --json flag outputs the balance as JSON.
+
+In quiet mode this will return 0 if the balance is greater than 0, otherwise 1.  If multiple holders are supplied this will return 0 if all of the balances are greater than 0, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		cli.Assert(tokenBalanceHolderAddress != "", quiet, "--holder is required")
-		address, err := ens.Resolve(client, tokenBalanceHolderAddress)
-		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve holder address %s", tokenBalanceHolderAddress))
+		cli.Assert(len(tokenBalanceHolderAddresses) > 0, quiet, "--holder is required")
 
 		cli.Assert(tokenStr != "", quiet, "--token is required")
-		token, err := tokenContract(tokenStr)
-		cli.ErrCheck(err, quiet, "Failed to obtain token contract")
+		tokenAddress, err := tokenContractAddress(tokenStr)
+		cli.ErrCheck(err, quiet, "Failed to obtain token address")
+
+		if len(tokenBalanceHolderAddresses) > 1 {
+			tokenBalanceMultiple(cmd, tokenAddress)
+		}
 
-		decimals, err := token.Decimals(nil)
-		cli.ErrCheck(err, quiet, "Failed to obtain token decimals")
+		address, err := util.ResolveAddress(client, tokenBalanceHolderAddresses[0])
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve holder address %s", tokenBalanceHolderAddresses[0]))
+
+		token, err := contracts.NewERC20(tokenAddress, client)
+		cli.ErrCheck(err, quiet, "Failed to obtain token contract")
 
 		balance, err := token.BalanceOf(nil, address)
 		cli.ErrCheck(err, quiet, "Failed to obtain token balance")
@@ -59,17 +75,104 @@ In quiet mode this will return 0 if the balance is greater than 0, otherwise 1.`
 			}
 		}
 
+		decimals, symbol := tokenMetadata(token, tokenAddress)
+		data := newJSONTokenBalance(tokenAddress, symbol, decimals, address, balance)
+		if tokenBalanceJSON {
+			cli.ErrCheck(outputJSON(cmd, data), quiet, "Failed to output JSON")
+			return
+		}
+		cli.WarnCheck(recordJSON(cmd, data), quiet, "Failed to record JSON output")
+
 		if tokenBalanceRaw {
 			fmt.Printf("%s\n", balance.String())
-		} else {
-			fmt.Printf("%s\n", util.TokenValueToString(balance, decimals, false))
+			return
 		}
+		fmt.Printf("%s %s\n", util.TokenValueToString(balance, decimals, false), symbol)
 	},
 }
 
+// tokenBalanceMultiple obtains and displays the balances of multiple holders,
+// making the calls for the balances and the token's metadata in a single
+// batch request.
+func tokenBalanceMultiple(cmd *cobra.Command, tokenAddress common.Address) {
+	holders := make([]common.Address, len(tokenBalanceHolderAddresses))
+	for i := range tokenBalanceHolderAddresses {
+		name := strings.TrimSpace(tokenBalanceHolderAddresses[i])
+		var err error
+		holders[i], err = util.ResolveAddress(client, name)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve holder address %s", name))
+	}
+
+	erc20ABI, err := abi.JSON(strings.NewReader(contracts.ERC20ABI))
+	cli.ErrCheck(err, quiet, "Failed to parse token ABI")
+	msgs := make([]ethereum.CallMsg, 0, len(holders)+2)
+	for _, method := range []string{"decimals", "symbol"} {
+		data, err := erc20ABI.Pack(method)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to create %s call", method))
+		msgs = append(msgs, ethereum.CallMsg{To: &tokenAddress, Data: data})
+	}
+	for i := range holders {
+		data, err := erc20ABI.Pack("balanceOf", holders[i])
+		cli.ErrCheck(err, quiet, "Failed to create balance call")
+		msgs = append(msgs, ethereum.CallMsg{To: &tokenAddress, Data: data})
+	}
+	ctx, cancel := localContext()
+	defer cancel()
+	outputs, errs, err := util.CallContracts(ctx, rpcClient, msgs, nil)
+	cli.ErrCheck(err, quiet, "Failed to obtain token balances")
+
+	decimals := uint8(18)
+	if errs[0] != nil || erc20ABI.Unpack(&decimals, "decimals", outputs[0]) != nil {
+		if verbose {
+			cli.Warn(quiet, "Failed to obtain token decimals; assuming 18")
+		}
+		decimals = 18
+	}
+	symbol := ""
+	if errs[1] != nil || erc20ABI.Unpack(&symbol, "symbol", outputs[1]) != nil || symbol == "" {
+		if verbose {
+			cli.Warn(quiet, "Failed to obtain token symbol; using token address")
+		}
+		symbol = tokenAddress.Hex()
+	}
+
+	data := make([]*jsonTokenBalance, len(holders))
+	allPositive := true
+	for i := range holders {
+		cli.ErrCheck(errs[i+2], quiet, fmt.Sprintf("Failed to obtain token balance of %s", tokenBalanceHolderAddresses[i]))
+		balance := new(big.Int)
+		cli.ErrCheck(erc20ABI.Unpack(&balance, "balanceOf", outputs[i+2]), quiet, fmt.Sprintf("Failed to obtain token balance of %s", tokenBalanceHolderAddresses[i]))
+		if balance.Sign() == 0 {
+			allPositive = false
+		}
+		data[i] = newJSONTokenBalance(tokenAddress, symbol, decimals, holders[i], balance)
+	}
+
+	if !quiet {
+		if tokenBalanceJSON {
+			cli.ErrCheck(outputJSON(cmd, data), quiet, "Failed to output JSON")
+		} else {
+			cli.WarnCheck(recordJSON(cmd, data), quiet, "Failed to record JSON output")
+			for i := range data {
+				if tokenBalanceRaw {
+					fmt.Printf("%s\t%s\n", strings.TrimSpace(tokenBalanceHolderAddresses[i]), data[i].Balance)
+				} else {
+					fmt.Printf("%s\t%s %s\n", strings.TrimSpace(tokenBalanceHolderAddresses[i]), data[i].BalanceTokens, symbol)
+				}
+			}
+		}
+	}
+	if allPositive {
+		os.Exit(_exit_success)
+	}
+	os.Exit(_exit_failure)
+}
+
 func init() {
 	tokenFlags(tokenBalanceCmd)
 	tokenCmd.AddCommand(tokenBalanceCmd)
 	tokenBalanceCmd.Flags().BoolVar(&tokenBalanceRaw, "raw", false, "Display raw output (no decimals)")
-	tokenBalanceCmd.Flags().StringVar(&tokenBalanceHolderAddress, "holder", "", "Holder of tokens")
+	tokenBalanceCmd.Flags().StringSliceVar(&tokenBalanceHolderAddresses, "holder", nil, "Holder (or comma-separated holders) of tokens; can be repeated")
+	tokenBalanceCmd.Flags().BoolVar(&tokenBalanceJSON, "json", false, "Display output as JSON")
+	jsonoutCmds["token:balance"] = true
 }
@@ -14,8 +14,6 @@
 package cmd
 
 import (
-	"bytes"
-	"encoding/hex"
 	"fmt"
 	"math/big"
 	"os"
@@ -25,7 +23,6 @@ import (
 	"github.com/wealdtech/ethereal/cli"
 	"github.com/wealdtech/ethereal/util"
 	"github.com/wealdtech/ethereal/util/funcparser"
-	ens "github.com/wealdtech/go-ens/v3"
 )
 
 var tokenDeployName string
@@ -44,6 +41,8 @@ var tokenDeployCmd = &cobra.Command{
 
     ethereal token deploy --name="My token" --symbol="MY" --decimals=18 --totalsupply=1000000 --owner=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --passphrase=secret
 
+If --showcalldata is supplied then the hex-encoded deployment data, including the constructor arguments, is output prior to the transaction hash.
+
 This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
@@ -56,7 +55,7 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		supply.Mul(supply, new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(tokenDeployDecimals)), nil))
 
 		cli.Assert(tokenDeployOwner != "", quiet, "--owner is required")
-		owner, err := ens.Resolve(client, tokenDeployOwner)
+		owner, err := util.ResolveAddress(client, tokenDeployOwner)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve owner address %s", tokenDeployOwner))
 
 		contract, err := util.ParseCombinedJSON(erc20ContractData, "ERC20Token")
@@ -69,6 +68,7 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		argData, err := contract.Abi.Pack("", constructorArgs...)
 		cli.ErrCheck(err, quiet, "Failed to convert arguments")
 		contract.Binary = append(contract.Binary, argData...)
+		outputCalldata(contract.Binary)
 
 		// Deploy the token contract
 		signedTx, err := createSignedTransaction(owner, nil, big.NewInt(0), gasLimit, contract.Binary)
@@ -76,9 +76,7 @@ This will return an exit status of 0 if the transaction is successfully submitte
 
 		if offline {
 			if !quiet {
-				buf := new(bytes.Buffer)
-				signedTx.EncodeRLP(buf)
-				fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+				outputOfflineTransaction(signedTx)
 			}
 			os.Exit(_exit_success)
 		}
@@ -108,4 +106,5 @@ func init() {
 	tokenDeployCmd.Flags().Uint64Var(&tokenDeploySupply, "supply", 0, "Total supply for the token (in whole tokens)")
 	tokenDeployCmd.Flags().StringVar(&tokenDeployOwner, "owner", "", "Address that owns the initial tokens")
 	addTransactionFlags(tokenDeployCmd, "the address from which to deploy the token contract")
+	addShowCalldataFlag(tokenDeployCmd, "deployment data for the token contract")
 }
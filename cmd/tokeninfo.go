@@ -20,7 +20,6 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
 	"github.com/wealdtech/ethereal/util"
-	ens "github.com/wealdtech/go-ens/v3"
 )
 
 // tokenInfoCmd represents the token info command
@@ -49,7 +48,7 @@ In quiet mode this will return 0 if the token exists, otherwise 1.`,
 		if verbose {
 			address, err := tokenContractAddress(tokenStr)
 			if err == nil {
-				fmt.Printf("Address:\t%s\n", ens.Format(client, address))
+				fmt.Printf("Address:\t%s\n", util.ENSFormat(client, address))
 			}
 		}
 
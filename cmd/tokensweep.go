@@ -14,8 +14,6 @@
 package cmd
 
 import (
-	"bytes"
-	"encoding/hex"
 	"fmt"
 	"math/big"
 	"os"
@@ -24,7 +22,6 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
 	"github.com/wealdtech/ethereal/util"
-	ens "github.com/wealdtech/go-ens/v3"
 )
 
 var tokenSweepFromAddress string
@@ -43,11 +40,11 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
 
 		cli.Assert(tokenSweepFromAddress != "", quiet, "--from is required")
-		fromAddress, err := ens.Resolve(client, tokenSweepFromAddress)
+		fromAddress, err := util.ResolveAddress(client, tokenSweepFromAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", tokenSweepFromAddress))
 
 		cli.Assert(tokenSweepToAddress != "", quiet, "--to is required")
-		toAddress, err := ens.Resolve(client, tokenSweepToAddress)
+		toAddress, err := util.ResolveAddress(client, tokenSweepToAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve to address %s", tokenSweepToAddress))
 
 		cli.Assert(tokenStr != "", quiet, "--token is required")
@@ -77,9 +74,7 @@ This will return an exit status of 0 if the transaction is successfully submitte
 
 		if offline {
 			if !quiet {
-				buf := new(bytes.Buffer)
-				signedTx.EncodeRLP(buf)
-				fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+				outputOfflineTransaction(signedTx)
 			}
 			os.Exit(_exit_success)
 		}
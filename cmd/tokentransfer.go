@@ -14,23 +14,26 @@
 package cmd
 
 import (
-	"bytes"
-	"encoding/hex"
 	"fmt"
+	"math/big"
 	"os"
 	"strconv"
+	"strings"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
 	"github.com/wealdtech/ethereal/util"
-	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/ethereal/util/contracts"
+	"github.com/wealdtech/ethereal/util/funcparser"
 )
 
 var tokenTransferAmount string
 var tokenTransferFromAddress string
 var tokenTransferToAddress string
 var tokenTransferDecimals string
+var tokenTransferRaw bool
 
 // tokenTransferCmd represents the token transfer command
 var tokenTransferCmd = &cobra.Command{
@@ -40,18 +43,26 @@ var tokenTransferCmd = &cobra.Command{
 
     ethereal token transfer --token=omg --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --to=0x52f1A3027d3aA514F17E454C93ae1F79b3B12d5d --amount=10 --passphrase=secret
 
+The amount is in tokens, and is scaled by the token's decimals.  To supply the amount in the token's base unit use --raw.
+
+If --simulate is supplied then the transaction is first run as a call against the pending block, and is not sent if it would fail.
+
+If --showcalldata is supplied then the hex-encoded calldata for the transfer is output prior to the transaction hash.
+
 This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(tokenTransferFromAddress != "", quiet, "--from is required")
-		fromAddress, err := ens.Resolve(client, tokenTransferFromAddress)
+		fromAddress, err := util.ResolveAddress(client, tokenTransferFromAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", tokenTransferFromAddress))
 
 		cli.Assert(tokenTransferToAddress != "", quiet, "--to is required")
-		toAddress, err := ens.Resolve(client, tokenTransferToAddress)
+		toAddress, err := util.ResolveAddress(client, tokenTransferToAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve to address %s", tokenTransferToAddress))
 
 		cli.Assert(tokenStr != "", quiet, "--token is required")
-		token, err := tokenContract(tokenStr)
+		tokenAddress, err := tokenContractAddress(tokenStr)
+		cli.ErrCheck(err, quiet, "Failed to obtain token address")
+		token, err := contracts.NewERC20(tokenAddress, client)
 		cli.ErrCheck(err, quiet, "Failed to obtain token contract")
 
 		var decimals uint8
@@ -63,12 +74,35 @@ This will return an exit status of 0 if the transaction is successfully submitte
 			decimals = uint8(tmpDecimals)
 		} else {
 			decimals, err = token.Decimals(nil)
+			if tokenTransferRaw && err != nil {
+				// Decimals are only used for display, so fall back
+				decimals = 18
+				err = nil
+			}
 			cli.ErrCheck(err, quiet, "Failed to obtain token decimals")
 		}
 
 		cli.Assert(tokenTransferAmount != "", quiet, "--amount is required")
-		amount, err := util.StringToTokenValue(tokenTransferAmount, decimals)
-		cli.ErrCheck(err, quiet, "Invalid amount")
+		var amount *big.Int
+		if tokenTransferRaw {
+			var success bool
+			amount, success = new(big.Int).SetString(tokenTransferAmount, 10)
+			cli.Assert(success && amount.Sign() >= 0, quiet, "Invalid amount; --raw requires a non-negative integer")
+		} else {
+			amount, err = util.StringToTokenValue(tokenTransferAmount, decimals)
+			cli.ErrCheck(err, quiet, "Invalid amount")
+		}
+
+		tokenABI, err := abi.JSON(strings.NewReader(contracts.ERC20ABI))
+		cli.ErrCheck(err, quiet, "Failed to parse token ABI")
+		method := tokenABI.Methods["transfer"]
+		methodArgs := []interface{}{toAddress, amount}
+		outputIf(verbose, fmt.Sprintf("Method is %s", method.Sig))
+		outputIf(verbose, fmt.Sprintf("About to call %s", funcparser.FormatCall(&method, methodArgs)))
+		outputIf(verbose, fmt.Sprintf("Amount is %s", util.TokenValueToString(amount, decimals, false)))
+		data, err := tokenABI.Pack(method.Name, methodArgs...)
+		cli.ErrCheck(err, quiet, "Failed to convert arguments")
+		outputCalldata(data)
 
 		// Obtain the balance of the address (if online)
 		if !offline {
@@ -77,21 +111,23 @@ This will return an exit status of 0 if the transaction is successfully submitte
 			cli.Assert(balance.Cmp(amount) >= 0, quiet, fmt.Sprintf("Balance of %s insufficient for transfer", util.TokenValueToString(balance, decimals, false)))
 		}
 
-		opts, err := generateTxOpts(fromAddress)
-		cli.ErrCheck(err, quiet, "Failed to generate transaction options")
+		simulateTransaction(fromAddress, &tokenAddress, big.NewInt(0), data)
 
-		signedTx, err := token.Transfer(opts, toAddress, amount)
+		signedTx, err := createSignedTransaction(fromAddress, &tokenAddress, big.NewInt(0), gasLimit, data)
 		cli.ErrCheck(err, quiet, "Failed to create transaction")
 
 		if offline {
 			if !quiet {
-				buf := new(bytes.Buffer)
-				signedTx.EncodeRLP(buf)
-				fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+				outputOfflineTransaction(signedTx)
 			}
 			os.Exit(_exit_success)
 		}
 
+		ctx, cancel := localContext()
+		defer cancel()
+		err = client.SendTransaction(ctx, signedTx)
+		cli.ErrCheck(err, quiet, "Failed to send transaction")
+
 		handleSubmittedTransaction(signedTx, log.Fields{
 			"group":          "token",
 			"command":        "transfer",
@@ -110,5 +146,8 @@ func init() {
 	tokenTransferCmd.Flags().StringVar(&tokenTransferFromAddress, "from", "", "Address from which to transfer tokens")
 	tokenTransferCmd.Flags().StringVar(&tokenTransferToAddress, "to", "", "Address to which to transfer tokens")
 	tokenTransferCmd.Flags().StringVar(&tokenTransferDecimals, "decimals", "18", "Number of decimals for the transfer (only required if offline)")
+	tokenTransferCmd.Flags().BoolVar(&tokenTransferRaw, "raw", false, "Amount is in the token's base unit (no decimals)")
 	addTransactionFlags(tokenTransferCmd, "the address from which to transfer tokens")
+	addSimulateFlag(tokenTransferCmd)
+	addShowCalldataFlag(tokenTransferCmd, "calldata for the transfer")
 }
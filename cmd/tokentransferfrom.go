@@ -14,16 +14,14 @@
 package cmd
 
 import (
-	"bytes"
-	"encoding/hex"
 	"fmt"
+	"math/big"
 	"os"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
 	"github.com/wealdtech/ethereal/util"
-	ens "github.com/wealdtech/go-ens/v3"
 )
 
 var tokenTransferFromAmount string
@@ -37,22 +35,24 @@ var tokenTransferFromCmd = &cobra.Command{
 	Short: "Transfer tokens from a third-party address to another address",
 	Long: `Transfer tokens from one third-party address to another, as part of a user's allowance .  For example:
 
-    ethereal token transferfrom --token=omg --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --to=0x52f1A3027d3aA514F17E454C93ae1F79b3B12d5d --by= --amount=10 --passphrase=secret
+    ethereal token transferfrom --token=omg --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --to=0x52f1A3027d3aA514F17E454C93ae1F79b3B12d5d --by=0x2ab7150Bba7D5F181b3aF5623e52b15bB1054845 --amount=10 --passphrase=secret
+
+The transaction is signed by the address supplied in --by, which must have an allowance from the address supplied in --from that covers the amount.  The balance and allowance are checked prior to sending the transaction.
 
 This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(!offline, quiet, "Offline mode not supported at current with this command")
 
 		cli.Assert(tokenTransferFromFromAddress != "", quiet, "--from is required")
-		fromAddress, err := ens.Resolve(client, tokenTransferFromFromAddress)
+		fromAddress, err := util.ResolveAddress(client, tokenTransferFromFromAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", tokenTransferFromFromAddress))
 
 		cli.Assert(tokenTransferFromToAddress != "", quiet, "--to is required")
-		toAddress, err := ens.Resolve(client, tokenTransferFromToAddress)
+		toAddress, err := util.ResolveAddress(client, tokenTransferFromToAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve to address %s", tokenTransferFromToAddress))
 
 		cli.Assert(tokenTransferFromByAddress != "", quiet, "--by is required")
-		byAddress, err := ens.Resolve(client, tokenTransferFromByAddress)
+		byAddress, err := util.ResolveAddress(client, tokenTransferFromByAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve by address %s", tokenTransferFromByAddress))
 
 		cli.Assert(tokenStr != "", quiet, "--token is required")
@@ -69,12 +69,12 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		// Obtain the balance of the address
 		balance, err := token.BalanceOf(nil, fromAddress)
 		cli.ErrCheck(err, quiet, "Failed to obtain balance of address from which to send funds")
-		cli.Assert(balance.Cmp(amount) > 0, quiet, fmt.Sprintf("Balance of %s insufficient for transfer", util.TokenValueToString(balance, decimals, false)))
+		cli.Assert(balance.Cmp(amount) >= 0, quiet, fmt.Sprintf("Balance of %s insufficient for transfer; short by %s", util.TokenValueToString(balance, decimals, false), util.TokenValueToString(new(big.Int).Sub(amount, balance), decimals, false)))
 
 		// Obtain the allowance of the address
 		allowance, err := token.Allowance(nil, fromAddress, byAddress)
 		cli.ErrCheck(err, quiet, "Failed to obtain allowance of address from which to send funds")
-		cli.Assert(allowance.Cmp(amount) > 0, quiet, fmt.Sprintf("Allowance of %s insufficient for transfer", util.TokenValueToString(allowance, decimals, false)))
+		cli.Assert(allowance.Cmp(amount) >= 0, quiet, fmt.Sprintf("Allowance of %s insufficient for transfer; short by %s", util.TokenValueToString(allowance, decimals, false), util.TokenValueToString(new(big.Int).Sub(amount, allowance), decimals, false)))
 
 		opts, err := generateTxOpts(byAddress)
 		cli.ErrCheck(err, quiet, "Failed to generate transaction options")
@@ -83,9 +83,7 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		cli.ErrCheck(err, quiet, "Failed to create transaction")
 		if offline {
 			if !quiet {
-				buf := new(bytes.Buffer)
-				signedTx.EncodeRLP(buf)
-				fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+				outputOfflineTransaction(signedTx)
 			}
 			os.Exit(_exit_success)
 		}
@@ -109,5 +107,5 @@ func init() {
 	tokenTransferFromCmd.Flags().StringVar(&tokenTransferFromFromAddress, "from", "", "Address from which to transfer tokens")
 	tokenTransferFromCmd.Flags().StringVar(&tokenTransferFromToAddress, "to", "", "Address to which to transfer tokens")
 	tokenTransferFromCmd.Flags().StringVar(&tokenTransferFromByAddress, "by", "", "Address allowed to transfer tokens")
-	addTransactionFlags(tokenTransferFromCmd, "the address from which to transfer tokens")
+	addTransactionFlags(tokenTransferFromCmd, "the address allowed to transfer tokens")
 }
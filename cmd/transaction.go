@@ -14,10 +14,18 @@
 package cmd
 
 import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
 )
 
 var transactionStr string
+var transactionWindow int64
 
 // transactionCmd represents the transaction command
 var transactionCmd = &cobra.Command{
@@ -33,3 +41,56 @@ func init() {
 func transactionFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&transactionStr, "transaction", "t", "", "raw transaction data or ID of the transaction")
 }
+
+func transactionPrefixFlags(cmd *cobra.Command) {
+	cmd.Flags().Int64Var(&transactionWindow, "window", 100, "number of recent blocks to search when a partial transaction ID is supplied")
+}
+
+// transactionHash obtains the hash of a transaction given its ID.  If the ID
+// is shorter than a full hash it is treated as a prefix, and recent blocks are
+// searched for a single matching transaction.
+func transactionHash(id string) (common.Hash, error) {
+	prefix := strings.ToLower(strings.TrimPrefix(id, "0x"))
+	if len(prefix) == 0 || len(prefix) > 64 {
+		return common.Hash{}, errors.New("invalid transaction ID")
+	}
+	if _, err := hex.DecodeString(prefix + strings.Repeat("0", len(prefix)%2)); err != nil {
+		return common.Hash{}, errors.New("invalid transaction ID")
+	}
+	if len(prefix) == 64 {
+		return common.HexToHash(prefix), nil
+	}
+
+	ctx, cancel := localContext()
+	defer cancel()
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	matches := make([]common.Hash, 0)
+	blockNumber := new(big.Int).Set(header.Number)
+	for i := int64(0); i < transactionWindow && blockNumber.Sign() >= 0; i++ {
+		ctx, cancel := localContext()
+		block, err := client.BlockByNumber(ctx, blockNumber)
+		cancel()
+		if err != nil {
+			return common.Hash{}, err
+		}
+		for _, tx := range block.Transactions() {
+			if strings.HasPrefix(hex.EncodeToString(tx.Hash().Bytes()), prefix) {
+				matches = append(matches, tx.Hash())
+			}
+		}
+		blockNumber.Sub(blockNumber, big.NewInt(1))
+	}
+
+	switch len(matches) {
+	case 0:
+		return common.Hash{}, fmt.Errorf("no transaction starting with %s found in the last %d blocks", id, transactionWindow)
+	case 1:
+		return matches[0], nil
+	default:
+		return common.Hash{}, fmt.Errorf("%d transactions starting with %s found in the last %d blocks", len(matches), id, transactionWindow)
+	}
+}
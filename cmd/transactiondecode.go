@@ -0,0 +1,211 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
+	"github.com/wealdtech/ethereal/util/funcparser"
+	"github.com/wealdtech/ethereal/util/txdata"
+	string2eth "github.com/wealdtech/go-string2eth"
+)
+
+var transactionDecodeData string
+var transactionDecodeSignatures string
+var transactionDecodeLookupSelectors bool
+var transactionDecodeSelectorEndpoint string
+
+// transactionDecodeCmd represents the transaction decode command
+var transactionDecodeCmd = &cobra.Command{
+	Use:   "decode",
+	Short: "Decode a raw transaction",
+	Long: `Decode a raw signed transaction, for example one generated with --offline, without sending it.  For example:
+
+    ethereal transaction decode --data=0xf86b808504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a0...
+
+where data is the hex string of the transaction, or the path to a file containing it.  If the ABI of the recipient is supplied with --abi or --json, or can be obtained from the cache, ENS or the Etherscan-compatible API supplied with --abisource, then the transaction data is decoded using it, otherwise well-known function signatures and any supplied with --signatures are used.
+
+If --lookupselectors is supplied and no ABI is available then candidate signatures for the function selector are obtained from the 4byte directory (or the endpoint supplied with --selectorendpoint), and the data is decoded using the first candidate that matches it.  Results of lookups are cached for each endpoint in the ethereal/selectors directory under the user configuration directory (for example $HOME/.config/ethereal/selectors on Linux).
+
+In quiet mode this will return 0 if the transaction decodes, otherwise 1.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(transactionDecodeData != "", quiet, "--data is required")
+		if !strings.HasPrefix(transactionDecodeData, "0x") {
+			// Read from file.
+			fileBytes, err := ioutil.ReadFile(transactionDecodeData)
+			cli.ErrCheck(err, quiet, "Failed to read transaction from filesystem")
+			transactionDecodeData = strings.TrimSpace(string(fileBytes))
+		}
+		data, err := hex.DecodeString(strings.TrimPrefix(transactionDecodeData, "0x"))
+		cli.ErrCheck(err, quiet, "Failed to decode data")
+		tx := &types.Transaction{}
+		err = tx.DecodeRLP(rlp.NewStream(bytes.NewReader(data), 0))
+		cli.ErrCheck(err, quiet, "Failed to decode raw transaction")
+		fromAddress, err := txFrom(tx)
+		cli.ErrCheck(err, quiet, "Failed to recover sender of transaction")
+
+		var method string
+		var methodArgs []string
+		var candidates []string
+		if tx.To() != nil && len(tx.Data()) >= 4 {
+			contract := parseContract("")
+			txChainID := chainID
+			if tx.Protected() {
+				txChainID = tx.ChainId()
+			}
+			err = contractResolveABI(contract, *tx.To(), txChainID)
+			cli.WarnCheck(err, quiet, "Failed to obtain ABI")
+			switch {
+			case len(contract.Abi.Methods) > 0:
+				abiMethod, err := contract.Abi.MethodById(tx.Data()[:4])
+				cli.ErrCheck(err, quiet, "Failed to find method in ABI")
+				methodArgs, err = transactionDecodeArgs(abiMethod, tx.Data()[4:])
+				cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to decode arguments for %s", abiMethod.Sig))
+				method = abiMethod.Sig
+			case transactionDecodeLookupSelectors:
+				if transactionDecodeSelectorEndpoint != "" {
+					viper.Set("selectorendpoint", transactionDecodeSelectorEndpoint)
+				}
+				var selector [4]byte
+				copy(selector[:], tx.Data()[:4])
+				candidates, err = util.LookupSelector(selector)
+				cli.WarnCheck(err, quiet, "Failed to look up function selector")
+				method, methodArgs = transactionDecodeCandidates(candidates, tx.Data()[4:])
+			}
+		}
+
+		if quiet {
+			os.Exit(_exit_success)
+		}
+
+		fmt.Printf("Hash:\t\t\t%s\n", tx.Hash().Hex())
+		if tx.Protected() {
+			fmt.Printf("Chain ID:\t\t%v\n", tx.ChainId())
+		} else {
+			fmt.Printf("Chain ID:\t\tNone (replayable)\n")
+		}
+		fmt.Printf("From:\t\t\t%v\n", fromAddress.Hex())
+		if tx.To() == nil {
+			fmt.Printf("To:\t\t\tContract creation\n")
+		} else {
+			fmt.Printf("To:\t\t\t%v\n", tx.To().Hex())
+		}
+		fmt.Printf("Nonce:\t\t\t%v\n", tx.Nonce())
+		fmt.Printf("Gas limit:\t\t%v\n", tx.Gas())
+		fmt.Printf("Gas price:\t\t%v\n", string2eth.WeiToString(tx.GasPrice(), true))
+		fmt.Printf("Value:\t\t\t%v\n", string2eth.WeiToString(tx.Value(), true))
+
+		if len(candidates) > 0 {
+			fmt.Printf("Candidates:\n")
+			for _, candidate := range candidates {
+				fmt.Printf("\t%s\n", candidate)
+			}
+		}
+		switch {
+		case len(tx.Data()) == 0:
+		case method != "":
+			fmt.Printf("Method:\t\t\t%s\n", method)
+			if len(methodArgs) > 0 {
+				fmt.Printf("Arguments:\n")
+				for _, methodArg := range methodArgs {
+					fmt.Printf("\t%s\n", methodArg)
+				}
+			}
+		case tx.To() == nil:
+			if verbose {
+				fmt.Printf("Data:\t\t\t0x%x\n", tx.Data())
+			}
+		default:
+			txdata.InitFunctionMap()
+			if transactionDecodeSignatures != "" {
+				for _, signature := range strings.Split(transactionDecodeSignatures, ";") {
+					txdata.AddFunctionSignature(signature)
+				}
+			}
+			fmt.Printf("Data:\t\t\t%v\n", txdata.DataToString(client, tx.Data()))
+		}
+	},
+}
+
+// transactionDecodeArgs decodes the arguments of a method from transaction data.
+func transactionDecodeArgs(method *abi.Method, data []byte) (args []string, err error) {
+	defer func() {
+		// The unpacker can panic on data that does not match the method
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid data: %v", r)
+		}
+	}()
+	values, err := method.Inputs.UnpackValues(data)
+	if err != nil {
+		return nil, err
+	}
+	// Ensure that the values account for all of the data
+	packed, err := method.Inputs.Pack(values...)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(packed, data) {
+		return nil, errors.New("data does not match method")
+	}
+	args = make([]string, len(values))
+	for i := range values {
+		name := method.Inputs[i].Name
+		if name == "" {
+			name = fmt.Sprintf("%d", i)
+		}
+		args[i] = fmt.Sprintf("%s: %s", name, funcparser.FormatValue(values[i]))
+	}
+	return args, nil
+}
+
+// transactionDecodeCandidates decodes transaction data using the first of
+// the candidate signatures that matches the data.
+func transactionDecodeCandidates(candidates []string, data []byte) (string, []string) {
+	for _, candidate := range candidates {
+		candidateAbi, err := contractParseFunction(candidate)
+		if err != nil {
+			continue
+		}
+		for _, method := range candidateAbi.Methods {
+			args, err := transactionDecodeArgs(&method, data)
+			if err == nil {
+				return method.Sig, args
+			}
+		}
+	}
+	return "", nil
+}
+
+func init() {
+	offlineCmds["transaction:decode"] = true
+	transactionCmd.AddCommand(transactionDecodeCmd)
+	contractFlags(transactionDecodeCmd)
+	transactionDecodeCmd.Flags().StringVar(&transactionDecodeData, "data", "", "Raw transaction (as a hex string, or path to a file containing a hex string)")
+	transactionDecodeCmd.Flags().BoolVar(&transactionDecodeLookupSelectors, "lookupselectors", false, "Look up unknown function selectors in the 4byte directory")
+	transactionDecodeCmd.Flags().StringVar(&transactionDecodeSelectorEndpoint, "selectorendpoint", "", "Endpoint for function selector lookups (defaults to the 4byte directory)")
+	transactionDecodeCmd.Flags().StringVar(&transactionDecodeSignatures, "signatures", "", "Semicolon-separated list of custom transaction signatures (e.g. myFunc(address,bytes32);myFunc2(bool)")
+}
@@ -26,8 +26,8 @@ import (
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/spf13/cobra"
 	"github.com/wealdtech/ethereal/cli"
+	"github.com/wealdtech/ethereal/util"
 	"github.com/wealdtech/ethereal/util/txdata"
-	ens "github.com/wealdtech/go-ens/v3"
 	string2eth "github.com/wealdtech/go-string2eth"
 )
 
@@ -43,6 +43,8 @@ var transactionInfoCmd = &cobra.Command{
 
     ethereal transaction info --transaction=0x5FfC014343cd971B7eb70732021E26C35B744cc4
 
+The transaction ID can be shortened to its first few bytes, in which case recent blocks will be searched for a matching transaction.
+
 In quiet mode this will return 0 if the transaction exists, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(transactionStr != "", quiet, "--transaction is required")
@@ -68,14 +70,21 @@ In quiet mode this will return 0 if the transaction exists, otherwise 1.`,
 			pending = true
 		} else {
 			// Assume input is a transaction ID
-			txHash = common.HexToHash(transactionStr)
+			var err error
+			txHash, err = transactionHash(transactionStr)
+			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain transaction %s", transactionStr))
 			ctx, cancel := localContext()
 			defer cancel()
-			var err error
 			tx, pending, err = client.TransactionByHash(ctx, txHash)
 			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain transaction %s", txHash.Hex()))
 		}
 
+		if json, err := tx.MarshalJSON(); err == nil && !transactionInfoJSON {
+			// JSON output records itself.
+			err = recordJSON(cmd, json)
+			cli.WarnCheck(err, quiet, "Failed to record JSON output")
+		}
+
 		if quiet {
 			os.Exit(_exit_success)
 		}
@@ -90,7 +99,8 @@ In quiet mode this will return 0 if the transaction exists, otherwise 1.`,
 		if transactionInfoJSON {
 			json, err := tx.MarshalJSON()
 			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain JSON for transaction %s", txHash.Hex()))
-			fmt.Printf("%s\n", string(json))
+			err = outputJSON(cmd, json)
+			cli.ErrCheck(err, quiet, "Failed to output JSON")
 			os.Exit(_exit_success)
 		}
 
@@ -133,16 +143,16 @@ In quiet mode this will return 0 if the transaction exists, otherwise 1.`,
 
 		fromAddress, err := txFrom(tx)
 		if err == nil {
-			fmt.Printf("From:\t\t\t%v\n", ens.Format(client, fromAddress))
+			fmt.Printf("From:\t\t\t%v\n", util.ENSFormat(client, fromAddress))
 		}
 
 		// To
 		if tx.To() == nil {
 			if receipt != nil {
-				fmt.Printf("Contract address:\t%v\n", ens.Format(client, receipt.ContractAddress))
+				fmt.Printf("Contract address:\t%v\n", util.ENSFormat(client, receipt.ContractAddress))
 			}
 		} else {
-			fmt.Printf("To:\t\t\t%v\n", ens.Format(client, *tx.To()))
+			fmt.Printf("To:\t\t\t%v\n", util.ENSFormat(client, *tx.To()))
 		}
 
 		if verbose {
@@ -163,7 +173,7 @@ In quiet mode this will return 0 if the transaction exists, otherwise 1.`,
 			fmt.Printf("Logs:\n")
 			for i, log := range receipt.Logs {
 				fmt.Printf("\t%d:\n", i)
-				fmt.Printf("\t\tFrom:\t%v\n", ens.Format(client, log.Address))
+				fmt.Printf("\t\tFrom:\t%v\n", util.ENSFormat(client, log.Address))
 				// Try to obtain decoded log
 				decoded := txdata.EventToString(client, log)
 				if decoded != "" {
@@ -190,7 +200,9 @@ In quiet mode this will return 0 if the transaction exists, otherwise 1.`,
 func init() {
 	transactionCmd.AddCommand(transactionInfoCmd)
 	transactionFlags(transactionInfoCmd)
+	transactionPrefixFlags(transactionInfoCmd)
 	transactionInfoCmd.Flags().BoolVar(&transactionInfoRaw, "raw", false, "Output the transaction as raw hex")
 	transactionInfoCmd.Flags().BoolVar(&transactionInfoJSON, "json", false, "Output the transaction as json")
 	transactionInfoCmd.Flags().StringVar(&transactionInfoSignatures, "signatures", "", "Semicolon-separated list of custom transaction signatures (e.g. myFunc(address,bytes32);myFunc2(bool)")
+	jsonoutCmds["transaction:info"] = true
 }
@@ -15,20 +15,24 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"math/big"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/wealdtech/ethereal/cli"
-	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/ethereal/util"
 	string2eth "github.com/wealdtech/go-string2eth"
 )
 
@@ -38,6 +42,10 @@ var transactionSendToAddress string
 var transactionSendData string
 var transactionSendRaw string
 var transactionSendRepeat int
+var transactionSendMaxGasPrice string
+var transactionSendWaitForPrice bool
+var transactionSendDeadline time.Duration
+var transactionSendPriceInterval time.Duration
 
 // transactionSendCmd represents the transaction send command
 var transactionSendCmd = &cobra.Command{
@@ -47,60 +55,75 @@ var transactionSendCmd = &cobra.Command{
 
     ethereal transaction send --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --to=0x2ab7150Bba7D5F181b3aF5623e52b15bB1054845	 --amount=1ether --passphrase=secret --data=0x12345
 
+If --waitforprice is supplied then the transaction will not be created until the network's gas price is at or below the value supplied in --maxgasprice, for example:
+
+    ethereal transaction send --from=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --to=0x2ab7150Bba7D5F181b3aF5623e52b15bB1054845 --amount=1ether --passphrase=secret --maxgasprice=20gwei --waitforprice --deadline=6h
+
+The gas price is checked every 15 seconds; this can be changed with --priceinterval.  If the gas price does not fall far enough before the deadline then the transaction will not be sent.
+
+A transaction that has already been signed, for example on an offline machine with --offline, can be sent with:
+
+    ethereal transaction send --raw=0xf86b808504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a0...
+
+where raw is the hex string of the transaction, the JSON output of --offlineformat=json, or the path to a file containing one transaction of either form per line.  If --from is not supplied then --data is treated in the same way as --raw.  Transactions that are for a different chain, or that are not replay-protected, will not be sent unless --force is supplied.
+
 This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if !offline && transactionSendRaw == "" && transactionSendFromAddress == "" && transactionSendData != "" {
+			// Data without a sender is a signed transaction
+			transactionSendRaw = transactionSendData
+		}
 		if transactionSendRaw != "" {
+			cli.Assert(!offline, quiet, "Cannot send raw transactions in offline mode")
 			// Send raw transactions.
 			signedTxs := make([]*types.Transaction, 0)
 
-			if !strings.HasPrefix(transactionSendRaw, "0x") {
+			if !strings.HasPrefix(transactionSendRaw, "0x") && !strings.HasPrefix(transactionSendRaw, "{") {
 				// Data is a file.
 				data, err := ioutil.ReadFile(transactionSendRaw)
 				cli.ErrCheck(err, quiet, "Failed to read raw transaction from filesystem")
 				lines := bytes.Split(bytes.Replace(data, []byte("\r\n"), []byte("\n"), -1), []byte("\n"))
 				for i := range lines {
 					if len(lines[i]) > 2 {
-						data, err := hex.DecodeString(strings.TrimPrefix(string(lines[i]), "0x"))
-						cli.ErrCheck(err, quiet, "Failed to decode transaction")
-						signedTx := &types.Transaction{}
-						stream := rlp.NewStream(bytes.NewReader(data), 0)
-						err = signedTx.DecodeRLP(stream)
+						signedTx, err := transactionSendDecode(string(lines[i]))
 						cli.ErrCheck(err, quiet, "Failed to decode transaction")
 						signedTxs = append(signedTxs, signedTx)
 					}
 				}
 			} else {
 				// Data is a direct transaction.
-				data, err := hex.DecodeString(strings.TrimPrefix(transactionSendRaw, "0x"))
-				cli.ErrCheck(err, quiet, "Failed to decode data")
-				// Decode the raw transaction
-				signedTx := &types.Transaction{}
-				stream := rlp.NewStream(bytes.NewReader(data), 0)
-				err = signedTx.DecodeRLP(stream)
+				signedTx, err := transactionSendDecode(transactionSendRaw)
 				cli.ErrCheck(err, quiet, "Failed to decode transaction")
 				signedTxs = append(signedTxs, signedTx)
 			}
 
+			for i := range signedTxs {
+				transactionSendCheckChainID(signedTxs[i])
+			}
+
+			mined := true
 			for i := range signedTxs {
 				ctx, cancel := localContext()
 				defer cancel()
 				err = client.SendTransaction(ctx, signedTxs[i])
 				cli.ErrCheck(err, quiet, "Failed to send transaction")
 
-				logTransaction(signedTxs[i], log.Fields{
-					"group":   "transaction",
-					"command": "send",
-				})
-
-				if !quiet {
-					fmt.Println(signedTxs[i].Hash().Hex())
+				if viper.GetBool("wait") {
+					// Provide the hash now as it will be a while before it is mined
+					outputIf(!quiet, signedTxs[i].Hash().Hex())
+				}
+				if !transactionSendSubmitted(signedTxs[i]) {
+					mined = false
 				}
 			}
+			if !mined {
+				os.Exit(_exit_not_mined)
+			}
 			os.Exit(_exit_success)
 		}
 
 		cli.Assert(transactionSendFromAddress != "", quiet, "--from is required")
-		fromAddress, err := ens.Resolve(client, transactionSendFromAddress)
+		fromAddress, err := util.ResolveAddress(client, transactionSendFromAddress)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve from address %s", transactionSendFromAddress))
 
 		var toAddress *common.Address
@@ -108,7 +131,7 @@ This will return an exit status of 0 if the transaction is successfully submitte
 			// This is valid because it can be a contract creation, but only if there is data as well
 			cli.Assert(transactionSendData != "", quiet, "Transactions without a to address are contract creations and must have data")
 		} else {
-			tmp, err := ens.Resolve(client, transactionSendToAddress)
+			tmp, err := util.ResolveAddress(client, transactionSendToAddress)
 			cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to resolve to address %s", transactionSendToAddress))
 			toAddress = &tmp
 		}
@@ -117,7 +140,7 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		if transactionSendAmount == "" {
 			amount = big.NewInt(0)
 		} else {
-			amount, err = string2eth.StringToWei(transactionSendAmount)
+			amount, err = parseAmount(transactionSendAmount)
 			cli.ErrCheck(err, quiet, "Invalid amount")
 		}
 
@@ -139,6 +162,15 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		data, err := hex.DecodeString(transactionSendData)
 		cli.ErrCheck(err, quiet, "Failed to parse data")
 
+		if transactionSendWaitForPrice {
+			cli.Assert(!offline, quiet, "Cannot wait for gas price in offline mode")
+			cli.Assert(transactionSendMaxGasPrice != "", quiet, "--maxgasprice is required when waiting for gas price")
+			maxGasPrice, err := parseAmount(transactionSendMaxGasPrice)
+			cli.ErrCheck(err, quiet, "Invalid maximum gas price")
+			transactionSendAwaitGasPrice(fromAddress, maxGasPrice)
+		}
+
+		mined := true
 		for i := 0; i < transactionSendRepeat; i++ {
 			// Create and sign the transaction
 			signedTx, err := createSignedTransaction(fromAddress, toAddress, amount, gasLimit, data)
@@ -146,9 +178,7 @@ This will return an exit status of 0 if the transaction is successfully submitte
 
 			if offline {
 				if !quiet {
-					buf := new(bytes.Buffer)
-					signedTx.EncodeRLP(buf)
-					fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+					outputOfflineTransaction(signedTx)
 				}
 				os.Exit(_exit_success)
 			}
@@ -157,21 +187,116 @@ This will return an exit status of 0 if the transaction is successfully submitte
 			defer cancel()
 			err = client.SendTransaction(ctx, signedTx)
 			cli.ErrCheck(err, quiet, "Failed to send transaction")
-			handleSubmittedTransaction(signedTx, log.Fields{
-				"group":   "transaction",
-				"command": "send",
-			}, false)
+			if !transactionSendSubmitted(signedTx) {
+				mined = false
+			}
 		}
+		if !mined {
+			os.Exit(_exit_not_mined)
+		}
+		os.Exit(_exit_success)
 	},
 }
 
+// transactionSendSubmitted handles a submitted transaction, returning false
+// if it was waited for but not mined.  A transaction that is mined but reverts
+// is treated as mined, as it is by other commands.
+func transactionSendSubmitted(tx *types.Transaction) bool {
+	if handleSubmittedTransaction(tx, log.Fields{
+		"group":   "transaction",
+		"command": "send",
+	}, false) {
+		return true
+	}
+	ctx, cancel := localContext()
+	defer cancel()
+	_, err := client.TransactionReceipt(ctx, tx.Hash())
+	return err == nil
+}
+
+// transactionSendCheckChainID ensures that a signed transaction is for the
+// chain to which we are connected, exiting if not and --force is not supplied.
+func transactionSendCheckChainID(tx *types.Transaction) {
+	if viper.GetBool("force") {
+		return
+	}
+	cli.Assert(tx.Protected(), quiet, fmt.Sprintf("Transaction %s is not replay-protected; use --force to send it anyway", tx.Hash().Hex()))
+	cli.Assert(tx.ChainId().Cmp(chainID) == 0, quiet, fmt.Sprintf("Transaction %s is for chain %v but connected to chain %v; use --force to send it anyway", tx.Hash().Hex(), tx.ChainId(), chainID))
+}
+
+// transactionSendAwaitGasPrice waits for the network gas price to drop to at
+// or below the supplied maximum, exiting if the deadline passes first.
+func transactionSendAwaitGasPrice(fromAddress common.Address, maxGasPrice *big.Int) {
+	deadlineCtx := context.Background()
+	if transactionSendDeadline != 0 {
+		var cancel context.CancelFunc
+		deadlineCtx, cancel = context.WithTimeout(deadlineCtx, transactionSendDeadline)
+		defer cancel()
+	}
+	cli.Assert(transactionSendPriceInterval > 0, quiet, "--priceinterval must be greater than 0")
+	for {
+		ctx, cancel := localContext()
+		currentGasPrice, err := client.SuggestGasPrice(ctx)
+		cancel()
+		cli.ErrCheck(err, quiet, "Failed to obtain gas price")
+		outputIf(verbose, fmt.Sprintf("Current gas price is %s", string2eth.WeiToString(currentGasPrice, true)))
+		if currentGasPrice.Cmp(maxGasPrice) <= 0 {
+			if viper.GetString("gasprice") == "" {
+				gasPrice = currentGasPrice
+			}
+			break
+		}
+		select {
+		case <-deadlineCtx.Done():
+			cli.Err(quiet, fmt.Sprintf("Gas price did not fall to %s before the deadline", string2eth.WeiToString(maxGasPrice, true)))
+		case <-time.After(transactionSendPriceInterval):
+		}
+	}
+
+	// Time has passed, so ensure that the nonce has not been used in the meantime
+	if nonce != -1 {
+		ctx, cancel := localContext()
+		defer cancel()
+		pendingNonce, err := client.PendingNonceAt(ctx, fromAddress)
+		cli.ErrCheck(err, quiet, "Failed to obtain nonce")
+		cli.Assert(uint64(nonce) >= pendingNonce, quiet, fmt.Sprintf("Nonce %d has been used whilst waiting for gas price", nonce))
+	}
+}
+
+// transactionSendDecode decodes a signed transaction supplied either as hex
+// or as the JSON output of --offlineformat=json.
+func transactionSendDecode(input string) (*types.Transaction, error) {
+	input = strings.TrimSpace(input)
+	if strings.HasPrefix(input, "{") {
+		var jsonTx jsonTransaction
+		if err := json.Unmarshal([]byte(input), &jsonTx); err != nil {
+			return nil, err
+		}
+		return jsonTx.transaction()
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(input, "0x"))
+	if err != nil {
+		return nil, err
+	}
+	signedTx := &types.Transaction{}
+	stream := rlp.NewStream(bytes.NewReader(data), 0)
+	if err := signedTx.DecodeRLP(stream); err != nil {
+		return nil, err
+	}
+	return signedTx, nil
+}
+
 func init() {
 	transactionCmd.AddCommand(transactionSendCmd)
-	transactionSendCmd.Flags().StringVar(&transactionSendAmount, "amount", "", "Amount of Ether to transfer")
+	transactionSendCmd.Flags().StringVar(&transactionSendAmount, "amount", "", "Amount of Ether to transfer, with a unit (e.g. \"1.5 ether\")")
 	transactionSendCmd.Flags().StringVar(&transactionSendFromAddress, "from", "", "Address from which to transfer Ether")
 	transactionSendCmd.Flags().StringVar(&transactionSendToAddress, "to", "", "Address to which to transfer Ether")
 	transactionSendCmd.Flags().StringVar(&transactionSendData, "data", "", "data to send with transaction (as a hex string)")
-	transactionSendCmd.Flags().StringVar(&transactionSendRaw, "raw", "", "raw transaction (as a hex string).  This overrides all other options")
+	transactionSendCmd.Flags().StringVar(&transactionSendRaw, "raw", "", "raw transaction (as a hex string, or path to a file containing hex strings).  This overrides all other options")
 	transactionSendCmd.Flags().IntVar(&transactionSendRepeat, "repeat", 1, "Number of times to repeat sending the transaction (incrementing the nonce each time)")
+	transactionSendCmd.Flags().StringVar(&transactionSendMaxGasPrice, "maxgasprice", "", "Maximum gas price at which to send the transaction, with a unit (used with --waitforprice)")
+	transactionSendCmd.Flags().BoolVar(&transactionSendWaitForPrice, "waitforprice", false, "Wait for the gas price to fall to the maximum gas price before sending the transaction")
+	transactionSendCmd.Flags().DurationVar(&transactionSendDeadline, "deadline", 0, "maximum time to wait for the gas price to fall before failing (default forever)")
+	transactionSendCmd.Flags().DurationVar(&transactionSendPriceInterval, "priceinterval", 15*time.Second, "time between checks of the gas price when waiting for it to fall (used with --waitforprice)")
 	addTransactionFlags(transactionSendCmd, "the address from which to transfer Ether")
 }
@@ -14,13 +14,11 @@
 package cmd
 
 import (
-	"bytes"
-	"encoding/hex"
 	"fmt"
 	"math/big"
 	"os"
+	"strings"
 
-	"github.com/ethereum/go-ethereum/common"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -28,48 +26,71 @@ import (
 	string2eth "github.com/wealdtech/go-string2eth"
 )
 
+var transactionUpPercent int64
+
 // transactionUpCmd represents the transaction up command
 var transactionUpCmd = &cobra.Command{
-	Use:   "up",
-	Short: "Increase the gas cost for a pending transaction",
-	Long: `Increase the gas cost for a pending transaction.  For example:
+	Use:     "up",
+	Aliases: []string{"bump"},
+	Short:   "Increase the gas cost for a pending transaction",
+	Long: `Increase the gas cost for a pending transaction, resending it with the same nonce.  For example:
 
     ethereal transaction up --gasprice=20gwei --passphrase=secret --transaction=0x454d2274155cce506359de6358785ce5366f6c13e825263674c272eec8532c0c
 
-If no gas price is supplied then it will default to just over 10% higher than the current gas price for the transaction.
+The transaction must be supplied as its full hash; unlike other transaction commands a prefix is not accepted, as only mined transactions can be searched for a prefix.
+
+If no gas price is supplied then it will be increased by the percentage supplied in --bumppercent, which defaults to (and cannot be less than) 10%, as nodes will not accept a replacement transaction with a smaller increase.  If --gasprice is supplied it must be at least the increased gas price.  All other details of the transaction are unchanged.  The transaction is re-signed by its sender, which must be available locally or supplied with --privatekey.
+
+It is an error to increase the gas cost of a transaction that has already been mined, or whose nonce has already been used by another mined transaction.
+
+With --offline the pending transaction is still obtained from the node, but the replacement transaction is printed rather than sent.
 
 This will return an exit status of 0 if the transaction is successfully submitted (and mined if --wait is supplied), 1 if the transaction is not successfully submitted, and 2 if the transaction is successfully submitted but not mined within the supplied time limit.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(transactionStr != "", quiet, "--transaction is required")
-		txHash := common.HexToHash(transactionStr)
+		cli.Assert(transactionUpPercent >= 10, quiet, "--bumppercent must be at least 10")
+		if client == nil {
+			// The pending transaction is obtained from the node even when offline
+			cli.ErrCheck(connect(), quiet, "Failed to connect to Ethereum node")
+		}
+		// Prefixes are resolved against mined blocks so cannot find a pending transaction
+		cli.Assert(len(strings.TrimPrefix(transactionStr, "0x")) == 64, quiet, "--transaction must be a full transaction hash")
+		txHash, err := transactionHash(transactionStr)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain transaction %s", transactionStr))
 		ctx, cancel := localContext()
 		defer cancel()
 		tx, pending, err := client.TransactionByHash(ctx, txHash)
 		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain transaction %s", txHash.Hex()))
 		cli.Assert(pending, quiet, fmt.Sprintf("Transaction %s has already been mined", txHash.Hex()))
 
-		minGasPrice := new(big.Int).Add(new(big.Int).Add(tx.GasPrice(), new(big.Int).Div(tx.GasPrice(), big.NewInt(10))), big.NewInt(1))
+		fromAddress, err := txFrom(tx)
+		cli.ErrCheck(err, quiet, "Failed to obtain from address")
+
+		// The transaction may no longer be pending if another with the same nonce has been mined
+		ctx, cancel = localContext()
+		defer cancel()
+		minedNonce, err := client.NonceAt(ctx, fromAddress, nil)
+		cli.ErrCheck(err, quiet, "Failed to obtain nonce of sender")
+		cli.Assert(tx.Nonce() >= minedNonce, quiet, fmt.Sprintf("A transaction with nonce %d from %s has already been mined", tx.Nonce(), fromAddress.Hex()))
+
+		minGasPrice := transactionUpGasPrice(tx.GasPrice(), transactionUpPercent)
 		if viper.GetString("gasprice") == "" {
 			// No gas price supplied; use the calculated minimum
 			gasPrice = minGasPrice
 		} else {
-			// Gas price supplied; ensure it is over 10% more than the current gas price
-			cli.Assert(gasPrice.Cmp(minGasPrice) > 0, quiet, fmt.Sprintf("Gas price must be at least %s", string2eth.WeiToString(minGasPrice, true)))
+			// Gas price supplied; ensure it is at least the calculated minimum
+			cli.Assert(gasPrice.Cmp(minGasPrice) >= 0, quiet, fmt.Sprintf("Gas price must be at least %s", string2eth.WeiToString(minGasPrice, true)))
 		}
+		outputIf(verbose, fmt.Sprintf("Gas price increased from %s to %s", string2eth.WeiToString(tx.GasPrice(), true), string2eth.WeiToString(gasPrice, true)))
 
 		// Create and sign the transaction
-		fromAddress, err := txFrom(tx)
-		cli.ErrCheck(err, quiet, "Failed to obtain from address")
-
 		nonce = int64(tx.Nonce())
 		signedTx, err := createSignedTransaction(fromAddress, tx.To(), tx.Value(), tx.Gas(), tx.Data())
 		cli.ErrCheck(err, quiet, "Failed to create transaction")
 
 		if offline {
 			if !quiet {
-				buf := new(bytes.Buffer)
-				signedTx.EncodeRLP(buf)
-				fmt.Printf("0x%s\n", hex.EncodeToString(buf.Bytes()))
+				outputOfflineTransaction(signedTx)
 			}
 			os.Exit(_exit_success)
 		}
@@ -79,15 +100,25 @@ This will return an exit status of 0 if the transaction is successfully submitte
 		err = client.SendTransaction(ctx, signedTx)
 		cli.ErrCheck(err, quiet, "Failed to send transaction")
 		handleSubmittedTransaction(signedTx, log.Fields{
-			"group":       "transaction",
-			"command":     "up",
-			"oldgasprice": tx.GasPrice().String(),
+			"group":            "transaction",
+			"command":          "up",
+			"oldtransactionid": txHash.Hex(),
+			"oldgasprice":      tx.GasPrice().String(),
 		}, true)
 	},
 }
 
+// transactionUpGasPrice increases a gas price by the given percentage,
+// rounding up so that the result always satisfies the replacement rules.
+func transactionUpGasPrice(current *big.Int, percent int64) *big.Int {
+	increased := new(big.Int).Mul(current, big.NewInt(100+percent))
+	increased.Add(increased, big.NewInt(99))
+	return increased.Div(increased, big.NewInt(100))
+}
+
 func init() {
 	transactionCmd.AddCommand(transactionUpCmd)
 	transactionFlags(transactionUpCmd)
-	addTransactionFlags(transactionUpCmd, "the address that holds the funds")
+	transactionUpCmd.Flags().Int64Var(&transactionUpPercent, "bumppercent", 10, "Percentage by which to increase the gas price")
+	addTransactionFlags(transactionUpCmd, "the sender of the transaction")
 }
@@ -14,16 +14,18 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 	"time"
 
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 	"github.com/wealdtech/ethereal/cli"
 	"github.com/wealdtech/ethereal/util"
 )
 
 var transactionWaitLimit time.Duration
+var transactionWaitFinalized bool
 
 // transactionWaitCmd represents the transaction info command
 var transactionWaitCmd = &cobra.Command{
@@ -33,24 +35,52 @@ var transactionWaitCmd = &cobra.Command{
 
     ethereal transaction wait --transaction=0x5FfC014343cd971B7eb70732021E26C35B744cc4 --limit=30s
 
-In quiet mode this will return 0 if the transaction is mined before the time limit is reached, otherwise 1.`,
+If --finalized is supplied then this will wait for the transaction to be in a finalized block, rather than just mined, checking every --pollinterval.  This requires a client that supports post-merge block tags, and fails immediately if the client cannot supply the finalized block.
+
+The transaction ID can be shortened to its first few bytes, in which case recent blocks will be searched for a matching transaction.
+
+In quiet mode this will return 0 if the transaction is mined (or finalized, if requested) before the time limit is reached, otherwise 1.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		cli.Assert(transactionStr != "", quiet, "--transaction is required")
-		txHash := common.HexToHash(transactionStr)
+		txHash, err := transactionHash(transactionStr)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain transaction %s", transactionStr))
 
+		start := time.Now()
 		mined := util.WaitForTransaction(client, txHash, transactionWaitLimit)
-		if mined {
-			outputIf(!quiet, "Transaction mined")
-			os.Exit(_exit_success)
-		} else {
+		if !mined {
 			outputIf(!quiet, "Transaction not mined")
 			os.Exit(_exit_failure)
 		}
+		if !transactionWaitFinalized {
+			outputIf(!quiet, "Transaction mined")
+			os.Exit(_exit_success)
+		}
+
+		outputIf(verbose, "Transaction mined; waiting for finality")
+		limit := transactionWaitLimit
+		if limit != 0 {
+			limit -= time.Since(start)
+			if limit <= 0 {
+				outputIf(!quiet, "Transaction not finalized")
+				os.Exit(_exit_failure)
+			}
+		}
+		finalized, err := util.WaitForFinality(client, rpcClient, txHash, limit, viper.GetDuration("pollinterval"))
+		cli.ErrCheck(err, quiet, "Failed to obtain finalized block; the connection may not support post-merge block tags")
+		if finalized {
+			outputIf(!quiet, "Transaction finalized")
+			os.Exit(_exit_success)
+		}
+		outputIf(!quiet, "Transaction not finalized")
+		os.Exit(_exit_failure)
 	},
 }
 
 func init() {
 	transactionCmd.AddCommand(transactionWaitCmd)
 	transactionFlags(transactionWaitCmd)
+	transactionPrefixFlags(transactionWaitCmd)
 	transactionWaitCmd.Flags().DurationVar(&transactionWaitLimit, "limit", 0, "maximum time to wait before failing (default forever)")
+	transactionWaitCmd.Flags().BoolVar(&transactionWaitFinalized, "finalized", false, "wait for the transaction to be in a finalized block")
+	transactionWaitCmd.Flags().Duration("pollinterval", 12*time.Second, "time between checks for the transaction being finalized")
 }
@@ -0,0 +1,218 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/ethereal/cli"
+)
+
+var transactionWatchConfirmations int64
+var transactionWatchLimit time.Duration
+var transactionWatchGrace time.Duration
+
+// transactionWatchCmd represents the transaction watch command
+var transactionWatchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watch the progress of a transaction",
+	Long: `Watch the progress of a transaction, printing changes to its status as they happen.  For example:
+
+    ethereal transaction watch --transaction=0x5c2f3c1c4fa4fbd2b6b0bc6f4a8cc7e23d2ae9e1a36e10c3e1a0ef81d2a3c4b5 --confirmations=6
+
+Status changes are reported when the transaction is seen in the mempool, when it is included in a block, and when it has been confirmed by the requested number of blocks.  If a chain reorganisation removes the transaction from its block this is reported.  If the node reports that it does not know the transaction for longer than --grace, either because it has not yet seen the transaction or because the transaction has been dropped, the watch ends.  Other failures to obtain the transaction, such as connection problems, are retried until --limit is reached.
+
+New blocks are picked up through a subscription if the connection supports it, such as websocket or IPC, otherwise the node is polled every --pollinterval.
+
+In quiet mode this will return 0 if the transaction is confirmed, 1 if it is confirmed but reverted or cannot be found, and 2 if it is dropped or not confirmed before the time limit is reached.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cli.Assert(transactionStr != "", quiet, "--transaction is required")
+		cli.Assert(transactionWatchConfirmations > 0, quiet, "--confirmations must be at least 1")
+		txHash, err := transactionHash(transactionStr)
+		cli.ErrCheck(err, quiet, fmt.Sprintf("Failed to obtain transaction %s", transactionStr))
+
+		interval := viper.GetDuration("pollinterval")
+		if interval <= 0 {
+			interval = 5 * time.Second
+		}
+
+		// Prefer notification of new blocks, falling back to polling if the
+		// connection does not support subscriptions.
+		heads := make(chan *types.Header)
+		var subErr <-chan error
+		sub, err := client.SubscribeNewHead(context.Background(), heads)
+		if err == nil {
+			defer sub.Unsubscribe()
+			subErr = sub.Err()
+			outputIf(verbose, "Subscribed to new blocks")
+		} else {
+			outputIf(verbose, fmt.Sprintf("Subscriptions not available (%v); polling every %v", err, interval))
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		var timeout <-chan time.Time
+		if transactionWatchLimit != 0 {
+			timeout = time.After(transactionWatchLimit)
+		}
+
+		watcher := &transactionWatcher{
+			txHash:        txHash,
+			confirmations: transactionWatchConfirmations,
+			grace:         transactionWatchGrace,
+		}
+		for {
+			if exitCode, done := watcher.check(); done {
+				os.Exit(exitCode)
+			}
+			select {
+			case <-heads:
+			case <-ticker.C:
+			case err := <-subErr:
+				// Subscription has gone away; continue by polling.
+				outputIf(verbose, fmt.Sprintf("Subscription failed (%v); polling every %v", err, interval))
+				subErr = nil
+			case <-timeout:
+				outputIf(!quiet, fmt.Sprintf("%s %s not confirmed within %v", time.Now().Format(time.RFC3339), txHash.Hex(), transactionWatchLimit))
+				os.Exit(_exit_not_mined)
+			}
+		}
+	},
+}
+
+// transactionWatcher tracks the state of a transaction between checks.
+type transactionWatcher struct {
+	txHash        common.Hash
+	confirmations int64
+	grace         time.Duration
+	missingSince  time.Time
+	seen          bool
+	pending       bool
+	blockHash     common.Hash
+	blockNumber   uint64
+}
+
+// check updates the state of the transaction, reporting any changes.  It
+// returns true with an exit code if the watch has finished.
+func (w *transactionWatcher) check() (int, bool) {
+	prefix := fmt.Sprintf("%s %s", time.Now().Format(time.RFC3339), w.txHash.Hex())
+
+	ctx, cancel := localContext()
+	receipt, err := client.TransactionReceipt(ctx, w.txHash)
+	cancel()
+	if err != nil && err != ethereum.NotFound {
+		outputIf(verbose, fmt.Sprintf("Failed to obtain receipt: %v", err))
+		return 0, false
+	}
+	if err == nil && receipt != nil && receipt.BlockNumber != nil {
+		w.missingSince = time.Time{}
+		if w.blockHash != receipt.BlockHash {
+			if w.blockHash != (common.Hash{}) {
+				outputIf(!quiet, fmt.Sprintf("%s moved from block %d to block %d due to chain reorganisation", prefix, w.blockNumber, receipt.BlockNumber.Uint64()))
+			} else {
+				outputIf(!quiet, fmt.Sprintf("%s included in block %d", prefix, receipt.BlockNumber.Uint64()))
+			}
+			w.seen = true
+			w.pending = false
+			w.blockHash = receipt.BlockHash
+			w.blockNumber = receipt.BlockNumber.Uint64()
+		}
+
+		ctx, cancel := localContext()
+		header, err := client.HeaderByNumber(ctx, nil)
+		cancel()
+		if err != nil {
+			outputIf(verbose, fmt.Sprintf("Failed to obtain latest block: %v", err))
+			return 0, false
+		}
+		if header.Number.Uint64() < w.blockNumber {
+			// Node is behind the block that holds the transaction; wait for it.
+			return 0, false
+		}
+		confirmations := int64(header.Number.Uint64()-w.blockNumber) + 1
+		if confirmations < w.confirmations {
+			outputIf(verbose, fmt.Sprintf("%s has %d of %d confirmations", prefix, confirmations, w.confirmations))
+			return 0, false
+		}
+		if receipt.Status == types.ReceiptStatusFailed {
+			outputIf(!quiet, fmt.Sprintf("%s confirmed after %d blocks but reverted (gas used %d)", prefix, confirmations, receipt.GasUsed))
+			return _exit_failure, true
+		}
+		outputIf(!quiet, fmt.Sprintf("%s confirmed after %d blocks (gas used %d)", prefix, confirmations, receipt.GasUsed))
+		return _exit_success, true
+	}
+
+	if w.blockHash != (common.Hash{}) {
+		outputIf(!quiet, fmt.Sprintf("%s removed from block %d due to chain reorganisation", prefix, w.blockNumber))
+		w.blockHash = common.Hash{}
+		w.blockNumber = 0
+	}
+
+	ctx, cancel = localContext()
+	_, pending, err := client.TransactionByHash(ctx, w.txHash)
+	cancel()
+	if err != nil && err != ethereum.NotFound {
+		outputIf(verbose, fmt.Sprintf("Failed to obtain transaction: %v", err))
+		return 0, false
+	}
+	if err == nil {
+		w.missingSince = time.Time{}
+	}
+	if err == nil && pending {
+		if !w.pending {
+			outputIf(!quiet, fmt.Sprintf("%s seen in mempool", prefix))
+			w.seen = true
+			w.pending = true
+		}
+		return 0, false
+	}
+	if err == nil {
+		// Known to the node as mined but without a receipt yet; try again.
+		return 0, false
+	}
+
+	// The node does not know the transaction.  It may not have reached the
+	// node yet, so allow a grace period before giving up on it.
+	if w.missingSince.IsZero() {
+		w.missingSince = time.Now()
+	}
+	if time.Since(w.missingSince) < w.grace {
+		outputIf(verbose, fmt.Sprintf("%s not known to the node; waiting", prefix))
+		return 0, false
+	}
+	if !w.seen {
+		outputIf(!quiet, fmt.Sprintf("%s not found", prefix))
+		return _exit_failure, true
+	}
+	outputIf(!quiet, fmt.Sprintf("%s dropped", prefix))
+	return _exit_not_mined, true
+}
+
+func init() {
+	transactionCmd.AddCommand(transactionWatchCmd)
+	transactionFlags(transactionWatchCmd)
+	transactionPrefixFlags(transactionWatchCmd)
+	transactionWatchCmd.Flags().StringVar(&transactionStr, "hash", "", "ID of the transaction (alias for --transaction)")
+	transactionWatchCmd.Flags().Int64Var(&transactionWatchConfirmations, "confirmations", 1, "number of blocks, including the one containing the transaction, required for it to be confirmed")
+	transactionWatchCmd.Flags().DurationVar(&transactionWatchLimit, "limit", 0, "maximum time to wait before failing (default forever)")
+	transactionWatchCmd.Flags().DurationVar(&transactionWatchGrace, "grace", time.Minute, "time for which the transaction can be unknown to the node before it is considered not found or dropped")
+	transactionWatchCmd.Flags().Duration("pollinterval", 5*time.Second, "time between checks when subscriptions are not available")
+}
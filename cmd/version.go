@@ -22,6 +22,9 @@ import (
 	"github.com/spf13/viper"
 )
 
+// ReleaseVersion is the current version of Ethereal
+var ReleaseVersion = "2.3.22"
+
 // versionCmd represents the version command
 var versionCmd = &cobra.Command{
 	Use:   "version",
@@ -30,7 +33,7 @@ var versionCmd = &cobra.Command{
 
     ethereal version.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		fmt.Println("2.3.22")
+		fmt.Println(ReleaseVersion)
 		if viper.GetBool("verbose") {
 			buildInfo, ok := dbg.ReadBuildInfo()
 			if ok {
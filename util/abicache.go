@@ -0,0 +1,76 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// abiCacheFile returns the path of the cache entry for a contract's ABI, or
+// an empty string if there is no user configuration directory.
+func abiCacheFile(address common.Address, chainID *big.Int) string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "ethereal", "abis", chainID.String(), fmt.Sprintf("%s.json", address.Hex()))
+}
+
+// CachedABI returns the cached ABI of a contract, or an empty string if it
+// is not cached.  Entries that are not valid ABIs, for example because they
+// have been corrupted, are removed so that the ABI is obtained afresh.
+func CachedABI(address common.Address, chainID *big.Int) string {
+	path := abiCacheFile(address, chainID)
+	if path == "" {
+		return ""
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	if _, err := abi.JSON(strings.NewReader(string(data))); err != nil {
+		_ = os.Remove(path)
+		return ""
+	}
+	return string(data)
+}
+
+// CacheABI caches the ABI of a contract.  Failure to cache is not an error,
+// as the cache is only an optimisation.
+func CacheABI(address common.Address, chainID *big.Int, contractABI string) {
+	path := abiCacheFile(address, chainID)
+	if path == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.FileMode(0700)); err != nil {
+		return
+	}
+	// Write to a temporary file first so that an interrupted write cannot
+	// leave a partial entry.
+	tmpPath := fmt.Sprintf("%s.tmp", path)
+	if err := ioutil.WriteFile(tmpPath, []byte(contractABI), os.FileMode(0600)); err != nil {
+		return
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestABICache(t *testing.T) {
+	cached := common.HexToAddress("0x5FfC014343cd971B7eb70732021E26C35B744cc4")
+	corrupt := common.HexToAddress("0x52f1A3027d3aA514F17E454C93ae1F79b3B12d5d")
+	transferABI := `[{"inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"name":"transfer","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+
+	home, err := ioutil.TempDir("", "ethereal")
+	require.Nil(t, err)
+	defer os.RemoveAll(home)
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", home)
+	oldConfigHome := os.Getenv("XDG_CONFIG_HOME")
+	defer os.Setenv("XDG_CONFIG_HOME", oldConfigHome)
+	os.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+
+	CacheABI(cached, big.NewInt(1), transferABI)
+	CacheABI(corrupt, big.NewInt(1), transferABI)
+	corruptPath := abiCacheFile(corrupt, big.NewInt(1))
+	require.Nil(t, ioutil.WriteFile(corruptPath, []byte(transferABI[:20]), 0600))
+
+	tests := []struct {
+		address common.Address
+		chainID *big.Int
+		output  string
+	}{
+		{ // 0 - cached
+			address: cached,
+			chainID: big.NewInt(1),
+			output:  transferABI,
+		},
+		{ // 1 - cached on another chain
+			address: cached,
+			chainID: big.NewInt(5),
+		},
+		{ // 2 - corrupt
+			address: corrupt,
+			chainID: big.NewInt(1),
+		},
+	}
+
+	for i, test := range tests {
+		output := CachedABI(test.address, test.chainID)
+		assert.Equal(t, test.output, output, fmt.Sprintf("incorrect ABI at test %d", i))
+	}
+
+	// Corrupt entry should have been removed
+	_, err = os.Stat(corruptPath)
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,100 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+// abiSourceResponse is the response from an Etherscan-compatible getabi
+// endpoint.  The result is the ABI as a JSON string on success, or an error
+// message on failure.
+type abiSourceResponse struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Result  string `json:"result"`
+}
+
+// LookupABI obtains the ABI of a verified contract from an
+// Etherscan-compatible API.  The endpoint is set with the "abisource"
+// configuration value, and an API key can be supplied with the
+// "abisourcekey" configuration value.  Results are cached on disk keyed by
+// chain ID and address, so that repeated lookups do not hit the network; the
+// "refreshabi" configuration value bypasses the cache.
+func LookupABI(address common.Address, chainID *big.Int) (string, error) {
+	if !viper.GetBool("refreshabi") {
+		if cached := CachedABI(address, chainID); cached != "" {
+			return cached, nil
+		}
+	}
+
+	endpoint := viper.GetString("abisource")
+	if endpoint == "" {
+		return "", errors.New("no ABI source configured")
+	}
+	reqURL, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("invalid ABI source: %v", err)
+	}
+	query := reqURL.Query()
+	query.Set("chainid", chainID.String())
+	query.Set("module", "contract")
+	query.Set("action", "getabi")
+	query.Set("address", address.Hex())
+	if apiKey := viper.GetString("abisourcekey"); apiKey != "" {
+		query.Set("apikey", apiKey)
+	}
+	reqURL.RawQuery = query.Encode()
+
+	httpClient := &http.Client{Timeout: viper.GetDuration("timeout")}
+	resp, err := httpClient.Get(reqURL.String())
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("ABI source returned status %d", resp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	var response abiSourceResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", errors.New("invalid ABI source response")
+	}
+	if response.Status != "1" {
+		if response.Result != "" {
+			return "", fmt.Errorf("ABI source failed to provide ABI: %s", response.Result)
+		}
+		return "", fmt.Errorf("ABI source failed to provide ABI: %s", response.Message)
+	}
+	if _, err := abi.JSON(strings.NewReader(response.Result)); err != nil {
+		return "", fmt.Errorf("ABI source returned invalid ABI: %v", err)
+	}
+
+	CacheABI(address, chainID, response.Result)
+	return response.Result, nil
+}
@@ -0,0 +1,130 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupABI(t *testing.T) {
+	verified := common.HexToAddress("0x5FfC014343cd971B7eb70732021E26C35B744cc4")
+	unverified := common.HexToAddress("0x52f1A3027d3aA514F17E454C93ae1F79b3B12d5d")
+	invalid := common.HexToAddress("0x2ab7150Bba7D5F181b3aF5623e52b15bB1054845")
+	transferABI := `[{"inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"name":"transfer","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("module") != "contract" || query.Get("action") != "getabi" || query.Get("apikey") != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch {
+		case query.Get("address") == verified.Hex() && query.Get("chainid") == "1":
+			fmt.Fprintf(w, `{"status":"1","message":"OK","result":%q}`, transferABI)
+		case query.Get("address") == invalid.Hex():
+			fmt.Fprint(w, `{"status":"1","message":"OK","result":"not an ABI"}`)
+		default:
+			fmt.Fprint(w, `{"status":"0","message":"NOTOK","result":"Contract source code not verified"}`)
+		}
+	}))
+
+	home, err := ioutil.TempDir("", "ethereal")
+	require.Nil(t, err)
+	defer os.RemoveAll(home)
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", home)
+	oldConfigHome := os.Getenv("XDG_CONFIG_HOME")
+	defer os.Setenv("XDG_CONFIG_HOME", oldConfigHome)
+	os.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+	homedir.DisableCache = true
+	defer func() { homedir.DisableCache = false }()
+	viper.Set("abisourcekey", "secret")
+	defer viper.Set("abisourcekey", "")
+
+	tests := []struct {
+		source  string
+		address common.Address
+		chainID *big.Int
+		output  string
+		err     string
+	}{
+		{ // 0 - no source
+			address: verified,
+			chainID: big.NewInt(1),
+			err:     "no ABI source configured",
+		},
+		{ // 1 - verified contract
+			source:  server.URL,
+			address: verified,
+			chainID: big.NewInt(1),
+			output:  transferABI,
+		},
+		{ // 2 - unverified contract
+			source:  server.URL,
+			address: unverified,
+			chainID: big.NewInt(1),
+			err:     "ABI source failed to provide ABI: Contract source code not verified",
+		},
+		{ // 3 - verified contract on another chain
+			source:  server.URL,
+			address: verified,
+			chainID: big.NewInt(5),
+			err:     "ABI source failed to provide ABI: Contract source code not verified",
+		},
+		{ // 4 - invalid ABI
+			source:  server.URL,
+			address: invalid,
+			chainID: big.NewInt(1),
+			err:     "ABI source returned invalid ABI: invalid character 'o' in literal null (expecting 'u')",
+		},
+	}
+
+	defer viper.Set("abisource", "")
+	for i, test := range tests {
+		viper.Set("abisource", test.source)
+		output, err := LookupABI(test.address, test.chainID)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("incorrect error at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		assert.Equal(t, test.output, output, fmt.Sprintf("incorrect ABI at test %d", i))
+	}
+
+	// Found ABIs should now be served from the cache
+	server.Close()
+	output, err := LookupABI(verified, big.NewInt(1))
+	require.Nil(t, err)
+	assert.Equal(t, transferABI, output)
+
+	// Refreshing should bypass the cache
+	viper.Set("refreshabi", true)
+	defer viper.Set("refreshabi", false)
+	_, err = LookupABI(verified, big.NewInt(1))
+	assert.NotNil(t, err)
+}
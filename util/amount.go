@@ -0,0 +1,44 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+
+	string2eth "github.com/wealdtech/go-string2eth"
+)
+
+var bareAmountRegexp = regexp.MustCompile(`^-?[0-9]*(\.[0-9]*)?$`)
+
+// StringToWei parses an amount of Ether, such as "1.5 ether", "30 gwei" or
+// "1000000000 wei", in to a number of Wei.  Numbers without a unit are
+// ambiguous so are rejected, unless allowBare is true in which case they are
+// taken to be a number of Wei.  Zero does not require a unit.
+func StringToWei(input string, allowBare bool) (*big.Int, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, errors.New("no amount supplied")
+	}
+	if !allowBare && bareAmountRegexp.MatchString(strings.Replace(input, " ", "", -1)) {
+		if zero, err := string2eth.StringToWei(input); err == nil && zero.Sign() == 0 {
+			return zero, nil
+		}
+		return nil, fmt.Errorf("%q has no unit; supply one, for example \"%s ether\", \"%s gwei\" or \"%s wei\"", input, input, input, input)
+	}
+	return string2eth.StringToWei(input)
+}
@@ -0,0 +1,89 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringToWei(t *testing.T) {
+	tests := []struct {
+		input     string
+		allowBare bool
+		res       *big.Int
+		err       string
+	}{
+		{ // 0 - ether
+			input: "1.5 ether",
+			res:   bigInt("1500000000000000000"),
+		},
+		{ // 1 - gwei without space
+			input: "30gwei",
+			res:   bigInt("30000000000"),
+		},
+		{ // 2 - wei
+			input: "1000000000 wei",
+			res:   bigInt("1000000000"),
+		},
+		{ // 3 - mixed case unit with surrounding space
+			input: " 2 Ether ",
+			res:   bigInt("2000000000000000000"),
+		},
+		{ // 4 - bare number
+			input: "1000",
+			err:   `"1000" has no unit; supply one, for example "1000 ether", "1000 gwei" or "1000 wei"`,
+		},
+		{ // 5 - bare decimal
+			input: "1.5",
+			err:   `"1.5" has no unit; supply one, for example "1.5 ether", "1.5 gwei" or "1.5 wei"`,
+		},
+		{ // 6 - bare number allowed
+			input:     "1000",
+			allowBare: true,
+			res:       bigInt("1000"),
+		},
+		{ // 7 - bare zero
+			input: "0",
+			res:   bigInt("0"),
+		},
+		{ // 8 - unknown unit
+			input: "1 bitcoin",
+			err:   `failed to parse unit of 1 bitcoin`,
+		},
+		{ // 9 - negative
+			input: "-1 ether",
+			err:   `value resulted in negative number of Wei`,
+		},
+		{ // 10 - empty
+			input: "",
+			err:   "no amount supplied",
+		},
+	}
+
+	for i, test := range tests {
+		res, err := StringToWei(test.input, test.allowBare)
+		if test.err != "" {
+			require.NotNil(t, err, fmt.Sprintf("missing error at test %d", i))
+			assert.Equal(t, test.err, err.Error(), fmt.Sprintf("incorrect error at test %d", i))
+		} else {
+			require.Nil(t, err, fmt.Sprintf("unexpected error at test %d", i))
+			assert.Equal(t, test.res, res, fmt.Sprintf("failed at test %d", i))
+		}
+	}
+}
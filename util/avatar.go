@@ -0,0 +1,90 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AvatarNFT is an NFT referenced by an ENS avatar record, in the form
+// eip155:<chain ID>/erc721:<contract>/<token ID>.
+type AvatarNFT struct {
+	ChainID  *big.Int
+	Contract common.Address
+	TokenID  *big.Int
+}
+
+// ParseAvatarNFT parses an ENS avatar record that references an ERC-721
+// NFT.
+func ParseAvatarNFT(record string) (*AvatarNFT, error) {
+	parts := strings.Split(strings.TrimSpace(record), "/")
+	if len(parts) != 3 {
+		return nil, errors.New("NFT reference must have three parts")
+	}
+
+	if !strings.HasPrefix(strings.ToLower(parts[0]), "eip155:") {
+		return nil, errors.New("NFT reference must start with eip155")
+	}
+	chainID, ok := new(big.Int).SetString(parts[0][len("eip155:"):], 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid chain ID %s", parts[0][len("eip155:"):])
+	}
+
+	assetParts := strings.SplitN(parts[1], ":", 2)
+	if len(assetParts) != 2 {
+		return nil, errors.New("NFT reference has no asset type")
+	}
+	if strings.ToLower(assetParts[0]) != "erc721" {
+		return nil, fmt.Errorf("unsupported asset type %s", assetParts[0])
+	}
+	if !common.IsHexAddress(assetParts[1]) {
+		return nil, fmt.Errorf("invalid contract address %s", assetParts[1])
+	}
+
+	tokenID, ok := new(big.Int).SetString(parts[2], 10)
+	if !ok || tokenID.Sign() < 0 {
+		return nil, fmt.Errorf("invalid token ID %s", parts[2])
+	}
+
+	return &AvatarNFT{
+		ChainID:  chainID,
+		Contract: common.HexToAddress(assetParts[1]),
+		TokenID:  tokenID,
+	}, nil
+}
+
+// MetadataImage obtains the image from NFT metadata, which is held in the
+// "image" field or, for some contracts, the "image_url" field.
+func MetadataImage(metadata []byte) (string, error) {
+	var fields struct {
+		Image    string `json:"image"`
+		ImageURL string `json:"image_url"`
+	}
+	if err := json.Unmarshal(metadata, &fields); err != nil {
+		return "", errors.New("metadata is not valid JSON")
+	}
+	if fields.Image != "" {
+		return fields.Image, nil
+	}
+	if fields.ImageURL != "" {
+		return fields.ImageURL, nil
+	}
+	return "", errors.New("metadata has no image")
+}
@@ -0,0 +1,118 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAvatarNFT(t *testing.T) {
+	tests := []struct {
+		record string
+		nft    *AvatarNFT
+		err    string
+	}{
+		{ // 0 - valid
+			record: "eip155:1/erc721:0xb7F7F6C52F2e2fdb1963Eab30438024864c313F6/2430",
+			nft: &AvatarNFT{
+				ChainID:  big.NewInt(1),
+				Contract: common.HexToAddress("0xb7F7F6C52F2e2fdb1963Eab30438024864c313F6"),
+				TokenID:  big.NewInt(2430),
+			},
+		},
+		{ // 1 - upper case asset type
+			record: "eip155:5/ERC721:0xb7F7F6C52F2e2fdb1963Eab30438024864c313F6/0",
+			nft: &AvatarNFT{
+				ChainID:  big.NewInt(5),
+				Contract: common.HexToAddress("0xb7F7F6C52F2e2fdb1963Eab30438024864c313F6"),
+				TokenID:  big.NewInt(0),
+			},
+		},
+		{ // 2 - URL
+			record: "https://example.com/avatar.png",
+			err:    "NFT reference must have three parts",
+		},
+		{ // 3 - not eip155
+			record: "cosmos:1/erc721:0xb7F7F6C52F2e2fdb1963Eab30438024864c313F6/2430",
+			err:    "NFT reference must start with eip155",
+		},
+		{ // 4 - bad chain ID
+			record: "eip155:x/erc721:0xb7F7F6C52F2e2fdb1963Eab30438024864c313F6/2430",
+			err:    "invalid chain ID x",
+		},
+		{ // 5 - unsupported asset type
+			record: "eip155:1/erc1155:0xb7F7F6C52F2e2fdb1963Eab30438024864c313F6/2430",
+			err:    "unsupported asset type erc1155",
+		},
+		{ // 6 - bad contract
+			record: "eip155:1/erc721:0xb7F7/2430",
+			err:    "invalid contract address 0xb7F7",
+		},
+		{ // 7 - bad token ID
+			record: "eip155:1/erc721:0xb7F7F6C52F2e2fdb1963Eab30438024864c313F6/-1",
+			err:    "invalid token ID -1",
+		},
+	}
+
+	for i, test := range tests {
+		nft, err := ParseAvatarNFT(test.record)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("incorrect error at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		assert.Equal(t, test.nft, nft, fmt.Sprintf("incorrect NFT at test %d", i))
+	}
+}
+
+func TestMetadataImage(t *testing.T) {
+	tests := []struct {
+		metadata string
+		image    string
+		err      string
+	}{
+		{ // 0 - image
+			metadata: `{"name":"Test","image":"ipfs://QmdTEBPdNxJFFsH1wRE3YeWHREWDiSex8xhgTnqknyxWgu"}`,
+			image:    "ipfs://QmdTEBPdNxJFFsH1wRE3YeWHREWDiSex8xhgTnqknyxWgu",
+		},
+		{ // 1 - image_url
+			metadata: `{"name":"Test","image_url":"https://example.com/image.png"}`,
+			image:    "https://example.com/image.png",
+		},
+		{ // 2 - no image
+			metadata: `{"name":"Test"}`,
+			err:      "metadata has no image",
+		},
+		{ // 3 - not JSON
+			metadata: `<html></html>`,
+			err:      "metadata is not valid JSON",
+		},
+	}
+
+	for i, test := range tests {
+		image, err := MetadataImage([]byte(test.metadata))
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("incorrect error at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		assert.Equal(t, test.image, image, fmt.Sprintf("incorrect image at test %d", i))
+	}
+}
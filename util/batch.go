@@ -0,0 +1,113 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"math/big"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BatchCall sends the supplied requests to the server as a single JSON-RPC
+// batch.  If the server does not accept batch requests then the requests are
+// sent individually instead.  Errors for individual requests are returned in
+// the Error field of each element.
+func BatchCall(ctx context.Context, client *rpc.Client, elems []rpc.BatchElem) error {
+	if len(elems) == 0 {
+		return nil
+	}
+	err := client.BatchCallContext(ctx, elems)
+	if err == nil && !batchUnsupported(elems) {
+		return nil
+	}
+
+	// Batch failed; fall back to sequential calls
+	for i := range elems {
+		elems[i].Error = client.CallContext(ctx, elems[i].Result, elems[i].Method, elems[i].Args...)
+	}
+	return ctx.Err()
+}
+
+// batchUnsupported returns true if the server responded to every element of a
+// batch with an error, which is how some providers reject batch requests.
+func batchUnsupported(elems []rpc.BatchElem) bool {
+	for i := range elems {
+		if elems[i].Error == nil {
+			return false
+		}
+	}
+	return len(elems) > 1
+}
+
+// BalancesAt obtains the balances of multiple addresses at a given block
+// with a single batch request.  A nil block obtains the latest balances.
+func BalancesAt(ctx context.Context, client *rpc.Client, addresses []common.Address, block *big.Int) ([]*big.Int, []error, error) {
+	blockArg := "latest"
+	if block != nil {
+		blockArg = hexutil.EncodeBig(block)
+	}
+	results := make([]hexutil.Big, len(addresses))
+	elems := make([]rpc.BatchElem, len(addresses))
+	for i := range addresses {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getBalance",
+			Args:   []interface{}{addresses[i], blockArg},
+			Result: &results[i],
+		}
+	}
+	if err := BatchCall(ctx, client, elems); err != nil {
+		return nil, nil, err
+	}
+
+	balances := make([]*big.Int, len(addresses))
+	errs := make([]error, len(addresses))
+	for i := range elems {
+		errs[i] = elems[i].Error
+		balances[i] = (*big.Int)(&results[i])
+	}
+	return balances, errs, nil
+}
+
+// CallContracts makes multiple contract calls at a given block with a single
+// batch request.  A nil block makes the calls against the latest block.
+func CallContracts(ctx context.Context, client *rpc.Client, msgs []ethereum.CallMsg, block *big.Int) ([][]byte, []error, error) {
+	blockArg := "latest"
+	if block != nil {
+		blockArg = hexutil.EncodeBig(block)
+	}
+	results := make([]hexutil.Bytes, len(msgs))
+	elems := make([]rpc.BatchElem, len(msgs))
+	for i := range msgs {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_call",
+			Args:   []interface{}{callArg(msgs[i]), blockArg},
+			Result: &results[i],
+		}
+	}
+	if err := BatchCall(ctx, client, elems); err != nil {
+		return nil, nil, err
+	}
+
+	outputs := make([][]byte, len(msgs))
+	errs := make([]error, len(msgs))
+	for i := range elems {
+		errs[i] = elems[i].Error
+		outputs[i] = results[i]
+	}
+	return outputs, errs, nil
+}
@@ -0,0 +1,296 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchTestRequest is a JSON-RPC request received by the test server.
+type batchTestRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params []string        `json:"params"`
+}
+
+// batchTestServer is a JSON-RPC server that returns a balance of the last
+// byte of the address for eth_getBalance, or an error for addresses ending
+// in ff.  Depending on its mode it accepts batches, rejects them outright,
+// or responds to every element of a batch with an error.
+func batchTestServer(mode string, batches *int, calls *int) *httptest.Server {
+	respond := func(req *batchTestRequest) map[string]interface{} {
+		res := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		if strings.HasSuffix(strings.ToLower(req.Params[0]), "ff") {
+			res["error"] = map[string]interface{}{"code": -32000, "message": "balance unavailable"}
+		} else {
+			balance, _ := new(big.Int).SetString(req.Params[0][len(req.Params[0])-2:], 16)
+			res["result"] = fmt.Sprintf("0x%x", balance)
+		}
+		return res
+	}
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.HasPrefix(strings.TrimSpace(string(body)), "[") {
+			*calls++
+			var req batchTestRequest
+			_ = json.Unmarshal(body, &req)
+			_ = json.NewEncoder(w).Encode(respond(&req))
+			return
+		}
+		*batches++
+		var reqs []*batchTestRequest
+		_ = json.Unmarshal(body, &reqs)
+		switch mode {
+		case "rejected":
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"batch requests not supported"}}`)
+		case "errors":
+			res := make([]map[string]interface{}, len(reqs))
+			for i := range reqs {
+				res[i] = map[string]interface{}{"jsonrpc": "2.0", "id": reqs[i].ID, "error": map[string]interface{}{"code": -32600, "message": "batch requests not supported"}}
+			}
+			_ = json.NewEncoder(w).Encode(res)
+		default:
+			res := make([]map[string]interface{}, len(reqs))
+			for i := range reqs {
+				res[i] = respond(reqs[i])
+			}
+			_ = json.NewEncoder(w).Encode(res)
+		}
+	}))
+}
+
+func TestBalancesAt(t *testing.T) {
+	tests := []struct {
+		mode      string
+		addresses []common.Address
+		balances  []*big.Int
+		errs      []string
+		batches   int
+		calls     int
+	}{
+		{ // 0 - no addresses
+			mode:      "batch",
+			addresses: []common.Address{},
+			balances:  []*big.Int{},
+			errs:      []string{},
+		},
+		{ // 1 - batch
+			mode:      "batch",
+			addresses: []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0x02")},
+			balances:  []*big.Int{big.NewInt(1), big.NewInt(2)},
+			errs:      []string{"", ""},
+			batches:   1,
+		},
+		{ // 2 - batch with a failed element
+			mode:      "batch",
+			addresses: []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0xff"), common.HexToAddress("0x03")},
+			balances:  []*big.Int{big.NewInt(1), nil, big.NewInt(3)},
+			errs:      []string{"", "balance unavailable", ""},
+			batches:   1,
+		},
+		{ // 3 - batch rejected
+			mode:      "rejected",
+			addresses: []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0xff"), common.HexToAddress("0x03")},
+			balances:  []*big.Int{big.NewInt(1), nil, big.NewInt(3)},
+			errs:      []string{"", "balance unavailable", ""},
+			batches:   1,
+			calls:     3,
+		},
+		{ // 4 - every element of batch rejected
+			mode:      "errors",
+			addresses: []common.Address{common.HexToAddress("0x01"), common.HexToAddress("0x02")},
+			balances:  []*big.Int{big.NewInt(1), big.NewInt(2)},
+			errs:      []string{"", ""},
+			batches:   1,
+			calls:     2,
+		},
+		{ // 5 - single failed element is not taken as a rejected batch
+			mode:      "errors",
+			addresses: []common.Address{common.HexToAddress("0x01")},
+			balances:  []*big.Int{nil},
+			errs:      []string{"batch requests not supported"},
+			batches:   1,
+		},
+	}
+
+	for i, test := range tests {
+		batches := 0
+		calls := 0
+		server := batchTestServer(test.mode, &batches, &calls)
+		client, err := rpc.DialHTTP(server.URL)
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+
+		balances, errs, err := BalancesAt(context.Background(), client, test.addresses, nil)
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		require.Len(t, balances, len(test.balances), fmt.Sprintf("failed at test %d", i))
+		for j := range test.balances {
+			if test.errs[j] != "" {
+				assert.EqualError(t, errs[j], test.errs[j], fmt.Sprintf("failed at test %d element %d", i, j))
+				continue
+			}
+			assert.Nil(t, errs[j], fmt.Sprintf("failed at test %d element %d", i, j))
+			assert.Equal(t, test.balances[j], balances[j], fmt.Sprintf("failed at test %d element %d", i, j))
+		}
+		assert.Equal(t, test.batches, batches, fmt.Sprintf("incorrect batches at test %d", i))
+		assert.Equal(t, test.calls, calls, fmt.Sprintf("incorrect calls at test %d", i))
+
+		client.Close()
+		server.Close()
+	}
+}
+
+func TestCallContracts(t *testing.T) {
+	tests := []struct {
+		mode    string
+		msgs    []ethereum.CallMsg
+		outputs [][]byte
+		errs    []string
+		batches int
+		calls   int
+	}{
+		{ // 0 - no calls
+			mode:    "batch",
+			msgs:    []ethereum.CallMsg{},
+			outputs: [][]byte{},
+			errs:    []string{},
+		},
+		{ // 1 - batch with a failed element
+			mode:    "batch",
+			msgs:    []ethereum.CallMsg{{Data: []byte{0x01, 0x02}}, {Data: []byte{0xff}}},
+			outputs: [][]byte{{0x01, 0x02}, nil},
+			errs:    []string{"", "execution reverted"},
+			batches: 1,
+		},
+		{ // 2 - batch rejected
+			mode:    "rejected",
+			msgs:    []ethereum.CallMsg{{Data: []byte{0x01}}, {Data: []byte{0x02}}},
+			outputs: [][]byte{{0x01}, {0x02}},
+			errs:    []string{"", ""},
+			batches: 1,
+			calls:   2,
+		},
+	}
+
+	for i, test := range tests {
+		batches := 0
+		calls := 0
+		respond := func(req map[string]interface{}) map[string]interface{} {
+			res := map[string]interface{}{"jsonrpc": "2.0", "id": req["id"]}
+			data := req["params"].([]interface{})[0].(map[string]interface{})["data"].(string)
+			if data == "0xff" {
+				res["error"] = map[string]interface{}{"code": -32000, "message": "execution reverted"}
+			} else {
+				// Echo the call data
+				res["result"] = data
+			}
+			return res
+		}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			w.Header().Set("Content-Type", "application/json")
+			if !strings.HasPrefix(strings.TrimSpace(string(body)), "[") {
+				calls++
+				var req map[string]interface{}
+				_ = json.Unmarshal(body, &req)
+				_ = json.NewEncoder(w).Encode(respond(req))
+				return
+			}
+			batches++
+			if test.mode == "rejected" {
+				fmt.Fprint(w, `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"batch requests not supported"}}`)
+				return
+			}
+			var reqs []map[string]interface{}
+			_ = json.Unmarshal(body, &reqs)
+			res := make([]map[string]interface{}, len(reqs))
+			for j := range reqs {
+				res[j] = respond(reqs[j])
+			}
+			_ = json.NewEncoder(w).Encode(res)
+		}))
+		client, err := rpc.DialHTTP(server.URL)
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+
+		outputs, errs, err := CallContracts(context.Background(), client, test.msgs, nil)
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		require.Len(t, outputs, len(test.outputs), fmt.Sprintf("failed at test %d", i))
+		for j := range test.outputs {
+			if test.errs[j] != "" {
+				assert.EqualError(t, errs[j], test.errs[j], fmt.Sprintf("failed at test %d element %d", i, j))
+				continue
+			}
+			assert.Nil(t, errs[j], fmt.Sprintf("failed at test %d element %d", i, j))
+			assert.Equal(t, test.outputs[j], outputs[j], fmt.Sprintf("failed at test %d element %d", i, j))
+		}
+		assert.Equal(t, test.batches, batches, fmt.Sprintf("incorrect batches at test %d", i))
+		assert.Equal(t, test.calls, calls, fmt.Sprintf("incorrect calls at test %d", i))
+
+		client.Close()
+		server.Close()
+	}
+}
+
+func TestBatchUnsupported(t *testing.T) {
+	failure := errors.New("failed")
+	tests := []struct {
+		errs        []error
+		unsupported bool
+	}{
+		{ // 0 - no elements
+			errs:        []error{},
+			unsupported: false,
+		},
+		{ // 1 - single failure
+			errs:        []error{failure},
+			unsupported: false,
+		},
+		{ // 2 - all succeeded
+			errs:        []error{nil, nil},
+			unsupported: false,
+		},
+		{ // 3 - some failed
+			errs:        []error{failure, nil, failure},
+			unsupported: false,
+		},
+		{ // 4 - all failed
+			errs:        []error{failure, failure},
+			unsupported: true,
+		},
+	}
+
+	for i, test := range tests {
+		elems := make([]rpc.BatchElem, len(test.errs))
+		for j := range test.errs {
+			elems[j].Error = test.errs[j]
+		}
+		assert.Equal(t, test.unsupported, batchUnsupported(elems), fmt.Sprintf("failed at test %d", i))
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var blockNumberRegexp = regexp.MustCompile("^[0-9]+$")
+var blockHashRegexp = regexp.MustCompile("^[0-9a-fA-F]{64}$")
+
+// blockTags are the tags that can be used in place of a block number.
+var blockTags = map[string]bool{
+	"earliest":  true,
+	"latest":    true,
+	"pending":   true,
+	"safe":      true,
+	"finalized": true,
+}
+
+// BlockSummary is a summary of a block as returned by the JSON-RPC API.
+// It contains fields, such as the base fee, that are not available from
+// go-ethereum's block structure, and can represent pending blocks, for
+// which the hash and miner are not known.
+type BlockSummary struct {
+	Number       *hexutil.Big    `json:"number"`
+	Hash         *common.Hash    `json:"hash"`
+	ParentHash   common.Hash     `json:"parentHash"`
+	Timestamp    hexutil.Uint64  `json:"timestamp"`
+	Miner        *common.Address `json:"miner"`
+	GasLimit     hexutil.Uint64  `json:"gasLimit"`
+	GasUsed      hexutil.Uint64  `json:"gasUsed"`
+	BaseFee      *hexutil.Big    `json:"baseFeePerGas"`
+	Transactions []common.Hash   `json:"transactions"`
+	Uncles       []common.Hash   `json:"uncles"`
+}
+
+// BlockSummaryFor obtains the summary of a block given its number, hash
+// or a tag such as "latest" or "pending".
+func BlockSummaryFor(ctx context.Context, client *rpc.Client, id string) (*BlockSummary, error) {
+	method, arg, err := blockQuery(id)
+	if err != nil {
+		return nil, err
+	}
+	var summary *BlockSummary
+	if err := client.CallContext(ctx, &summary, method, arg, false); err != nil {
+		return nil, err
+	}
+	if summary == nil || summary.Number == nil {
+		return nil, errors.New("block not found")
+	}
+	return summary, nil
+}
+
+// blockQuery obtains the JSON-RPC method and argument to obtain a block
+// given its number, hash or tag.  Hashes can be supplied with or without a
+// 0x prefix.
+func blockQuery(id string) (string, interface{}, error) {
+	switch {
+	case blockNumberRegexp.MatchString(id):
+		number, _ := new(big.Int).SetString(id, 10)
+		return "eth_getBlockByNumber", hexutil.EncodeBig(number), nil
+	case blockHashRegexp.MatchString(strings.TrimPrefix(id, "0x")):
+		return "eth_getBlockByHash", common.HexToHash(id), nil
+	case blockTags[id]:
+		return "eth_getBlockByNumber", id, nil
+	default:
+		return "", nil, fmt.Errorf("invalid block %q; must be a number, a hash or one of earliest, latest, pending, safe or finalized", id)
+	}
+}
+
+// blockSinceSample is the number of blocks before the latest block used to
+// estimate the chain's average block time.
+const blockSinceSample = 1000
+
+// BlockSince obtains the number of the first block produced within the
+// given duration of now.  The block is estimated from the chain's average
+// block time and refined from the timestamps of the blocks around the
+// estimate, so is accurate regardless of the chain's block time.
+func BlockSince(ctx context.Context, client *ethclient.Client, latest *types.Header, since time.Duration) (uint64, error) {
+	target := time.Now().Add(-since).Unix()
+	if target < 0 {
+		target = 0
+	}
+	return blockSince(latest.Number.Uint64(), latest.Time, uint64(target), func(number uint64) (uint64, error) {
+		header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return 0, err
+		}
+		return header.Time, nil
+	})
+}
+
+// blockSince searches for the first block with a timestamp at or after the
+// target, given a function that obtains the timestamp of a block.  If the
+// latest block is older than the target then the latest block is returned.
+//
+// The first block examined is blockSinceSample blocks before the latest,
+// which provides the average block time.  Subsequent blocks are estimated by
+// interpolating between the closest blocks either side of the target,
+// falling back to bisection when an estimate does not halve the range, so
+// on chains with a regular block time only a handful of blocks are obtained.
+func blockSince(latestNumber uint64, latestTime uint64, target uint64, blockTime func(uint64) (uint64, error)) (uint64, error) {
+	if latestTime < target {
+		return latestNumber, nil
+	}
+
+	timestamps := map[uint64]uint64{latestNumber: latestTime}
+	timestamp := func(number uint64) (uint64, error) {
+		if t, exists := timestamps[number]; exists {
+			return t, nil
+		}
+		t, err := blockTime(number)
+		if err != nil {
+			return 0, err
+		}
+		timestamps[number] = t
+		return t, nil
+	}
+
+	// The result is in the range low..high; high is at or after the target
+	// and, if lowKnown, the block before low is before the target.
+	low, high := uint64(0), latestNumber
+	highTime := latestTime
+	lowTime := uint64(0)
+	lowKnown := false
+	bisect := false
+	for low < high {
+		var guess uint64
+		switch {
+		case bisect:
+			guess = low + (high-low)/2
+		case lowKnown:
+			// Interpolate between the blocks either side of the target.
+			guess = low - 1 + ceilDiv((target-lowTime)*(high-low+1), highTime-lowTime)
+		case high == latestNumber:
+			// Sample the average block time.
+			guess = 0
+			if latestNumber > blockSinceSample {
+				guess = latestNumber - blockSinceSample
+			}
+		case latestTime > highTime:
+			// Extrapolate back from the average block time since high.
+			blocks := (highTime - target) * (latestNumber - high) / (latestTime - highTime)
+			guess = 0
+			if high > blocks {
+				guess = high - blocks
+			}
+		default:
+			guess = low + (high-low)/2
+		}
+		if guess < low {
+			guess = low
+		}
+		if guess >= high {
+			guess = high - 1
+		}
+
+		t, err := timestamp(guess)
+		if err != nil {
+			return 0, err
+		}
+		size := high - low
+		if t >= target {
+			high, highTime = guess, t
+		} else {
+			low, lowTime, lowKnown = guess+1, t, true
+		}
+		bisect = !bisect && high-low > size/2 && lowKnown
+	}
+	return low, nil
+}
+
+// ceilDiv divides a by b, rounding up.
+func ceilDiv(a uint64, b uint64) uint64 {
+	return (a + b - 1) / b
+}
+
+// BlockRange is an inclusive range of blocks.
+type BlockRange struct {
+	From uint64
+	To   uint64
+}
+
+// BlockRanges splits the blocks from..to inclusive into consecutive ranges
+// of at most size blocks, allowing queries such as those for logs to stay
+// within the limits that nodes place on them.
+func BlockRanges(from uint64, to uint64, size uint64) []BlockRange {
+	ranges := make([]BlockRange, 0)
+	if from > to || size == 0 {
+		return ranges
+	}
+	for start := from; ; start += size {
+		end := start + size - 1
+		if end > to || end < start {
+			end = to
+		}
+		ranges = append(ranges, BlockRange{From: start, To: end})
+		if end == to {
+			break
+		}
+	}
+	return ranges
+}
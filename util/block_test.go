@@ -0,0 +1,255 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockSince(t *testing.T) {
+	// Block times are 12s for the first 1000 blocks and 2s thereafter, with
+	// a gap of a minute after block 1500.
+	timestamps := make([]uint64, 2001)
+	for i := range timestamps {
+		switch {
+		case i == 0:
+			timestamps[i] = 1700000000
+		case i <= 1000:
+			timestamps[i] = timestamps[i-1] + 12
+		case i == 1501:
+			timestamps[i] = timestamps[i-1] + 60
+		default:
+			timestamps[i] = timestamps[i-1] + 2
+		}
+	}
+	blockTime := func(number uint64) (uint64, error) {
+		if number >= uint64(len(timestamps)) {
+			return 0, errors.New("block not found")
+		}
+		return timestamps[number], nil
+	}
+	failing := func(number uint64) (uint64, error) {
+		return 0, errors.New("connection refused")
+	}
+
+	tests := []struct {
+		latest    uint64
+		target    uint64
+		blockTime func(uint64) (uint64, error)
+		res       uint64
+		err       string
+	}{
+		{ // 0 - exact timestamp
+			latest:    2000,
+			target:    timestamps[1200],
+			blockTime: blockTime,
+			res:       1200,
+		},
+		{ // 1 - between blocks
+			latest:    2000,
+			target:    timestamps[500] + 5,
+			blockTime: blockTime,
+			res:       501,
+		},
+		{ // 2 - within a gap
+			latest:    2000,
+			target:    timestamps[1500] + 30,
+			blockTime: blockTime,
+			res:       1501,
+		},
+		{ // 3 - before the start of the chain
+			latest:    2000,
+			target:    timestamps[0] - 3600,
+			blockTime: blockTime,
+			res:       0,
+		},
+		{ // 4 - latest block older than the target
+			latest:    2000,
+			target:    timestamps[2000] + 60,
+			blockTime: blockTime,
+			res:       2000,
+		},
+		{ // 5 - latest block
+			latest:    2000,
+			target:    timestamps[2000],
+			blockTime: blockTime,
+			res:       2000,
+		},
+		{ // 6 - failure to obtain block
+			latest:    2000,
+			target:    timestamps[1000],
+			blockTime: failing,
+			err:       "connection refused",
+		},
+	}
+
+	for i, test := range tests {
+		res, err := blockSince(test.latest, timestamps[test.latest], test.target, test.blockTime)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("failed at test %d", i))
+			continue
+		}
+		assert.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		assert.Equal(t, test.res, res, fmt.Sprintf("failed at test %d", i))
+	}
+}
+
+func TestBlockSinceCalls(t *testing.T) {
+	// 12s block time with the occasional missed slot.
+	latest := uint64(20000000)
+	timestamp := func(number uint64) uint64 {
+		return 1600000000 + number*12 + number/97*12
+	}
+
+	tests := []struct {
+		since    uint64
+		maxCalls int
+	}{
+		{ // 0 - within the sample
+			since:    3600,
+			maxCalls: 4,
+		},
+		{ // 1 - beyond the sample
+			since:    30 * 24 * 3600,
+			maxCalls: 6,
+		},
+		{ // 2 - most of the chain
+			since:    timestamp(latest) - timestamp(10),
+			maxCalls: 6,
+		},
+	}
+
+	for i, test := range tests {
+		calls := 0
+		blockTime := func(number uint64) (uint64, error) {
+			calls++
+			return timestamp(number), nil
+		}
+		target := timestamp(latest) - test.since
+		res, err := blockSince(latest, timestamp(latest), target, blockTime)
+		assert.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		assert.True(t, timestamp(res) >= target && (res == 0 || timestamp(res-1) < target), fmt.Sprintf("failed at test %d", i))
+		assert.True(t, calls <= test.maxCalls, fmt.Sprintf("failed at test %d: %d calls", i, calls))
+	}
+}
+
+func TestBlockRanges(t *testing.T) {
+	tests := []struct {
+		from uint64
+		to   uint64
+		size uint64
+		res  []BlockRange
+	}{
+		{ // 0 - single block
+			from: 10,
+			to:   10,
+			size: 100,
+			res:  []BlockRange{{From: 10, To: 10}},
+		},
+		{ // 1 - exact multiple
+			from: 0,
+			to:   199,
+			size: 100,
+			res:  []BlockRange{{From: 0, To: 99}, {From: 100, To: 199}},
+		},
+		{ // 2 - partial final range
+			from: 5,
+			to:   30,
+			size: 10,
+			res:  []BlockRange{{From: 5, To: 14}, {From: 15, To: 24}, {From: 25, To: 30}},
+		},
+		{ // 3 - range ending at the maximum block
+			from: ^uint64(0) - 2,
+			to:   ^uint64(0),
+			size: 2,
+			res:  []BlockRange{{From: ^uint64(0) - 2, To: ^uint64(0) - 1}, {From: ^uint64(0), To: ^uint64(0)}},
+		},
+		{ // 4 - from after to
+			from: 20,
+			to:   10,
+			size: 10,
+			res:  []BlockRange{},
+		},
+		{ // 5 - zero size
+			from: 10,
+			to:   20,
+			size: 0,
+			res:  []BlockRange{},
+		},
+	}
+
+	for i, test := range tests {
+		res := BlockRanges(test.from, test.to, test.size)
+		assert.Equal(t, test.res, res, fmt.Sprintf("failed at test %d", i))
+	}
+}
+
+func TestBlockQuery(t *testing.T) {
+	hash := common.HexToHash("0xabababababababababababababababababababababababababababababababab")
+	tests := []struct {
+		id     string
+		method string
+		arg    interface{}
+		err    string
+	}{
+		{ // 0 - number
+			id:     "16",
+			method: "eth_getBlockByNumber",
+			arg:    "0x10",
+		},
+		{ // 1 - hash
+			id:     "0xabababababababababababababababababababababababababababababababab",
+			method: "eth_getBlockByHash",
+			arg:    hash,
+		},
+		{ // 2 - hash without prefix
+			id:     "abababababababababababababababababababababababababababababababab",
+			method: "eth_getBlockByHash",
+			arg:    hash,
+		},
+		{ // 3 - tag
+			id:     "finalized",
+			method: "eth_getBlockByNumber",
+			arg:    "finalized",
+		},
+		{ // 4 - unknown tag
+			id:  "final",
+			err: `invalid block "final"; must be a number, a hash or one of earliest, latest, pending, safe or finalized`,
+		},
+		{ // 5 - short hash
+			id:  "0xabab",
+			err: `invalid block "0xabab"; must be a number, a hash or one of earliest, latest, pending, safe or finalized`,
+		},
+		{ // 6 - invalid hash
+			id:  "0xzbababababababababababababababababababababababababababababababab",
+			err: `invalid block "0xzbababababababababababababababababababababababababababababababab"; must be a number, a hash or one of earliest, latest, pending, safe or finalized`,
+		},
+	}
+
+	for i, test := range tests {
+		method, arg, err := blockQuery(test.id)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("failed at test %d", i))
+		} else {
+			assert.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+			assert.Equal(t, test.method, method, fmt.Sprintf("failed at test %d", i))
+			assert.Equal(t, test.arg, arg, fmt.Sprintf("failed at test %d", i))
+		}
+	}
+}
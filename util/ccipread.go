@@ -0,0 +1,293 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+// maxOffchainLookups is the maximum number of offchain lookups that will be
+// followed when resolving a single name.
+const maxOffchainLookups = 4
+
+var (
+	// offchainLookupSelector is the selector for the EIP-3668 OffchainLookup error.
+	offchainLookupSelector = []byte{0x55, 0x6f, 0x18, 0x30}
+	// resolveSelector is the selector for the ENSIP-10 resolve(bytes,bytes) function.
+	resolveSelector = []byte{0x90, 0x61, 0xb9, 0x23}
+	// addrSelector is the selector for the addr(bytes32) function.
+	addrSelector = []byte{0x3b, 0x3b, 0x57, 0xde}
+	// supportsInterfaceSelector is the selector for the supportsInterface(bytes4) function.
+	supportsInterfaceSelector = []byte{0x01, 0xff, 0xc9, 0xa7}
+)
+
+// ResolveAddress resolves a name or address string to an address.  It
+// behaves as ens.Resolve(), but if that fails to resolve a name it will
+// additionally attempt ENSIP-10 wildcard resolution, following any EIP-3668
+// (CCIP-Read) offchain lookups requested by the resolver.  It also honours
+// any override of the ENS registry address.  Hex addresses must have a valid
+// checksum unless "nochecksum" is set.
+func ResolveAddress(backend bind.ContractBackend, input string) (common.Address, error) {
+	if IsHexAddressString(input) {
+		return HexAddress(input)
+	}
+	if viper.GetString("ensregistry") != "" && strings.Contains(input, ".") {
+		// The ens library always uses the well-known registry, so resolve
+		// directly
+		return resolveWildcard(backend, input)
+	}
+	address, err := ens.Resolve(backend, input)
+	if err == nil || !strings.Contains(input, ".") {
+		return address, err
+	}
+
+	offchainAddress, offchainErr := resolveWildcard(backend, input)
+	if offchainErr != nil {
+		// Return the original error as it is more likely to be relevant
+		return address, err
+	}
+	return offchainAddress, nil
+}
+
+// resolveWildcard resolves a name using the ENSIP-10 resolver for the name
+// or its closest parent.
+func resolveWildcard(backend bind.ContractBackend, name string) (common.Address, error) {
+	name, err := ens.NormaliseDomain(name)
+	if err != nil {
+		return ens.UnknownAddress, err
+	}
+	node, err := ens.NameHash(name)
+	if err != nil {
+		return ens.UnknownAddress, err
+	}
+
+	registry, err := ENSRegistry(backend)
+	if err != nil {
+		return ens.UnknownAddress, err
+	}
+	var resolverAddress common.Address
+	for parent := name; parent != ""; parent = ens.Domain(parent) {
+		resolverAddress, err = registry.ResolverAddress(parent)
+		if err != nil {
+			return ens.UnknownAddress, err
+		}
+		if resolverAddress != ens.UnknownAddress {
+			break
+		}
+	}
+	if resolverAddress == ens.UnknownAddress {
+		return ens.UnknownAddress, errors.New("no resolver")
+	}
+
+	addrData := append(append([]byte{}, addrSelector...), node[:]...)
+	if !supportsWildcard(backend, resolverAddress) {
+		// Call the resolver directly
+		res, err := callWithOffchainLookup(backend, resolverAddress, addrData)
+		if err != nil {
+			return ens.UnknownAddress, err
+		}
+		return addressFromResult(res)
+	}
+
+	args, err := abiArguments("bytes", "bytes")
+	if err != nil {
+		return ens.UnknownAddress, err
+	}
+	packed, err := args.Pack(ens.DNSWireFormat(name), addrData)
+	if err != nil {
+		return ens.UnknownAddress, err
+	}
+	res, err := callWithOffchainLookup(backend, resolverAddress, append(append([]byte{}, resolveSelector...), packed...))
+	if err != nil {
+		return ens.UnknownAddress, err
+	}
+	// Result is an ABI-encoded bytes containing the result of addr()
+	outputArgs, err := abiArguments("bytes")
+	if err != nil {
+		return ens.UnknownAddress, err
+	}
+	values, err := outputArgs.UnpackValues(res)
+	if err != nil {
+		return ens.UnknownAddress, err
+	}
+	return addressFromResult(values[0].([]byte))
+}
+
+// supportsWildcard returns true if the resolver supports ENSIP-10.
+func supportsWildcard(backend bind.ContractBackend, resolverAddress common.Address) bool {
+	data := make([]byte, 36)
+	copy(data, supportsInterfaceSelector)
+	copy(data[4:], resolveSelector)
+	ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("timeout"))
+	defer cancel()
+	res, err := backend.CallContract(ctx, ethereum.CallMsg{To: &resolverAddress, Data: data}, nil)
+	if err != nil || len(res) < 32 {
+		return false
+	}
+	return res[31] == 1
+}
+
+// callWithOffchainLookup calls a contract, following any offchain lookups
+// that the contract requests.
+func callWithOffchainLookup(backend bind.ContractBackend, to common.Address, data []byte) ([]byte, error) {
+	for i := 0; i < maxOffchainLookups; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("timeout"))
+		res, err := backend.CallContract(ctx, ethereum.CallMsg{To: &to, Data: data}, nil)
+		cancel()
+		if err == nil {
+			return res, nil
+		}
+		revertData := revertDataFromError(err)
+		if !bytes.HasPrefix(revertData, offchainLookupSelector) {
+			return nil, err
+		}
+
+		lookupArgs, err := abiArguments("address", "string[]", "bytes", "bytes4", "bytes")
+		if err != nil {
+			return nil, err
+		}
+		values, err := lookupArgs.UnpackValues(revertData[4:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid offchain lookup: %v", err)
+		}
+		sender := values[0].(common.Address)
+		urls := values[1].([]string)
+		callData := values[2].([]byte)
+		callbackFunction := values[3].([4]byte)
+		extraData := values[4].([]byte)
+		if sender != to {
+			return nil, errors.New("offchain lookup sender does not match contract")
+		}
+
+		response, err := offchainLookup(sender, urls, callData)
+		if err != nil {
+			return nil, err
+		}
+
+		callbackArgs, err := abiArguments("bytes", "bytes")
+		if err != nil {
+			return nil, err
+		}
+		packed, err := callbackArgs.Pack(response, extraData)
+		if err != nil {
+			return nil, err
+		}
+		data = append(callbackFunction[:], packed...)
+	}
+	return nil, errors.New("too many offchain lookups")
+}
+
+// offchainLookup fetches data from the first of the supplied gateways to respond.
+func offchainLookup(sender common.Address, urls []string, callData []byte) ([]byte, error) {
+	senderStr := strings.ToLower(sender.Hex())
+	dataStr := fmt.Sprintf("0x%x", callData)
+	httpClient := &http.Client{Timeout: viper.GetDuration("timeout")}
+
+	var lastErr error = errors.New("no gateway URLs supplied")
+	for _, url := range urls {
+		var resp *http.Response
+		var err error
+		url = strings.Replace(url, "{sender}", senderStr, -1)
+		if strings.Contains(url, "{data}") {
+			resp, err = httpClient.Get(strings.Replace(url, "{data}", dataStr, -1))
+		} else {
+			body, _ := json.Marshal(map[string]string{"data": dataStr, "sender": senderStr})
+			resp, err = httpClient.Post(url, "application/json", bytes.NewReader(body))
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			lastErr = fmt.Errorf("gateway returned status %d", resp.StatusCode)
+			if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+				// Client errors are not retried
+				return nil, lastErr
+			}
+			continue
+		}
+		var result struct {
+			Data string `json:"data"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			lastErr = fmt.Errorf("invalid gateway response: %v", err)
+			continue
+		}
+		return hex.DecodeString(strings.TrimPrefix(result.Data, "0x"))
+	}
+	return nil, lastErr
+}
+
+// revertDataFromError obtains the revert data from an error returned by a call, if present.
+func revertDataFromError(err error) []byte {
+	dataErr, isDataErr := err.(interface{ ErrorData() interface{} })
+	if !isDataErr {
+		return nil
+	}
+	dataStr, isString := dataErr.ErrorData().(string)
+	if !isString {
+		return nil
+	}
+	data, err := hex.DecodeString(strings.TrimPrefix(dataStr, "0x"))
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// addressFromResult obtains an address from the ABI-encoded result of addr().
+func addressFromResult(res []byte) (common.Address, error) {
+	if len(res) < 32 {
+		return ens.UnknownAddress, errors.New("invalid address result")
+	}
+	address := common.BytesToAddress(res[12:32])
+	if address == ens.UnknownAddress {
+		return ens.UnknownAddress, errors.New("no address")
+	}
+	return address, nil
+}
+
+// abiArguments creates a set of unnamed ABI arguments of the given types.
+func abiArguments(types ...string) (abi.Arguments, error) {
+	args := make(abi.Arguments, len(types))
+	for i := range types {
+		t, err := abi.NewType(types[i], "", nil)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = abi.Argument{Type: t}
+	}
+	return args, nil
+}
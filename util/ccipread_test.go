@@ -0,0 +1,225 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ccipTestCallback is the selector of the callback function used in tests.
+var ccipTestCallback = [4]byte{0x11, 0x22, 0x33, 0x44}
+
+// ccipTestBackend is a backend that requests an offchain lookup for any call
+// other than to the callback function, and returns the response passed to
+// the callback.
+type ccipTestBackend struct {
+	bind.ContractBackend
+	sender  common.Address
+	urls    []string
+	lookup  []byte
+	callErr error
+}
+
+func (b *ccipTestBackend) CallContract(ctx context.Context, msg ethereum.CallMsg, block *big.Int) ([]byte, error) {
+	if b.callErr != nil {
+		return nil, b.callErr
+	}
+	if bytes.HasPrefix(msg.Data, ccipTestCallback[:]) {
+		args, err := abiArguments("bytes", "bytes")
+		if err != nil {
+			return nil, err
+		}
+		values, err := args.UnpackValues(msg.Data[4:])
+		if err != nil {
+			return nil, err
+		}
+		return values[0].([]byte), nil
+	}
+	if b.lookup != nil {
+		return nil, &revertError{data: fmt.Sprintf("0x%x", b.lookup)}
+	}
+	args, err := abiArguments("address", "string[]", "bytes", "bytes4", "bytes")
+	if err != nil {
+		return nil, err
+	}
+	packed, err := args.Pack(b.sender, b.urls, []byte{0xaa, 0xbb}, ccipTestCallback, []byte{})
+	if err != nil {
+		return nil, err
+	}
+	return nil, &revertError{data: fmt.Sprintf("0x%x", append(append([]byte{}, offchainLookupSelector...), packed...))}
+}
+
+func TestRevertDataFromError(t *testing.T) {
+	tests := []struct {
+		err  error
+		data []byte
+	}{
+		{ // 0 - no data
+			err: errors.New("execution reverted"),
+		},
+		{ // 1 - data
+			err:  &revertError{data: "0x556f1830"},
+			data: []byte{0x55, 0x6f, 0x18, 0x30},
+		},
+		{ // 2 - data without prefix
+			err:  &revertError{data: "556f1830"},
+			data: []byte{0x55, 0x6f, 0x18, 0x30},
+		},
+		{ // 3 - data not hex
+			err: &revertError{data: "0xzz"},
+		},
+		{ // 4 - data not a string
+			err: &revertError{data: 5},
+		},
+	}
+
+	for i, test := range tests {
+		assert.Equal(t, test.data, revertDataFromError(test.err), fmt.Sprintf("failed at test %d", i))
+	}
+}
+
+func TestABIArguments(t *testing.T) {
+	tests := []struct {
+		types []string
+		sig   []string
+		err   string
+	}{
+		{ // 0 - none
+			types: []string{},
+			sig:   []string{},
+		},
+		{ // 1 - offchain lookup
+			types: []string{"address", "string[]", "bytes", "bytes4", "bytes"},
+			sig:   []string{"address", "string[]", "bytes", "bytes4", "bytes"},
+		},
+		{ // 2 - invalid type
+			types: []string{"bytes", "notatype"},
+			err:   "unsupported arg type: notatype",
+		},
+	}
+
+	for i, test := range tests {
+		args, err := abiArguments(test.types...)
+		if test.err != "" {
+			require.NotNil(t, err, fmt.Sprintf("failed at test %d", i))
+			assert.Contains(t, err.Error(), test.err, fmt.Sprintf("failed at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		sig := make([]string, len(args))
+		for j := range args {
+			sig[j] = args[j].Type.String()
+		}
+		assert.Equal(t, test.sig, sig, fmt.Sprintf("failed at test %d", i))
+	}
+}
+
+func TestCallWithOffchainLookup(t *testing.T) {
+	contract := common.HexToAddress("0x5FfC014343cd971B7eb70732021E26C35B744cc4")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/missing/"):
+			w.WriteHeader(http.StatusNotFound)
+		case r.URL.Path == fmt.Sprintf("/gateway/%s/0xaabb.json", strings.ToLower(contract.Hex())):
+			fmt.Fprint(w, `{"data":"0x1234"}`)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		backend *ccipTestBackend
+		res     []byte
+		err     string
+	}{
+		{ // 0 - lookup
+			backend: &ccipTestBackend{
+				sender: contract,
+				urls:   []string{fmt.Sprintf("%s/gateway/{sender}/{data}.json", server.URL)},
+			},
+			res: []byte{0x12, 0x34},
+		},
+		{ // 1 - lookup after failed gateway
+			backend: &ccipTestBackend{
+				sender: contract,
+				urls:   []string{fmt.Sprintf("%s/broken/{sender}/{data}.json", server.URL), fmt.Sprintf("%s/gateway/{sender}/{data}.json", server.URL)},
+			},
+			res: []byte{0x12, 0x34},
+		},
+		{ // 2 - gateway client error
+			backend: &ccipTestBackend{
+				sender: contract,
+				urls:   []string{fmt.Sprintf("%s/missing/{sender}/{data}.json", server.URL), fmt.Sprintf("%s/gateway/{sender}/{data}.json", server.URL)},
+			},
+			err: "gateway returned status 404",
+		},
+		{ // 3 - no gateways
+			backend: &ccipTestBackend{
+				sender: contract,
+				urls:   []string{},
+			},
+			err: "no gateway URLs supplied",
+		},
+		{ // 4 - sender mismatch
+			backend: &ccipTestBackend{
+				sender: common.HexToAddress("0x52f1A3027d3aA514F17E454C93ae1F79b3B12d5d"),
+				urls:   []string{fmt.Sprintf("%s/gateway/{sender}/{data}.json", server.URL)},
+			},
+			err: "offchain lookup sender does not match contract",
+		},
+		{ // 5 - invalid lookup
+			backend: &ccipTestBackend{
+				lookup: append(append([]byte{}, offchainLookupSelector...), 0x01, 0x02),
+			},
+			err: "invalid offchain lookup: abi: cannot marshal in to go type: length insufficient 2 require 32",
+		},
+		{ // 6 - other revert
+			backend: &ccipTestBackend{
+				lookup: []byte{0x82, 0xb4, 0x29, 0x00},
+			},
+			err: "execution reverted",
+		},
+		{ // 7 - call failure
+			backend: &ccipTestBackend{
+				callErr: errors.New("connection refused"),
+			},
+			err: "connection refused",
+		},
+	}
+
+	for i, test := range tests {
+		res, err := callWithOffchainLookup(test.backend, contract, []byte{0x3b, 0x3b, 0x57, 0xde})
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("failed at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		assert.Equal(t, test.res, res, fmt.Sprintf("failed at test %d", i))
+	}
+}
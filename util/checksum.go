@@ -0,0 +1,57 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+)
+
+// IsHexAddressString returns true if the input is a 0x-prefixed hex address.
+func IsHexAddressString(input string) bool {
+	return (strings.HasPrefix(input, "0x") || strings.HasPrefix(input, "0X")) && common.IsHexAddress(input)
+}
+
+// ChecksummedAddress parses a 0x-prefixed hex address, validating its EIP-55
+// checksum.  Addresses that are entirely lower- or upper-case carry no
+// checksum and are accepted as-is.
+func ChecksummedAddress(input string) (common.Address, error) {
+	if !IsHexAddressString(input) {
+		return common.Address{}, fmt.Errorf("%s is not a hex address", input)
+	}
+	address := common.HexToAddress(input)
+	hexPart := input[2:]
+	if hexPart == strings.ToLower(hexPart) || hexPart == strings.ToUpper(hexPart) {
+		return address, nil
+	}
+	if hexPart != address.Hex()[2:] {
+		return common.Address{}, fmt.Errorf("address %s has an invalid checksum; did you mean %s?", input, address.Hex())
+	}
+	return address, nil
+}
+
+// HexAddress parses a 0x-prefixed hex address.  Its checksum is validated
+// unless "nochecksum" is set.
+func HexAddress(input string) (common.Address, error) {
+	if viper.GetBool("nochecksum") {
+		if !IsHexAddressString(input) {
+			return common.Address{}, fmt.Errorf("%s is not a hex address", input)
+		}
+		return common.HexToAddress(input), nil
+	}
+	return ChecksummedAddress(input)
+}
@@ -0,0 +1,70 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecksummedAddress(t *testing.T) {
+	tests := []struct {
+		input  string
+		output common.Address
+		err    string
+	}{
+		{ // 0 - correct checksum
+			input:  "0x5FfC014343cd971B7eb70732021E26C35B744cc4",
+			output: common.HexToAddress("0x5FfC014343cd971B7eb70732021E26C35B744cc4"),
+		},
+		{ // 1 - lower case
+			input:  "0x5ffc014343cd971b7eb70732021e26c35b744cc4",
+			output: common.HexToAddress("0x5FfC014343cd971B7eb70732021E26C35B744cc4"),
+		},
+		{ // 2 - upper case
+			input:  "0x5FFC014343CD971B7EB70732021E26C35B744CC4",
+			output: common.HexToAddress("0x5FfC014343cd971B7eb70732021E26C35B744cc4"),
+		},
+		{ // 3 - incorrect checksum
+			input: "0x5FfC014343cd971B7eb70732021E26C35B744cC4",
+			err:   "address 0x5FfC014343cd971B7eb70732021E26C35B744cC4 has an invalid checksum; did you mean 0x5FfC014343cd971B7eb70732021E26C35B744cc4?",
+		},
+		{ // 4 - no prefix
+			input: "5FfC014343cd971B7eb70732021E26C35B744cc4",
+			err:   "5FfC014343cd971B7eb70732021E26C35B744cc4 is not a hex address",
+		},
+		{ // 5 - too short
+			input: "0x5FfC014343cd971B7eb70732021E26C35B744c",
+			err:   "0x5FfC014343cd971B7eb70732021E26C35B744c is not a hex address",
+		},
+		{ // 6 - name
+			input: "enstest.eth",
+			err:   "enstest.eth is not a hex address",
+		},
+	}
+
+	for i, tt := range tests {
+		address, err := ChecksummedAddress(tt.input)
+		if tt.err != "" {
+			assert.EqualError(t, err, tt.err, fmt.Sprintf("failed at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		assert.Equal(t, tt.output, address, fmt.Sprintf("failed at test %d", i))
+	}
+}
@@ -1,4 +1,6 @@
 package contracts
 
 //go:generate abigen -abi ERC20.abi -out erc20.go -pkg contracts -type ERC20
+//go:generate abigen -abi ERC721.abi -out erc721.go -pkg contracts -type ERC721
+//go:generate abigen -abi ERC1155.abi -out erc1155.go -pkg contracts -type ERC1155
 //go:generate abigen -abi eth2deposit.abi -out eth2deposit.go -pkg contracts -type Eth2Deposit
@@ -0,0 +1,58 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Create2Address calculates the address of a contract created by the
+// CREATE2 opcode as per EIP-1014, being the last 20 bytes of
+// keccak256(0xff ++ deployer ++ salt ++ keccak256(init code)).
+func Create2Address(deployer common.Address, salt [32]byte, initCodeHash common.Hash) common.Address {
+	return crypto.CreateAddress2(deployer, salt, initCodeHash.Bytes())
+}
+
+// ParseSalt parses a CREATE2 salt.  The salt can be a hex string of up to 32
+// bytes or a decimal number, and in either case is treated as a uint256 so
+// shorter values are left-padded with zeros.
+func ParseSalt(input string) ([32]byte, error) {
+	var salt [32]byte
+	if strings.HasPrefix(input, "0x") {
+		data, err := hex.DecodeString(strings.TrimPrefix(input, "0x"))
+		if err != nil {
+			return salt, fmt.Errorf("invalid salt %s", input)
+		}
+		if len(data) > 32 {
+			return salt, fmt.Errorf("salt %s too long (maximum 32 bytes, got %d)", input, len(data))
+		}
+		copy(salt[32-len(data):], data)
+		return salt, nil
+	}
+
+	val, ok := new(big.Int).SetString(input, 10)
+	if !ok || val.Sign() < 0 {
+		return salt, fmt.Errorf("invalid salt %s", input)
+	}
+	if val.BitLen() > 256 {
+		return salt, fmt.Errorf("salt %s too large", input)
+	}
+	return common.BigToHash(val), nil
+}
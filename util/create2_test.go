@@ -0,0 +1,136 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreate2Address(t *testing.T) {
+	// Examples from EIP-1014
+	tests := []struct {
+		deployer string
+		salt     string
+		initCode string
+		address  string
+	}{
+		{ // 0
+			deployer: "0x0000000000000000000000000000000000000000",
+			salt:     "0x0000000000000000000000000000000000000000000000000000000000000000",
+			initCode: "0x00",
+			address:  "0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38",
+		},
+		{ // 1
+			deployer: "0xdeadbeef00000000000000000000000000000000",
+			salt:     "0x0000000000000000000000000000000000000000000000000000000000000000",
+			initCode: "0x00",
+			address:  "0xB928f69Bb1D91Cd65274e3c79d8986362984fDA3",
+		},
+		{ // 2
+			deployer: "0xdeadbeef00000000000000000000000000000000",
+			salt:     "0x000000000000000000000000feed000000000000000000000000000000000000",
+			initCode: "0x00",
+			address:  "0xD04116cDd17beBE565EB2422F2497E06cC1C9833",
+		},
+		{ // 3
+			deployer: "0x0000000000000000000000000000000000000000",
+			salt:     "0x0000000000000000000000000000000000000000000000000000000000000000",
+			initCode: "0xdeadbeef",
+			address:  "0x70f2b2914A2a4b783FaEFb75f459A580616Fcb5e",
+		},
+		{ // 4
+			deployer: "0x00000000000000000000000000000000deadbeef",
+			salt:     "0x00000000000000000000000000000000000000000000000000000000cafebabe",
+			initCode: "0xdeadbeef",
+			address:  "0x60f3f640a8508fC6a86d45DF051962668E1e8AC7",
+		},
+		{ // 5
+			deployer: "0x00000000000000000000000000000000deadbeef",
+			salt:     "0x00000000000000000000000000000000000000000000000000000000cafebabe",
+			initCode: "0xdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef",
+			address:  "0x1d8bfDC5D46DC4f61D6b6115972536eBE6A8854C",
+		},
+		{ // 6
+			deployer: "0x0000000000000000000000000000000000000000",
+			salt:     "0x0000000000000000000000000000000000000000000000000000000000000000",
+			initCode: "0x",
+			address:  "0xE33C0C7F7df4809055C3ebA6c09CFe4BaF1BD9e0",
+		},
+	}
+
+	for i, test := range tests {
+		salt, err := ParseSalt(test.salt)
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		initCodeHash := crypto.Keccak256Hash(MustDecodeHexString(test.initCode))
+		address := Create2Address(common.HexToAddress(test.deployer), salt, initCodeHash)
+		assert.Equal(t, test.address, address.Hex(), fmt.Sprintf("incorrect address at test %d", i))
+	}
+}
+
+func TestParseSalt(t *testing.T) {
+	tests := []struct {
+		input string
+		salt  string
+		err   string
+	}{
+		{ // 0 - full hex
+			input: "0x000000000000000000000000feed000000000000000000000000000000000000",
+			salt:  "0x000000000000000000000000feed000000000000000000000000000000000000",
+		},
+		{ // 1 - short hex
+			input: "0xcafebabe",
+			salt:  "0x00000000000000000000000000000000000000000000000000000000cafebabe",
+		},
+		{ // 2 - decimal
+			input: "256",
+			salt:  "0x0000000000000000000000000000000000000000000000000000000000000100",
+		},
+		{ // 3 - zero
+			input: "0",
+			salt:  "0x0000000000000000000000000000000000000000000000000000000000000000",
+		},
+		{ // 4 - invalid hex
+			input: "0xcafebabg",
+			err:   "invalid salt 0xcafebabg",
+		},
+		{ // 5 - hex too long
+			input: "0x00000000000000000000000000000000000000000000000000000000000000cafe",
+			err:   "salt 0x00000000000000000000000000000000000000000000000000000000000000cafe too long (maximum 32 bytes, got 33)",
+		},
+		{ // 6 - negative
+			input: "-1",
+			err:   "invalid salt -1",
+		},
+		{ // 7 - decimal too large
+			input: "115792089237316195423570985008687907853269984665640564039457584007913129639936",
+			err:   "salt 115792089237316195423570985008687907853269984665640564039457584007913129639936 too large",
+		},
+	}
+
+	for i, test := range tests {
+		salt, err := ParseSalt(test.input)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("incorrect error at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		assert.Equal(t, test.salt, common.Hash(salt).Hex(), fmt.Sprintf("incorrect salt at test %d", i))
+	}
+}
@@ -1,11 +1,18 @@
 package util
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
 	"time"
 
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/miekg/dns"
+	"github.com/wealdtech/go-ens/v3/contracts/dnsresolver"
 	"golang.org/x/crypto/sha3"
 )
 
@@ -26,6 +33,39 @@ func DNSWireFormatDomainHash(domain string) (hash [32]byte) {
 	return
 }
 
+// DNSRecords obtains the records of multiple resource types for a name from
+// a DNS resolver with a single batch request.  Errors for individual resource
+// types are returned alongside the records.
+func DNSRecords(ctx context.Context, client *rpc.Client, resolver common.Address, domainHash [32]byte, nameHash [32]byte, resources []uint16) ([][]byte, []error, error) {
+	resolverABI, err := abi.JSON(strings.NewReader(dnsresolver.ContractABI))
+	if err != nil {
+		return nil, nil, err
+	}
+	msgs := make([]ethereum.CallMsg, len(resources))
+	for i := range resources {
+		data, err := resolverABI.Pack("dnsRecord", domainHash, nameHash, resources[i])
+		if err != nil {
+			return nil, nil, err
+		}
+		msgs[i] = ethereum.CallMsg{To: &resolver, Data: data}
+	}
+	outputs, errs, err := CallContracts(ctx, client, msgs, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	records := make([][]byte, len(resources))
+	for i := range outputs {
+		if errs[i] != nil {
+			continue
+		}
+		if err := resolverABI.Unpack(&records[i], "dnsRecord", outputs[i]); err != nil {
+			errs[i] = err
+		}
+	}
+	return records, errs, nil
+}
+
 // DNSWireFormat turns a domain name in to wire format
 func DNSWireFormat(domain string) []byte {
 	// Remove leading and trailing dots
@@ -84,3 +124,60 @@ func IncrementSerial(serial uint32) uint32 {
 	// confusing the nameservers so just increment it.
 	return serial + 1
 }
+
+// DNSUnpackRRs unpacks wire-format resource records, as stored by a DNS
+// resolver where multiple records of the same type are concatenated.
+func DNSUnpackRRs(data []byte) ([]dns.RR, error) {
+	rrs := make([]dns.RR, 0)
+	offset := 0
+	for offset < len(data) {
+		var rr dns.RR
+		var err error
+		rr, offset, err = dns.UnpackRR(data, offset)
+		if err != nil {
+			return rrs, err
+		}
+		rrs = append(rrs, rr)
+	}
+	return rrs, nil
+}
+
+// DNSSECDetails describes the fields of DNSSEC records such as RRSIG that are
+// otherwise hard to pick out of their presentation format.  It returns an
+// empty string for other records.
+func DNSSECDetails(rr dns.RR) string {
+	switch r := rr.(type) {
+	case *dns.RRSIG:
+		return fmt.Sprintf("covers %s, algorithm %s, key tag %d, signer %s, valid from %s to %s",
+			dns.TypeToString[r.TypeCovered],
+			dnsAlgorithm(r.Algorithm),
+			r.KeyTag,
+			r.SignerName,
+			time.Unix(int64(r.Inception), 0).UTC().Format(time.RFC3339),
+			time.Unix(int64(r.Expiration), 0).UTC().Format(time.RFC3339))
+	case *dns.NSEC:
+		types := make([]string, len(r.TypeBitMap))
+		for i := range r.TypeBitMap {
+			types[i] = dns.TypeToString[r.TypeBitMap[i]]
+		}
+		return fmt.Sprintf("next name %s, types %s", r.NextDomain, strings.Join(types, " "))
+	case *dns.DNSKEY:
+		role := "zone signing key"
+		if r.Flags&dns.SEP != 0 {
+			role = "key signing key"
+		}
+		return fmt.Sprintf("%s, algorithm %s, key tag %d", role, dnsAlgorithm(r.Algorithm), r.KeyTag())
+	case *dns.DS:
+		return fmt.Sprintf("key tag %d, algorithm %s, digest type %d", r.KeyTag, dnsAlgorithm(r.Algorithm), r.DigestType)
+	default:
+		return ""
+	}
+}
+
+// dnsAlgorithm returns the name and number of a DNSSEC algorithm.
+func dnsAlgorithm(algorithm uint8) string {
+	if name, exists := dns.AlgorithmToString[algorithm]; exists {
+		return fmt.Sprintf("%s (%d)", name, algorithm)
+	}
+	return fmt.Sprintf("%d", algorithm)
+}
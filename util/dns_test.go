@@ -14,10 +14,13 @@ package util
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/miekg/dns"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestWireFormat(t *testing.T) {
@@ -59,3 +62,78 @@ func TestIncrementSerial(t *testing.T) {
 		assert.Equal(t, tt.output, output, fmt.Sprintf("failed at test %d", i))
 	}
 }
+
+// signedZone is a zone containing DNSSEC records.
+const signedZone = `$ORIGIN wealdtech.eth.
+@ 3600 IN A 192.0.2.1
+@ 3600 IN RRSIG A 13 2 3600 20300101000000 20200101000000 12345 wealdtech.eth. AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8gISIjJCUmJygpKissLS4vMDEyMzQ1Njc4OTo7PD0+Pw==
+@ 3600 IN NSEC www.wealdtech.eth. A RRSIG NSEC DNSKEY
+@ 3600 IN RRSIG NSEC 13 2 3600 20300101000000 20200101000000 12345 wealdtech.eth. QEFCQ0RFRkdISUpLTE1OT1BRUlNUVVZXWFlaW1xdXl9gYWJjZGVmZ2hpamtsbW5vcHFyc3R1dnd4eXp7fH1+fw==
+@ 3600 IN DNSKEY 257 3 13 AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8gISIjJCUmJygpKissLS4vMDEyMzQ1Njc4OTo7PD0+Pw==
+@ 3600 IN DS 12345 13 2 0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20
+`
+
+func TestDNSSECRecords(t *testing.T) {
+	// Pack the records as they are stored by the resolver, with records of
+	// the same type concatenated.
+	stored := make(map[uint16][]byte)
+	zp := dns.NewZoneParser(strings.NewReader(signedZone), "wealdtech.eth.", "")
+	for rr, ok := zp.Next(); ok; rr, ok = zp.Next() {
+		data := make([]byte, 1024)
+		offset, err := dns.PackRR(rr, data, 0, nil, false)
+		require.Nil(t, err)
+		stored[rr.Header().Rrtype] = append(stored[rr.Header().Rrtype], data[:offset]...)
+	}
+	require.Nil(t, zp.Err())
+
+	tests := []struct {
+		rrType  uint16
+		details []string
+	}{
+		{ // 0 - not DNSSEC
+			rrType:  dns.TypeA,
+			details: []string{""},
+		},
+		{ // 1 - multiple signatures
+			rrType: dns.TypeRRSIG,
+			details: []string{
+				"covers A, algorithm ECDSAP256SHA256 (13), key tag 12345, signer wealdtech.eth., valid from 2020-01-01T00:00:00Z to 2030-01-01T00:00:00Z",
+				"covers NSEC, algorithm ECDSAP256SHA256 (13), key tag 12345, signer wealdtech.eth., valid from 2020-01-01T00:00:00Z to 2030-01-01T00:00:00Z",
+			},
+		},
+		{ // 2 - NSEC
+			rrType:  dns.TypeNSEC,
+			details: []string{"next name www.wealdtech.eth., types A RRSIG NSEC DNSKEY"},
+		},
+		{ // 3 - DNSKEY
+			rrType:  dns.TypeDNSKEY,
+			details: []string{"key signing key, algorithm ECDSAP256SHA256 (13), key tag 59409"},
+		},
+		{ // 4 - DS
+			rrType:  dns.TypeDS,
+			details: []string{"key tag 12345, algorithm ECDSAP256SHA256 (13), digest type 2"},
+		},
+	}
+
+	for i, test := range tests {
+		rrs, err := DNSUnpackRRs(stored[test.rrType])
+		require.Nil(t, err, fmt.Sprintf("failed to unpack at test %d", i))
+		require.Equal(t, len(test.details), len(rrs), fmt.Sprintf("incorrect number of records at test %d", i))
+		for j := range rrs {
+			assert.Equal(t, test.details[j], DNSSECDetails(rrs[j]), fmt.Sprintf("incorrect details at test %d record %d", i, j))
+		}
+	}
+}
+
+func TestDNSUnpackRRsTruncated(t *testing.T) {
+	rr, err := dns.NewRR("wealdtech.eth. 3600 IN A 192.0.2.1")
+	require.Nil(t, err)
+	data := make([]byte, 1024)
+	offset, err := dns.PackRR(rr, data, 0, nil, false)
+	require.Nil(t, err)
+
+	// Second record is cut short
+	rrs, err := DNSUnpackRRs(append(data[:offset], data[:offset-1]...))
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, len(rrs))
+}
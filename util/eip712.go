@@ -0,0 +1,68 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// EIP712Domain is an EIP-712 domain, as returned by the EIP-5267
+// eip712Domain() function.  Fields is a bitmap of the fields that are
+// present in the domain, in the order name, version, chain ID, verifying
+// contract, salt.
+type EIP712Domain struct {
+	Fields            byte
+	Name              string
+	Version           string
+	ChainID           *big.Int
+	VerifyingContract common.Address
+	Salt              [32]byte
+}
+
+// Separator calculates the EIP-712 domain separator for the domain.
+func (d *EIP712Domain) Separator() common.Hash {
+	typeFields := make([]string, 0, 5)
+	encoded := make([]byte, 0, 32*6)
+	if d.Fields&0x01 != 0 {
+		typeFields = append(typeFields, "string name")
+		encoded = append(encoded, crypto.Keccak256([]byte(d.Name))...)
+	}
+	if d.Fields&0x02 != 0 {
+		typeFields = append(typeFields, "string version")
+		encoded = append(encoded, crypto.Keccak256([]byte(d.Version))...)
+	}
+	if d.Fields&0x04 != 0 {
+		typeFields = append(typeFields, "uint256 chainId")
+		chainID := d.ChainID
+		if chainID == nil {
+			chainID = big.NewInt(0)
+		}
+		encoded = append(encoded, math.U256Bytes(new(big.Int).Set(chainID))...)
+	}
+	if d.Fields&0x08 != 0 {
+		typeFields = append(typeFields, "address verifyingContract")
+		encoded = append(encoded, common.LeftPadBytes(d.VerifyingContract.Bytes(), 32)...)
+	}
+	if d.Fields&0x10 != 0 {
+		typeFields = append(typeFields, "bytes32 salt")
+		encoded = append(encoded, d.Salt[:]...)
+	}
+	typeHash := crypto.Keccak256([]byte("EIP712Domain(" + strings.Join(typeFields, ",") + ")"))
+	return crypto.Keccak256Hash(append(typeHash, encoded...))
+}
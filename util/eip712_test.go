@@ -0,0 +1,58 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEIP712DomainSeparator(t *testing.T) {
+	tests := []struct {
+		domain *EIP712Domain
+		output string
+	}{
+		{
+			// Example from EIP-712
+			domain: &EIP712Domain{
+				Fields:            0x0f,
+				Name:              "Ether Mail",
+				Version:           "1",
+				ChainID:           big.NewInt(1),
+				VerifyingContract: common.HexToAddress("0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"),
+			},
+			output: "0xf2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f",
+		},
+		{
+			// Fields not in the bitmap are ignored
+			domain: &EIP712Domain{
+				Fields:            0x0f,
+				Name:              "Ether Mail",
+				Version:           "1",
+				ChainID:           big.NewInt(1),
+				VerifyingContract: common.HexToAddress("0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"),
+				Salt:              [32]byte{0x01},
+			},
+			output: "0xf2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f",
+		},
+	}
+
+	for i, tt := range tests {
+		assert.Equal(t, tt.output, tt.domain.Separator().Hex(), fmt.Sprintf("failed at test %d", i))
+	}
+}
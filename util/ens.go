@@ -0,0 +1,166 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/viper"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+// ethControllerInterfaceID is the ID of the interface of the .eth
+// registration controller.
+var ethControllerInterfaceID = [4]byte{0x01, 0x8f, 0xac, 0x06}
+
+// ENSRegistryAddress returns the address of the ENS registry.  This is the
+// well-known address unless overridden by the 'ensregistry' configuration
+// value.
+func ENSRegistryAddress(backend bind.ContractBackend) (common.Address, error) {
+	if viper.GetString("ensregistry") != "" {
+		if !common.IsHexAddress(viper.GetString("ensregistry")) {
+			return ens.UnknownAddress, fmt.Errorf("invalid ENS registry address %s", viper.GetString("ensregistry"))
+		}
+		return common.HexToAddress(viper.GetString("ensregistry")), nil
+	}
+	return ens.RegistryContractAddress(backend)
+}
+
+// ENSRegistry obtains the ENS registry.
+func ENSRegistry(backend bind.ContractBackend) (*ens.Registry, error) {
+	address, err := ENSRegistryAddress(backend)
+	if err != nil {
+		return nil, err
+	}
+	return ens.NewRegistryAt(backend, address)
+}
+
+// ENSResolver obtains the resolver for a domain.
+func ENSResolver(backend bind.ContractBackend, domain string) (*ens.Resolver, error) {
+	registry, err := ENSRegistry(backend)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ensure the name is registered
+	ownerAddress, err := registry.Owner(domain)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.Equal(ownerAddress.Bytes(), ens.UnknownAddress.Bytes()) {
+		return nil, errors.New("unregistered name")
+	}
+
+	resolverAddress, err := registry.ResolverAddress(domain)
+	if err != nil {
+		return nil, err
+	}
+	return ens.NewResolverAt(backend, domain, resolverAddress)
+}
+
+// ENSDNSResolver obtains the DNS resolver for a domain.
+func ENSDNSResolver(backend bind.ContractBackend, domain string) (*ens.DNSResolver, error) {
+	registry, err := ENSRegistry(backend)
+	if err != nil {
+		return nil, err
+	}
+	resolverAddress, err := registry.ResolverAddress(domain)
+	if err != nil {
+		return nil, err
+	}
+	return ens.NewDNSResolverAt(backend, domain, resolverAddress)
+}
+
+// ENSReverseResolve resolves an address to its ENS name through the reverse
+// resolver for the address.  It returns an error if there is no name.
+func ENSReverseResolve(backend bind.ContractBackend, address common.Address) (string, error) {
+	registry, err := ENSRegistry(backend)
+	if err != nil {
+		return "", err
+	}
+	resolverAddress, err := registry.ResolverAddress(fmt.Sprintf("%x.addr.reverse", address.Bytes()))
+	if err != nil {
+		return "", err
+	}
+	resolver, err := ens.NewReverseResolverAt(backend, resolverAddress)
+	if err != nil {
+		return "", err
+	}
+	name, err := resolver.Name(address)
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		return "", errors.New("no resolution")
+	}
+	return name, nil
+}
+
+// ENSFormat provides a string version of an address, reverse resolving it
+// to an ENS name if possible.
+func ENSFormat(backend bind.ContractBackend, address common.Address) string {
+	name, err := ENSReverseResolve(backend, address)
+	if err != nil {
+		return address.Hex()
+	}
+	return name
+}
+
+// ENSPublicResolverAddress obtains the address of the public resolver, as
+// given by the name resolver.eth.
+func ENSPublicResolverAddress(backend bind.ContractBackend) (common.Address, error) {
+	resolver, err := ENSResolver(backend, "resolver.eth")
+	if err != nil {
+		return ens.UnknownAddress, err
+	}
+	return resolver.Address()
+}
+
+// ENSReverseRegistrar obtains the reverse registrar, which is the owner of
+// addr.reverse.
+func ENSReverseRegistrar(backend bind.ContractBackend) (*ens.ReverseRegistrar, error) {
+	registry, err := ENSRegistry(backend)
+	if err != nil {
+		return nil, err
+	}
+	address, err := registry.Owner("addr.reverse")
+	if err != nil {
+		return nil, err
+	}
+	if address == ens.UnknownAddress {
+		return nil, errors.New("no reverse registrar")
+	}
+	return ens.NewReverseRegistrarAt(backend, address)
+}
+
+// ENSETHController obtains the registration controller for a domain such as
+// "eth", as given by the interface implementer on the domain's resolver.
+func ENSETHController(backend bind.ContractBackend, domain string) (*ens.ETHController, error) {
+	resolver, err := ENSResolver(backend, domain)
+	if err != nil {
+		return nil, err
+	}
+	address, err := resolver.InterfaceImplementer(ethControllerInterfaceID)
+	if err != nil {
+		return nil, err
+	}
+	if address == ens.UnknownAddress {
+		return nil, fmt.Errorf("no controller for domain %s", domain)
+	}
+	return ens.NewETHControllerAt(backend, domain, address)
+}
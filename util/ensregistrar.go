@@ -0,0 +1,174 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/go-ens/v3/contracts/baseregistrar"
+)
+
+// ENSRegistrar is the base registrar for a domain such as "eth".  It provides
+// the same functions as the ens library's base registrar, but is obtained
+// from the ENS registry in use rather than always the well-known registry.
+type ENSRegistrar struct {
+	backend      bind.ContractBackend
+	domain       string
+	Contract     *baseregistrar.Contract
+	ContractAddr common.Address
+}
+
+// ENSRegistrarFor obtains the base registrar for a domain, which is the
+// owner of the domain in the registry.
+func ENSRegistrarFor(backend bind.ContractBackend, domain string) (*ENSRegistrar, error) {
+	registry, err := ENSRegistry(backend)
+	if err != nil {
+		return nil, err
+	}
+	address, err := registry.Owner(domain)
+	if err != nil {
+		return nil, err
+	}
+	if address == ens.UnknownAddress {
+		return nil, fmt.Errorf("no registrar for domain %s", domain)
+	}
+	contract, err := baseregistrar.NewContract(address, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &ENSRegistrar{
+		backend:      backend,
+		domain:       domain,
+		Contract:     contract,
+		ContractAddr: address,
+	}, nil
+}
+
+// tokenID obtains the ID of the token that represents a name, which can be
+// supplied with or without the registrar's domain.
+func (r *ENSRegistrar) tokenID(domain string) (*big.Int, error) {
+	name, err := ens.UnqualifiedName(domain, r.domain)
+	if err != nil {
+		return nil, err
+	}
+	labelHash, err := ens.LabelHash(name)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(labelHash[:]), nil
+}
+
+// Owner obtains the owner of the token that represents the name.
+func (r *ENSRegistrar) Owner(domain string) (common.Address, error) {
+	id, err := r.tokenID(domain)
+	if err != nil {
+		return ens.UnknownAddress, err
+	}
+	owner, err := r.Contract.OwnerOf(nil, id)
+	// The registrar reverts rather than return an empty owner
+	if err != nil && err.Error() == "abi: unmarshalling empty output" {
+		return ens.UnknownAddress, nil
+	}
+	return owner, err
+}
+
+// SetOwner transfers the token that represents the name to a new owner.
+func (r *ENSRegistrar) SetOwner(opts *bind.TransactOpts, domain string, newOwner common.Address) (*types.Transaction, error) {
+	owner, err := r.Owner(domain)
+	if err != nil {
+		return nil, err
+	}
+	id, err := r.tokenID(domain)
+	if err != nil {
+		return nil, err
+	}
+	return r.Contract.TransferFrom(opts, owner, newOwner, id)
+}
+
+// Expiry obtains the unix timestamp at which the registration expires.
+func (r *ENSRegistrar) Expiry(domain string) (*big.Int, error) {
+	id, err := r.tokenID(domain)
+	if err != nil {
+		return nil, err
+	}
+	return r.Contract.NameExpires(nil, id)
+}
+
+// PriorAuctionContract obtains the previous (auction) registrar, or nil if
+// there is none.
+func (r *ENSRegistrar) PriorAuctionContract() (*ens.AuctionRegistrar, error) {
+	address, err := r.Contract.PreviousRegistrar(nil)
+	if err != nil {
+		// Means there is no prior registrar
+		return nil, nil
+	}
+	auctionRegistrar, err := ens.NewAuctionRegistrarAt(r.backend, r.domain, address)
+	if err != nil {
+		return nil, errors.New("failed to instantiate prior auction contract")
+	}
+
+	// Confirm this really is an auction registrar by checking its hash of
+	// an empty bid
+	var empty [32]byte
+	expected := crypto.Keccak256(empty[:], ens.UnknownAddress.Bytes(), empty[:], empty[:])
+	shaBid, err := auctionRegistrar.ShaBid(empty, ens.UnknownAddress, big.NewInt(0), empty)
+	if err != nil || !bytes.Equal(shaBid[:], expected) {
+		return nil, errors.New("failed to confirm auction contract")
+	}
+	return auctionRegistrar, nil
+}
+
+// RegisteredWith returns one of "temporary", "permanent" or "none" for the
+// registrar with which the name is registered.
+func (r *ENSRegistrar) RegisteredWith(domain string) (string, error) {
+	name, err := ens.UnqualifiedName(domain, r.domain)
+	if err != nil {
+		return "", err
+	}
+	registry, err := ENSRegistry(r.backend)
+	if err != nil {
+		return "", err
+	}
+	owner, err := registry.Owner(domain)
+	if err != nil {
+		return "", err
+	}
+
+	auctionRegistrar, err := r.PriorAuctionContract()
+	if err != nil {
+		return "", err
+	}
+	if auctionRegistrar != nil {
+		state, err := auctionRegistrar.State(name)
+		if err != nil {
+			return "", err
+		}
+		if state == "Won" || state == "Owned" {
+			return "temporary", nil
+		}
+	}
+
+	if owner == ens.UnknownAddress {
+		return "none", nil
+	}
+	return "permanent", nil
+}
@@ -19,6 +19,7 @@ arg
    | boolArg
    | domainArg
    | arrayArg
+   | tupleArg
    ;
 
 intArg
@@ -46,12 +47,16 @@ arrayArg
    : '[' funcArgs ']'
    ;
 
+tupleArg
+   : '(' funcArgs ')'
+   ;
+
 NAME
    : NAMESTART NAMEPART*
    ;
 
 INT
-   : DIGIT+
+   : DIGIT+ ('_' DIGIT+)* ('.' DIGIT+)? ([eE] '-'? DIGIT+)?
    ;
 
 HEX
@@ -23,13 +23,14 @@ import (
 	"github.com/wealdtech/ethereal/util/funcparser/parser"
 )
 
-// ParseCall parses a call string and returns a suitable Method
+// ParseCall parses a call string and returns a suitable Method.  Members of
+// the contract's enums can be supplied as <enum>.<member>, for example
+// setStatus(Status.Active).
 func ParseCall(client *ethclient.Client, contract *util.Contract, call string) (*abi.Method, []interface{}, error) {
 	if contract == nil {
 		return nil, nil, errors.New("no contract")
 	}
-
-	is := antlr.NewInputStream(call)
+	is := antlr.NewInputStream(markNames(call))
 	lexer := parser.NewFuncLexer(is)
 	stream := antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel)
 	tree := parser.NewFuncParser(stream).Start()
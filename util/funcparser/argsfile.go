@@ -0,0 +1,267 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package funcparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/wealdtech/ethereal/util"
+)
+
+// ParseArgsFile parses the arguments for the named method from a JSON
+// document, returning a suitable Method.  The document is either an array
+// of values in argument order, or an object mapping argument names or
+// positions (starting at 0) to values.
+//
+// Numbers can be supplied as JSON numbers or strings, including members of
+// the contract's enums as <enum>.<member>, byte values as hex strings, and
+// addresses as hex strings or ENS names.  Arrays are supplied as JSON
+// arrays, and tuples as either JSON arrays or objects keyed by component
+// name.
+func ParseArgsFile(client *ethclient.Client, contract *util.Contract, name string, data []byte) (*abi.Method, []interface{}, error) {
+	if contract == nil {
+		return nil, nil, errors.New("no contract")
+	}
+
+	name = strings.TrimSuffix(strings.TrimSpace(name), "()")
+	var method abi.Method
+	if name == "constructor" {
+		method = contract.Abi.Constructor
+	} else {
+		var exists bool
+		method, exists = contract.Abi.Methods[name]
+		if !exists {
+			return nil, nil, fmt.Errorf("unknown method name %s", name)
+		}
+	}
+
+	values, err := argsFileValues(method.Inputs, data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	args := make([]interface{}, len(method.Inputs))
+	for i := range method.Inputs {
+		arg, err := jsonToValue(client, contract.Enums, &method.Inputs[i].Type, values[i])
+		if err != nil {
+			return nil, nil, fmt.Errorf("argument %s: %v", argsFileLabel(method.Inputs, i), err)
+		}
+		args[i] = arg.Interface()
+	}
+	return &method, args, nil
+}
+
+// argsFileValues matches the values in the document to the method's inputs.
+func argsFileValues(inputs abi.Arguments, data []byte) ([]json.RawMessage, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var values []json.RawMessage
+		if err := json.Unmarshal(data, &values); err != nil {
+			return nil, fmt.Errorf("invalid arguments: %v", err)
+		}
+		if len(values) != len(inputs) {
+			return nil, fmt.Errorf("expected %d arguments, got %d", len(inputs), len(values))
+		}
+		return values, nil
+	}
+
+	var keyed map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keyed); err != nil {
+		return nil, fmt.Errorf("invalid arguments: %v", err)
+	}
+	values := make([]json.RawMessage, len(inputs))
+	for key, value := range keyed {
+		pos := argsFilePosition(inputs, key)
+		if pos == -1 {
+			return nil, fmt.Errorf("unknown argument %s", key)
+		}
+		if values[pos] != nil {
+			return nil, fmt.Errorf("argument %s supplied more than once", argsFileLabel(inputs, pos))
+		}
+		values[pos] = value
+	}
+	for i := range values {
+		if values[i] == nil {
+			return nil, fmt.Errorf("argument %s missing", argsFileLabel(inputs, i))
+		}
+	}
+	return values, nil
+}
+
+// argsFilePosition returns the position of the argument with the given name
+// or position, or -1 if there is no such argument.
+func argsFilePosition(inputs abi.Arguments, key string) int {
+	for i := range inputs {
+		if inputs[i].Name != "" && inputs[i].Name == key {
+			return i
+		}
+	}
+	pos, err := strconv.Atoi(key)
+	if err != nil || pos < 0 || pos >= len(inputs) {
+		return -1
+	}
+	return pos
+}
+
+// argsFileLabel returns a label for the argument at the given position.
+func argsFileLabel(inputs abi.Arguments, pos int) string {
+	if inputs[pos].Name == "" {
+		return strconv.Itoa(pos)
+	}
+	return fmt.Sprintf("%d (%s)", pos, inputs[pos].Name)
+}
+
+// jsonToValue turns a JSON value in to a value of the given ABI type.
+func jsonToValue(client *ethclient.Client, enums map[string][]string, inputType *abi.Type, data json.RawMessage) (reflect.Value, error) {
+	switch inputType.T {
+	case abi.SliceTy, abi.ArrayTy:
+		var elems []json.RawMessage
+		if err := json.Unmarshal(data, &elems); err != nil {
+			return reflect.Value{}, fmt.Errorf("expected array for %s", inputType.String())
+		}
+		var value reflect.Value
+		if inputType.T == abi.ArrayTy {
+			if len(elems) != inputType.Size {
+				return reflect.Value{}, fmt.Errorf("expected %d values for %s, got %d", inputType.Size, inputType.String(), len(elems))
+			}
+			value = reflect.New(reflectType(inputType)).Elem()
+		} else {
+			value = reflect.MakeSlice(reflectType(inputType), len(elems), len(elems))
+		}
+		for i := range elems {
+			elem, err := jsonToValue(client, enums, inputType.Elem, elems[i])
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("element %d: %v", i, err)
+			}
+			value.Index(i).Set(elem)
+		}
+		return value, nil
+	case abi.TupleTy:
+		return jsonToTuple(client, enums, inputType, data)
+	case abi.BoolTy:
+		var val bool
+		if err := json.Unmarshal(data, &val); err != nil {
+			return reflect.Value{}, fmt.Errorf("expected true or false for %s", inputType.String())
+		}
+		return reflect.ValueOf(val), nil
+	case abi.StringTy:
+		var val string
+		if err := json.Unmarshal(data, &val); err != nil {
+			return reflect.Value{}, fmt.Errorf("expected string for %s", inputType.String())
+		}
+		return reflect.ValueOf(val), nil
+	}
+
+	// Remaining types are simple values supplied as strings or, for
+	// integers, numbers
+	input := string(data)
+	if strings.HasPrefix(input, `"`) {
+		if err := json.Unmarshal(data, &input); err != nil {
+			return reflect.Value{}, err
+		}
+	} else if inputType.T != abi.IntTy && inputType.T != abi.UintTy {
+		return reflect.Value{}, fmt.Errorf("expected string for %s", inputType.String())
+	}
+
+	var err error
+	if (inputType.T == abi.IntTy || inputType.T == abi.UintTy) && isEnumValue(input) {
+		input, err = enumValue(enums, input)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	var val interface{}
+	switch inputType.T {
+	case abi.IntTy:
+		val, err = StrToInt(inputType, input)
+	case abi.UintTy:
+		val, err = StrToUint(inputType, input)
+	case abi.AddressTy:
+		val, err = resolveAddress(client, input)
+	case abi.HashTy:
+		if len(strings.TrimPrefix(input, "0x")) != 64 {
+			return reflect.Value{}, fmt.Errorf("invalid hash %s", input)
+		}
+		val, err = StrToHash(inputType, input)
+	case abi.BytesTy, abi.FixedBytesTy:
+		val, err = StrToBytes(inputType, input)
+	default:
+		err = fmt.Errorf("unhandled type %s", inputType.String())
+	}
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return reflect.ValueOf(val), nil
+}
+
+// jsonToTuple turns a JSON array or object in to a tuple of the given ABI type.
+func jsonToTuple(client *ethclient.Client, enums map[string][]string, inputType *abi.Type, data json.RawMessage) (reflect.Value, error) {
+	elems := make([]json.RawMessage, len(inputType.TupleElems))
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case len(trimmed) > 0 && trimmed[0] == '[':
+		var values []json.RawMessage
+		if err := json.Unmarshal(data, &values); err != nil {
+			return reflect.Value{}, err
+		}
+		if len(values) != len(elems) {
+			return reflect.Value{}, fmt.Errorf("expected %d values for tuple %s, got %d", len(elems), inputType.String(), len(values))
+		}
+		copy(elems, values)
+	case len(trimmed) > 0 && trimmed[0] == '{':
+		var keyed map[string]json.RawMessage
+		if err := json.Unmarshal(data, &keyed); err != nil {
+			return reflect.Value{}, err
+		}
+		for key, value := range keyed {
+			pos := -1
+			for i := range inputType.TupleRawNames {
+				if inputType.TupleRawNames[i] == key {
+					pos = i
+					break
+				}
+			}
+			if pos == -1 {
+				return reflect.Value{}, fmt.Errorf("unknown component %s for tuple %s", key, inputType.String())
+			}
+			elems[pos] = value
+		}
+		for i := range elems {
+			if elems[i] == nil {
+				return reflect.Value{}, fmt.Errorf("component %s missing", inputType.TupleRawNames[i])
+			}
+		}
+	default:
+		return reflect.Value{}, fmt.Errorf("expected array or object for tuple %s", inputType.String())
+	}
+
+	value := reflect.New(inputType.TupleType).Elem()
+	for i := range elems {
+		elem, err := jsonToValue(client, enums, inputType.TupleElems[i], elems[i])
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("component %d: %v", i, err)
+		}
+		value.Field(i).Set(elem)
+	}
+	return value, nil
+}
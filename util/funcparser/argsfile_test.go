@@ -0,0 +1,147 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package funcparser
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/ethereal/util"
+)
+
+func TestParseArgsFile(t *testing.T) {
+	transferABI := `[{"inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"name":"transfer","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+	tupleABI := `[{"inputs":[{"components":[{"name":"owner","type":"address"},{"name":"value","type":"uint256"}],"name":"arg1","type":"tuple"},{"name":"arg2","type":"bytes4[2]"}],"name":"test","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+	tests := []struct {
+		abi    string
+		name   string
+		input  string
+		packed string
+		err    string
+	}{
+		{ // 0 - positional array
+			abi:    transferABI,
+			name:   "transfer",
+			input:  `["0x5FfC014343cd971B7eb70732021E26C35B744cc4", 42]`,
+			packed: "0000000000000000000000005ffc014343cd971b7eb70732021e26c35b744cc4000000000000000000000000000000000000000000000000000000000000002a",
+		},
+		{ // 1 - keyed by name, number as string
+			abi:    transferABI,
+			name:   "transfer()",
+			input:  `{"value": "42", "to": "0x5FfC014343cd971B7eb70732021E26C35B744cc4"}`,
+			packed: "0000000000000000000000005ffc014343cd971b7eb70732021e26c35b744cc4000000000000000000000000000000000000000000000000000000000000002a",
+		},
+		{ // 2 - keyed by position
+			abi:    transferABI,
+			name:   "transfer",
+			input:  `{"0": "0x5FfC014343cd971B7eb70732021E26C35B744cc4", "1": "4.2e1"}`,
+			packed: "0000000000000000000000005ffc014343cd971b7eb70732021e26c35b744cc4000000000000000000000000000000000000000000000000000000000000002a",
+		},
+		{ // 3 - tuple as object and fixed bytes array
+			abi:    tupleABI,
+			name:   "test",
+			input:  `{"arg1": {"owner": "0x5FfC014343cd971B7eb70732021E26C35B744cc4", "value": 42}, "arg2": ["0x01020304", "0x05060708"]}`,
+			packed: "0000000000000000000000005ffc014343cd971b7eb70732021e26c35b744cc4000000000000000000000000000000000000000000000000000000000000002a01020304000000000000000000000000000000000000000000000000000000000506070800000000000000000000000000000000000000000000000000000000",
+		},
+		{ // 4 - tuple as array
+			abi:    tupleABI,
+			name:   "test",
+			input:  `[["0x5FfC014343cd971B7eb70732021E26C35B744cc4", 42], ["0x01020304", "0x05060708"]]`,
+			packed: "0000000000000000000000005ffc014343cd971b7eb70732021e26c35b744cc4000000000000000000000000000000000000000000000000000000000000002a01020304000000000000000000000000000000000000000000000000000000000506070800000000000000000000000000000000000000000000000000000000",
+		},
+		{ // 5 - unknown method
+			abi:   transferABI,
+			name:  "approve",
+			input: `[]`,
+			err:   "unknown method name approve",
+		},
+		{ // 6 - missing argument
+			abi:   transferABI,
+			name:  "transfer",
+			input: `{"to": "0x5FfC014343cd971B7eb70732021E26C35B744cc4"}`,
+			err:   "argument 1 (value) missing",
+		},
+		{ // 7 - unknown argument
+			abi:   transferABI,
+			name:  "transfer",
+			input: `{"to": "0x5FfC014343cd971B7eb70732021E26C35B744cc4", "value": 1, "amount": 1}`,
+			err:   "unknown argument amount",
+		},
+		{ // 8 - wrong number of arguments
+			abi:   transferABI,
+			name:  "transfer",
+			input: `["0x5FfC014343cd971B7eb70732021E26C35B744cc4"]`,
+			err:   "expected 2 arguments, got 1",
+		},
+		{ // 9 - negative unsigned integer
+			abi:   transferABI,
+			name:  "transfer",
+			input: `["0x5FfC014343cd971B7eb70732021E26C35B744cc4", -1]`,
+			err:   "argument 1 (value): invalid unsigned integer -1",
+		},
+		{ // 10 - bad address checksum
+			abi:   transferABI,
+			name:  "transfer",
+			input: `["0x5FFC014343cd971B7eb70732021E26C35B744cc4", 1]`,
+			err:   "argument 0 (to): address 0x5FFC014343cd971B7eb70732021E26C35B744cc4 has an invalid checksum; did you mean 0x5FfC014343cd971B7eb70732021E26C35B744cc4?",
+		},
+		{ // 11 - fixed bytes too long
+			abi:   tupleABI,
+			name:  "test",
+			input: `[["0x5FfC014343cd971B7eb70732021E26C35B744cc4", 42], ["0x0102030405", "0x05060708"]]`,
+			err:   "argument 1 (arg2): element 0: byte string 0x0102030405 too long for bytes4 (expected 4 bytes, got 5)",
+		},
+		{ // 12 - missing tuple component
+			abi:   tupleABI,
+			name:  "test",
+			input: `[{"owner": "0x5FfC014343cd971B7eb70732021E26C35B744cc4"}, ["0x01020304", "0x05060708"]]`,
+			err:   "argument 0 (arg1): component value missing",
+		},
+		{ // 13 - wrong array length
+			abi:   tupleABI,
+			name:  "test",
+			input: `[["0x5FfC014343cd971B7eb70732021E26C35B744cc4", 42], ["0x01020304"]]`,
+			err:   "argument 1 (arg2): expected 2 values for bytes4[2], got 1",
+		},
+		{ // 14 - invalid JSON
+			abi:   transferABI,
+			name:  "transfer",
+			input: `{"to":`,
+			err:   "invalid arguments: unexpected end of JSON input",
+		},
+		{ // 15 - unsigned integer out of range
+			abi:   `[{"inputs":[{"name":"arg1","type":"uint8"}],"name":"test","outputs":[],"stateMutability":"nonpayable","type":"function"}]`,
+			name:  "test",
+			input: `[256]`,
+			err:   "argument 0 (arg1): integer 256 out of range for uint8 (0 to 255)",
+		},
+	}
+
+	for i, test := range tests {
+		contract, err := util.ParseCombinedJSON(fmt.Sprintf(`{"contracts":{"Test.sol:Test":{"abi":%q}}}`, test.abi), "Test")
+		require.Nil(t, err, fmt.Sprintf("failed to parse contract JSON at test %d", i))
+		method, args, err := ParseArgsFile(nil, contract, test.name, []byte(test.input))
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("incorrect error at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed to parse arguments at test %d", i))
+		packed, err := method.Inputs.Pack(args...)
+		require.Nil(t, err, fmt.Sprintf("failed to pack arguments at test %d", i))
+		assert.Equal(t, test.packed, hex.EncodeToString(packed), fmt.Sprintf("incorrect encoding at test %d", i))
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package funcparser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// enumNameRegex matches valid enum and member names.
+var enumNameRegex = regexp.MustCompile(`^[A-Za-z$_][A-Za-z0-9$_]*$`)
+
+// ParseEnumMap parses enum definitions of the form
+// "Status=Pending,Active,Closed;Role=User,Admin".  As with Solidity, the
+// value of each member is its position in the definition, starting at 0.
+func ParseEnumMap(input string) (map[string][]string, error) {
+	enums := make(map[string][]string)
+	for _, definition := range strings.Split(input, ";") {
+		definition = strings.TrimSpace(definition)
+		if definition == "" {
+			continue
+		}
+		parts := strings.SplitN(definition, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("enum definition %s must be of the form name=member1,member2,...", definition)
+		}
+		name := strings.TrimSpace(parts[0])
+		if !enumNameRegex.MatchString(name) {
+			return nil, fmt.Errorf("invalid enum name %s", name)
+		}
+		if _, exists := enums[name]; exists {
+			return nil, fmt.Errorf("enum %s defined more than once", name)
+		}
+		members := strings.Split(parts[1], ",")
+		if len(members) > 256 {
+			return nil, fmt.Errorf("enum %s has more than 256 members", name)
+		}
+		seen := make(map[string]bool)
+		for i := range members {
+			members[i] = strings.TrimSpace(members[i])
+			if !enumNameRegex.MatchString(members[i]) {
+				return nil, fmt.Errorf("invalid member %s of enum %s", members[i], name)
+			}
+			if seen[members[i]] {
+				return nil, fmt.Errorf("member %s of enum %s defined more than once", members[i], name)
+			}
+			seen[members[i]] = true
+		}
+		enums[name] = members
+	}
+	return enums, nil
+}
+
+// enumValue returns the value of an enum member given as <enum>.<member>.
+func enumValue(enums map[string][]string, input string) (string, error) {
+	parts := strings.Split(input, ".")
+	if len(parts) != 2 {
+		return "", fmt.Errorf("invalid enum value %s", input)
+	}
+	members, exists := enums[parts[0]]
+	if !exists {
+		return "", fmt.Errorf("unknown enum %s", parts[0])
+	}
+	for i := range members {
+		if members[i] == parts[1] {
+			return strconv.Itoa(i), nil
+		}
+	}
+	return "", fmt.Errorf("unknown member %s of enum %s (expected one of %s)", parts[1], parts[0], strings.Join(members, ", "))
+}
+
+// isEnumValue returns true if the input looks like an enum value, rather
+// than a number.
+func isEnumValue(input string) bool {
+	parts := strings.Split(input, ".")
+	return len(parts) == 2 && enumNameRegex.MatchString(parts[0]) && enumNameRegex.MatchString(parts[1])
+}
+
+// isNameChar returns true if the character can be part of a name.
+func isNameChar(c byte) bool {
+	return c == '$' || c == '_' || (c >= '0' && c <= '9') || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
@@ -0,0 +1,157 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package funcparser
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/ethereal/util"
+)
+
+func TestParseEnumMap(t *testing.T) {
+	tests := []struct {
+		input string
+		enums map[string][]string
+		err   string
+	}{
+		{ // 0 - empty
+			input: "",
+			enums: map[string][]string{},
+		},
+		{ // 1 - single enum
+			input: "Status=Pending,Active,Closed",
+			enums: map[string][]string{"Status": {"Pending", "Active", "Closed"}},
+		},
+		{ // 2 - multiple enums with whitespace
+			input: "Status = Pending, Active ; Role=User,Admin;",
+			enums: map[string][]string{"Status": {"Pending", "Active"}, "Role": {"User", "Admin"}},
+		},
+		{ // 3 - no members
+			input: "Status",
+			err:   "enum definition Status must be of the form name=member1,member2,...",
+		},
+		{ // 4 - invalid name
+			input: "1Status=Pending",
+			err:   "invalid enum name 1Status",
+		},
+		{ // 5 - empty member
+			input: "Status=Pending,,Closed",
+			err:   "invalid member  of enum Status",
+		},
+		{ // 6 - duplicate member
+			input: "Status=Pending,Active,Pending",
+			err:   "member Pending of enum Status defined more than once",
+		},
+		{ // 7 - duplicate enum
+			input: "Status=Pending;Status=Active",
+			err:   "enum Status defined more than once",
+		},
+	}
+
+	for i, test := range tests {
+		enums, err := ParseEnumMap(test.input)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("incorrect error at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		assert.Equal(t, test.enums, enums, fmt.Sprintf("incorrect enums at test %d", i))
+	}
+}
+
+func TestParseCallEnums(t *testing.T) {
+	abi := `[{"inputs":[{"name":"arg1","type":"uint8"},{"name":"arg2","type":"uint8[]"},{"name":"arg3","type":"string"},{"name":"arg4","type":"uint256"}],"name":"test","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+	enums := map[string][]string{
+		"Status": {"Pending", "Active", "Closed"},
+		"Role":   {"User", "Admin"},
+	}
+	tests := []struct {
+		input  string
+		packed string
+		err    string
+	}{
+		{ // 0 - enums in plain and array arguments, not in strings
+			input:  `test(Status.Active,[Role.Admin,Status.Closed],"Status.Active",1.5e1)`,
+			packed: "0000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000008000000000000000000000000000000000000000000000000000000000000000e0000000000000000000000000000000000000000000000000000000000000000f000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000d5374617475732e41637469766500000000000000000000000000000000000000",
+		},
+		{ // 1 - unknown enum
+			input: `test(State.Active,[],"",0)`,
+			err:   "argument 0 of type uint8 at State.Active: unknown enum State",
+		},
+		{ // 2 - unknown member
+			input: `test(Status.Open,[],"",0)`,
+			err:   "argument 0 of type uint8 at Status.Open: unknown member Open of enum Status (expected one of Pending, Active, Closed)",
+		},
+		{ // 3 - name that is not an enum value
+			input: `test(1,[my-wallet.enstest.eth],"",0)`,
+			err:   "argument 1 of type uint8 at my-wallet.enstest.eth: expected a number or an enum value",
+		},
+		{ // 4 - enum value for a string
+			input: `test(1,[],Status.Active,0)`,
+			err:   "argument 2 of type string at Status.Active: unexpected name for type string",
+		},
+	}
+
+	for i, test := range tests {
+		contract, err := util.ParseCombinedJSON(fmt.Sprintf(`{"contracts":{"Test.sol:Test":{"abi":%q}}}`, abi), "Test")
+		require.Nil(t, err, fmt.Sprintf("failed to parse contract JSON at test %d", i))
+		contract.Enums = enums
+		method, args, err := ParseCall(nil, contract, test.input)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("incorrect error at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed to parse call at test %d", i))
+		packed, err := method.Inputs.Pack(args...)
+		require.Nil(t, err, fmt.Sprintf("failed to pack arguments at test %d", i))
+		assert.Equal(t, test.packed, hex.EncodeToString(packed), fmt.Sprintf("incorrect encoding at test %d", i))
+	}
+}
+
+func TestParseArgsFileEnums(t *testing.T) {
+	abi := `[{"inputs":[{"name":"status","type":"uint8"}],"name":"setStatus","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+	tests := []struct {
+		input  string
+		packed string
+		err    string
+	}{
+		{ // 0 - enum value
+			input:  `{"status": "Status.Closed"}`,
+			packed: "0000000000000000000000000000000000000000000000000000000000000002",
+		},
+		{ // 1 - unknown member
+			input: `["Status.Open"]`,
+			err:   "argument 0 (status): unknown member Open of enum Status (expected one of Pending, Active, Closed)",
+		},
+	}
+
+	for i, test := range tests {
+		contract, err := util.ParseCombinedJSON(fmt.Sprintf(`{"contracts":{"Test.sol:Test":{"abi":%q}}}`, abi), "Test")
+		require.Nil(t, err, fmt.Sprintf("failed to parse contract JSON at test %d", i))
+		contract.Enums = map[string][]string{"Status": {"Pending", "Active", "Closed"}}
+		method, args, err := ParseArgsFile(nil, contract, "setStatus", []byte(test.input))
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("incorrect error at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		packed, err := method.Inputs.Pack(args...)
+		require.Nil(t, err, fmt.Sprintf("failed to pack arguments at test %d", i))
+		assert.Equal(t, test.packed, hex.EncodeToString(packed), fmt.Sprintf("incorrect encoding at test %d", i))
+	}
+}
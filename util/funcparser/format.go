@@ -0,0 +1,94 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package funcparser
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/wealdtech/ethereal/util"
+)
+
+// PreviewCall parses a call string as per ParseCall, additionally returning
+// the canonical signature of the method (e.g. "transfer(address,uint256)").
+// This allows callers to show what will be called before packing the
+// arguments.
+func PreviewCall(client *ethclient.Client, contract *util.Contract, call string) (*abi.Method, string, []interface{}, error) {
+	method, args, err := ParseCall(client, contract, call)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	return method, method.Sig, args, nil
+}
+
+// FormatCall provides a human-readable version of a call to a method with
+// the given values, for example "setValue(6)".
+func FormatCall(method *abi.Method, args []interface{}) string {
+	values := make([]string, len(args))
+	for i := range args {
+		values[i] = FormatValue(args[i])
+	}
+	return fmt.Sprintf("%s(%s)", method.Name, strings.Join(values, ","))
+}
+
+// FormatValue provides a human-readable version of a value as generated by
+// the parser or unpacked from ABI-encoded data.
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case common.Address:
+		return v.Hex()
+	case *big.Int:
+		return v.String()
+	case []byte:
+		return fmt.Sprintf("0x%x", v)
+	case string:
+		return fmt.Sprintf("%q", v)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Array, reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			// Fixed bytes
+			data := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(data), rv)
+			return fmt.Sprintf("0x%x", data)
+		}
+		values := make([]string, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			values[i] = FormatValue(rv.Index(i).Interface())
+		}
+		return fmt.Sprintf("[%s]", strings.Join(values, ","))
+	case reflect.Struct:
+		// Tuple
+		values := make([]string, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			values[i] = FormatValue(rv.Field(i).Interface())
+		}
+		return fmt.Sprintf("(%s)", strings.Join(values, ","))
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return ""
+		}
+		return FormatValue(rv.Elem().Interface())
+	}
+	return fmt.Sprintf("%v", value)
+}
@@ -0,0 +1,125 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package funcparser
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/ethereal/util"
+)
+
+func TestFormatValue(t *testing.T) {
+	tests := []struct {
+		input  interface{}
+		output string
+	}{
+		{ // 0 - big int
+			input:  big.NewInt(1000),
+			output: "1000",
+		},
+		{ // 1 - negative big int
+			input:  big.NewInt(-5),
+			output: "-5",
+		},
+		{ // 2 - address
+			input:  common.HexToAddress("0x5ffc014343cd971b7eb70732021e26c35b744cc4"),
+			output: "0x5FfC014343cd971B7eb70732021E26C35B744cc4",
+		},
+		{ // 3 - bytes
+			input:  []byte{0x01, 0x02, 0x03},
+			output: "0x010203",
+		},
+		{ // 4 - fixed bytes
+			input:  [4]byte{0x01, 0x02, 0x03, 0x04},
+			output: "0x01020304",
+		},
+		{ // 5 - string
+			input:  "hello",
+			output: `"hello"`,
+		},
+		{ // 6 - bool
+			input:  true,
+			output: "true",
+		},
+		{ // 7 - small int
+			input:  uint8(7),
+			output: "7",
+		},
+		{ // 8 - array of arrays
+			input:  [][]*big.Int{{big.NewInt(1), big.NewInt(2)}, {big.NewInt(3)}},
+			output: "[[1,2],[3]]",
+		},
+		{ // 9 - array of bytes
+			input:  [][]byte{{0x01}, {0x02}},
+			output: "[0x01,0x02]",
+		},
+		{ // 10 - tuple
+			input: struct {
+				Owner common.Address
+				Value *big.Int
+			}{common.HexToAddress("0x5ffc014343cd971b7eb70732021e26c35b744cc4"), big.NewInt(42)},
+			output: "(0x5FfC014343cd971B7eb70732021E26C35B744cc4,42)",
+		},
+	}
+
+	for i, test := range tests {
+		assert.Equal(t, test.output, FormatValue(test.input), fmt.Sprintf("incorrect value at test %d", i))
+	}
+}
+
+func TestPreviewCall(t *testing.T) {
+	tests := []struct {
+		abi       string
+		input     string
+		signature string
+		call      string
+		err       string
+	}{
+		{ // 0 - simple call
+			abi:       `[{"inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"name":"transfer","outputs":[],"stateMutability":"nonpayable","type":"function"}]`,
+			input:     `transfer(0x5ffc014343cd971b7eb70732021e26c35b744cc4,1000)`,
+			signature: "transfer(address,uint256)",
+			call:      "transfer(0x5FfC014343cd971B7eb70732021E26C35B744cc4,1000)",
+		},
+		{ // 1 - bytes and tuple
+			abi:       `[{"inputs":[{"name":"data","type":"bytes"},{"components":[{"name":"a","type":"uint256"},{"name":"b","type":"bool"}],"name":"arg2","type":"tuple"}],"name":"test","outputs":[],"stateMutability":"nonpayable","type":"function"}]`,
+			input:     `test(0x0102,(1,true))`,
+			signature: "test(bytes,(uint256,bool))",
+			call:      "test(0x0102,(1,true))",
+		},
+		{ // 2 - unknown method
+			abi:   `[{"inputs":[{"name":"to","type":"address"},{"name":"value","type":"uint256"}],"name":"transfer","outputs":[],"stateMutability":"nonpayable","type":"function"}]`,
+			input: `transfr(0x5ffc014343cd971b7eb70732021e26c35b744cc4,1000)`,
+			err:   "unknown method name transfr",
+		},
+	}
+
+	for i, test := range tests {
+		contract, err := util.ParseCombinedJSON(fmt.Sprintf(`{"contracts":{"Test.sol:Test":{"abi":%q}}}`, test.abi), "Test")
+		require.Nil(t, err, fmt.Sprintf("failed to parse contract JSON at test %d", i))
+		method, signature, args, err := PreviewCall(nil, contract, test.input)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("incorrect error at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed to parse call at test %d", i))
+		assert.Equal(t, test.signature, signature, fmt.Sprintf("incorrect signature at test %d", i))
+		assert.Equal(t, test.call, FormatCall(method, args), fmt.Sprintf("incorrect call at test %d", i))
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package funcparser
+
+import (
+	"strings"
+)
+
+// nameMarker starts a string that holds a name supplied without the @ prefix.
+const nameMarker = "\x01"
+
+// markNames marks names in a call before it is parsed.  The grammar only
+// accepts names with an @ prefix, so names such as vitalik.eth or enum values
+// such as Status.Active are turned in to strings starting with nameMarker.
+// Nothing is resolved here: names are interpreted according to the type of
+// their argument when the call is parsed.  Strings and names that already
+// have a prefix are left untouched.
+func markNames(call string) string {
+	if !strings.Contains(call, ".") {
+		return call
+	}
+
+	var res strings.Builder
+	for i := 0; i < len(call); {
+		c := call[i]
+		j := i + 1
+		switch {
+		case c == '"' || c == '\'':
+			for j < len(call) && call[j] != c {
+				if call[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j < len(call) {
+				j++
+			}
+		case c == '@':
+			for j < len(call) && call[j] != ',' && call[j] != ')' {
+				j++
+			}
+		case isNameChar(c) || c >= 0x80:
+			for j < len(call) && (isNameChar(call[j]) || call[j] == '.' || call[j] == '-' || call[j] >= 0x80) {
+				j++
+			}
+			if isENSName(call[i:j]) {
+				res.WriteString(`"` + nameMarker + call[i:j] + `"`)
+				i = j
+				continue
+			}
+		}
+		if j > len(call) {
+			j = len(call)
+		}
+		res.WriteString(call[i:j])
+		i = j
+	}
+	return res.String()
+}
+
+// isENSName returns true if the input looks like an ENS name or an enum
+// value rather than a number.
+func isENSName(input string) bool {
+	labels := strings.Split(input, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for i := range labels {
+		if labels[i] == "" {
+			return false
+		}
+	}
+	// Numbers such as 1.5e18 have a final part starting with a digit.
+	tld := labels[len(labels)-1]
+	return tld[0] < '0' || tld[0] > '9'
+}
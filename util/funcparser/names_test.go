@@ -0,0 +1,112 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package funcparser
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/ethereal/util"
+)
+
+func TestIsENSName(t *testing.T) {
+	tests := []struct {
+		input string
+		res   bool
+	}{
+		{ // 0 - simple name
+			input: "vitalik.eth",
+			res:   true,
+		},
+		{ // 1 - subdomain with hyphen
+			input: "my-wallet.enstest.eth",
+			res:   true,
+		},
+		{ // 2 - label starting with a digit
+			input: "123.eth",
+			res:   true,
+		},
+		{ // 3 - decimal number
+			input: "1.5",
+			res:   false,
+		},
+		{ // 4 - number in scientific notation
+			input: "1.5e18",
+			res:   false,
+		},
+		{ // 5 - no dot
+			input: "eth",
+			res:   false,
+		},
+		{ // 6 - empty label
+			input: "vitalik..eth",
+			res:   false,
+		},
+	}
+
+	for i, test := range tests {
+		assert.Equal(t, test.res, isENSName(test.input), fmt.Sprintf("failed at test %d", i))
+	}
+}
+
+func TestParseNamesOffline(t *testing.T) {
+	abi := `[{"inputs":[{"name":"amount","type":"uint256"},{"name":"to","type":"address[]"},{"name":"owner","type":"address"},{"name":"label","type":"string"}],"name":"test","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"name":"data","type":"bytes20"}],"name":"store","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+	tests := []struct {
+		input  string
+		packed string
+		err    string
+	}{
+		{ // 0 - hex addresses, decimal numbers and names in strings are untouched
+			input:  `test(1.5e1,[0x5FfC014343cd971B7eb70732021E26C35B744cc4],0x5FfC014343cd971B7eb70732021E26C35B744cc4,"a.eth")`,
+			packed: "000000000000000000000000000000000000000000000000000000000000000f00000000000000000000000000000000000000000000000000000000000000800000000000000000000000005ffc014343cd971b7eb70732021e26c35b744cc400000000000000000000000000000000000000000000000000000000000000c000000000000000000000000000000000000000000000000000000000000000010000000000000000000000005ffc014343cd971b7eb70732021e26c35b744cc40000000000000000000000000000000000000000000000000000000000000005612e657468000000000000000000000000000000000000000000000000000000",
+		},
+		{ // 1 - ENS name without a connection
+			input: `test(1,[0x5FfC014343cd971B7eb70732021E26C35B744cc4,vitalik.eth],0x5FfC014343cd971B7eb70732021E26C35B744cc4,"")`,
+			err:   "argument 1 of type address at vitalik.eth: cannot resolve ENS name vitalik.eth without a connection; supply the address in hex",
+		},
+		{ // 2 - prefixed ENS name without a connection
+			input: `test(1,[],@vitalik.eth,"")`,
+			err:   "argument 2 of type address at @vitalik.eth: cannot resolve ENS name vitalik.eth without a connection; supply the address in hex",
+		},
+		{ // 3 - ENS name for a bytes argument
+			input: `store(vitalik.eth)`,
+			err:   "argument 0 of type bytes20 at vitalik.eth: unexpected name for type bytes20",
+		},
+		{ // 4 - ENS name for an integer argument
+			input: `test(vitalik.eth,[],0x5FfC014343cd971B7eb70732021E26C35B744cc4,"")`,
+			err:   "argument 0 of type uint256 at vitalik.eth: unknown enum vitalik",
+		},
+		{ // 5 - unknown method is reported before names are considered
+			input: `unknown(vitalik.eth)`,
+			err:   "unknown method name unknown",
+		},
+	}
+
+	for i, test := range tests {
+		contract, err := util.ParseCombinedJSON(fmt.Sprintf(`{"contracts":{"Test.sol:Test":{"abi":%q}}}`, abi), "Test")
+		require.Nil(t, err, fmt.Sprintf("failed to parse contract JSON at test %d", i))
+		method, args, err := ParseCall(nil, contract, test.input)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("incorrect error at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed to parse call at test %d", i))
+		packed, err := method.Inputs.Pack(args...)
+		require.Nil(t, err, fmt.Sprintf("failed to pack arguments at test %d", i))
+		assert.Equal(t, test.packed, hex.EncodeToString(packed), fmt.Sprintf("incorrect encoding at test %d", i))
+	}
+}
@@ -14,15 +14,17 @@
 package funcparser
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
+	"reflect"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/wealdtech/ethereal/util"
 	"github.com/wealdtech/ethereal/util/funcparser/parser"
-	ens "github.com/wealdtech/go-ens/v3"
 )
 
 type methodListener struct {
@@ -33,12 +35,21 @@ type methodListener struct {
 	// Arrays are all of the same type but can be nested.
 	curArray      []interface{}
 	maxArrayLevel int
+	// Tuples, and arrays that contain or are contained in tuples, are
+	// built up generically.
+	curComposite []*compositeValue
 	// Result of parsing the argument
 	method *abi.Method
 	args   []interface{}
 	err    error
 }
 
+// compositeValue is a tuple or array value under construction.
+type compositeValue struct {
+	t      *abi.Type
+	values []reflect.Value
+}
+
 // newMethodListener creates a new method listener
 func newMethodListener(client *ethclient.Client, contract *util.Contract) *methodListener {
 	return &methodListener{
@@ -65,10 +76,13 @@ func (l *methodListener) EnterFuncName(c *parser.FuncNameContext) {
 
 func (l *methodListener) EnterIntArg(c *parser.IntArgContext) {
 	if l.err == nil {
-		input := l.method.Inputs[l.curArg]
-		var err error
+		argType, err := l.argType()
+		if err != nil {
+			l.err = l.argError(argType, c.GetText(), err)
+			return
+		}
 		var arg interface{}
-		baseType := baseType(&input.Type)
+		baseType := baseType(argType)
 		switch baseType.T {
 		case abi.IntTy:
 			arg, err = StrToInt(baseType, c.GetText())
@@ -80,35 +94,49 @@ func (l *methodListener) EnterIntArg(c *parser.IntArgContext) {
 			err = fmt.Errorf("unexpected type %v", baseType)
 		}
 		if err != nil {
-			l.err = err
+			l.err = l.argError(baseType, c.GetText(), err)
 		} else {
-			l.pushArg(arg)
+			l.pushArg(arg, c.GetText())
 		}
 	}
 }
 
 func (l *methodListener) EnterBoolArg(c *parser.BoolArgContext) {
 	if l.err == nil {
-		input := l.method.Inputs[l.curArg]
-		baseType := baseType(&input.Type)
+		argType, err := l.argType()
+		if err != nil {
+			l.err = l.argError(argType, c.GetText(), err)
+			return
+		}
+		baseType := baseType(argType)
 		arg, err := StrToBool(baseType, c.GetText())
 		if err != nil {
-			l.err = err
+			l.err = l.argError(baseType, c.GetText(), err)
 		} else {
-			l.pushArg(arg)
+			l.pushArg(arg, c.GetText())
 		}
 	}
 }
 
 func (l *methodListener) EnterStringArg(c *parser.StringArgContext) {
 	if l.err == nil {
-		input := l.method.Inputs[l.curArg]
-		baseType := baseType(&input.Type)
+		if text := c.GetText(); strings.HasPrefix(text, `"`+nameMarker) {
+			// A name supplied without the @ prefix
+			name := strings.TrimSuffix(strings.TrimPrefix(text, `"`+nameMarker), `"`)
+			l.nameArg(name, name)
+			return
+		}
+		argType, err := l.argType()
+		if err != nil {
+			l.err = l.argError(argType, c.GetText(), err)
+			return
+		}
+		baseType := baseType(argType)
 		arg, err := StrToStr(baseType, c.GetText())
 		if err != nil {
-			l.err = err
+			l.err = l.argError(baseType, c.GetText(), err)
 		} else {
-			l.pushArg(arg)
+			l.pushArg(arg, c.GetText())
 		}
 	}
 }
@@ -116,10 +144,23 @@ func (l *methodListener) EnterStringArg(c *parser.StringArgContext) {
 func (l *methodListener) EnterArrayArg(c *parser.ArrayArgContext) {
 	if l.err == nil {
 		if len(l.method.Inputs) <= l.curArg {
-			l.err = fmt.Errorf("too many arguments for method at %s", c.GetText())
+			l.err = l.tooManyArgs(c.GetText())
 			return
 		}
 		input := l.method.Inputs[l.curArg]
+		if len(l.curComposite) > 0 || baseType(&input.Type).T == abi.TupleTy {
+			argType, err := l.argType()
+			if err != nil {
+				l.err = l.argError(argType, c.GetText(), err)
+				return
+			}
+			if argType.T != abi.SliceTy && argType.T != abi.ArrayTy {
+				l.err = l.argError(argType, c.GetText(), errors.New("unexpected array"))
+				return
+			}
+			l.curComposite = append(l.curComposite, &compositeValue{t: argType})
+			return
+		}
 		baseType := baseType(&input.Type)
 		level := arrayLevel(&input.Type)
 		if len(l.curArray) == 0 {
@@ -143,6 +184,39 @@ func (l *methodListener) EnterArrayArg(c *parser.ArrayArgContext) {
 
 func (l *methodListener) ExitArrayArg(c *parser.ArrayArgContext) {
 	if l.err == nil {
+		if len(l.curComposite) > 0 {
+			composite := l.curComposite[len(l.curComposite)-1]
+			var value reflect.Value
+			if composite.t.T == abi.ArrayTy {
+				if len(composite.values) != composite.t.Size {
+					l.err = l.argError(composite.t, c.GetText(), fmt.Errorf("expected %d elements, got %d", composite.t.Size, len(composite.values)))
+					return
+				}
+				value = reflect.New(reflectType(composite.t)).Elem()
+				for i := range composite.values {
+					value.Index(i).Set(composite.values[i])
+				}
+			} else {
+				value = reflect.MakeSlice(reflectType(composite.t), 0, len(composite.values))
+				value = reflect.Append(value, composite.values...)
+			}
+			l.popComposite(value)
+			return
+		}
+
+		// Ensure that fixed-size arrays have the correct number of elements
+		arrayType := &l.method.Inputs[l.curArg].Type
+		for i := 1; i < len(l.curArray); i++ {
+			arrayType = arrayType.Elem
+		}
+		if arrayType.T == abi.ArrayTy {
+			elements := reflect.ValueOf(l.curArray[len(l.curArray)-1]).Len()
+			if elements != arrayType.Size {
+				l.err = l.argError(arrayType, c.GetText(), fmt.Errorf("expected %d elements, got %d", arrayType.Size, elements))
+				return
+			}
+		}
+
 		level := len(l.curArray)
 		if level == 1 {
 			// Only array; push to args
@@ -242,32 +316,105 @@ func (l *methodListener) ExitArrayArg(c *parser.ArrayArgContext) {
 	}
 }
 
+func (l *methodListener) EnterTupleArg(c *parser.TupleArgContext) {
+	if l.err == nil {
+		if len(l.method.Inputs) <= l.curArg {
+			l.err = l.tooManyArgs(c.GetText())
+			return
+		}
+		argType, err := l.argType()
+		if err != nil {
+			l.err = l.argError(argType, c.GetText(), err)
+			return
+		}
+		if argType.T != abi.TupleTy {
+			l.err = l.argError(argType, c.GetText(), errors.New("unexpected tuple"))
+			return
+		}
+		l.curComposite = append(l.curComposite, &compositeValue{t: argType})
+	}
+}
+
+func (l *methodListener) ExitTupleArg(c *parser.TupleArgContext) {
+	if l.err == nil {
+		composite := l.curComposite[len(l.curComposite)-1]
+		if len(composite.values) != len(composite.t.TupleElems) {
+			l.err = l.argError(composite.t, c.GetText(), fmt.Errorf("expected %d values, got %d", len(composite.t.TupleElems), len(composite.values)))
+			return
+		}
+		value := reflect.New(composite.t.TupleType).Elem()
+		for i := range composite.values {
+			value.Field(i).Set(composite.values[i])
+		}
+		l.popComposite(value)
+	}
+}
+
+// popComposite removes the current composite value, adding its final value
+// to its parent or to the arguments as appropriate.
+func (l *methodListener) popComposite(value reflect.Value) {
+	l.curComposite = l.curComposite[:len(l.curComposite)-1]
+	if len(l.curComposite) == 0 {
+		l.args = append(l.args, value.Interface())
+	} else {
+		parent := l.curComposite[len(l.curComposite)-1]
+		parent.values = append(parent.values, value)
+	}
+}
+
 func (l *methodListener) EnterDomainArg(c *parser.DomainArgContext) {
 	if l.err == nil {
-		input := l.method.Inputs[l.curArg]
-		var err error
-		var arg interface{}
-		baseType := baseType(&input.Type)
-		switch baseType.T {
-		case abi.AddressTy:
-			arg, err = ens.Resolve(l.client, c.GetText()[1:])
-		default:
-			err = fmt.Errorf("unexpected type %v", baseType)
+		l.nameArg(c.GetText()[1:], c.GetText())
+	}
+}
+
+// nameArg handles a name.  Names for address arguments are resolved with ENS,
+// and names for integer arguments are enum values.
+func (l *methodListener) nameArg(name string, token string) {
+	argType, err := l.argType()
+	if err != nil {
+		l.err = l.argError(argType, token, err)
+		return
+	}
+	var arg interface{}
+	baseType := baseType(argType)
+	switch baseType.T {
+	case abi.AddressTy:
+		arg, err = resolveAddress(l.client, name)
+	case abi.IntTy, abi.UintTy:
+		if !isEnumValue(name) {
+			err = errors.New("expected a number or an enum value")
+			break
 		}
+		var value string
+		value, err = enumValue(l.contract.Enums, name)
 		if err != nil {
-			l.err = err
+			break
+		}
+		if baseType.T == abi.IntTy {
+			arg, err = StrToInt(baseType, value)
 		} else {
-			l.pushArg(arg)
+			arg, err = StrToUint(baseType, value)
 		}
+	default:
+		err = fmt.Errorf("unexpected name for type %v", baseType)
+	}
+	if err != nil {
+		l.err = l.argError(baseType, token, err)
+	} else {
+		l.pushArg(arg, token)
 	}
 }
 
 func (l *methodListener) EnterHexArg(c *parser.HexArgContext) {
 	if l.err == nil {
-		input := l.method.Inputs[l.curArg]
-		var err error
+		argType, err := l.argType()
+		if err != nil {
+			l.err = l.argError(argType, c.GetText(), err)
+			return
+		}
 		var arg interface{}
-		baseType := baseType(&input.Type)
+		baseType := baseType(argType)
 		switch baseType.T {
 		case abi.AddressTy:
 			arg, err = StrToAddress(baseType, c.GetText())
@@ -279,9 +426,9 @@ func (l *methodListener) EnterHexArg(c *parser.HexArgContext) {
 			err = fmt.Errorf("unexpected type %v", baseType)
 		}
 		if err != nil {
-			l.err = err
+			l.err = l.argError(baseType, c.GetText(), err)
 		} else {
-			l.pushArg(arg)
+			l.pushArg(arg, c.GetText())
 		}
 	}
 }
@@ -289,20 +436,54 @@ func (l *methodListener) EnterHexArg(c *parser.HexArgContext) {
 func (l *methodListener) EnterArg(c *parser.ArgContext) {
 	if l.err == nil {
 		if l.curArg >= len(l.method.Inputs) {
-			l.err = fmt.Errorf("too many arguments (expected %d)", len(l.method.Inputs))
+			l.err = l.tooManyArgs(c.GetText())
 		}
 	}
 }
 
 func (l *methodListener) ExitArg(c *parser.ArgContext) {
 	if l.err == nil {
-		// We only increment the argument if we aren't in an array
-		if len(l.curArray) == 0 {
+		// We only increment the argument if we aren't in an array or tuple
+		if len(l.curArray) == 0 && len(l.curComposite) == 0 {
 			l.curArg++
 		}
 	}
 }
 
+// ExitStart ensures that all of the method's arguments have been supplied.
+func (l *methodListener) ExitStart(c *parser.StartContext) {
+	if l.err == nil && l.method != nil && len(l.args) < len(l.method.Inputs) {
+		l.err = fmt.Errorf("too few arguments (expected %d, got %d)", len(l.method.Inputs), len(l.args))
+	}
+}
+
+// argError provides an error for the current argument, including its position,
+// its expected type and the text that failed to parse.
+func (l *methodListener) argError(argType *abi.Type, token string, err error) error {
+	return fmt.Errorf("argument %d of type %s at %s: %v", l.curArg, argType.String(), token, err)
+}
+
+// tooManyArgs provides an error for an argument beyond those of the method.
+func (l *methodListener) tooManyArgs(token string) error {
+	return fmt.Errorf("argument %d at %s: too many arguments (expected %d)", l.curArg, token, len(l.method.Inputs))
+}
+
+// argType returns the type of the next value to be parsed.  If the value
+// cannot be placed the type of its container is returned alongside the error.
+func (l *methodListener) argType() (*abi.Type, error) {
+	if len(l.curComposite) == 0 {
+		return &l.method.Inputs[l.curArg].Type, nil
+	}
+	composite := l.curComposite[len(l.curComposite)-1]
+	if composite.t.T != abi.TupleTy {
+		return composite.t.Elem, nil
+	}
+	if len(composite.values) >= len(composite.t.TupleElems) {
+		return composite.t, fmt.Errorf("too many values for tuple (expected %d)", len(composite.t.TupleElems))
+	}
+	return composite.t.TupleElems[len(composite.values)], nil
+}
+
 func baseType(inputType *abi.Type) *abi.Type {
 	switch inputType.T {
 	case abi.SliceTy:
@@ -324,8 +505,21 @@ func arrayLevel(inputType *abi.Type) int {
 	}
 }
 
-func (l *methodListener) pushArg(arg interface{}) {
-	if len(l.curArray) == 0 {
+func (l *methodListener) pushArg(arg interface{}, token string) {
+	if len(l.curComposite) > 0 {
+		argType, err := l.argType()
+		if err != nil {
+			l.err = l.argError(argType, token, err)
+			return
+		}
+		value := reflect.ValueOf(arg)
+		if !value.Type().AssignableTo(reflectType(argType)) {
+			l.err = l.argError(argType, token, fmt.Errorf("unexpected value %v", arg))
+			return
+		}
+		composite := l.curComposite[len(l.curComposite)-1]
+		composite.values = append(composite.values, value)
+	} else if len(l.curArray) == 0 {
 		l.args = append(l.args, arg)
 	} else {
 		input := l.method.Inputs[l.curArg]
@@ -373,6 +567,56 @@ func (l *methodListener) pushArg(arg interface{}) {
 	}
 }
 
+// reflectType returns the Go type used to hold values of the given ABI type.
+func reflectType(inputType *abi.Type) reflect.Type {
+	switch inputType.T {
+	case abi.IntTy:
+		switch inputType.Size {
+		case 8:
+			return reflect.TypeOf(int8(0))
+		case 16:
+			return reflect.TypeOf(int16(0))
+		case 32:
+			return reflect.TypeOf(int32(0))
+		case 64:
+			return reflect.TypeOf(int64(0))
+		default:
+			return reflect.TypeOf(&big.Int{})
+		}
+	case abi.UintTy:
+		switch inputType.Size {
+		case 8:
+			return reflect.TypeOf(uint8(0))
+		case 16:
+			return reflect.TypeOf(uint16(0))
+		case 32:
+			return reflect.TypeOf(uint32(0))
+		case 64:
+			return reflect.TypeOf(uint64(0))
+		default:
+			return reflect.TypeOf(&big.Int{})
+		}
+	case abi.BoolTy:
+		return reflect.TypeOf(false)
+	case abi.StringTy:
+		return reflect.TypeOf("")
+	case abi.SliceTy:
+		return reflect.SliceOf(reflectType(inputType.Elem))
+	case abi.ArrayTy:
+		return reflect.ArrayOf(inputType.Size, reflectType(inputType.Elem))
+	case abi.TupleTy:
+		return inputType.TupleType
+	case abi.AddressTy:
+		return reflect.TypeOf(common.Address{})
+	case abi.HashTy:
+		return reflect.TypeOf(common.Hash{})
+	case abi.FixedBytesTy:
+		return reflect.ArrayOf(inputType.Size, reflect.TypeOf(byte(0)))
+	default:
+		return reflect.TypeOf([]byte{})
+	}
+}
+
 func makeArray(baseType *abi.Type, level int) (interface{}, error) {
 	if level == 2 {
 		switch baseType.T {
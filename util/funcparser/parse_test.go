@@ -30,6 +30,7 @@ func TestParse(t *testing.T) {
 		json   string
 		input  string
 		output interface{}
+		err    string
 	}{
 		{ // 0 - no parameters
 			json:  `{"contracts":{"Test.sol:Test":{"abi":"[{\"constant\":false,\"inputs\":[],\"name\":\"test\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]"}}}`,
@@ -154,12 +155,43 @@ func TestParse(t *testing.T) {
 			json:  `{"contracts":{"Test.sol:Test":{"abi":"[{\"inputs\":[{\"name\":\"arg1\",\"type\":\"uint256\"}],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"}]"}}}`,
 			input: `constructor(12345)`,
 		},
+		{ // 17 - fixed-size array of uint parameters
+			json:  `{"contracts":{"Test.sol:Test":{"abi":"[{\"constant\":false,\"inputs\":[{\"name\":\"arg1\",\"type\":\"uint256[2]\"}],\"name\":\"test\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]"}}}`,
+			input: `test([1,2])`,
+			output: []interface{}{[]*big.Int{
+				big.NewInt(1), big.NewInt(2),
+			}},
+		},
+		{ // 18 - fixed-size array of uint parameters with too many elements
+			json:  `{"contracts":{"Test.sol:Test":{"abi":"[{\"constant\":false,\"inputs\":[{\"name\":\"arg1\",\"type\":\"uint256[2]\"}],\"name\":\"test\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]"}}}`,
+			input: `test([1,2,3])`,
+			err:   "argument 0 of type uint256[2] at [1,2,3]: expected 2 elements, got 3",
+		},
+		{ // 19 - dynamic array of fixed-size arrays with too few elements
+			json:  `{"contracts":{"Test.sol:Test":{"abi":"[{\"constant\":false,\"inputs\":[{\"name\":\"arg1\",\"type\":\"bool\"},{\"name\":\"arg2\",\"type\":\"uint256[2][]\"}],\"name\":\"test\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]"}}}`,
+			input: `test(true,[[1,2],[3]])`,
+			err:   "argument 1 of type uint256[2] at [3]: expected 2 elements, got 1",
+		},
+		{ // 20 - integers in scientific notation and with digit separators
+			json:   `{"contracts":{"Test.sol:Test":{"abi":"[{\"constant\":false,\"inputs\":[{\"name\":\"arg1\",\"type\":\"uint256\"},{\"name\":\"arg2\",\"type\":\"uint256\"},{\"name\":\"arg3\",\"type\":\"int64\"},{\"name\":\"arg4\",\"type\":\"uint32\"}],\"name\":\"test\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]"}}}`,
+			input:  `test(1e18,1.5E18,-2_500e-2,1_000_000)`,
+			output: []interface{}{_bigInt("1000000000000000000"), _bigInt("1500000000000000000"), int64(-25), uint32(1000000)},
+		},
+		{ // 21 - integer in scientific notation that is not a whole number
+			json:  `{"contracts":{"Test.sol:Test":{"abi":"[{\"constant\":false,\"inputs\":[{\"name\":\"arg1\",\"type\":\"uint256\"}],\"name\":\"test\",\"outputs\":[],\"payable\":false,\"stateMutability\":\"nonpayable\",\"type\":\"function\"}]"}}}`,
+			input: `test(1e-3)`,
+			err:   "argument 0 of type uint256 at 1e-3: invalid unsigned integer 1e-3: not a whole number",
+		},
 	}
 
 	for i, test := range tests {
 		contract, err := util.ParseCombinedJSON(test.json, "Test")
 		require.Nil(t, err, fmt.Sprintf("failed to parse contract JSON at test %d", i))
 		_, args, err := ParseCall(nil, contract, test.input)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("incorrect error at test %d", i))
+			continue
+		}
 		assert.Nil(t, err, fmt.Sprintf("failed to parse call at test %d", i))
 		if test.output != nil {
 			assert.Equal(t, test.output, args, fmt.Sprintf("incorrect value at test %d", i))
@@ -167,7 +199,345 @@ func TestParse(t *testing.T) {
 	}
 }
 
+func _bigInt(input string) *big.Int {
+	val, _ := new(big.Int).SetString(input, 10)
+	return val
+}
+
 func _bytes(input string) []byte {
 	bytes, _ := hex.DecodeString(input)
 	return bytes
 }
+
+func TestParseTuple(t *testing.T) {
+	tests := []struct {
+		abi    string
+		input  string
+		packed string
+		err    string
+	}{
+		{ // 0 - static tuple
+			abi:    `[{"inputs":[{"components":[{"name":"owner","type":"address"},{"name":"value","type":"uint256"}],"name":"arg1","type":"tuple"}],"name":"test","outputs":[],"stateMutability":"nonpayable","type":"function"}]`,
+			input:  `test((0x5FfC014343cd971B7eb70732021E26C35B744cc4,42))`,
+			packed: "0000000000000000000000005ffc014343cd971b7eb70732021e26c35b744cc4000000000000000000000000000000000000000000000000000000000000002a",
+		},
+		{ // 1 - dynamic tuple
+			abi:    `[{"inputs":[{"components":[{"name":"owner","type":"address"},{"name":"value","type":"uint256"},{"name":"label","type":"string"}],"name":"arg1","type":"tuple"}],"name":"test","outputs":[],"stateMutability":"nonpayable","type":"function"}]`,
+			input:  `test((0x5FfC014343cd971B7eb70732021E26C35B744cc4,42,"name"))`,
+			packed: "00000000000000000000000000000000000000000000000000000000000000200000000000000000000000005ffc014343cd971b7eb70732021e26c35b744cc4000000000000000000000000000000000000000000000000000000000000002a000000000000000000000000000000000000000000000000000000000000006000000000000000000000000000000000000000000000000000000000000000046e616d6500000000000000000000000000000000000000000000000000000000",
+		},
+		{ // 2 - nested tuple
+			abi:    `[{"inputs":[{"components":[{"name":"a","type":"uint256"},{"components":[{"name":"b","type":"uint256"},{"name":"c","type":"uint8"}],"name":"inner","type":"tuple"}],"name":"arg1","type":"tuple"}],"name":"test","outputs":[],"stateMutability":"nonpayable","type":"function"}]`,
+			input:  `test((1,(2,3)))`,
+			packed: "000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000003",
+		},
+		{ // 3 - array of tuples
+			abi:    `[{"inputs":[{"components":[{"name":"a","type":"uint256"},{"name":"b","type":"bool"}],"name":"arg1","type":"tuple[]"}],"name":"test","outputs":[],"stateMutability":"nonpayable","type":"function"}]`,
+			input:  `test([(1,true),(2,false)])`,
+			packed: "000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000000",
+		},
+		{ // 4 - tuple containing an array, alongside a plain argument
+			abi:    `[{"inputs":[{"components":[{"name":"a","type":"uint256"},{"name":"b","type":"uint256[2]"}],"name":"arg1","type":"tuple"},{"name":"arg2","type":"bool"}],"name":"test","outputs":[],"stateMutability":"nonpayable","type":"function"}]`,
+			input:  `test((1,[2,3]),true)`,
+			packed: "0000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000030000000000000000000000000000000000000000000000000000000000000001",
+		},
+		{ // 5 - too few values
+			abi:   `[{"inputs":[{"components":[{"name":"a","type":"uint256"},{"name":"b","type":"bool"}],"name":"arg1","type":"tuple"}],"name":"test","outputs":[],"stateMutability":"nonpayable","type":"function"}]`,
+			input: `test((1))`,
+			err:   "argument 0 of type (uint256,bool) at (1): expected 2 values, got 1",
+		},
+		{ // 6 - too many values
+			abi:   `[{"inputs":[{"components":[{"name":"a","type":"uint256"},{"name":"b","type":"bool"}],"name":"arg1","type":"tuple"}],"name":"test","outputs":[],"stateMutability":"nonpayable","type":"function"}]`,
+			input: `test((1,true,false))`,
+			err:   "argument 0 of type (uint256,bool) at false: too many values for tuple (expected 2)",
+		},
+		{ // 7 - tuple for a non-tuple argument
+			abi:   `[{"inputs":[{"name":"arg1","type":"uint256"}],"name":"test","outputs":[],"stateMutability":"nonpayable","type":"function"}]`,
+			input: `test((1,2))`,
+			err:   "argument 0 of type uint256 at (1,2): unexpected tuple",
+		},
+	}
+
+	for i, test := range tests {
+		contract, err := util.ParseCombinedJSON(fmt.Sprintf(`{"contracts":{"Test.sol:Test":{"abi":%q}}}`, test.abi), "Test")
+		require.Nil(t, err, fmt.Sprintf("failed to parse contract JSON at test %d", i))
+		method, args, err := ParseCall(nil, contract, test.input)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("incorrect error at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed to parse call at test %d", i))
+		packed, err := method.Inputs.Pack(args...)
+		require.Nil(t, err, fmt.Sprintf("failed to pack arguments at test %d", i))
+		assert.Equal(t, test.packed, hex.EncodeToString(packed), fmt.Sprintf("incorrect encoding at test %d", i))
+	}
+}
+
+func TestParseSignedInts(t *testing.T) {
+	tests := []struct {
+		argType string
+		input   string
+		packed  string
+		err     string
+	}{
+		{ // 0 - minimum int8
+			argType: "int8",
+			input:   `test(-128)`,
+			packed:  "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff80",
+		},
+		{ // 1 - negative int8
+			argType: "int8",
+			input:   `test(-5)`,
+			packed:  "fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffb",
+		},
+		{ // 2 - maximum int8
+			argType: "int8",
+			input:   `test(127)`,
+			packed:  "000000000000000000000000000000000000000000000000000000000000007f",
+		},
+		{ // 3 - below minimum int8
+			argType: "int8",
+			input:   `test(-129)`,
+			err:     "argument 0 of type int8 at -129: integer -129 out of range for int8 (-128 to 127)",
+		},
+		{ // 4 - above maximum int8
+			argType: "int8",
+			input:   `test(128)`,
+			err:     "argument 0 of type int8 at 128: integer 128 out of range for int8 (-128 to 127)",
+		},
+		{ // 5 - minimum int16
+			argType: "int16",
+			input:   `test(-32768)`,
+			packed:  "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff8000",
+		},
+		{ // 6 - negative int16
+			argType: "int16",
+			input:   `test(-5)`,
+			packed:  "fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffb",
+		},
+		{ // 7 - maximum int16
+			argType: "int16",
+			input:   `test(32767)`,
+			packed:  "0000000000000000000000000000000000000000000000000000000000007fff",
+		},
+		{ // 8 - below minimum int16
+			argType: "int16",
+			input:   `test(-32769)`,
+			err:     "argument 0 of type int16 at -32769: integer -32769 out of range for int16 (-32768 to 32767)",
+		},
+		{ // 9 - above maximum int16
+			argType: "int16",
+			input:   `test(32768)`,
+			err:     "argument 0 of type int16 at 32768: integer 32768 out of range for int16 (-32768 to 32767)",
+		},
+		{ // 10 - minimum int24
+			argType: "int24",
+			input:   `test(-8388608)`,
+			packed:  "ffffffffffffffffffffffffffffffffffffffffffffffffffffffffff800000",
+		},
+		{ // 11 - negative int24
+			argType: "int24",
+			input:   `test(-5)`,
+			packed:  "fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffb",
+		},
+		{ // 12 - maximum int24
+			argType: "int24",
+			input:   `test(8388607)`,
+			packed:  "00000000000000000000000000000000000000000000000000000000007fffff",
+		},
+		{ // 13 - below minimum int24
+			argType: "int24",
+			input:   `test(-8388609)`,
+			err:     "argument 0 of type int24 at -8388609: integer -8388609 out of range for int24 (-8388608 to 8388607)",
+		},
+		{ // 14 - above maximum int24
+			argType: "int24",
+			input:   `test(8388608)`,
+			err:     "argument 0 of type int24 at 8388608: integer 8388608 out of range for int24 (-8388608 to 8388607)",
+		},
+		{ // 15 - minimum int32
+			argType: "int32",
+			input:   `test(-2147483648)`,
+			packed:  "ffffffffffffffffffffffffffffffffffffffffffffffffffffffff80000000",
+		},
+		{ // 16 - negative int32
+			argType: "int32",
+			input:   `test(-5)`,
+			packed:  "fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffb",
+		},
+		{ // 17 - maximum int32
+			argType: "int32",
+			input:   `test(2147483647)`,
+			packed:  "000000000000000000000000000000000000000000000000000000007fffffff",
+		},
+		{ // 18 - below minimum int32
+			argType: "int32",
+			input:   `test(-2147483649)`,
+			err:     "argument 0 of type int32 at -2147483649: integer -2147483649 out of range for int32 (-2147483648 to 2147483647)",
+		},
+		{ // 19 - above maximum int32
+			argType: "int32",
+			input:   `test(2147483648)`,
+			err:     "argument 0 of type int32 at 2147483648: integer 2147483648 out of range for int32 (-2147483648 to 2147483647)",
+		},
+		{ // 20 - minimum int64
+			argType: "int64",
+			input:   `test(-9223372036854775808)`,
+			packed:  "ffffffffffffffffffffffffffffffffffffffffffffffff8000000000000000",
+		},
+		{ // 21 - negative int64
+			argType: "int64",
+			input:   `test(-5)`,
+			packed:  "fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffb",
+		},
+		{ // 22 - maximum int64
+			argType: "int64",
+			input:   `test(9223372036854775807)`,
+			packed:  "0000000000000000000000000000000000000000000000007fffffffffffffff",
+		},
+		{ // 23 - below minimum int64
+			argType: "int64",
+			input:   `test(-9223372036854775809)`,
+			err:     "argument 0 of type int64 at -9223372036854775809: integer -9223372036854775809 out of range for int64 (-9223372036854775808 to 9223372036854775807)",
+		},
+		{ // 24 - above maximum int64
+			argType: "int64",
+			input:   `test(9223372036854775808)`,
+			err:     "argument 0 of type int64 at 9223372036854775808: integer 9223372036854775808 out of range for int64 (-9223372036854775808 to 9223372036854775807)",
+		},
+		{ // 25 - minimum int128
+			argType: "int128",
+			input:   `test(-170141183460469231731687303715884105728)`,
+			packed:  "ffffffffffffffffffffffffffffffff80000000000000000000000000000000",
+		},
+		{ // 26 - negative int128
+			argType: "int128",
+			input:   `test(-5)`,
+			packed:  "fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffb",
+		},
+		{ // 27 - maximum int128
+			argType: "int128",
+			input:   `test(170141183460469231731687303715884105727)`,
+			packed:  "000000000000000000000000000000007fffffffffffffffffffffffffffffff",
+		},
+		{ // 28 - below minimum int128
+			argType: "int128",
+			input:   `test(-170141183460469231731687303715884105729)`,
+			err:     "argument 0 of type int128 at -170141183460469231731687303715884105729: integer -170141183460469231731687303715884105729 out of range for int128 (-170141183460469231731687303715884105728 to 170141183460469231731687303715884105727)",
+		},
+		{ // 29 - above maximum int128
+			argType: "int128",
+			input:   `test(170141183460469231731687303715884105728)`,
+			err:     "argument 0 of type int128 at 170141183460469231731687303715884105728: integer 170141183460469231731687303715884105728 out of range for int128 (-170141183460469231731687303715884105728 to 170141183460469231731687303715884105727)",
+		},
+		{ // 30 - minimum int256
+			argType: "int256",
+			input:   `test(-57896044618658097711785492504343953926634992332820282019728792003956564819968)`,
+			packed:  "8000000000000000000000000000000000000000000000000000000000000000",
+		},
+		{ // 31 - negative int256
+			argType: "int256",
+			input:   `test(-5)`,
+			packed:  "fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffb",
+		},
+		{ // 32 - maximum int256
+			argType: "int256",
+			input:   `test(57896044618658097711785492504343953926634992332820282019728792003956564819967)`,
+			packed:  "7fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+		},
+		{ // 33 - below minimum int256
+			argType: "int256",
+			input:   `test(-57896044618658097711785492504343953926634992332820282019728792003956564819969)`,
+			err:     "argument 0 of type int256 at -57896044618658097711785492504343953926634992332820282019728792003956564819969: integer -57896044618658097711785492504343953926634992332820282019728792003956564819969 out of range for int256 (-57896044618658097711785492504343953926634992332820282019728792003956564819968 to 57896044618658097711785492504343953926634992332820282019728792003956564819967)",
+		},
+		{ // 34 - above maximum int256
+			argType: "int256",
+			input:   `test(57896044618658097711785492504343953926634992332820282019728792003956564819968)`,
+			err:     "argument 0 of type int256 at 57896044618658097711785492504343953926634992332820282019728792003956564819968: integer 57896044618658097711785492504343953926634992332820282019728792003956564819968 out of range for int256 (-57896044618658097711785492504343953926634992332820282019728792003956564819968 to 57896044618658097711785492504343953926634992332820282019728792003956564819967)",
+		},
+		{ // 35 - array of negative int8
+			argType: "int8[]",
+			input:   `test([-128,-1,127])`,
+			packed:  "00000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000003ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff80ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff000000000000000000000000000000000000000000000000000000000000007f",
+		},
+		{ // 36 - nested array of negative int16
+			argType: "int16[][]",
+			input:   `test([[-1,2],[-32768]])`,
+			packed:  "00000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000a00000000000000000000000000000000000000000000000000000000000000002ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff00000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000001ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff8000",
+		},
+		{ // 37 - nested array of negative int256
+			argType: "int256[][]",
+			input:   `test([[-5],[-1,1]])`,
+			packed:  "00000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000000800000000000000000000000000000000000000000000000000000000000000001fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffb0000000000000000000000000000000000000000000000000000000000000002ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff0000000000000000000000000000000000000000000000000000000000000001",
+		},
+		{ // 38 - out of range value in nested array
+			argType: "int16[][]",
+			input:   `test([[-1],[-32769]])`,
+			err:     "argument 0 of type int16 at -32769: integer -32769 out of range for int16 (-32768 to 32767)",
+		},
+	}
+
+	for i, test := range tests {
+		abi := fmt.Sprintf(`[{"inputs":[{"name":"arg1","type":"%s"}],"name":"test","outputs":[],"stateMutability":"nonpayable","type":"function"}]`, test.argType)
+		contract, err := util.ParseCombinedJSON(fmt.Sprintf(`{"contracts":{"Test.sol:Test":{"abi":%q}}}`, abi), "Test")
+		require.Nil(t, err, fmt.Sprintf("failed to parse contract JSON at test %d", i))
+		method, args, err := ParseCall(nil, contract, test.input)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("incorrect error at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed to parse call at test %d", i))
+		packed, err := method.Inputs.Pack(args...)
+		require.Nil(t, err, fmt.Sprintf("failed to pack arguments at test %d", i))
+		assert.Equal(t, test.packed, hex.EncodeToString(packed), fmt.Sprintf("incorrect encoding at test %d", i))
+	}
+}
+
+func TestParseArgumentCount(t *testing.T) {
+	abi := `[{"inputs":[{"name":"arg1","type":"uint256[][]"},{"name":"arg2","type":"bool[2][]"}],"name":"test","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{ // 0 - correct number of arguments
+			input: `test([[1,2],[3]],[[true,false]])`,
+		},
+		{ // 1 - no arguments
+			input: `test()`,
+			err:   "too few arguments (expected 2, got 0)",
+		},
+		{ // 2 - nested array only
+			input: `test([[1,2],[3]])`,
+			err:   "too few arguments (expected 2, got 1)",
+		},
+		{ // 3 - additional plain argument
+			input: `test([[1,2],[3]],[[true,false]],5)`,
+			err:   "argument 2 at 5: too many arguments (expected 2)",
+		},
+		{ // 4 - additional nested array argument
+			input: `test([[1,2],[3]],[[true,false]],[[4]])`,
+			err:   "argument 2 at [[4]]: too many arguments (expected 2)",
+		},
+		{ // 5 - bad value inside nested array
+			input: `test([[1,-2]],[[true,false]])`,
+			err:   "argument 0 of type uint256 at -2: invalid unsigned integer -2",
+		},
+		{ // 6 - wrong number of elements inside nested array
+			input: `test([[1,2]],[[true,false],[true]])`,
+			err:   "argument 1 of type bool[2] at [true]: expected 2 elements, got 1",
+		},
+	}
+
+	for i, test := range tests {
+		contract, err := util.ParseCombinedJSON(fmt.Sprintf(`{"contracts":{"Test.sol:Test":{"abi":%q}}}`, abi), "Test")
+		require.Nil(t, err, fmt.Sprintf("failed to parse contract JSON at test %d", i))
+		method, args, err := ParseCall(nil, contract, test.input)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("incorrect error at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed to parse call at test %d", i))
+		_, err = method.Inputs.Pack(args...)
+		require.Nil(t, err, fmt.Sprintf("failed to pack arguments at test %d", i))
+	}
+}
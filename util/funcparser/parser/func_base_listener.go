@@ -80,3 +80,9 @@ func (s *BaseFuncListener) EnterArrayArg(ctx *ArrayArgContext) {}
 
 // ExitArrayArg is called when production arrayArg is exited.
 func (s *BaseFuncListener) ExitArrayArg(ctx *ArrayArgContext) {}
+
+// EnterTupleArg is called when production tupleArg is entered.
+func (s *BaseFuncListener) EnterTupleArg(ctx *TupleArgContext) {}
+
+// ExitTupleArg is called when production tupleArg is exited.
+func (s *BaseFuncListener) ExitTupleArg(ctx *TupleArgContext) {}
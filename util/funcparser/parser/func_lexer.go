@@ -14,89 +14,107 @@ var _ = fmt.Printf
 var _ = unicode.IsLetter
 
 var serializedLexerAtn = []uint16{
-	3, 24715, 42794, 33075, 47597, 16764, 15335, 30598, 22884, 2, 17, 182,
+	3, 24715, 42794, 33075, 47597, 16764, 15335, 30598, 22884, 2, 17, 212,
 	8, 1, 4, 2, 9, 2, 4, 3, 9, 3, 4, 4, 9, 4, 4, 5, 9, 5, 4, 6, 9, 6, 4, 7,
 	9, 7, 4, 8, 9, 8, 4, 9, 9, 9, 4, 10, 9, 10, 4, 11, 9, 11, 4, 12, 9, 12,
-	4, 13, 9, 13, 4, 14, 9, 14, 4, 15, 9, 15, 4, 16, 9, 16, 4, 17, 9, 17, 4,
-	18, 9, 18, 4, 19, 9, 19, 4, 20, 9, 20, 4, 21, 9, 21, 4, 22, 9, 22, 4, 23,
-	9, 23, 4, 24, 9, 24, 4, 25, 9, 25, 4, 26, 9, 26, 3, 2, 3, 2, 3, 3, 3, 3,
-	3, 4, 3, 4, 3, 5, 3, 5, 3, 6, 3, 6, 3, 6, 3, 6, 3, 6, 3, 7, 3, 7, 3, 7,
-	3, 7, 3, 7, 3, 7, 3, 8, 3, 8, 3, 9, 3, 9, 3, 10, 3, 10, 7, 10, 79, 10,
-	10, 12, 10, 14, 10, 82, 11, 10, 3, 11, 6, 11, 85, 10, 11, 13, 11, 14, 11,
-	86, 3, 12, 3, 12, 3, 12, 3, 12, 6, 12, 93, 10, 12, 13, 12, 14, 12, 94,
-	3, 13, 3, 13, 7, 13, 99, 10, 13, 12, 13, 14, 13, 102, 11, 13, 3, 13, 3,
-	13, 3, 13, 7, 13, 107, 10, 13, 12, 13, 14, 13, 110, 11, 13, 3, 13, 5, 13,
-	113, 10, 13, 3, 14, 3, 14, 5, 14, 117, 10, 14, 3, 15, 3, 15, 6, 15, 121,
-	10, 15, 13, 15, 14, 15, 122, 3, 16, 3, 16, 3, 17, 3, 17, 3, 17, 3, 17,
-	3, 17, 3, 17, 3, 17, 3, 17, 5, 17, 135, 10, 17, 3, 18, 3, 18, 3, 18, 3,
-	18, 3, 18, 3, 18, 3, 18, 3, 18, 3, 18, 3, 18, 5, 18, 147, 10, 18, 3, 19,
-	3, 19, 3, 19, 5, 19, 152, 10, 19, 3, 20, 3, 20, 3, 20, 5, 20, 157, 10,
-	20, 3, 21, 3, 21, 5, 21, 161, 10, 21, 3, 22, 3, 22, 3, 22, 5, 22, 166,
-	10, 22, 3, 23, 3, 23, 3, 24, 3, 24, 5, 24, 172, 10, 24, 3, 25, 3, 25, 3,
-	26, 6, 26, 177, 10, 26, 13, 26, 14, 26, 178, 3, 26, 3, 26, 2, 2, 27, 3,
-	3, 5, 4, 7, 5, 9, 6, 11, 7, 13, 8, 15, 9, 17, 10, 19, 11, 21, 12, 23, 13,
-	25, 14, 27, 15, 29, 16, 31, 2, 33, 2, 35, 2, 37, 2, 39, 2, 41, 2, 43, 2,
-	45, 2, 47, 2, 49, 2, 51, 17, 3, 2, 10, 4, 2, 43, 43, 46, 46, 5, 2, 50,
-	59, 67, 92, 99, 124, 6, 2, 12, 12, 15, 15, 36, 36, 94, 94, 6, 2, 12, 12,
-	15, 15, 41, 41, 94, 94, 4, 2, 38, 38, 97, 97, 4, 2, 67, 72, 99, 104, 4,
-	2, 67, 92, 99, 124, 5, 2, 11, 12, 14, 15, 34, 34, 2, 188, 2, 3, 3, 2, 2,
-	2, 2, 5, 3, 2, 2, 2, 2, 7, 3, 2, 2, 2, 2, 9, 3, 2, 2, 2, 2, 11, 3, 2, 2,
-	2, 2, 13, 3, 2, 2, 2, 2, 15, 3, 2, 2, 2, 2, 17, 3, 2, 2, 2, 2, 19, 3, 2,
-	2, 2, 2, 21, 3, 2, 2, 2, 2, 23, 3, 2, 2, 2, 2, 25, 3, 2, 2, 2, 2, 27, 3,
-	2, 2, 2, 2, 29, 3, 2, 2, 2, 2, 51, 3, 2, 2, 2, 3, 53, 3, 2, 2, 2, 5, 55,
-	3, 2, 2, 2, 7, 57, 3, 2, 2, 2, 9, 59, 3, 2, 2, 2, 11, 61, 3, 2, 2, 2, 13,
-	66, 3, 2, 2, 2, 15, 72, 3, 2, 2, 2, 17, 74, 3, 2, 2, 2, 19, 76, 3, 2, 2,
-	2, 21, 84, 3, 2, 2, 2, 23, 88, 3, 2, 2, 2, 25, 112, 3, 2, 2, 2, 27, 116,
-	3, 2, 2, 2, 29, 118, 3, 2, 2, 2, 31, 124, 3, 2, 2, 2, 33, 134, 3, 2, 2,
-	2, 35, 146, 3, 2, 2, 2, 37, 151, 3, 2, 2, 2, 39, 156, 3, 2, 2, 2, 41, 160,
-	3, 2, 2, 2, 43, 165, 3, 2, 2, 2, 45, 167, 3, 2, 2, 2, 47, 171, 3, 2, 2,
-	2, 49, 173, 3, 2, 2, 2, 51, 176, 3, 2, 2, 2, 53, 54, 7, 42, 2, 2, 54, 4,
-	3, 2, 2, 2, 55, 56, 7, 43, 2, 2, 56, 6, 3, 2, 2, 2, 57, 58, 7, 46, 2, 2,
-	58, 8, 3, 2, 2, 2, 59, 60, 7, 47, 2, 2, 60, 10, 3, 2, 2, 2, 61, 62, 7,
-	118, 2, 2, 62, 63, 7, 116, 2, 2, 63, 64, 7, 119, 2, 2, 64, 65, 7, 103,
-	2, 2, 65, 12, 3, 2, 2, 2, 66, 67, 7, 104, 2, 2, 67, 68, 7, 99, 2, 2, 68,
-	69, 7, 110, 2, 2, 69, 70, 7, 117, 2, 2, 70, 71, 7, 103, 2, 2, 71, 14, 3,
-	2, 2, 2, 72, 73, 7, 93, 2, 2, 73, 16, 3, 2, 2, 2, 74, 75, 7, 95, 2, 2,
-	75, 18, 3, 2, 2, 2, 76, 80, 5, 41, 21, 2, 77, 79, 5, 43, 22, 2, 78, 77,
-	3, 2, 2, 2, 79, 82, 3, 2, 2, 2, 80, 78, 3, 2, 2, 2, 80, 81, 3, 2, 2, 2,
-	81, 20, 3, 2, 2, 2, 82, 80, 3, 2, 2, 2, 83, 85, 5, 45, 23, 2, 84, 83, 3,
-	2, 2, 2, 85, 86, 3, 2, 2, 2, 86, 84, 3, 2, 2, 2, 86, 87, 3, 2, 2, 2, 87,
-	22, 3, 2, 2, 2, 88, 89, 7, 50, 2, 2, 89, 90, 7, 122, 2, 2, 90, 92, 3, 2,
-	2, 2, 91, 93, 5, 47, 24, 2, 92, 91, 3, 2, 2, 2, 93, 94, 3, 2, 2, 2, 94,
-	92, 3, 2, 2, 2, 94, 95, 3, 2, 2, 2, 95, 24, 3, 2, 2, 2, 96, 100, 7, 36,
-	2, 2, 97, 99, 5, 37, 19, 2, 98, 97, 3, 2, 2, 2, 99, 102, 3, 2, 2, 2, 100,
-	98, 3, 2, 2, 2, 100, 101, 3, 2, 2, 2, 101, 103, 3, 2, 2, 2, 102, 100, 3,
-	2, 2, 2, 103, 113, 7, 36, 2, 2, 104, 108, 7, 41, 2, 2, 105, 107, 5, 39,
-	20, 2, 106, 105, 3, 2, 2, 2, 107, 110, 3, 2, 2, 2, 108, 106, 3, 2, 2, 2,
-	108, 109, 3, 2, 2, 2, 109, 111, 3, 2, 2, 2, 110, 108, 3, 2, 2, 2, 111,
-	113, 7, 41, 2, 2, 112, 96, 3, 2, 2, 2, 112, 104, 3, 2, 2, 2, 113, 26, 3,
-	2, 2, 2, 114, 117, 5, 33, 17, 2, 115, 117, 5, 35, 18, 2, 116, 114, 3, 2,
-	2, 2, 116, 115, 3, 2, 2, 2, 117, 28, 3, 2, 2, 2, 118, 120, 7, 66, 2, 2,
-	119, 121, 10, 2, 2, 2, 120, 119, 3, 2, 2, 2, 121, 122, 3, 2, 2, 2, 122,
-	120, 3, 2, 2, 2, 122, 123, 3, 2, 2, 2, 123, 30, 3, 2, 2, 2, 124, 125, 9,
-	3, 2, 2, 125, 32, 3, 2, 2, 2, 126, 127, 7, 118, 2, 2, 127, 128, 7, 116,
-	2, 2, 128, 129, 7, 119, 2, 2, 129, 135, 7, 103, 2, 2, 130, 131, 7, 86,
-	2, 2, 131, 132, 7, 116, 2, 2, 132, 133, 7, 119, 2, 2, 133, 135, 7, 103,
-	2, 2, 134, 126, 3, 2, 2, 2, 134, 130, 3, 2, 2, 2, 135, 34, 3, 2, 2, 2,
-	136, 137, 7, 104, 2, 2, 137, 138, 7, 99, 2, 2, 138, 139, 7, 110, 2, 2,
-	139, 140, 7, 117, 2, 2, 140, 147, 7, 103, 2, 2, 141, 142, 7, 72, 2, 2,
-	142, 143, 7, 99, 2, 2, 143, 144, 7, 110, 2, 2, 144, 145, 7, 117, 2, 2,
-	145, 147, 7, 103, 2, 2, 146, 136, 3, 2, 2, 2, 146, 141, 3, 2, 2, 2, 147,
-	36, 3, 2, 2, 2, 148, 152, 10, 4, 2, 2, 149, 150, 7, 94, 2, 2, 150, 152,
-	11, 2, 2, 2, 151, 148, 3, 2, 2, 2, 151, 149, 3, 2, 2, 2, 152, 38, 3, 2,
-	2, 2, 153, 157, 10, 5, 2, 2, 154, 155, 7, 94, 2, 2, 155, 157, 11, 2, 2,
-	2, 156, 153, 3, 2, 2, 2, 156, 154, 3, 2, 2, 2, 157, 40, 3, 2, 2, 2, 158,
-	161, 5, 49, 25, 2, 159, 161, 9, 6, 2, 2, 160, 158, 3, 2, 2, 2, 160, 159,
-	3, 2, 2, 2, 161, 42, 3, 2, 2, 2, 162, 166, 5, 49, 25, 2, 163, 166, 9, 6,
-	2, 2, 164, 166, 5, 45, 23, 2, 165, 162, 3, 2, 2, 2, 165, 163, 3, 2, 2,
-	2, 165, 164, 3, 2, 2, 2, 166, 44, 3, 2, 2, 2, 167, 168, 4, 50, 59, 2, 168,
-	46, 3, 2, 2, 2, 169, 172, 5, 45, 23, 2, 170, 172, 9, 7, 2, 2, 171, 169,
-	3, 2, 2, 2, 171, 170, 3, 2, 2, 2, 172, 48, 3, 2, 2, 2, 173, 174, 9, 8,
-	2, 2, 174, 50, 3, 2, 2, 2, 175, 177, 9, 9, 2, 2, 176, 175, 3, 2, 2, 2,
-	177, 178, 3, 2, 2, 2, 178, 176, 3, 2, 2, 2, 178, 179, 3, 2, 2, 2, 179,
-	180, 3, 2, 2, 2, 180, 181, 8, 26, 2, 2, 181, 52, 3, 2, 2, 2, 19, 2, 80,
-	86, 94, 100, 108, 112, 116, 122, 134, 146, 151, 156, 160, 165, 171, 178,
-	3, 8, 2, 2,
+	4, 13, 9, 13, 4, 14, 9, 14, 4, 15, 9, 15, 4, 16, 9, 16, 4, 17, 9, 17,
+	4, 18, 9, 18, 4, 19, 9, 19, 4, 20, 9, 20, 4, 21, 9, 21, 4, 22, 9, 22,
+	4, 23, 9, 23, 4, 24, 9, 24, 4, 25, 9, 25, 4, 26, 9, 26, 3, 2, 3, 2, 3,
+	3, 3, 3, 3, 4, 3, 4, 3, 5, 3, 5, 3, 6, 3, 6, 3, 6, 3, 6, 3, 6, 3, 7, 3,
+	7, 3, 7, 3, 7, 3, 7, 3, 7, 3, 8, 3, 8, 3, 9, 3, 9, 3, 10, 3, 10, 7, 10,
+	79, 10, 10, 12, 10, 14, 10, 82, 11, 10, 3, 11, 6, 11, 85, 10, 11, 13,
+	11, 14, 11, 86, 3, 11, 3, 11, 6, 11, 91, 10, 11, 13, 11, 14, 11, 92, 7,
+	11, 95, 10, 11, 12, 11, 14, 11, 98, 11, 11, 3, 11, 3, 11, 6, 11, 102,
+	10, 11, 13, 11, 14, 11, 103, 5, 11, 106, 10, 11, 3, 11, 3, 11, 5, 11,
+	110, 10, 11, 3, 11, 6, 11, 113, 10, 11, 13, 11, 14, 11, 114, 5, 11,
+	117, 10, 11, 3, 12, 3, 12, 3, 12, 3, 12, 6, 12, 123, 10, 12, 13, 12,
+	14, 12, 124, 3, 13, 3, 13, 7, 13, 129, 10, 13, 12, 13, 14, 13, 132, 11,
+	13, 3, 13, 3, 13, 3, 13, 7, 13, 137, 10, 13, 12, 13, 14, 13, 140, 11,
+	13, 3, 13, 5, 13, 143, 10, 13, 3, 14, 3, 14, 5, 14, 147, 10, 14, 3, 15,
+	3, 15, 6, 15, 151, 10, 15, 13, 15, 14, 15, 152, 3, 16, 3, 16, 3, 17, 3,
+	17, 3, 17, 3, 17, 3, 17, 3, 17, 3, 17, 3, 17, 5, 17, 165, 10, 17, 3,
+	18, 3, 18, 3, 18, 3, 18, 3, 18, 3, 18, 3, 18, 3, 18, 3, 18, 3, 18, 5,
+	18, 177, 10, 18, 3, 19, 3, 19, 3, 19, 5, 19, 182, 10, 19, 3, 20, 3, 20,
+	3, 20, 5, 20, 187, 10, 20, 3, 21, 3, 21, 5, 21, 191, 10, 21, 3, 22, 3,
+	22, 3, 22, 5, 22, 196, 10, 22, 3, 23, 3, 23, 3, 24, 3, 24, 5, 24, 202,
+	10, 24, 3, 25, 3, 25, 3, 26, 6, 26, 207, 10, 26, 13, 26, 14, 26, 208,
+	3, 26, 3, 26, 2, 2, 27, 3, 3, 5, 4, 7, 5, 9, 6, 11, 7, 13, 8, 15, 9,
+	17, 10, 19, 11, 21, 12, 23, 13, 25, 14, 27, 15, 29, 16, 31, 2, 33, 2,
+	35, 2, 37, 2, 39, 2, 41, 2, 43, 2, 45, 2, 47, 2, 49, 2, 51, 17, 3, 2,
+	11, 4, 2, 71, 71, 103, 103, 4, 2, 43, 43, 46, 46, 5, 2, 50, 59, 67, 92,
+	99, 124, 6, 2, 12, 12, 15, 15, 36, 36, 94, 94, 6, 2, 12, 12, 15, 15,
+	41, 41, 94, 94, 4, 2, 38, 38, 97, 97, 4, 2, 67, 72, 99, 104, 4, 2, 67,
+	92, 99, 124, 5, 2, 11, 12, 14, 15, 34, 34, 2, 225, 2, 3, 3, 2, 2, 2, 2,
+	5, 3, 2, 2, 2, 2, 7, 3, 2, 2, 2, 2, 9, 3, 2, 2, 2, 2, 11, 3, 2, 2, 2,
+	2, 13, 3, 2, 2, 2, 2, 15, 3, 2, 2, 2, 2, 17, 3, 2, 2, 2, 2, 19, 3, 2,
+	2, 2, 2, 21, 3, 2, 2, 2, 2, 23, 3, 2, 2, 2, 2, 25, 3, 2, 2, 2, 2, 27,
+	3, 2, 2, 2, 2, 29, 3, 2, 2, 2, 2, 51, 3, 2, 2, 2, 3, 53, 3, 2, 2, 2, 5,
+	55, 3, 2, 2, 2, 7, 57, 3, 2, 2, 2, 9, 59, 3, 2, 2, 2, 11, 61, 3, 2, 2,
+	2, 13, 66, 3, 2, 2, 2, 15, 72, 3, 2, 2, 2, 17, 74, 3, 2, 2, 2, 19, 76,
+	3, 2, 2, 2, 21, 84, 3, 2, 2, 2, 23, 118, 3, 2, 2, 2, 25, 142, 3, 2, 2,
+	2, 27, 146, 3, 2, 2, 2, 29, 148, 3, 2, 2, 2, 31, 154, 3, 2, 2, 2, 33,
+	164, 3, 2, 2, 2, 35, 176, 3, 2, 2, 2, 37, 181, 3, 2, 2, 2, 39, 186, 3,
+	2, 2, 2, 41, 190, 3, 2, 2, 2, 43, 195, 3, 2, 2, 2, 45, 197, 3, 2, 2, 2,
+	47, 201, 3, 2, 2, 2, 49, 203, 3, 2, 2, 2, 51, 206, 3, 2, 2, 2, 53, 54,
+	7, 42, 2, 2, 54, 4, 3, 2, 2, 2, 55, 56, 7, 43, 2, 2, 56, 6, 3, 2, 2, 2,
+	57, 58, 7, 46, 2, 2, 58, 8, 3, 2, 2, 2, 59, 60, 7, 47, 2, 2, 60, 10, 3,
+	2, 2, 2, 61, 62, 7, 118, 2, 2, 62, 63, 7, 116, 2, 2, 63, 64, 7, 119, 2,
+	2, 64, 65, 7, 103, 2, 2, 65, 12, 3, 2, 2, 2, 66, 67, 7, 104, 2, 2, 67,
+	68, 7, 99, 2, 2, 68, 69, 7, 110, 2, 2, 69, 70, 7, 117, 2, 2, 70, 71, 7,
+	103, 2, 2, 71, 14, 3, 2, 2, 2, 72, 73, 7, 93, 2, 2, 73, 16, 3, 2, 2, 2,
+	74, 75, 7, 95, 2, 2, 75, 18, 3, 2, 2, 2, 76, 80, 5, 41, 21, 2, 77, 79,
+	5, 43, 22, 2, 78, 77, 3, 2, 2, 2, 79, 82, 3, 2, 2, 2, 80, 78, 3, 2, 2,
+	2, 80, 81, 3, 2, 2, 2, 81, 20, 3, 2, 2, 2, 82, 80, 3, 2, 2, 2, 83, 85,
+	5, 45, 23, 2, 84, 83, 3, 2, 2, 2, 85, 86, 3, 2, 2, 2, 86, 84, 3, 2, 2,
+	2, 86, 87, 3, 2, 2, 2, 87, 96, 3, 2, 2, 2, 88, 90, 7, 97, 2, 2, 89, 91,
+	5, 45, 23, 2, 90, 89, 3, 2, 2, 2, 91, 92, 3, 2, 2, 2, 92, 90, 3, 2, 2,
+	2, 92, 93, 3, 2, 2, 2, 93, 95, 3, 2, 2, 2, 94, 88, 3, 2, 2, 2, 95, 98,
+	3, 2, 2, 2, 96, 94, 3, 2, 2, 2, 96, 97, 3, 2, 2, 2, 97, 105, 3, 2, 2,
+	2, 98, 96, 3, 2, 2, 2, 99, 101, 7, 48, 2, 2, 100, 102, 5, 45, 23, 2,
+	101, 100, 3, 2, 2, 2, 102, 103, 3, 2, 2, 2, 103, 101, 3, 2, 2, 2, 103,
+	104, 3, 2, 2, 2, 104, 106, 3, 2, 2, 2, 105, 99, 3, 2, 2, 2, 105, 106,
+	3, 2, 2, 2, 106, 116, 3, 2, 2, 2, 107, 109, 9, 2, 2, 2, 108, 110, 7,
+	47, 2, 2, 109, 108, 3, 2, 2, 2, 109, 110, 3, 2, 2, 2, 110, 112, 3, 2,
+	2, 2, 111, 113, 5, 45, 23, 2, 112, 111, 3, 2, 2, 2, 113, 114, 3, 2, 2,
+	2, 114, 112, 3, 2, 2, 2, 114, 115, 3, 2, 2, 2, 115, 117, 3, 2, 2, 2,
+	116, 107, 3, 2, 2, 2, 116, 117, 3, 2, 2, 2, 117, 22, 3, 2, 2, 2, 118,
+	119, 7, 50, 2, 2, 119, 120, 7, 122, 2, 2, 120, 122, 3, 2, 2, 2, 121,
+	123, 5, 47, 24, 2, 122, 121, 3, 2, 2, 2, 123, 124, 3, 2, 2, 2, 124,
+	122, 3, 2, 2, 2, 124, 125, 3, 2, 2, 2, 125, 24, 3, 2, 2, 2, 126, 130,
+	7, 36, 2, 2, 127, 129, 5, 37, 19, 2, 128, 127, 3, 2, 2, 2, 129, 132, 3,
+	2, 2, 2, 130, 128, 3, 2, 2, 2, 130, 131, 3, 2, 2, 2, 131, 133, 3, 2, 2,
+	2, 132, 130, 3, 2, 2, 2, 133, 143, 7, 36, 2, 2, 134, 138, 7, 41, 2, 2,
+	135, 137, 5, 39, 20, 2, 136, 135, 3, 2, 2, 2, 137, 140, 3, 2, 2, 2,
+	138, 136, 3, 2, 2, 2, 138, 139, 3, 2, 2, 2, 139, 141, 3, 2, 2, 2, 140,
+	138, 3, 2, 2, 2, 141, 143, 7, 41, 2, 2, 142, 126, 3, 2, 2, 2, 142, 134,
+	3, 2, 2, 2, 143, 26, 3, 2, 2, 2, 144, 147, 5, 33, 17, 2, 145, 147, 5,
+	35, 18, 2, 146, 144, 3, 2, 2, 2, 146, 145, 3, 2, 2, 2, 147, 28, 3, 2,
+	2, 2, 148, 150, 7, 66, 2, 2, 149, 151, 10, 3, 2, 2, 150, 149, 3, 2, 2,
+	2, 151, 152, 3, 2, 2, 2, 152, 150, 3, 2, 2, 2, 152, 153, 3, 2, 2, 2,
+	153, 30, 3, 2, 2, 2, 154, 155, 9, 4, 2, 2, 155, 32, 3, 2, 2, 2, 156,
+	157, 7, 118, 2, 2, 157, 158, 7, 116, 2, 2, 158, 159, 7, 119, 2, 2, 159,
+	165, 7, 103, 2, 2, 160, 161, 7, 86, 2, 2, 161, 162, 7, 116, 2, 2, 162,
+	163, 7, 119, 2, 2, 163, 165, 7, 103, 2, 2, 164, 156, 3, 2, 2, 2, 164,
+	160, 3, 2, 2, 2, 165, 34, 3, 2, 2, 2, 166, 167, 7, 104, 2, 2, 167, 168,
+	7, 99, 2, 2, 168, 169, 7, 110, 2, 2, 169, 170, 7, 117, 2, 2, 170, 177,
+	7, 103, 2, 2, 171, 172, 7, 72, 2, 2, 172, 173, 7, 99, 2, 2, 173, 174,
+	7, 110, 2, 2, 174, 175, 7, 117, 2, 2, 175, 177, 7, 103, 2, 2, 176, 166,
+	3, 2, 2, 2, 176, 171, 3, 2, 2, 2, 177, 36, 3, 2, 2, 2, 178, 182, 10, 5,
+	2, 2, 179, 180, 7, 94, 2, 2, 180, 182, 11, 2, 2, 2, 181, 178, 3, 2, 2,
+	2, 181, 179, 3, 2, 2, 2, 182, 38, 3, 2, 2, 2, 183, 187, 10, 6, 2, 2,
+	184, 185, 7, 94, 2, 2, 185, 187, 11, 2, 2, 2, 186, 183, 3, 2, 2, 2,
+	186, 184, 3, 2, 2, 2, 187, 40, 3, 2, 2, 2, 188, 191, 5, 49, 25, 2, 189,
+	191, 9, 7, 2, 2, 190, 188, 3, 2, 2, 2, 190, 189, 3, 2, 2, 2, 191, 42,
+	3, 2, 2, 2, 192, 196, 5, 49, 25, 2, 193, 196, 9, 7, 2, 2, 194, 196, 5,
+	45, 23, 2, 195, 192, 3, 2, 2, 2, 195, 193, 3, 2, 2, 2, 195, 194, 3, 2,
+	2, 2, 196, 44, 3, 2, 2, 2, 197, 198, 4, 50, 59, 2, 198, 46, 3, 2, 2, 2,
+	199, 202, 5, 45, 23, 2, 200, 202, 9, 8, 2, 2, 201, 199, 3, 2, 2, 2,
+	201, 200, 3, 2, 2, 2, 202, 48, 3, 2, 2, 2, 203, 204, 9, 9, 2, 2, 204,
+	50, 3, 2, 2, 2, 205, 207, 9, 10, 2, 2, 206, 205, 3, 2, 2, 2, 207, 208,
+	3, 2, 2, 2, 208, 206, 3, 2, 2, 2, 208, 209, 3, 2, 2, 2, 209, 210, 3, 2,
+	2, 2, 210, 211, 8, 26, 2, 2, 211, 52, 3, 2, 2, 2, 26, 2, 80, 86, 92,
+	96, 103, 105, 109, 114, 116, 124, 130, 138, 142, 146, 152, 164, 176,
+	181, 186, 190, 195, 201, 208, 3, 8, 2, 2,
 }
 
 var lexerDeserializer = antlr.NewATNDeserializer(nil)
@@ -38,6 +38,9 @@ type FuncListener interface {
 	// EnterArrayArg is called when entering the arrayArg production.
 	EnterArrayArg(c *ArrayArgContext)
 
+	// EnterTupleArg is called when entering the tupleArg production.
+	EnterTupleArg(c *TupleArgContext)
+
 	// ExitStart is called when exiting the start production.
 	ExitStart(c *StartContext)
 
@@ -67,4 +70,7 @@ type FuncListener interface {
 
 	// ExitArrayArg is called when exiting the arrayArg production.
 	ExitArrayArg(c *ArrayArgContext)
+
+	// ExitTupleArg is called when exiting the tupleArg production.
+	ExitTupleArg(c *TupleArgContext)
 }
@@ -16,32 +16,35 @@ var _ = reflect.Copy
 var _ = strconv.Itoa
 
 var parserATN = []uint16{
-	3, 24715, 42794, 33075, 47597, 16764, 15335, 30598, 22884, 3, 17, 66, 4,
-	2, 9, 2, 4, 3, 9, 3, 4, 4, 9, 4, 4, 5, 9, 5, 4, 6, 9, 6, 4, 7, 9, 7, 4,
-	8, 9, 8, 4, 9, 9, 9, 4, 10, 9, 10, 4, 11, 9, 11, 3, 2, 3, 2, 3, 2, 3, 2,
-	3, 2, 3, 2, 3, 3, 3, 3, 3, 4, 3, 4, 3, 4, 7, 4, 34, 10, 4, 12, 4, 14, 4,
-	37, 11, 4, 5, 4, 39, 10, 4, 3, 5, 3, 5, 3, 5, 3, 5, 3, 5, 3, 5, 5, 5, 47,
-	10, 5, 3, 6, 5, 6, 50, 10, 6, 3, 6, 3, 6, 3, 7, 3, 7, 3, 8, 3, 8, 3, 9,
-	3, 9, 3, 10, 3, 10, 3, 11, 3, 11, 3, 11, 3, 11, 3, 11, 2, 2, 12, 2, 4,
-	6, 8, 10, 12, 14, 16, 18, 20, 2, 3, 3, 2, 7, 8, 2, 63, 2, 22, 3, 2, 2,
-	2, 4, 28, 3, 2, 2, 2, 6, 38, 3, 2, 2, 2, 8, 46, 3, 2, 2, 2, 10, 49, 3,
-	2, 2, 2, 12, 53, 3, 2, 2, 2, 14, 55, 3, 2, 2, 2, 16, 57, 3, 2, 2, 2, 18,
-	59, 3, 2, 2, 2, 20, 61, 3, 2, 2, 2, 22, 23, 5, 4, 3, 2, 23, 24, 7, 3, 2,
-	2, 24, 25, 5, 6, 4, 2, 25, 26, 7, 4, 2, 2, 26, 27, 7, 2, 2, 3, 27, 3, 3,
-	2, 2, 2, 28, 29, 7, 11, 2, 2, 29, 5, 3, 2, 2, 2, 30, 35, 5, 8, 5, 2, 31,
-	32, 7, 5, 2, 2, 32, 34, 5, 8, 5, 2, 33, 31, 3, 2, 2, 2, 34, 37, 3, 2, 2,
-	2, 35, 33, 3, 2, 2, 2, 35, 36, 3, 2, 2, 2, 36, 39, 3, 2, 2, 2, 37, 35,
-	3, 2, 2, 2, 38, 30, 3, 2, 2, 2, 38, 39, 3, 2, 2, 2, 39, 7, 3, 2, 2, 2,
-	40, 47, 5, 10, 6, 2, 41, 47, 5, 12, 7, 2, 42, 47, 5, 14, 8, 2, 43, 47,
-	5, 16, 9, 2, 44, 47, 5, 18, 10, 2, 45, 47, 5, 20, 11, 2, 46, 40, 3, 2,
-	2, 2, 46, 41, 3, 2, 2, 2, 46, 42, 3, 2, 2, 2, 46, 43, 3, 2, 2, 2, 46, 44,
-	3, 2, 2, 2, 46, 45, 3, 2, 2, 2, 47, 9, 3, 2, 2, 2, 48, 50, 7, 6, 2, 2,
-	49, 48, 3, 2, 2, 2, 49, 50, 3, 2, 2, 2, 50, 51, 3, 2, 2, 2, 51, 52, 7,
-	12, 2, 2, 52, 11, 3, 2, 2, 2, 53, 54, 7, 13, 2, 2, 54, 13, 3, 2, 2, 2,
-	55, 56, 7, 14, 2, 2, 56, 15, 3, 2, 2, 2, 57, 58, 9, 2, 2, 2, 58, 17, 3,
-	2, 2, 2, 59, 60, 7, 16, 2, 2, 60, 19, 3, 2, 2, 2, 61, 62, 7, 9, 2, 2, 62,
-	63, 5, 6, 4, 2, 63, 64, 7, 10, 2, 2, 64, 21, 3, 2, 2, 2, 6, 35, 38, 46,
-	49,
+	3, 24715, 42794, 33075, 47597, 16764, 15335, 30598, 22884, 3, 17, 74,
+	4, 2, 9, 2, 4, 3, 9, 3, 4, 4, 9, 4, 4, 5, 9, 5, 4, 6, 9, 6, 4, 7, 9,
+	7, 4, 8, 9, 8, 4, 9, 9, 9, 4, 10, 9, 10, 4, 11, 9, 11, 4, 12, 9, 12,
+	3, 2, 3, 2, 3, 2, 3, 2, 3, 2, 3, 2, 3, 3, 3, 3, 3, 4, 3, 4, 3, 4, 7,
+	4, 36, 10, 4, 12, 4, 14, 4, 39, 11, 4, 5, 4, 41, 10, 4, 3, 5, 3, 5, 3,
+	5, 3, 5, 3, 5, 3, 5, 3, 5, 5, 5, 50, 10, 5, 3, 6, 5, 6, 53, 10, 6, 3,
+	6, 3, 6, 3, 7, 3, 7, 3, 8, 3, 8, 3, 9, 3, 9, 3, 10, 3, 10, 3, 11, 3,
+	11, 3, 11, 3, 11, 3, 11, 3, 12, 3, 12, 3, 12, 3, 12, 3, 12, 2, 2, 13,
+	2, 4, 6, 8, 10, 12, 14, 16, 18, 20, 22, 2, 3, 3, 2, 7, 8, 2, 70, 2,
+	24, 3, 2, 2, 2, 4, 30, 3, 2, 2, 2, 6, 40, 3, 2, 2, 2, 8, 49, 3, 2, 2,
+	2, 10, 52, 3, 2, 2, 2, 12, 56, 3, 2, 2, 2, 14, 58, 3, 2, 2, 2, 16, 60,
+	3, 2, 2, 2, 18, 62, 3, 2, 2, 2, 20, 64, 3, 2, 2, 2, 22, 69, 3, 2, 2,
+	2, 24, 25, 5, 4, 3, 2, 25, 26, 7, 3, 2, 2, 26, 27, 5, 6, 4, 2, 27, 28,
+	7, 4, 2, 2, 28, 29, 7, 2, 2, 3, 29, 3, 3, 2, 2, 2, 30, 31, 7, 11, 2,
+	2, 31, 5, 3, 2, 2, 2, 32, 37, 5, 8, 5, 2, 33, 34, 7, 5, 2, 2, 34, 36,
+	5, 8, 5, 2, 35, 33, 3, 2, 2, 2, 36, 39, 3, 2, 2, 2, 37, 35, 3, 2, 2,
+	2, 37, 38, 3, 2, 2, 2, 38, 41, 3, 2, 2, 2, 39, 37, 3, 2, 2, 2, 40, 32,
+	3, 2, 2, 2, 40, 41, 3, 2, 2, 2, 41, 7, 3, 2, 2, 2, 42, 50, 5, 10, 6,
+	2, 43, 50, 5, 12, 7, 2, 44, 50, 5, 14, 8, 2, 45, 50, 5, 16, 9, 2, 46,
+	50, 5, 18, 10, 2, 47, 50, 5, 20, 11, 2, 48, 50, 5, 22, 12, 2, 49, 42,
+	3, 2, 2, 2, 49, 43, 3, 2, 2, 2, 49, 44, 3, 2, 2, 2, 49, 45, 3, 2, 2,
+	2, 49, 46, 3, 2, 2, 2, 49, 47, 3, 2, 2, 2, 49, 48, 3, 2, 2, 2, 50, 9,
+	3, 2, 2, 2, 51, 53, 7, 6, 2, 2, 52, 51, 3, 2, 2, 2, 52, 53, 3, 2, 2,
+	2, 53, 54, 3, 2, 2, 2, 54, 55, 7, 12, 2, 2, 55, 11, 3, 2, 2, 2, 56,
+	57, 7, 13, 2, 2, 57, 13, 3, 2, 2, 2, 58, 59, 7, 14, 2, 2, 59, 15, 3,
+	2, 2, 2, 60, 61, 9, 2, 2, 2, 61, 17, 3, 2, 2, 2, 62, 63, 7, 16, 2, 2,
+	63, 19, 3, 2, 2, 2, 64, 65, 7, 9, 2, 2, 65, 66, 5, 6, 4, 2, 66, 67, 7,
+	10, 2, 2, 67, 21, 3, 2, 2, 2, 69, 70, 7, 3, 2, 2, 70, 71, 5, 6, 4, 2,
+	71, 72, 7, 4, 2, 2, 72, 23, 3, 2, 2, 2, 6, 37, 40, 49, 52,
 }
 var deserializer = antlr.NewATNDeserializer(nil)
 var deserializedATN = deserializer.DeserializeFromUInt16(parserATN)
@@ -56,7 +59,7 @@ var symbolicNames = []string{
 
 var ruleNames = []string{
 	"start", "funcName", "funcArgs", "arg", "intArg", "hexArg", "stringArg",
-	"boolArg", "domainArg", "arrayArg",
+	"boolArg", "domainArg", "arrayArg", "tupleArg",
 }
 var decisionToDFA = make([]*antlr.DFA, len(deserializedATN.DecisionToState))
 
@@ -116,6 +119,7 @@ const (
 	FuncParserRULE_boolArg   = 7
 	FuncParserRULE_domainArg = 8
 	FuncParserRULE_arrayArg  = 9
+	FuncParserRULE_tupleArg  = 10
 )
 
 // IStartContext is an interface to support dynamic dispatch.
@@ -222,23 +226,23 @@ func (p *FuncParser) Start() (localctx IStartContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(20)
+		p.SetState(22)
 		p.FuncName()
 	}
 	{
-		p.SetState(21)
+		p.SetState(23)
 		p.Match(FuncParserT__0)
 	}
 	{
-		p.SetState(22)
+		p.SetState(24)
 		p.FuncArgs()
 	}
 	{
-		p.SetState(23)
+		p.SetState(25)
 		p.Match(FuncParserT__1)
 	}
 	{
-		p.SetState(24)
+		p.SetState(26)
 		p.Match(FuncParserEOF)
 	}
 
@@ -329,7 +333,7 @@ func (p *FuncParser) FuncName() (localctx IFuncNameContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(26)
+		p.SetState(28)
 		p.Match(FuncParserNAME)
 	}
 
@@ -439,30 +443,30 @@ func (p *FuncParser) FuncArgs() (localctx IFuncArgsContext) {
 	}()
 
 	p.EnterOuterAlt(localctx, 1)
-	p.SetState(36)
+	p.SetState(38)
 	p.GetErrorHandler().Sync(p)
 	_la = p.GetTokenStream().LA(1)
 
-	if ((_la)&-(0x1f+1)) == 0 && ((1<<uint(_la))&((1<<FuncParserT__3)|(1<<FuncParserT__4)|(1<<FuncParserT__5)|(1<<FuncParserT__6)|(1<<FuncParserINT)|(1<<FuncParserHEX)|(1<<FuncParserSTRING)|(1<<FuncParserDOMAIN))) != 0 {
+	if ((_la)&-(0x1f+1)) == 0 && ((1<<uint(_la))&((1<<FuncParserT__0)|(1<<FuncParserT__3)|(1<<FuncParserT__4)|(1<<FuncParserT__5)|(1<<FuncParserT__6)|(1<<FuncParserINT)|(1<<FuncParserHEX)|(1<<FuncParserSTRING)|(1<<FuncParserDOMAIN))) != 0 {
 		{
-			p.SetState(28)
+			p.SetState(30)
 			p.Arg()
 		}
-		p.SetState(33)
+		p.SetState(35)
 		p.GetErrorHandler().Sync(p)
 		_la = p.GetTokenStream().LA(1)
 
 		for _la == FuncParserT__2 {
 			{
-				p.SetState(29)
+				p.SetState(31)
 				p.Match(FuncParserT__2)
 			}
 			{
-				p.SetState(30)
+				p.SetState(32)
 				p.Arg()
 			}
 
-			p.SetState(35)
+			p.SetState(37)
 			p.GetErrorHandler().Sync(p)
 			_la = p.GetTokenStream().LA(1)
 		}
@@ -570,6 +574,16 @@ func (s *ArgContext) ArrayArg() IArrayArgContext {
 	return t.(IArrayArgContext)
 }
 
+func (s *ArgContext) TupleArg() ITupleArgContext {
+	var t = s.GetTypedRuleContext(reflect.TypeOf((*ITupleArgContext)(nil)).Elem(), 0)
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(ITupleArgContext)
+}
+
 func (s *ArgContext) GetRuleContext() antlr.RuleContext {
 	return s
 }
@@ -610,52 +624,59 @@ func (p *FuncParser) Arg() (localctx IArgContext) {
 		}
 	}()
 
-	p.SetState(44)
+	p.SetState(47)
 	p.GetErrorHandler().Sync(p)
 
 	switch p.GetTokenStream().LA(1) {
 	case FuncParserT__3, FuncParserINT:
 		p.EnterOuterAlt(localctx, 1)
 		{
-			p.SetState(38)
+			p.SetState(40)
 			p.IntArg()
 		}
 
 	case FuncParserHEX:
 		p.EnterOuterAlt(localctx, 2)
 		{
-			p.SetState(39)
+			p.SetState(41)
 			p.HexArg()
 		}
 
 	case FuncParserSTRING:
 		p.EnterOuterAlt(localctx, 3)
 		{
-			p.SetState(40)
+			p.SetState(42)
 			p.StringArg()
 		}
 
 	case FuncParserT__4, FuncParserT__5:
 		p.EnterOuterAlt(localctx, 4)
 		{
-			p.SetState(41)
+			p.SetState(43)
 			p.BoolArg()
 		}
 
 	case FuncParserDOMAIN:
 		p.EnterOuterAlt(localctx, 5)
 		{
-			p.SetState(42)
+			p.SetState(44)
 			p.DomainArg()
 		}
 
 	case FuncParserT__6:
 		p.EnterOuterAlt(localctx, 6)
 		{
-			p.SetState(43)
+			p.SetState(45)
 			p.ArrayArg()
 		}
 
+	case FuncParserT__0:
+		p.EnterOuterAlt(localctx, 7)
+		{
+			p.SetState(46)
+			p.TupleArg()
+		}
+
 	default:
 		panic(antlr.NewNoViableAltException(p, nil, nil, nil, nil, nil))
 	}
@@ -747,19 +768,19 @@ func (p *FuncParser) IntArg() (localctx IIntArgContext) {
 	}()
 
 	p.EnterOuterAlt(localctx, 1)
-	p.SetState(47)
+	p.SetState(50)
 	p.GetErrorHandler().Sync(p)
 	_la = p.GetTokenStream().LA(1)
 
 	if _la == FuncParserT__3 {
 		{
-			p.SetState(46)
+			p.SetState(49)
 			p.Match(FuncParserT__3)
 		}
 
 	}
 	{
-		p.SetState(49)
+		p.SetState(52)
 		p.Match(FuncParserINT)
 	}
 
@@ -850,7 +871,7 @@ func (p *FuncParser) HexArg() (localctx IHexArgContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(51)
+		p.SetState(54)
 		p.Match(FuncParserHEX)
 	}
 
@@ -941,7 +962,7 @@ func (p *FuncParser) StringArg() (localctx IStringArgContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(53)
+		p.SetState(56)
 		p.Match(FuncParserSTRING)
 	}
 
@@ -1028,7 +1049,7 @@ func (p *FuncParser) BoolArg() (localctx IBoolArgContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(55)
+		p.SetState(58)
 		_la = p.GetTokenStream().LA(1)
 
 		if !(_la == FuncParserT__4 || _la == FuncParserT__5) {
@@ -1126,7 +1147,7 @@ func (p *FuncParser) DomainArg() (localctx IDomainArgContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(57)
+		p.SetState(60)
 		p.Match(FuncParserDOMAIN)
 	}
 
@@ -1223,17 +1244,122 @@ func (p *FuncParser) ArrayArg() (localctx IArrayArgContext) {
 
 	p.EnterOuterAlt(localctx, 1)
 	{
-		p.SetState(59)
+		p.SetState(62)
 		p.Match(FuncParserT__6)
 	}
 	{
-		p.SetState(60)
+		p.SetState(63)
 		p.FuncArgs()
 	}
 	{
-		p.SetState(61)
+		p.SetState(64)
 		p.Match(FuncParserT__7)
 	}
 
 	return localctx
 }
+
+// ITupleArgContext is an interface to support dynamic dispatch.
+type ITupleArgContext interface {
+	antlr.ParserRuleContext
+
+	// GetParser returns the parser.
+	GetParser() antlr.Parser
+
+	// IsTupleArgContext differentiates from other interfaces.
+	IsTupleArgContext()
+}
+
+type TupleArgContext struct {
+	*antlr.BaseParserRuleContext
+	parser antlr.Parser
+}
+
+func NewEmptyTupleArgContext() *TupleArgContext {
+	var p = new(TupleArgContext)
+	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(nil, -1)
+	p.RuleIndex = FuncParserRULE_tupleArg
+	return p
+}
+
+func (*TupleArgContext) IsTupleArgContext() {}
+
+func NewTupleArgContext(parser antlr.Parser, parent antlr.ParserRuleContext, invokingState int) *TupleArgContext {
+	var p = new(TupleArgContext)
+
+	p.BaseParserRuleContext = antlr.NewBaseParserRuleContext(parent, invokingState)
+
+	p.parser = parser
+	p.RuleIndex = FuncParserRULE_tupleArg
+
+	return p
+}
+
+func (s *TupleArgContext) GetParser() antlr.Parser { return s.parser }
+
+func (s *TupleArgContext) FuncArgs() IFuncArgsContext {
+	var t = s.GetTypedRuleContext(reflect.TypeOf((*IFuncArgsContext)(nil)).Elem(), 0)
+
+	if t == nil {
+		return nil
+	}
+
+	return t.(IFuncArgsContext)
+}
+
+func (s *TupleArgContext) GetRuleContext() antlr.RuleContext {
+	return s
+}
+
+func (s *TupleArgContext) ToStringTree(ruleNames []string, recog antlr.Recognizer) string {
+	return antlr.TreesStringTree(s, ruleNames, recog)
+}
+
+func (s *TupleArgContext) EnterRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(FuncListener); ok {
+		listenerT.EnterTupleArg(s)
+	}
+}
+
+func (s *TupleArgContext) ExitRule(listener antlr.ParseTreeListener) {
+	if listenerT, ok := listener.(FuncListener); ok {
+		listenerT.ExitTupleArg(s)
+	}
+}
+
+func (p *FuncParser) TupleArg() (localctx ITupleArgContext) {
+	localctx = NewTupleArgContext(p, p.GetParserRuleContext(), p.GetState())
+	p.EnterRule(localctx, 20, FuncParserRULE_tupleArg)
+
+	defer func() {
+		p.ExitRule()
+	}()
+
+	defer func() {
+		if err := recover(); err != nil {
+			if v, ok := err.(antlr.RecognitionException); ok {
+				localctx.SetException(v)
+				p.GetErrorHandler().ReportError(p, v)
+				p.GetErrorHandler().Recover(p, v)
+			} else {
+				panic(err)
+			}
+		}
+	}()
+
+	p.EnterOuterAlt(localctx, 1)
+	{
+		p.SetState(67)
+		p.Match(FuncParserT__0)
+	}
+	{
+		p.SetState(68)
+		p.FuncArgs()
+	}
+	{
+		p.SetState(69)
+		p.Match(FuncParserT__1)
+	}
+
+	return localctx
+}
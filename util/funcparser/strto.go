@@ -15,14 +15,18 @@ package funcparser
 
 import (
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 	"reflect"
+	"strconv"
 	"strings"
-	"unsafe"
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/viper"
+	"github.com/wealdtech/ethereal/util"
 )
 
 // StrTo turns a string in to any simple type as given in the ABI information.
@@ -65,10 +69,18 @@ func StrTo(inputType *abi.Type, input string) (interface{}, error) {
 // StrToInt turns a string in to an int type as given by the ABI information.
 // It can return various types so return interface{}
 func StrToInt(inputType *abi.Type, input string) (interface{}, error) {
-	val := big.NewInt(0)
-	val, success := val.SetString(input, 10)
-	if !success {
-		return nil, fmt.Errorf("invalid integer %s", input)
+	val, err := strToBigInt(input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid integer %s: %v", input, err)
+	}
+	if inputType.Size < 8 || inputType.Size > 256 || inputType.Size%8 != 0 {
+		return nil, fmt.Errorf("unexpected int size %d", inputType.Size)
+	}
+	// Range is -2^(n-1) to 2^(n-1)-1
+	min := new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), uint(inputType.Size-1)))
+	max := new(big.Int).Sub(new(big.Int).Neg(min), big.NewInt(1))
+	if val.Cmp(min) < 0 || val.Cmp(max) > 0 {
+		return nil, fmt.Errorf("integer %s out of range for int%d (%v to %v)", input, inputType.Size, min, max)
 	}
 	switch inputType.Size {
 	case 8:
@@ -78,11 +90,9 @@ func StrToInt(inputType *abi.Type, input string) (interface{}, error) {
 	case 32:
 		return int32(val.Int64()), nil
 	case 64:
-		return int64(val.Int64()), nil
-	case 256:
-		return val, nil
+		return val.Int64(), nil
 	default:
-		return nil, fmt.Errorf("unexpected int size %d", inputType.Size)
+		return val, nil
 	}
 }
 
@@ -92,14 +102,21 @@ var _zero = big.NewInt(0)
 // StrToUint turns a string in to a uint type as given by the ABI information.
 // It can return various types so return interface{}
 func StrToUint(inputType *abi.Type, input string) (interface{}, error) {
-	val := big.NewInt(0)
-	val, success := val.SetString(input, 10)
-	if !success {
-		return nil, fmt.Errorf("invalid unsigned integer %s", input)
+	val, err := strToBigInt(input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid unsigned integer %s: %v", input, err)
 	}
 	if val.Cmp(_zero) < 0 {
 		return nil, fmt.Errorf("invalid unsigned integer %s", input)
 	}
+	if inputType.Size < 8 || inputType.Size > 256 || inputType.Size%8 != 0 {
+		return nil, fmt.Errorf("unexpected int size %d", inputType.Size)
+	}
+	// Range is 0 to 2^n-1
+	if val.BitLen() > inputType.Size {
+		max := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(inputType.Size)), big.NewInt(1))
+		return nil, fmt.Errorf("integer %s out of range for uint%d (0 to %v)", input, inputType.Size, max)
+	}
 	switch inputType.Size {
 	case 8:
 		return uint8(val.Uint64()), nil
@@ -109,11 +126,61 @@ func StrToUint(inputType *abi.Type, input string) (interface{}, error) {
 		return uint32(val.Uint64()), nil
 	case 64:
 		return val.Uint64(), nil
-	case 256:
-		return val, nil
 	default:
-		return nil, fmt.Errorf("unexpected int size %d", inputType.Size)
+		return val, nil
+	}
+}
+
+// _maxExponent is the largest exponent accepted in scientific notation; it
+// comfortably exceeds the largest 256-bit value.
+const _maxExponent = 80
+
+// strToBigInt turns a decimal string in to a big integer.  Digits can be
+// grouped with underscores (e.g. 1_000_000) and scientific notation can be
+// used (e.g. 1e18 or 1.5e18) as long as the result is a whole number.
+func strToBigInt(input string) (*big.Int, error) {
+	str := input
+	if strings.HasPrefix(str, "_") || strings.HasSuffix(str, "_") || strings.Contains(str, "__") {
+		return nil, errors.New("misplaced digit separator")
 	}
+	str = strings.Replace(str, "_", "", -1)
+
+	exponent := int64(0)
+	if pos := strings.IndexAny(str, "eE"); pos != -1 {
+		var err error
+		exponent, err = strconv.ParseInt(str[pos+1:], 10, 64)
+		if err != nil {
+			return nil, errors.New("invalid exponent")
+		}
+		if exponent > _maxExponent {
+			return nil, errors.New("exponent too large")
+		}
+		str = str[:pos]
+	}
+	if pos := strings.Index(str, "."); pos != -1 {
+		// Move the decimal point in to the exponent
+		exponent -= int64(len(str) - pos - 1)
+		str = str[:pos] + str[pos+1:]
+	}
+
+	val, success := new(big.Int).SetString(str, 10)
+	if !success {
+		return nil, errors.New("not a number")
+	}
+	if exponent > 0 {
+		val.Mul(val, new(big.Int).Exp(big.NewInt(10), big.NewInt(exponent), nil))
+	} else if exponent < 0 && val.Sign() != 0 {
+		if -exponent > int64(len(str)) {
+			return nil, errors.New("not a whole number")
+		}
+		divisor := new(big.Int).Exp(big.NewInt(10), big.NewInt(-exponent), nil)
+		remainder := new(big.Int)
+		val.QuoRem(val, divisor, remainder)
+		if remainder.Sign() != 0 {
+			return nil, errors.New("not a whole number")
+		}
+	}
+	return val, nil
 }
 
 // StrToStr turns a string in to a string type as given by the ABI information.
@@ -133,6 +200,22 @@ func StrToAddress(inputType *abi.Type, input string) (common.Address, error) {
 	return common.HexToAddress(strings.TrimPrefix(input, "0x")), nil
 }
 
+// resolveAddress turns a hex address or ENS name in to an address.  ENS names
+// can only be resolved with a client, so without one a hex address is required.
+func resolveAddress(client *ethclient.Client, input string) (common.Address, error) {
+	if util.IsHexAddressString(input) {
+		return util.HexAddress(input)
+	}
+	if client == nil {
+		return common.Address{}, fmt.Errorf("cannot resolve ENS name %s without a connection; supply the address in hex", input)
+	}
+	address, err := util.ResolveAddress(client, input)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to resolve ENS name %s: %v", input, err)
+	}
+	return address, nil
+}
+
 // StrToHash turns a string in to a hash type as given by the ABI information.
 func StrToHash(inputType *abi.Type, input string) (common.Hash, error) {
 	return common.HexToHash(strings.TrimPrefix(input, "0x")), nil
@@ -140,82 +223,29 @@ func StrToHash(inputType *abi.Type, input string) (common.Hash, error) {
 
 // StrToBytes turns a string in to a bytes type as given by the ABI information.
 // It can return various types so return interface{}
+//
+// Values for fixed-size byte types must be exactly the size of the type.  If
+// "padbytes" is set then shorter values are right-padded with zeros, as
+// Solidity does for string literals.
 func StrToBytes(inputType *abi.Type, input string) (interface{}, error) {
 	decoded, err := hex.DecodeString(strings.TrimPrefix(input, "0x"))
 	if err != nil {
 		return nil, fmt.Errorf("invalid byte string %s", input)
 	}
-	if inputType.Size == 0 {
+	if inputType.T != abi.FixedBytesTy {
 		return decoded, nil
 	}
-	slice := make([]byte, inputType.Size)
-	copy(slice[inputType.Size-len(decoded):inputType.Size], decoded)
-	hdr := (*reflect.SliceHeader)(unsafe.Pointer(&slice))
-	switch inputType.Size {
-	case 0:
-		return slice, nil
-	case 1:
-		return *(*[1]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 2:
-		return *(*[2]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 3:
-		return *(*[3]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 4:
-		return *(*[4]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 5:
-		return *(*[5]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 6:
-		return *(*[6]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 7:
-		return *(*[7]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 8:
-		return *(*[8]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 9:
-		return *(*[9]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 10:
-		return *(*[10]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 11:
-		return *(*[11]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 12:
-		return *(*[12]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 13:
-		return *(*[13]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 14:
-		return *(*[14]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 15:
-		return *(*[15]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 16:
-		return *(*[16]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 17:
-		return *(*[17]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 18:
-		return *(*[18]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 19:
-		return *(*[19]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 20:
-		return *(*[20]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 21:
-		return *(*[21]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 22:
-		return *(*[22]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 23:
-		return *(*[23]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 24:
-		return *(*[24]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 25:
-		return *(*[25]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 26:
-		return *(*[26]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 27:
-		return *(*[27]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 28:
-		return *(*[28]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 29:
-		return *(*[29]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 31:
-		return *(*[31]uint8)(unsafe.Pointer(hdr.Data)), nil
-	case 32:
-		return *(*[32]uint8)(unsafe.Pointer(hdr.Data)), nil
+	if inputType.Size < 1 || inputType.Size > 32 {
+		return nil, fmt.Errorf("invalid byte size %d", inputType.Size)
+	}
+	if len(decoded) > inputType.Size {
+		return nil, fmt.Errorf("byte string %s too long for bytes%d (expected %d bytes, got %d)", input, inputType.Size, inputType.Size, len(decoded))
 	}
-	return nil, fmt.Errorf("invalid byte size %d", inputType.Size)
+	if len(decoded) < inputType.Size && !viper.GetBool("padbytes") {
+		return nil, fmt.Errorf("byte string %s too short for bytes%d (expected %d bytes, got %d)", input, inputType.Size, inputType.Size, len(decoded))
+	}
+
+	value := reflect.New(reflectType(inputType)).Elem()
+	reflect.Copy(value, reflect.ValueOf(decoded))
+	return value.Interface(), nil
 }
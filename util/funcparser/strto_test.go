@@ -0,0 +1,113 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package funcparser
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrToBytes(t *testing.T) {
+	type strToBytesTest struct {
+		argType string
+		input   string
+		pad     bool
+		output  interface{}
+		err     string
+	}
+	tests := []strToBytesTest{
+		{ // 0 - dynamic bytes
+			argType: "bytes",
+			input:   "0x010203",
+			output:  []byte{0x01, 0x02, 0x03},
+		},
+		{ // 1 - empty dynamic bytes
+			argType: "bytes",
+			input:   "0x",
+			output:  []byte{},
+		},
+		{ // 2 - invalid hex
+			argType: "bytes4",
+			input:   "0x0102030g",
+			err:     "invalid byte string 0x0102030g",
+		},
+		{ // 3 - odd length
+			argType: "bytes4",
+			input:   "0x0102030",
+			err:     "invalid byte string 0x0102030",
+		},
+		{ // 4 - short padded on the right
+			argType: "bytes4",
+			input:   "0x0102",
+			pad:     true,
+			output:  [4]byte{0x01, 0x02, 0x00, 0x00},
+		},
+		{ // 5 - over-length not truncated when padding
+			argType: "bytes4",
+			input:   "0x0102030405",
+			pad:     true,
+			err:     "byte string 0x0102030405 too long for bytes4 (expected 4 bytes, got 5)",
+		},
+		{ // 6 - empty padded
+			argType: "bytes2",
+			input:   "0x",
+			pad:     true,
+			output:  [2]byte{},
+		},
+	}
+
+	// Exact-length, short and over-length values for every size
+	for size := 1; size <= 32; size++ {
+		argType := fmt.Sprintf("bytes%d", size)
+		exact := make([]byte, size)
+		for i := range exact {
+			exact[i] = byte(i + 1)
+		}
+		output := reflect.New(reflect.ArrayOf(size, reflect.TypeOf(byte(0)))).Elem()
+		reflect.Copy(output, reflect.ValueOf(exact))
+		tests = append(tests, strToBytesTest{
+			argType: argType,
+			input:   fmt.Sprintf("0x%x", exact),
+			output:  output.Interface(),
+		}, strToBytesTest{
+			argType: argType,
+			input:   fmt.Sprintf("0x%x", exact[:size-1]),
+			err:     fmt.Sprintf("byte string 0x%x too short for %s (expected %d bytes, got %d)", exact[:size-1], argType, size, size-1),
+		}, strToBytesTest{
+			argType: argType,
+			input:   fmt.Sprintf("0x%x00", exact),
+			err:     fmt.Sprintf("byte string 0x%x00 too long for %s (expected %d bytes, got %d)", exact, argType, size, size+1),
+		})
+	}
+
+	for i, test := range tests {
+		inputType, err := abi.NewType(test.argType, "", nil)
+		require.Nil(t, err, fmt.Sprintf("failed to create type at test %d", i))
+		viper.Set("padbytes", test.pad)
+		output, err := StrToBytes(&inputType, test.input)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("incorrect error at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		assert.Equal(t, test.output, output, fmt.Sprintf("incorrect value at test %d", i))
+	}
+	viper.Set("padbytes", false)
+}
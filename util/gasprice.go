@@ -20,8 +20,10 @@ import (
 	"sort"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	string2eth "github.com/wealdtech/go-string2eth"
 )
 
@@ -125,3 +127,14 @@ func BlockHasMinerTransactions(block *types.Block, chainID *big.Int) bool {
 	}
 	return false
 }
+
+// SuggestPriorityFee obtains the priority fee per gas that the node suggests
+// for timely inclusion of a transaction.  This is only available from nodes
+// for chains that support EIP-1559.
+func SuggestPriorityFee(ctx context.Context, client *rpc.Client) (*big.Int, error) {
+	var fee hexutil.Big
+	if err := client.CallContext(ctx, &fee, "eth_maxPriorityFeePerGas"); err != nil {
+		return nil, err
+	}
+	return (*big.Int)(&fee), nil
+}
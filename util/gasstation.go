@@ -0,0 +1,94 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultGasStationEndpoint is the default ETH Gas Station endpoint.
+const DefaultGasStationEndpoint = "https://ethgasstation.info/api/ethgasAPI.json"
+
+// GasStationTiers are the gas prices suggested by a gas station for
+// different speeds of inclusion.
+type GasStationTiers struct {
+	Slow     *big.Int
+	Standard *big.Int
+	Fast     *big.Int
+}
+
+// gasStationResponse is the response from an ETH Gas Station-compatible
+// endpoint.  Prices are in tenths of a GWei.
+type gasStationResponse struct {
+	SafeLow *json.Number `json:"safeLow"`
+	Average *json.Number `json:"average"`
+	Fast    *json.Number `json:"fast"`
+}
+
+// GasStationPrices obtains the suggested gas prices from an ETH Gas
+// Station-compatible endpoint.
+func GasStationPrices(endpoint string) (*GasStationTiers, error) {
+	httpClient := &http.Client{Timeout: viper.GetDuration("timeout")}
+	resp, err := httpClient.Get(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("gas station returned status %d", resp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var response gasStationResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, errors.New("invalid gas station response")
+	}
+	if response.SafeLow == nil || response.Average == nil || response.Fast == nil {
+		return nil, errors.New("gas station response missing prices")
+	}
+
+	tiers := &GasStationTiers{}
+	if tiers.Slow, err = gasStationPrice(*response.SafeLow); err != nil {
+		return nil, err
+	}
+	if tiers.Standard, err = gasStationPrice(*response.Average); err != nil {
+		return nil, err
+	}
+	if tiers.Fast, err = gasStationPrice(*response.Fast); err != nil {
+		return nil, err
+	}
+	return tiers, nil
+}
+
+// gasStationPrice converts a gas station price in tenths of a GWei to Wei.
+func gasStationPrice(input json.Number) (*big.Int, error) {
+	price, success := new(big.Float).SetString(input.String())
+	if !success || price.Sign() < 0 {
+		return nil, fmt.Errorf("invalid gas station price %s", input)
+	}
+	// Round to the nearest Wei, as decimal prices are not exact
+	price.Mul(price, big.NewFloat(1e8))
+	price.Add(price, big.NewFloat(0.5))
+	res, _ := price.Int(nil)
+	return res, nil
+}
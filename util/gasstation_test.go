@@ -0,0 +1,77 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGasStationPrices(t *testing.T) {
+	tests := []struct {
+		response string
+		status   int
+		output   *GasStationTiers
+		err      string
+	}{
+		{ // 0 - good
+			response: `{"fast":200.0,"fastest":300.0,"safeLow":95.3,"average":120.5,"block_time":13.5}`,
+			output: &GasStationTiers{
+				Slow:     big.NewInt(9530000000),
+				Standard: big.NewInt(12050000000),
+				Fast:     big.NewInt(20000000000),
+			},
+		},
+		{ // 1 - missing price
+			response: `{"fast":200.0,"average":120.5}`,
+			err:      "gas station response missing prices",
+		},
+		{ // 2 - negative price
+			response: `{"fast":200.0,"safeLow":-1,"average":120.5}`,
+			err:      "invalid gas station price -1",
+		},
+		{ // 3 - invalid response
+			response: `<html></html>`,
+			err:      "invalid gas station response",
+		},
+		{ // 4 - bad status
+			status: http.StatusServiceUnavailable,
+			err:    "gas station returned status 503",
+		},
+	}
+
+	for i, test := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if test.status != 0 {
+				w.WriteHeader(test.status)
+				return
+			}
+			fmt.Fprint(w, test.response)
+		}))
+		tiers, err := GasStationPrices(server.URL)
+		server.Close()
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("incorrect error at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		assert.Equal(t, test.output, tiers, fmt.Sprintf("incorrect tiers at test %d", i))
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"strings"
+
+	bip32 "github.com/FactomProject/go-bip32"
+	bip39 "github.com/FactomProject/go-bip39"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DefaultHDPath is the derivation path of the first Ethereum account.
+const DefaultHDPath = "m/44'/60'/0'/0/0"
+
+// MnemonicPrivateKey derives a private key from a BIP-39 mnemonic, an
+// optional secret, and a BIP-32 derivation path such as m/44'/60'/0'/0/3.
+func MnemonicPrivateKey(mnemonic string, secret string, path string) (*ecdsa.PrivateKey, error) {
+	derivationPath, err := accounts.ParseDerivationPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path: %v", err)
+	}
+
+	// Normalise whitespace, as mnemonics read from files may contain newlines
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return nil, errors.New("invalid mnemonic")
+	}
+	if _, err := bip39.MnemonicToByteArray(mnemonic); err != nil {
+		return nil, errors.New("invalid mnemonic checksum")
+	}
+	seed := bip39.NewSeed(mnemonic, secret)
+
+	key, err := bip32.NewMasterKey(seed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain master key: %v", err)
+	}
+	for _, index := range derivationPath {
+		key, err = key.NewChildKey(index)
+		if err != nil {
+			return nil, fmt.Errorf("failed to obtain child key: %v", err)
+		}
+	}
+
+	return crypto.ToECDSA(key.Key)
+}
@@ -0,0 +1,74 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMnemonicPrivateKey(t *testing.T) {
+	mnemonic := "test test test test test test test test test test test junk"
+	tests := []struct {
+		mnemonic string
+		path     string
+		address  string
+		err      string
+	}{
+		{ // 0 - default path
+			mnemonic: mnemonic,
+			path:     DefaultHDPath,
+			address:  "0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266",
+		},
+		{ // 1 - later account
+			mnemonic: mnemonic,
+			path:     "m/44'/60'/0'/0/3",
+			address:  "0x90F79bf6EB2c4f870365E785982E1f101E93b906",
+		},
+		{ // 2 - extra whitespace
+			mnemonic: "  test test test test test test\ntest test test test test junk\n",
+			path:     "m/44'/60'/0'/0/1",
+			address:  "0x70997970C51812dc3A010C7d01b50e0d17dc79C8",
+		},
+		{ // 3 - invalid path
+			mnemonic: mnemonic,
+			path:     "m/44'/60'/x",
+			err:      "invalid path: invalid component: x",
+		},
+		{ // 4 - unknown word
+			mnemonic: "test test test test test test test test test test test nonsense",
+			path:     DefaultHDPath,
+			err:      "invalid mnemonic",
+		},
+		{ // 5 - invalid checksum
+			mnemonic: "test test test test test test test test test test test test",
+			path:     DefaultHDPath,
+			err:      "invalid mnemonic checksum",
+		},
+	}
+
+	for i, test := range tests {
+		key, err := MnemonicPrivateKey(test.mnemonic, "", test.path)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("incorrect error at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		assert.Equal(t, test.address, crypto.PubkeyToAddress(key.PublicKey).Hex(), fmt.Sprintf("incorrect address at test %d", i))
+	}
+}
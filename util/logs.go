@@ -0,0 +1,125 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// EventTopics creates the topics with which to filter logs for an event.
+// Filters are keyed by the position of the event's input, and each input
+// matches any of its supplied values.  Inputs without filters match any
+// value.
+func EventTopics(event *abi.Event, filters map[int][]interface{}) ([][]common.Hash, error) {
+	topics := make([][]common.Hash, 0)
+	if !event.Anonymous {
+		topics = append(topics, []common.Hash{event.ID})
+	}
+	for i, input := range event.Inputs {
+		values, exists := filters[i]
+		if !input.Indexed {
+			if exists {
+				return nil, fmt.Errorf("input %s is not indexed so cannot be filtered", inputName(input, i))
+			}
+			continue
+		}
+		if !exists {
+			topics = append(topics, nil)
+			continue
+		}
+		inputTopics := make([]common.Hash, len(values))
+		for j := range values {
+			topic, err := eventTopic(values[j])
+			if err != nil {
+				return nil, fmt.Errorf("input %s: %v", inputName(input, i), err)
+			}
+			inputTopics[j] = topic
+		}
+		topics = append(topics, inputTopics)
+	}
+
+	// Trailing inputs without filters do not need to be supplied.
+	for len(topics) > 0 && topics[len(topics)-1] == nil {
+		topics = topics[:len(topics)-1]
+	}
+	return topics, nil
+}
+
+// eventTopic creates the topic for a single indexed value.
+func eventTopic(value interface{}) (common.Hash, error) {
+	if v, isBigInt := value.(*big.Int); isBigInt && v.Sign() < 0 {
+		// Negative values are stored in two's complement.
+		return common.BytesToHash(math.U256Bytes(new(big.Int).Set(v))), nil
+	}
+	topics, err := abi.MakeTopics([]interface{}{value})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return topics[0][0], nil
+}
+
+// DecodeLog decodes the values of an event's inputs from a log, returning
+// them in the order of the inputs.  Indexed inputs of dynamic types such as
+// strings are stored as the hash of their value, so are returned as a
+// common.Hash.
+func DecodeLog(event *abi.Event, log *types.Log) ([]interface{}, error) {
+	topics := log.Topics
+	if !event.Anonymous {
+		if len(topics) == 0 || topics[0] != event.ID {
+			return nil, fmt.Errorf("log is not a %s event", event.Name)
+		}
+		topics = topics[1:]
+	}
+
+	values := make([]interface{}, len(event.Inputs))
+	nonIndexed, err := event.Inputs.NonIndexed().UnpackValues(log.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode data: %v", err)
+	}
+	for i, input := range event.Inputs {
+		if !input.Indexed {
+			values[i] = nonIndexed[0]
+			nonIndexed = nonIndexed[1:]
+			continue
+		}
+		if len(topics) == 0 {
+			return nil, errors.New("log has too few topics")
+		}
+		value := make(map[string]interface{})
+		if err := abi.ParseTopicsIntoMap(value, abi.Arguments{input}, topics[:1]); err != nil {
+			return nil, fmt.Errorf("failed to decode input %s: %v", inputName(input, i), err)
+		}
+		values[i] = value[input.Name]
+		topics = topics[1:]
+	}
+	if len(topics) != 0 {
+		return nil, errors.New("log has too many topics")
+	}
+	return values, nil
+}
+
+// inputName returns the name of an input, or its position if it is unnamed.
+func inputName(input abi.Argument, position int) string {
+	if input.Name == "" {
+		return fmt.Sprintf("%d", position)
+	}
+	return input.Name
+}
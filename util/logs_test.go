@@ -0,0 +1,170 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const logsTestABI = `[
+{"type":"event","name":"Transfer","anonymous":false,"inputs":[{"name":"from","type":"address","indexed":true},{"name":"to","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}]},
+{"type":"event","name":"Note","anonymous":true,"inputs":[{"name":"","type":"int256","indexed":true},{"name":"text","type":"string","indexed":true},{"name":"data","type":"bytes","indexed":false}]}
+]`
+
+func TestEventTopics(t *testing.T) {
+	contractABI, err := abi.JSON(strings.NewReader(logsTestABI))
+	require.Nil(t, err)
+	transfer := contractABI.Events["Transfer"]
+	note := contractABI.Events["Note"]
+	from := common.HexToAddress("0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf")
+	to := common.HexToAddress("0x2B5AD5c4795c026514f8317c7a215E218DcCD6cF")
+
+	tests := []struct {
+		event   *abi.Event
+		filters map[int][]interface{}
+		res     [][]common.Hash
+		err     string
+	}{
+		{ // 0 - no filters
+			event: &transfer,
+			res:   [][]common.Hash{{transfer.ID}},
+		},
+		{ // 1 - second input
+			event:   &transfer,
+			filters: map[int][]interface{}{1: {to}},
+			res:     [][]common.Hash{{transfer.ID}, nil, {common.BytesToHash(to.Bytes())}},
+		},
+		{ // 2 - multiple values
+			event:   &transfer,
+			filters: map[int][]interface{}{0: {from, to}},
+			res:     [][]common.Hash{{transfer.ID}, {common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())}},
+		},
+		{ // 3 - non-indexed input
+			event:   &transfer,
+			filters: map[int][]interface{}{2: {big.NewInt(1)}},
+			err:     "input value is not indexed so cannot be filtered",
+		},
+		{ // 4 - anonymous event with negative integer and string
+			event:   &note,
+			filters: map[int][]interface{}{0: {big.NewInt(-1)}, 1: {"hello"}},
+			res:     [][]common.Hash{{common.HexToHash("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")}, {crypto.Keccak256Hash([]byte("hello"))}},
+		},
+		{ // 5 - unsupported value
+			event:   &note,
+			filters: map[int][]interface{}{0: {[]int{1}}},
+			err:     "input arg0: unsupported indexed type: []int",
+		},
+	}
+
+	for i, test := range tests {
+		res, err := EventTopics(test.event, test.filters)
+		if test.err != "" {
+			require.NotNil(t, err, fmt.Sprintf("missing error at test %d", i))
+			assert.Equal(t, test.err, err.Error(), fmt.Sprintf("incorrect error at test %d", i))
+		} else {
+			require.Nil(t, err, fmt.Sprintf("unexpected error at test %d", i))
+			assert.Equal(t, test.res, res, fmt.Sprintf("failed at test %d", i))
+		}
+	}
+}
+
+func TestDecodeLog(t *testing.T) {
+	contractABI, err := abi.JSON(strings.NewReader(logsTestABI))
+	require.Nil(t, err)
+	transfer := contractABI.Events["Transfer"]
+	note := contractABI.Events["Note"]
+	from := common.HexToAddress("0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf")
+	to := common.HexToAddress("0x2B5AD5c4795c026514f8317c7a215E218DcCD6cF")
+	transferData, err := transfer.Inputs.NonIndexed().Pack(big.NewInt(1000))
+	require.Nil(t, err)
+	noteData, err := note.Inputs.NonIndexed().Pack([]byte{0x01, 0x02})
+	require.Nil(t, err)
+
+	tests := []struct {
+		event *abi.Event
+		log   *types.Log
+		res   []interface{}
+		err   string
+	}{
+		{ // 0 - transfer
+			event: &transfer,
+			log: &types.Log{
+				Topics: []common.Hash{transfer.ID, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+				Data:   transferData,
+			},
+			res: []interface{}{from, to, big.NewInt(1000)},
+		},
+		{ // 1 - anonymous with hashed string
+			event: &note,
+			log: &types.Log{
+				Topics: []common.Hash{common.HexToHash("0xfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe"), crypto.Keccak256Hash([]byte("hello"))},
+				Data:   noteData,
+			},
+			res: []interface{}{big.NewInt(-2), crypto.Keccak256Hash([]byte("hello")), []byte{0x01, 0x02}},
+		},
+		{ // 2 - different event
+			event: &transfer,
+			log: &types.Log{
+				Topics: []common.Hash{note.ID, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+				Data:   transferData,
+			},
+			err: "log is not a Transfer event",
+		},
+		{ // 3 - too few topics
+			event: &transfer,
+			log: &types.Log{
+				Topics: []common.Hash{transfer.ID, common.BytesToHash(from.Bytes())},
+				Data:   transferData,
+			},
+			err: "log has too few topics",
+		},
+		{ // 4 - too many topics
+			event: &transfer,
+			log: &types.Log{
+				Topics: []common.Hash{transfer.ID, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes()), common.BytesToHash(to.Bytes())},
+				Data:   transferData,
+			},
+			err: "log has too many topics",
+		},
+		{ // 5 - short data
+			event: &transfer,
+			log: &types.Log{
+				Topics: []common.Hash{transfer.ID, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())},
+				Data:   transferData[:16],
+			},
+			err: "failed to decode data: abi: cannot marshal in to go type: length insufficient 16 require 32",
+		},
+	}
+
+	for i, test := range tests {
+		res, err := DecodeLog(test.event, test.log)
+		if test.err != "" {
+			require.NotNil(t, err, fmt.Sprintf("missing error at test %d", i))
+			assert.Equal(t, test.err, err.Error(), fmt.Sprintf("incorrect error at test %d", i))
+		} else {
+			require.Nil(t, err, fmt.Sprintf("unexpected error at test %d", i))
+			assert.Equal(t, test.res, res, fmt.Sprintf("failed at test %d", i))
+		}
+	}
+}
@@ -0,0 +1,311 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package output formats structured results as text, JSON or CSV.
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// Text is human-readable output, with one line per record.
+	Text = "text"
+	// JSON is a JSON object for a single record, or an array of objects for
+	// multiple records.
+	JSON = "json"
+	// CSV is comma-separated values with a header line.
+	CSV = "csv"
+)
+
+// Field is a named value within a record.
+type Field struct {
+	Name  string
+	Value interface{}
+	// Text, if set, is used in place of the value in text output.
+	Text string
+	// Hidden fields are not included in text output.
+	Hidden bool
+}
+
+// Record is an ordered set of fields.  Fields with a nil value are omitted.
+type Record []Field
+
+// MarshalJSON implements json.Marshaler, keeping the order of the fields.
+func (r Record) MarshalJSON() ([]byte, error) {
+	buf := new(bytes.Buffer)
+	buf.WriteString("{")
+	first := true
+	for _, field := range r {
+		if isNil(field.Value) {
+			continue
+		}
+		if !first {
+			buf.WriteString(",")
+		}
+		first = false
+		name, err := json.Marshal(field.Name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(jsonValue(field.Value))
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s: %v", field.Name, err)
+		}
+		buf.Write(name)
+		buf.WriteString(":")
+		buf.Write(value)
+	}
+	buf.WriteString("}")
+	return buf.Bytes(), nil
+}
+
+// Formatter writes records in a given format.
+type Formatter struct {
+	format string
+	names  func(common.Address) string
+	array  bool
+}
+
+// New creates a formatter for the given format.
+func New(format string) (*Formatter, error) {
+	switch format {
+	case Text, JSON, CSV:
+		return &Formatter{format: format}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q; supported formats are text, json and csv", format)
+	}
+}
+
+// Format returns the format of the formatter.
+func (f *Formatter) Format() string {
+	return f.format
+}
+
+// SetNameResolver sets a function to provide names for addresses in text
+// output, for example by ENS reverse resolution.  Other formats always
+// output addresses in hex.
+func (f *Formatter) SetNameResolver(names func(common.Address) string) {
+	f.names = names
+}
+
+// SetArray sets JSON output to always be an array, regardless of the number
+// of records.  This suits commands that output a list of results.
+func (f *Formatter) SetArray(array bool) {
+	f.array = array
+}
+
+// Array returns true if JSON output is always an array.
+func (f *Formatter) Array() bool {
+	return f.array
+}
+
+// Write writes the records.  With JSON a single record is written as an
+// object and multiple records as an array, unless SetArray has been used.
+func (f *Formatter) Write(w io.Writer, records ...Record) error {
+	switch f.format {
+	case JSON:
+		var data []byte
+		var err error
+		if len(records) == 1 && !f.array {
+			data, err = json.Marshal(records[0])
+		} else {
+			if records == nil {
+				records = []Record{}
+			}
+			data, err = json.Marshal(records)
+		}
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", string(data))
+		return err
+	case CSV:
+		return f.writeCSV(w, records)
+	default:
+		return f.writeText(w, records)
+	}
+}
+
+// writeText writes one line per record, with the visible fields separated
+// by two spaces and aligned in columns.
+func (f *Formatter) writeText(w io.Writer, records []Record) error {
+	rows := make([][]string, len(records))
+	widths := make([]int, 0)
+	for i, record := range records {
+		for _, field := range record {
+			if field.Hidden || isNil(field.Value) {
+				continue
+			}
+			text := field.Text
+			if text == "" {
+				text = f.textValue(field.Value)
+			}
+			col := len(rows[i])
+			rows[i] = append(rows[i], text)
+			if col == len(widths) {
+				widths = append(widths, 0)
+			}
+			if len(text) > widths[col] {
+				widths[col] = len(text)
+			}
+		}
+	}
+	for _, row := range rows {
+		for col := range row {
+			if col == len(row)-1 {
+				if _, err := fmt.Fprintln(w, row[col]); err != nil {
+					return err
+				}
+			} else {
+				if _, err := fmt.Fprintf(w, "%-*s  ", widths[col], row[col]); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// writeCSV writes a header line followed by one line per record.  Nested
+// records are flattened, with their field names prefixed by the name of the
+// parent field.
+func (f *Formatter) writeCSV(w io.Writer, records []Record) error {
+	columns := make([]string, 0)
+	seen := make(map[string]bool)
+	rows := make([]map[string]string, len(records))
+	for i, record := range records {
+		rows[i] = make(map[string]string)
+		flatten("", record, rows[i], func(name string) {
+			if !seen[name] {
+				seen[name] = true
+				columns = append(columns, name)
+			}
+		})
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(columns); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		line := make([]string, len(columns))
+		for i, column := range columns {
+			line[i] = row[column]
+		}
+		if err := writer.Write(line); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// flatten adds the values of a record to a row, keyed by their names.
+func flatten(prefix string, record Record, row map[string]string, column func(string)) {
+	for _, field := range record {
+		if isNil(field.Value) {
+			continue
+		}
+		name := prefix + field.Name
+		if nested, isRecord := field.Value.(Record); isRecord {
+			flatten(name+".", nested, row, column)
+			continue
+		}
+		column(name)
+		row[name] = plainValue(field.Value)
+	}
+}
+
+// textValue provides the text representation of a value.
+func (f *Formatter) textValue(value interface{}) string {
+	switch v := value.(type) {
+	case common.Address:
+		if f.names != nil {
+			return f.names(v)
+		}
+		return v.Hex()
+	case Record:
+		values := make([]string, 0, len(v))
+		for _, field := range v {
+			if !isNil(field.Value) {
+				values = append(values, fmt.Sprintf("%s=%s", field.Name, f.textValue(field.Value)))
+			}
+		}
+		return fmt.Sprintf("(%s)", strings.Join(values, ", "))
+	case []Record:
+		values := make([]string, len(v))
+		for i := range v {
+			values[i] = f.textValue(v[i])
+		}
+		return fmt.Sprintf("[%s]", strings.Join(values, ", "))
+	default:
+		return plainValue(value)
+	}
+}
+
+// plainValue provides the representation of a simple value.
+func plainValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case *big.Int:
+		return v.String()
+	case common.Address:
+		return v.Hex()
+	case common.Hash:
+		return v.Hex()
+	case []byte:
+		return fmt.Sprintf("0x%x", v)
+	case Record, []Record:
+		data, _ := json.Marshal(v)
+		return string(data)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// jsonValue provides the value to be marshalled in JSON output.  Large
+// integers are output as strings to avoid loss of precision.
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *big.Int:
+		return v.String()
+	case common.Address:
+		return v.Hex()
+	case []byte:
+		return fmt.Sprintf("0x%x", v)
+	default:
+		return value
+	}
+}
+
+// isNil returns true if the value is nil, including typed nil pointers.
+func isNil(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case *big.Int:
+		return v == nil
+	default:
+		return false
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	address := common.HexToAddress("0x5ffc014343cd971b7eb70732021e26c35b744cc4")
+	balance := Record{
+		{Name: "address", Value: address, Text: "wealdtech.eth"},
+		{Name: "balance", Value: big.NewInt(1500), Text: "1.5 KWei"},
+		{Name: "block", Value: uint64(10), Hidden: true},
+		{Name: "error", Value: nil},
+	}
+	failed := Record{
+		{Name: "address", Value: "unknown.eth"},
+		{Name: "error", Value: "no address", Text: "error: no address"},
+	}
+	nested := Record{
+		{Name: "owner", Value: address},
+		{Name: "token", Value: Record{
+			{Name: "symbol", Value: "USDC"},
+			{Name: "decimals", Value: uint8(6)},
+		}},
+	}
+
+	tests := []struct {
+		format  string
+		names   func(common.Address) string
+		array   bool
+		records []Record
+		output  string
+		err     string
+	}{
+		{ // 0 - unknown format
+			format: "xml",
+			err:    `unknown output format "xml"; supported formats are text, json and csv`,
+		},
+		{ // 1 - single text record
+			format:  Text,
+			records: []Record{balance},
+			output:  "wealdtech.eth  1.5 KWei\n",
+		},
+		{ // 2 - aligned text records
+			format:  Text,
+			records: []Record{balance, failed},
+			output:  "wealdtech.eth  1.5 KWei\nunknown.eth    error: no address\n",
+		},
+		{ // 3 - single JSON record
+			format:  JSON,
+			records: []Record{balance},
+			output:  `{"address":"0x5FfC014343cd971B7eb70732021E26C35B744cc4","balance":"1500","block":10}` + "\n",
+		},
+		{ // 4 - multiple JSON records
+			format:  JSON,
+			records: []Record{balance, failed},
+			output:  `[{"address":"0x5FfC014343cd971B7eb70732021E26C35B744cc4","balance":"1500","block":10},{"address":"unknown.eth","error":"no address"}]` + "\n",
+		},
+		{ // 5 - CSV with differing fields
+			format:  CSV,
+			records: []Record{balance, failed},
+			output:  "address,balance,block,error\n0x5FfC014343cd971B7eb70732021E26C35B744cc4,1500,10,\nunknown.eth,,,no address\n",
+		},
+		{ // 6 - nested text record with name resolution
+			format:  Text,
+			names:   func(common.Address) string { return "wealdtech.eth" },
+			records: []Record{nested},
+			output:  "wealdtech.eth  (symbol=USDC, decimals=6)\n",
+		},
+		{ // 7 - nested JSON record
+			format:  JSON,
+			records: []Record{nested},
+			output:  `{"owner":"0x5FfC014343cd971B7eb70732021E26C35B744cc4","token":{"symbol":"USDC","decimals":6}}` + "\n",
+		},
+		{ // 8 - nested CSV record
+			format:  CSV,
+			names:   func(common.Address) string { return "wealdtech.eth" },
+			records: []Record{nested},
+			output:  "owner,token.symbol,token.decimals\n0x5FfC014343cd971B7eb70732021E26C35B744cc4,USDC,6\n",
+		},
+		{ // 9 - single JSON record as an array
+			format:  JSON,
+			array:   true,
+			records: []Record{failed},
+			output:  `[{"address":"unknown.eth","error":"no address"}]` + "\n",
+		},
+		{ // 10 - no JSON records as an array
+			format: JSON,
+			array:  true,
+			output: "[]\n",
+		},
+	}
+
+	for i, test := range tests {
+		formatter, err := New(test.format)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("failed at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		if test.names != nil {
+			formatter.SetNameResolver(test.names)
+		}
+		formatter.SetArray(test.array)
+		buf := new(bytes.Buffer)
+		require.Nil(t, formatter.Write(buf, test.records...), fmt.Sprintf("failed at test %d", i))
+		assert.Equal(t, test.output, buf.String(), fmt.Sprintf("failed at test %d", i))
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultSelectorEndpoint is the default endpoint used to look up function selectors.
+const DefaultSelectorEndpoint = "https://www.4byte.directory/api/v1/signatures/"
+
+// selectorResponse is the response from a 4byte directory-compatible endpoint.
+type selectorResponse struct {
+	Results []struct {
+		ID            int64  `json:"id"`
+		TextSignature string `json:"text_signature"`
+	} `json:"results"`
+}
+
+// LookupSelector obtains the candidate function signatures for a 4-byte
+// function selector from a 4byte directory-compatible endpoint.  The
+// endpoint can be set with the "selectorendpoint" configuration value.
+// Results are cached on disk, separately for each endpoint, so that repeated
+// lookups do not hit the network.  Candidates are returned oldest first, as later registrations of
+// the same selector are commonly collisions.
+func LookupSelector(selector [4]byte) ([]string, error) {
+	endpoint := viper.GetString("selectorendpoint")
+	if endpoint == "" {
+		endpoint = DefaultSelectorEndpoint
+	}
+
+	key := fmt.Sprintf("0x%x", selector)
+	cache := loadSelectorCache(endpoint)
+	if signatures, exists := cache[key]; exists {
+		return signatures, nil
+	}
+	reqURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector endpoint: %v", err)
+	}
+	query := reqURL.Query()
+	query.Set("hex_signature", key)
+	reqURL.RawQuery = query.Encode()
+
+	httpClient := &http.Client{Timeout: viper.GetDuration("timeout")}
+	resp, err := httpClient.Get(reqURL.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("selector endpoint returned status %d", resp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var response selectorResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, errors.New("invalid selector endpoint response")
+	}
+	sort.Slice(response.Results, func(i, j int) bool {
+		return response.Results[i].ID < response.Results[j].ID
+	})
+	signatures := make([]string, len(response.Results))
+	for i := range response.Results {
+		signatures[i] = response.Results[i].TextSignature
+	}
+
+	if len(signatures) > 0 {
+		// Only cache selectors that are found, as others may be added later
+		cache[key] = signatures
+		saveSelectorCache(endpoint, cache)
+	}
+	return signatures, nil
+}
+
+// selectorCachePath returns the path of the selector cache for an endpoint,
+// or an empty string if there is no user configuration directory.
+func selectorCachePath(endpoint string) string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	hash := sha256.Sum256([]byte(endpoint))
+	return filepath.Join(dir, "ethereal", "selectors", fmt.Sprintf("%x.json", hash[:8]))
+}
+
+// loadSelectorCache loads the selector cache for an endpoint from disk.  Any
+// problems result in an empty cache.
+func loadSelectorCache(endpoint string) map[string][]string {
+	cache := make(map[string][]string)
+	path := selectorCachePath(endpoint)
+	if path == "" {
+		return cache
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return make(map[string][]string)
+	}
+	return cache
+}
+
+// saveSelectorCache saves the selector cache for an endpoint to disk.
+// Failure to save is not an error, as the cache is only an optimisation.
+func saveSelectorCache(endpoint string, cache map[string][]string) {
+	path := selectorCachePath(endpoint)
+	if path == "" {
+		return
+	}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), os.FileMode(0700)); err != nil {
+		return
+	}
+	// Write to a temporary file first so that an interrupted write cannot
+	// leave a partial cache.
+	tmpPath := fmt.Sprintf("%s.tmp", path)
+	if err := ioutil.WriteFile(tmpPath, data, os.FileMode(0600)); err != nil {
+		return
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupSelector(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("hex_signature") {
+		case "0xa9059cbb":
+			fmt.Fprint(w, `{"results":[{"id":31780,"text_signature":"many_msg_babbage(bytes1)"},{"id":145,"text_signature":"transfer(address,uint256)"}]}`)
+		case "0x00000000":
+			fmt.Fprint(w, `{"results":[]}`)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+
+	home, err := ioutil.TempDir("", "ethereal")
+	require.Nil(t, err)
+	defer os.RemoveAll(home)
+	oldHome := os.Getenv("HOME")
+	defer os.Setenv("HOME", oldHome)
+	os.Setenv("HOME", home)
+	oldConfigHome := os.Getenv("XDG_CONFIG_HOME")
+	defer os.Setenv("XDG_CONFIG_HOME", oldConfigHome)
+	os.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+	viper.Set("selectorendpoint", server.URL)
+	defer viper.Set("selectorendpoint", "")
+
+	tests := []struct {
+		selector [4]byte
+		output   []string
+		err      string
+	}{
+		{ // 0 - multiple candidates, oldest first
+			selector: [4]byte{0xa9, 0x05, 0x9c, 0xbb},
+			output:   []string{"transfer(address,uint256)", "many_msg_babbage(bytes1)"},
+		},
+		{ // 1 - no candidates
+			selector: [4]byte{0x00, 0x00, 0x00, 0x00},
+			output:   []string{},
+		},
+		{ // 2 - endpoint failure
+			selector: [4]byte{0x01, 0x02, 0x03, 0x04},
+			err:      "selector endpoint returned status 500",
+		},
+	}
+
+	for i, test := range tests {
+		signatures, err := LookupSelector(test.selector)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("incorrect error at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		assert.Equal(t, test.output, signatures, fmt.Sprintf("incorrect signatures at test %d", i))
+	}
+
+	// Found selectors should now be served from the cache
+	server.Close()
+	signatures, err := LookupSelector([4]byte{0xa9, 0x05, 0x9c, 0xbb})
+	require.Nil(t, err)
+	assert.Equal(t, []string{"transfer(address,uint256)", "many_msg_babbage(bytes1)"}, signatures)
+
+	// Another endpoint should not use the cache
+	viper.Set("selectorendpoint", server.URL+"/other")
+	_, err = LookupSelector([4]byte{0xa9, 0x05, 0x9c, 0xbb})
+	assert.NotNil(t, err)
+}
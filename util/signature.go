@@ -0,0 +1,48 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	secp256k1N     = crypto.S256().Params().N
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+// IsLowS returns true if the S value of a 65-byte signature is no greater
+// than half of the curve order, as required by EIP-2.
+func IsLowS(signature []byte) bool {
+	s := new(big.Int).SetBytes(signature[32:64])
+	return s.Cmp(secp256k1HalfN) <= 0
+}
+
+// NormalizeSignature returns the low-S form of a 65-byte signature with a
+// recovery ID of 0 or 1.  A signature with a high S value is converted by
+// replacing S with N-S and flipping the recovery ID; both forms recover to
+// the same signer, but contracts that follow EIP-2 only accept the low-S form.
+func NormalizeSignature(signature []byte) []byte {
+	res := append([]byte{}, signature...)
+	if IsLowS(signature) {
+		return res
+	}
+	s := new(big.Int).Sub(secp256k1N, new(big.Int).SetBytes(signature[32:64]))
+	copy(res[32:64], common.LeftPadBytes(s.Bytes(), 32))
+	res[64] ^= 1
+	return res
+}
@@ -0,0 +1,71 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeSignature(t *testing.T) {
+	// Signatures of keccak256("test") by the key 0x00…01.
+	hash := MustDecodeHexString("9c22ff5f21f0b81b113e63f7db6da94fedef11b2119b4088b89664fb9a3cb658")
+	tests := []struct {
+		signature  string
+		lowS       bool
+		normalized string
+		signer     string
+	}{
+		{ // 0 - low S
+			signature:  "bb958903eb2617ebb142d54c20df2c4eb46159e2c717b0240037336418cb8ed359f4d1342c037dd7bec0deacdbba00fac2fa9f50a51865bbe474bc706175675e00",
+			lowS:       true,
+			normalized: "bb958903eb2617ebb142d54c20df2c4eb46159e2c717b0240037336418cb8ed359f4d1342c037dd7bec0deacdbba00fac2fa9f50a51865bbe474bc706175675e00",
+			signer:     "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf",
+		},
+		{ // 1 - high S equivalent of 0
+			signature:  "bb958903eb2617ebb142d54c20df2c4eb46159e2c717b0240037336418cb8ed3a60b2ecbd3fc8228413f21532445ff03f7b43d960a303a7fdb5da21c6ec0d9e301",
+			lowS:       false,
+			normalized: "bb958903eb2617ebb142d54c20df2c4eb46159e2c717b0240037336418cb8ed359f4d1342c037dd7bec0deacdbba00fac2fa9f50a51865bbe474bc706175675e00",
+			signer:     "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf",
+		},
+		{ // 2 - S of exactly half the curve order is low
+			signature:  "bb958903eb2617ebb142d54c20df2c4eb46159e2c717b0240037336418cb8ed37fffffffffffffffffffffffffffffff5d576e7357a4501ddfe92f46681b20a000",
+			lowS:       true,
+			normalized: "bb958903eb2617ebb142d54c20df2c4eb46159e2c717b0240037336418cb8ed37fffffffffffffffffffffffffffffff5d576e7357a4501ddfe92f46681b20a000",
+		},
+		{ // 3 - S of one more than half the curve order is high
+			signature:  "bb958903eb2617ebb142d54c20df2c4eb46159e2c717b0240037336418cb8ed37fffffffffffffffffffffffffffffff5d576e7357a4501ddfe92f46681b20a100",
+			lowS:       false,
+			normalized: "bb958903eb2617ebb142d54c20df2c4eb46159e2c717b0240037336418cb8ed37fffffffffffffffffffffffffffffff5d576e7357a4501ddfe92f46681b20a001",
+		},
+	}
+
+	for i, test := range tests {
+		signature := MustDecodeHexString(test.signature)
+		assert.Equal(t, test.lowS, IsLowS(signature), fmt.Sprintf("incorrect low S at test %d", i))
+		normalized := NormalizeSignature(signature)
+		assert.Equal(t, test.normalized, hex.EncodeToString(normalized), fmt.Sprintf("incorrect normalized signature at test %d", i))
+		assert.Equal(t, test.signature, hex.EncodeToString(signature), fmt.Sprintf("signature altered at test %d", i))
+		if test.signer != "" {
+			key, err := crypto.SigToPub(hash, normalized)
+			require.Nil(t, err, fmt.Sprintf("failed to recover at test %d", i))
+			assert.Equal(t, test.signer, crypto.PubkeyToAddress(*key).Hex(), fmt.Sprintf("incorrect signer at test %d", i))
+		}
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// AccountOverride overrides the state of an account for a call.
+type AccountOverride struct {
+	Balance   *hexutil.Big                `json:"balance,omitempty"`
+	Nonce     *hexutil.Uint64             `json:"nonce,omitempty"`
+	Code      hexutil.Bytes               `json:"code,omitempty"`
+	StateDiff map[common.Hash]common.Hash `json:"stateDiff,omitempty"`
+}
+
+// StateOverrides overrides the state of a number of accounts for a call.
+type StateOverrides map[common.Address]*AccountOverride
+
+// prestateAccount is the state of an account as returned by the prestateTracer.
+type prestateAccount struct {
+	Balance *hexutil.Big                `json:"balance"`
+	Nonce   *uint64                     `json:"nonce"`
+	Code    hexutil.Bytes               `json:"code"`
+	Storage map[common.Hash]common.Hash `json:"storage"`
+}
+
+// Merge merges the supplied overrides in to these overrides, with the
+// supplied overrides taking precedence.
+func (s StateOverrides) Merge(other StateOverrides) {
+	for address, override := range other {
+		existing, exists := s[address]
+		if !exists {
+			existing = &AccountOverride{}
+			s[address] = existing
+		}
+		if override.Balance != nil {
+			existing.Balance = override.Balance
+		}
+		if override.Nonce != nil {
+			existing.Nonce = override.Nonce
+		}
+		if override.Code != nil {
+			existing.Code = override.Code
+		}
+		if len(override.StateDiff) > 0 && existing.StateDiff == nil {
+			existing.StateDiff = make(map[common.Hash]common.Hash)
+		}
+		for slot, value := range override.StateDiff {
+			existing.StateDiff[slot] = value
+		}
+	}
+}
+
+// callArg creates the JSON-RPC representation of a call.
+func callArg(msg ethereum.CallMsg) map[string]interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+	}
+	if len(msg.Data) > 0 {
+		arg["data"] = hexutil.Bytes(msg.Data)
+	}
+	if msg.Value != nil {
+		arg["value"] = (*hexutil.Big)(msg.Value)
+	}
+	if msg.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(msg.Gas)
+	}
+	if msg.GasPrice != nil {
+		arg["gasPrice"] = (*hexutil.Big)(msg.GasPrice)
+	}
+	return arg
+}
+
+// CallWithOverrides makes a call against the latest block with the given state overrides.
+func CallWithOverrides(ctx context.Context, client *rpc.Client, msg ethereum.CallMsg, overrides StateOverrides) ([]byte, error) {
+	var res hexutil.Bytes
+	if err := client.CallContext(ctx, &res, "eth_call", callArg(msg), "latest", overrides); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// CallStateChanges obtains the changes to state that a call against the
+// latest block with the given state overrides would make.  This requires a
+// node that supports debug_traceCall with the prestateTracer.
+func CallStateChanges(ctx context.Context, client *rpc.Client, msg ethereum.CallMsg, overrides StateOverrides) (StateOverrides, error) {
+	var res struct {
+		Pre  map[common.Address]*prestateAccount `json:"pre"`
+		Post map[common.Address]*prestateAccount `json:"post"`
+	}
+	config := map[string]interface{}{
+		"tracer":         "prestateTracer",
+		"tracerConfig":   map[string]interface{}{"diffMode": true},
+		"stateOverrides": overrides,
+	}
+	if err := client.CallContext(ctx, &res, "debug_traceCall", callArg(msg), "latest", config); err != nil {
+		return nil, err
+	}
+	if res.Pre == nil && res.Post == nil {
+		return nil, fmt.Errorf("tracer did not return state differences")
+	}
+
+	changes := make(StateOverrides)
+	for address, account := range res.Post {
+		override := &AccountOverride{
+			Balance:   account.Balance,
+			Code:      account.Code,
+			StateDiff: account.Storage,
+		}
+		if account.Nonce != nil {
+			nonce := hexutil.Uint64(*account.Nonce)
+			override.Nonce = &nonce
+		}
+		changes[address] = override
+	}
+	// Storage that has been cleared appears only in the pre-state
+	for address, account := range res.Pre {
+		for slot := range account.Storage {
+			override, exists := changes[address]
+			if !exists {
+				override = &AccountOverride{}
+				changes[address] = override
+			}
+			if override.StateDiff == nil {
+				override.StateDiff = make(map[common.Hash]common.Hash)
+			}
+			if _, exists := override.StateDiff[slot]; !exists {
+				override.StateDiff[slot] = common.Hash{}
+			}
+		}
+	}
+	return changes, nil
+}
+
+var (
+	revertErrorSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+	revertPanicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+)
+
+// panicDescriptions are the descriptions of the codes used by Solidity in
+// Panic(uint256) reverts.
+var panicDescriptions = map[uint64]string{
+	0x00: "generic compiler panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic overflow or underflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array encoding",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to uninitialised function",
+}
+
+// IsRevert returns true if the error returned by a call shows that the call
+// reverted, as opposed to failing for some other reason such as a problem
+// with the connection.
+func IsRevert(err error) bool {
+	if err == nil {
+		return false
+	}
+	if revertDataFromError(err) != nil {
+		return true
+	}
+	// Nodes that do not return revert data report the revert in the message,
+	// for example "execution reverted" or "Reverted"
+	return strings.Contains(strings.ToLower(err.Error()), "revert")
+}
+
+// RevertReason obtains a human-readable reason from the error returned by a
+// call that reverted, if available.
+func RevertReason(err error) string {
+	data := revertDataFromError(err)
+	if len(data) < 4 {
+		return err.Error()
+	}
+	switch {
+	case bytes.Equal(data[:4], revertErrorSelector):
+		args, argsErr := abiArguments("string")
+		if argsErr == nil {
+			if values, unpackErr := args.UnpackValues(data[4:]); unpackErr == nil {
+				return values[0].(string)
+			}
+		}
+	case bytes.Equal(data[:4], revertPanicSelector):
+		if len(data) == 36 {
+			code := new(big.Int).SetBytes(data[4:])
+			if code.IsUint64() {
+				if description, exists := panicDescriptions[code.Uint64()]; exists {
+					return fmt.Sprintf("panic 0x%x (%s)", code, description)
+				}
+			}
+			return fmt.Sprintf("panic 0x%x", code)
+		}
+	}
+	return fmt.Sprintf("%v (data 0x%x)", err, data)
+}
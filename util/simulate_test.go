@@ -0,0 +1,107 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// revertError is an error carrying revert data, as returned by the RPC client.
+type revertError struct {
+	data interface{}
+}
+
+func (e *revertError) Error() string {
+	return "execution reverted"
+}
+
+func (e *revertError) ErrorData() interface{} {
+	return e.data
+}
+
+func TestRevertReason(t *testing.T) {
+	tests := []struct {
+		err    error
+		reason string
+	}{
+		{ // 0 - no data
+			err:    errors.New("execution reverted"),
+			reason: "execution reverted",
+		},
+		{ // 1 - Error(string)
+			err:    &revertError{data: "0x08c379a0000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000124e6f7420656e6f7567682062616c616e63650000000000000000000000000000"},
+			reason: "Not enough balance",
+		},
+		{ // 2 - Panic(uint256) with known code
+			err:    &revertError{data: "0x4e487b710000000000000000000000000000000000000000000000000000000000000011"},
+			reason: "panic 0x11 (arithmetic overflow or underflow)",
+		},
+		{ // 3 - Panic(uint256) with unknown code
+			err:    &revertError{data: "0x4e487b710000000000000000000000000000000000000000000000000000000000000099"},
+			reason: "panic 0x99",
+		},
+		{ // 4 - custom error
+			err:    &revertError{data: "0x82b42900"},
+			reason: "execution reverted (data 0x82b42900)",
+		},
+		{ // 5 - data not hex
+			err:    &revertError{data: "reverted"},
+			reason: "execution reverted",
+		},
+	}
+
+	for i, test := range tests {
+		assert.Equal(t, test.reason, RevertReason(test.err), fmt.Sprintf("failed at test %d", i))
+	}
+}
+
+func TestIsRevert(t *testing.T) {
+	tests := []struct {
+		err    error
+		revert bool
+	}{
+		{ // 0 - nil
+			err:    nil,
+			revert: false,
+		},
+		{ // 1 - message only
+			err:    errors.New("execution reverted"),
+			revert: true,
+		},
+		{ // 2 - alternative message
+			err:    errors.New("VM execution error: Reverted"),
+			revert: true,
+		},
+		{ // 3 - revert data
+			err:    &revertError{data: "0x82b42900"},
+			revert: true,
+		},
+		{ // 4 - connection failure
+			err:    errors.New("dial tcp 127.0.0.1:8545: connect: connection refused"),
+			revert: false,
+		},
+		{ // 5 - timeout
+			err:    errors.New("context deadline exceeded"),
+			revert: false,
+		},
+	}
+
+	for i, test := range tests {
+		assert.Equal(t, test.revert, IsRevert(test.err), fmt.Sprintf("failed at test %d", i))
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const siweHeaderSuffix = " wants you to sign in with your Ethereum account:"
+
+var siweNonceRegex = regexp.MustCompile("^[a-zA-Z0-9]{8,}$")
+
+// siweField is a named field of a SIWE message.
+type siweField struct {
+	name     string
+	optional bool
+	value    *string
+}
+
+// SIWEMessage is an EIP-4361 (Sign-In with Ethereum) message.
+type SIWEMessage struct {
+	Domain         string
+	Address        common.Address
+	Statement      string
+	URI            string
+	Version        string
+	ChainID        uint64
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time
+	NotBefore      *time.Time
+	RequestID      string
+	Resources      []string
+}
+
+// String returns the message in its EIP-4361 text form.
+func (m *SIWEMessage) String() string {
+	var sb strings.Builder
+	sb.WriteString(m.Domain)
+	sb.WriteString(siweHeaderSuffix)
+	sb.WriteString("\n")
+	sb.WriteString(m.Address.Hex())
+	sb.WriteString("\n\n")
+	if m.Statement != "" {
+		sb.WriteString(m.Statement)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+	sb.WriteString(fmt.Sprintf("URI: %s\n", m.URI))
+	sb.WriteString(fmt.Sprintf("Version: %s\n", m.Version))
+	sb.WriteString(fmt.Sprintf("Chain ID: %d\n", m.ChainID))
+	sb.WriteString(fmt.Sprintf("Nonce: %s\n", m.Nonce))
+	sb.WriteString(fmt.Sprintf("Issued At: %s", m.IssuedAt.Format(time.RFC3339)))
+	if m.ExpirationTime != nil {
+		sb.WriteString(fmt.Sprintf("\nExpiration Time: %s", m.ExpirationTime.Format(time.RFC3339)))
+	}
+	if m.NotBefore != nil {
+		sb.WriteString(fmt.Sprintf("\nNot Before: %s", m.NotBefore.Format(time.RFC3339)))
+	}
+	if m.RequestID != "" {
+		sb.WriteString(fmt.Sprintf("\nRequest ID: %s", m.RequestID))
+	}
+	if len(m.Resources) > 0 {
+		sb.WriteString("\nResources:")
+		for _, resource := range m.Resources {
+			sb.WriteString(fmt.Sprintf("\n- %s", resource))
+		}
+	}
+	return sb.String()
+}
+
+// Validate checks that the message is well-formed and valid at the given time.
+func (m *SIWEMessage) Validate(now time.Time) error {
+	if m.Domain == "" {
+		return errors.New("domain missing")
+	}
+	if m.URI == "" {
+		return errors.New("URI missing")
+	}
+	if m.Version != "1" {
+		return fmt.Errorf("unsupported version %s", m.Version)
+	}
+	if !siweNonceRegex.MatchString(m.Nonce) {
+		return errors.New("nonce must be at least 8 alphanumeric characters")
+	}
+	if strings.Contains(m.Statement, "\n") {
+		return errors.New("statement cannot contain newlines")
+	}
+	if m.ExpirationTime != nil && !now.Before(*m.ExpirationTime) {
+		return fmt.Errorf("message expired at %s", m.ExpirationTime.Format(time.RFC3339))
+	}
+	if m.NotBefore != nil && now.Before(*m.NotBefore) {
+		return fmt.Errorf("message not valid before %s", m.NotBefore.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// ParseSIWEMessage parses an EIP-4361 message from its text form.
+func ParseSIWEMessage(input string) (*SIWEMessage, error) {
+	lines := strings.Split(strings.TrimRight(strings.Replace(input, "\r\n", "\n", -1), "\n"), "\n")
+	if len(lines) < 8 {
+		return nil, errors.New("message too short")
+	}
+	m := &SIWEMessage{}
+
+	if !strings.HasSuffix(lines[0], siweHeaderSuffix) {
+		return nil, errors.New("invalid message header")
+	}
+	m.Domain = strings.TrimSuffix(lines[0], siweHeaderSuffix)
+	if !common.IsHexAddress(lines[1]) {
+		return nil, fmt.Errorf("invalid address %s", lines[1])
+	}
+	m.Address = common.HexToAddress(lines[1])
+	if lines[1] != m.Address.Hex() {
+		return nil, errors.New("address is not in checksum format")
+	}
+	if lines[2] != "" {
+		return nil, errors.New("missing blank line after address")
+	}
+	line := 3
+	if lines[line] != "" {
+		// Statement present
+		m.Statement = lines[line]
+		line++
+		if lines[line] != "" {
+			return nil, errors.New("missing blank line after statement")
+		}
+	}
+	line++
+
+	var chainID, issuedAt, expirationTime, notBefore string
+	fields := []*siweField{
+		{"URI", false, &m.URI},
+		{"Version", false, &m.Version},
+		{"Chain ID", false, &chainID},
+		{"Nonce", false, &m.Nonce},
+		{"Issued At", false, &issuedAt},
+		{"Expiration Time", true, &expirationTime},
+		{"Not Before", true, &notBefore},
+		{"Request ID", true, &m.RequestID},
+	}
+	for _, field := range fields {
+		prefix := fmt.Sprintf("%s: ", field.name)
+		if line < len(lines) && strings.HasPrefix(lines[line], prefix) {
+			*field.value = strings.TrimPrefix(lines[line], prefix)
+			line++
+			continue
+		}
+		if !field.optional {
+			return nil, fmt.Errorf("missing %s", field.name)
+		}
+	}
+	if line < len(lines) && lines[line] == "Resources:" {
+		line++
+		for ; line < len(lines) && strings.HasPrefix(lines[line], "- "); line++ {
+			m.Resources = append(m.Resources, strings.TrimPrefix(lines[line], "- "))
+		}
+	}
+	if line < len(lines) {
+		return nil, fmt.Errorf("unexpected content %q", lines[line])
+	}
+
+	var err error
+	m.ChainID, err = strconv.ParseUint(chainID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chain ID %s", chainID)
+	}
+	m.IssuedAt, err = time.Parse(time.RFC3339, issuedAt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid issued at time %s", issuedAt)
+	}
+	if expirationTime != "" {
+		t, err := time.Parse(time.RFC3339, expirationTime)
+		if err != nil {
+			return nil, fmt.Errorf("invalid expiration time %s", expirationTime)
+		}
+		m.ExpirationTime = &t
+	}
+	if notBefore != "" {
+		t, err := time.Parse(time.RFC3339, notBefore)
+		if err != nil {
+			return nil, fmt.Errorf("invalid not before time %s", notBefore)
+		}
+		m.NotBefore = &t
+	}
+	return m, nil
+}
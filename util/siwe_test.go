@@ -0,0 +1,111 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSIWEMessage(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{
+			input: "example.com wants you to sign in with your Ethereum account:\n0x5FfC014343cd971B7eb70732021E26C35B744cc4\n\nI accept the terms of service.\n\nURI: https://example.com/login\nVersion: 1\nChain ID: 1\nNonce: 32891756\nIssued At: 2021-09-30T16:25:24Z",
+		},
+		{
+			input: "example.com wants you to sign in with your Ethereum account:\n0x5FfC014343cd971B7eb70732021E26C35B744cc4\n\n\nURI: https://example.com/login\nVersion: 1\nChain ID: 5\nNonce: abcdefgh12\nIssued At: 2021-09-30T16:25:24Z\nExpiration Time: 2021-10-30T16:25:24Z\nNot Before: 2021-09-30T16:25:24Z\nRequest ID: 1234\nResources:\n- ipfs://bafybeiemxf5abjwjbikoz4mc3a3dla6ual3jsgpdr4cjr3oz3evfyavhwq/\n- https://example.com/my-web2-claim.json",
+		},
+		{
+			input: "example.com wants to sign in with your Ethereum account:\n0x5FfC014343cd971B7eb70732021E26C35B744cc4\n\n\nURI: https://example.com/login\nVersion: 1\nChain ID: 1\nNonce: 32891756\nIssued At: 2021-09-30T16:25:24Z",
+			err:   "invalid message header",
+		},
+		{
+			input: "example.com wants you to sign in with your Ethereum account:\n0x5ffc014343cd971b7eb70732021e26c35b744cc4\n\n\nURI: https://example.com/login\nVersion: 1\nChain ID: 1\nNonce: 32891756\nIssued At: 2021-09-30T16:25:24Z",
+			err:   "address is not in checksum format",
+		},
+		{
+			input: "example.com wants you to sign in with your Ethereum account:\n0x5FfC014343cd971B7eb70732021E26C35B744cc4\n\n\nURI: https://example.com/login\nVersion: 1\nNonce: 32891756\nIssued At: 2021-09-30T16:25:24Z",
+			err:   "missing Chain ID",
+		},
+		{
+			input: "example.com wants you to sign in with your Ethereum account:\n0x5FfC014343cd971B7eb70732021E26C35B744cc4\n\n\nURI: https://example.com/login\nVersion: 1\nChain ID: 1\nNonce: 32891756\nIssued At: yesterday",
+			err:   "invalid issued at time yesterday",
+		},
+		{
+			input: "example.com wants you to sign in with your Ethereum account:\n0x5FfC014343cd971B7eb70732021E26C35B744cc4\n\n\nURI: https://example.com/login\nVersion: 1\nChain ID: 1\nNonce: 32891756\nIssued At: 2021-09-30T16:25:24Z\nExtra: value",
+			err:   `unexpected content "Extra: value"`,
+		},
+	}
+
+	for i, tt := range tests {
+		msg, err := ParseSIWEMessage(tt.input)
+		if tt.err != "" {
+			assert.EqualError(t, err, tt.err, fmt.Sprintf("failed at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		assert.Equal(t, tt.input, msg.String(), fmt.Sprintf("failed at test %d", i))
+	}
+}
+
+func TestSIWEMessageValidate(t *testing.T) {
+	issuedAt := time.Date(2021, 9, 30, 16, 25, 24, 0, time.UTC)
+	expirationTime := issuedAt.Add(time.Hour)
+	tests := []struct {
+		msg *SIWEMessage
+		now time.Time
+		err string
+	}{
+		{
+			msg: &SIWEMessage{Domain: "example.com", URI: "https://example.com/", Version: "1", Nonce: "32891756", IssuedAt: issuedAt, ExpirationTime: &expirationTime},
+			now: issuedAt,
+		},
+		{
+			msg: &SIWEMessage{Domain: "example.com", URI: "https://example.com/", Version: "1", Nonce: "32891756", IssuedAt: issuedAt, ExpirationTime: &expirationTime},
+			now: expirationTime,
+			err: "message expired at 2021-09-30T17:25:24Z",
+		},
+		{
+			msg: &SIWEMessage{Domain: "example.com", URI: "https://example.com/", Version: "1", Nonce: "32891756", IssuedAt: issuedAt, NotBefore: &expirationTime},
+			now: issuedAt,
+			err: "message not valid before 2021-09-30T17:25:24Z",
+		},
+		{
+			msg: &SIWEMessage{Domain: "example.com", URI: "https://example.com/", Version: "1", Nonce: "1234", IssuedAt: issuedAt},
+			now: issuedAt,
+			err: "nonce must be at least 8 alphanumeric characters",
+		},
+		{
+			msg: &SIWEMessage{Domain: "example.com", URI: "https://example.com/", Version: "2", Nonce: "32891756", IssuedAt: issuedAt},
+			now: issuedAt,
+			err: "unsupported version 2",
+		},
+	}
+
+	for i, tt := range tests {
+		err := tt.msg.Validate(tt.now)
+		if tt.err != "" {
+			assert.EqualError(t, err, tt.err, fmt.Sprintf("failed at test %d", i))
+		} else {
+			assert.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		}
+	}
+}
@@ -29,6 +29,10 @@ type Contract struct {
 	Name   string
 	Abi    abi.ABI
 	Binary []byte
+	// Enums maps the names of the contract's enums to their members, in
+	// order of declaration.  The ABI does not contain this information, so
+	// it must be supplied separately.
+	Enums map[string][]string
 }
 
 // ParseCombinedJSON parses a combined JSON output of solc for a specific contract
@@ -0,0 +1,117 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var fixedBytesRe = regexp.MustCompile(`^bytes([0-9]+)$`)
+var intRe = regexp.MustCompile(`^(u?)int([0-9]*)$`)
+
+// MappingSlot calculates the storage slot for the value of a mapping with the
+// given key, where the mapping itself is at the given base slot.  The key
+// should already be encoded with EncodeMappingKey().
+func MappingSlot(base common.Hash, key []byte) common.Hash {
+	return crypto.Keccak256Hash(key, base.Bytes())
+}
+
+// ArraySlot calculates the storage slot for the element of a dynamic array
+// with the given index, where the array itself is at the given base slot.
+// This assumes that each element occupies a single slot.
+func ArraySlot(base common.Hash, index *big.Int) common.Hash {
+	start := new(big.Int).SetBytes(crypto.Keccak256(base.Bytes()))
+	slot := new(big.Int).Add(start, index)
+	return common.BigToHash(math.U256(slot))
+}
+
+// StringToSlot converts a decimal or hex string to a storage slot.
+func StringToSlot(input string) (common.Hash, error) {
+	if strings.HasPrefix(input, "0x") {
+		data, err := hex.DecodeString(strings.TrimPrefix(input, "0x"))
+		if err != nil {
+			return common.Hash{}, err
+		}
+		if len(data) > 32 {
+			return common.Hash{}, errors.New("slot too long")
+		}
+		return common.BytesToHash(data), nil
+	}
+	slot, success := new(big.Int).SetString(input, 10)
+	if !success || slot.Sign() < 0 || slot.BitLen() > 256 {
+		return common.Hash{}, fmt.Errorf("invalid slot %s", input)
+	}
+	return common.BigToHash(slot), nil
+}
+
+// EncodeMappingKey encodes a key of the given Solidity type as used when
+// calculating the storage slot of a mapping's value.  Value types are padded
+// to 32 bytes; string and bytes keys are used as-is.
+func EncodeMappingKey(keyType string, key string) ([]byte, error) {
+	switch {
+	case keyType == "address":
+		if !common.IsHexAddress(key) {
+			return nil, fmt.Errorf("invalid address %s", key)
+		}
+		return common.LeftPadBytes(common.HexToAddress(key).Bytes(), 32), nil
+	case keyType == "bool":
+		val, err := strconv.ParseBool(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bool %s", key)
+		}
+		res := make([]byte, 32)
+		if val {
+			res[31] = 1
+		}
+		return res, nil
+	case keyType == "string":
+		return []byte(key), nil
+	case keyType == "bytes":
+		return hex.DecodeString(strings.TrimPrefix(key, "0x"))
+	case fixedBytesRe.MatchString(keyType):
+		size, _ := strconv.Atoi(fixedBytesRe.FindStringSubmatch(keyType)[1])
+		if size < 1 || size > 32 {
+			return nil, fmt.Errorf("invalid type %s", keyType)
+		}
+		data, err := hex.DecodeString(strings.TrimPrefix(key, "0x"))
+		if err != nil {
+			return nil, err
+		}
+		if len(data) > size {
+			return nil, fmt.Errorf("value too long for %s", keyType)
+		}
+		return common.RightPadBytes(data, 32), nil
+	case intRe.MatchString(keyType):
+		val, success := math.ParseBig256(key)
+		if !success {
+			return nil, fmt.Errorf("invalid integer %s", key)
+		}
+		if intRe.FindStringSubmatch(keyType)[1] == "u" && val.Sign() < 0 {
+			return nil, fmt.Errorf("negative value for %s", keyType)
+		}
+		return math.PaddedBigBytes(math.U256(val), 32), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %s", keyType)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMappingSlot(t *testing.T) {
+	tests := []struct {
+		base    string
+		keyType string
+		key     string
+		output  string
+		err     string
+	}{
+		{base: "0", keyType: "uint256", key: "0", output: "0xad3228b676f7d3cd4284a5443f17f1962b36e491b30a40b2405849e597ba5fb5"},
+		{base: "0x00", keyType: "bool", key: "false", output: "0xad3228b676f7d3cd4284a5443f17f1962b36e491b30a40b2405849e597ba5fb5"},
+		{base: "0", keyType: "address", key: "0x0000000000000000000000000000000000000000", output: "0xad3228b676f7d3cd4284a5443f17f1962b36e491b30a40b2405849e597ba5fb5"},
+		{base: "0", keyType: "bytes32", key: "0x", output: "0xad3228b676f7d3cd4284a5443f17f1962b36e491b30a40b2405849e597ba5fb5"},
+		{base: "0", keyType: "string", key: "", output: "0x290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e563"},
+		{base: "0", keyType: "uint256", key: "-1", err: "negative value for uint256"},
+		{base: "0", keyType: "bytes2", key: "0x010203", err: "value too long for bytes2"},
+		{base: "0", keyType: "address", key: "0x01", err: "invalid address 0x01"},
+		{base: "0", keyType: "fixed", key: "1", err: "unsupported key type fixed"},
+	}
+	for i, tt := range tests {
+		base, err := StringToSlot(tt.base)
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		key, err := EncodeMappingKey(tt.keyType, tt.key)
+		if tt.err != "" {
+			assert.EqualError(t, err, tt.err, fmt.Sprintf("failed at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		assert.Equal(t, tt.output, MappingSlot(base, key).Hex(), fmt.Sprintf("failed at test %d", i))
+	}
+}
+
+func TestArraySlot(t *testing.T) {
+	tests := []struct {
+		base   common.Hash
+		index  int64
+		output string
+	}{
+		{base: common.Hash{}, index: 0, output: "0x290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e563"},
+		{base: common.Hash{}, index: 1, output: "0x290decd9548b62a8d60345a988386fc84ba6bc95484008f6362f93160ef3e564"},
+	}
+	for i, tt := range tests {
+		assert.Equal(t, tt.output, ArraySlot(tt.base, big.NewInt(tt.index)).Hex(), fmt.Sprintf("failed at test %d", i))
+	}
+}
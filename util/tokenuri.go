@@ -0,0 +1,121 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// DefaultIPFSGateway is the default gateway used to fetch ipfs:// URIs.
+const DefaultIPFSGateway = "https://ipfs.io/ipfs/"
+
+// DefaultArweaveGateway is the default gateway used to fetch ar:// URIs.
+const DefaultArweaveGateway = "https://arweave.net/"
+
+// SubstituteTokenID replaces the ERC-1155 {id} placeholder in a token URI
+// with the token ID as 64 lower-case hex characters.
+func SubstituteTokenID(uri string, id *big.Int) string {
+	if !strings.Contains(uri, "{id}") {
+		return uri
+	}
+	return strings.Replace(uri, "{id}", fmt.Sprintf("%064x", id), -1)
+}
+
+// DecodeDataURI decodes the contents of an RFC 2397 data: URI.
+func DecodeDataURI(uri string) ([]byte, error) {
+	if !strings.HasPrefix(uri, "data:") {
+		return nil, errors.New("not a data URI")
+	}
+	comma := strings.Index(uri, ",")
+	if comma == -1 {
+		return nil, errors.New("data URI has no data")
+	}
+	header := uri[len("data:"):comma]
+	data := uri[comma+1:]
+	if strings.HasSuffix(header, ";base64") {
+		res, err := base64.StdEncoding.DecodeString(data)
+		if err != nil {
+			// Some contracts omit padding
+			res, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(data, "="))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 data: %v", err)
+		}
+		return res, nil
+	}
+	res, err := url.PathUnescape(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data: %v", err)
+	}
+	return []byte(res), nil
+}
+
+// TokenURIToURL turns a token URI in to an HTTP(S) URL from which it can be
+// fetched.  ipfs:// and ar:// URIs are fetched through gateways, which can
+// be set with the "ipfsgateway" and "arweavegateway" configuration values.
+func TokenURIToURL(uri string) (string, error) {
+	switch {
+	case strings.HasPrefix(uri, "ipfs://"):
+		gateway := viper.GetString("ipfsgateway")
+		if gateway == "" {
+			gateway = DefaultIPFSGateway
+		}
+		path := strings.TrimPrefix(uri, "ipfs://")
+		// Some URIs duplicate the path prefix
+		path = strings.TrimPrefix(path, "ipfs/")
+		return strings.TrimSuffix(gateway, "/") + "/" + path, nil
+	case strings.HasPrefix(uri, "ar://"):
+		gateway := viper.GetString("arweavegateway")
+		if gateway == "" {
+			gateway = DefaultArweaveGateway
+		}
+		return strings.TrimSuffix(gateway, "/") + "/" + strings.TrimPrefix(uri, "ar://"), nil
+	case strings.HasPrefix(uri, "https://"), strings.HasPrefix(uri, "http://"):
+		return uri, nil
+	default:
+		return "", fmt.Errorf("unsupported URI %s", uri)
+	}
+}
+
+// FetchTokenURI obtains the contents of a token URI.  data: URIs are decoded
+// directly; other URIs are fetched over the network.
+func FetchTokenURI(uri string) ([]byte, error) {
+	if strings.HasPrefix(uri, "data:") {
+		return DecodeDataURI(uri)
+	}
+	reqURL, err := TokenURIToURL(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{Timeout: viper.GetDuration("timeout")}
+	resp, err := httpClient.Get(reqURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", reqURL, resp.StatusCode)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
@@ -0,0 +1,182 @@
+// Copyright © 2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubstituteTokenID(t *testing.T) {
+	tests := []struct {
+		uri    string
+		id     *big.Int
+		output string
+	}{
+		{ // 0 - no placeholder
+			uri:    "ipfs://QmdTEBPdNxJFFsH1wRE3YeWHREWDiSex8xhgTnqknyxWgu/1",
+			id:     big.NewInt(1),
+			output: "ipfs://QmdTEBPdNxJFFsH1wRE3YeWHREWDiSex8xhgTnqknyxWgu/1",
+		},
+		{ // 1 - placeholder
+			uri:    "https://token.example.com/{id}.json",
+			id:     big.NewInt(314592),
+			output: "https://token.example.com/000000000000000000000000000000000000000000000000000000000004cce0.json",
+		},
+	}
+
+	for i, test := range tests {
+		assert.Equal(t, test.output, SubstituteTokenID(test.uri, test.id), fmt.Sprintf("failed at test %d", i))
+	}
+}
+
+func TestDecodeDataURI(t *testing.T) {
+	tests := []struct {
+		uri    string
+		output string
+		err    string
+	}{
+		{ // 0 - not a data URI
+			uri: "https://token.example.com/1",
+			err: "not a data URI",
+		},
+		{ // 1 - no data
+			uri: "data:application/json;base64",
+			err: "data URI has no data",
+		},
+		{ // 2 - base64
+			uri:    "data:application/json;base64,eyJuYW1lIjoiVGVzdCJ9",
+			output: `{"name":"Test"}`,
+		},
+		{ // 3 - base64 without padding
+			uri:    "data:application/json;base64,eyJuYW1lIjoiVGVzdDEifQ",
+			output: `{"name":"Test1"}`,
+		},
+		{ // 4 - invalid base64
+			uri: "data:application/json;base64,!!!",
+			err: "invalid base64 data: illegal base64 data at input byte 0",
+		},
+		{ // 5 - percent-encoded
+			uri:    `data:application/json,{"name":"Test%20token"}`,
+			output: `{"name":"Test token"}`,
+		},
+	}
+
+	for i, test := range tests {
+		output, err := DecodeDataURI(test.uri)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("incorrect error at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		assert.Equal(t, test.output, string(output), fmt.Sprintf("incorrect output at test %d", i))
+	}
+}
+
+func TestTokenURIToURL(t *testing.T) {
+	tests := []struct {
+		uri     string
+		gateway string
+		output  string
+		err     string
+	}{
+		{ // 0 - IPFS
+			uri:    "ipfs://QmdTEBPdNxJFFsH1wRE3YeWHREWDiSex8xhgTnqknyxWgu/1.json",
+			output: "https://ipfs.io/ipfs/QmdTEBPdNxJFFsH1wRE3YeWHREWDiSex8xhgTnqknyxWgu/1.json",
+		},
+		{ // 1 - IPFS with duplicated prefix
+			uri:    "ipfs://ipfs/QmdTEBPdNxJFFsH1wRE3YeWHREWDiSex8xhgTnqknyxWgu",
+			output: "https://ipfs.io/ipfs/QmdTEBPdNxJFFsH1wRE3YeWHREWDiSex8xhgTnqknyxWgu",
+		},
+		{ // 2 - IPFS with custom gateway
+			uri:     "ipfs://QmdTEBPdNxJFFsH1wRE3YeWHREWDiSex8xhgTnqknyxWgu",
+			gateway: "http://localhost:8080/ipfs",
+			output:  "http://localhost:8080/ipfs/QmdTEBPdNxJFFsH1wRE3YeWHREWDiSex8xhgTnqknyxWgu",
+		},
+		{ // 3 - Arweave
+			uri:    "ar://bnLyqS6oUVPZUkw0Ay6XCzDd7tIVnL8PZvbNXPtiNhk",
+			output: "https://arweave.net/bnLyqS6oUVPZUkw0Ay6XCzDd7tIVnL8PZvbNXPtiNhk",
+		},
+		{ // 4 - HTTPS
+			uri:    "https://token.example.com/1",
+			output: "https://token.example.com/1",
+		},
+		{ // 5 - unsupported
+			uri: "ftp://token.example.com/1",
+			err: "unsupported URI ftp://token.example.com/1",
+		},
+	}
+
+	defer viper.Set("ipfsgateway", "")
+	for i, test := range tests {
+		viper.Set("ipfsgateway", test.gateway)
+		output, err := TokenURIToURL(test.uri)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("incorrect error at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		assert.Equal(t, test.output, output, fmt.Sprintf("incorrect output at test %d", i))
+	}
+}
+
+func TestFetchTokenURI(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ipfs/QmdTEBPdNxJFFsH1wRE3YeWHREWDiSex8xhgTnqknyxWgu":
+			fmt.Fprint(w, `{"name":"Test"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	viper.Set("ipfsgateway", server.URL+"/ipfs/")
+	defer viper.Set("ipfsgateway", "")
+
+	tests := []struct {
+		uri    string
+		output string
+		err    string
+	}{
+		{ // 0 - data URI
+			uri:    "data:application/json;base64,eyJuYW1lIjoiVGVzdCJ9",
+			output: `{"name":"Test"}`,
+		},
+		{ // 1 - IPFS
+			uri:    "ipfs://QmdTEBPdNxJFFsH1wRE3YeWHREWDiSex8xhgTnqknyxWgu",
+			output: `{"name":"Test"}`,
+		},
+		{ // 2 - not found
+			uri: server.URL + "/missing",
+			err: fmt.Sprintf("%s/missing returned status 404", server.URL),
+		},
+	}
+
+	for i, test := range tests {
+		output, err := FetchTokenURI(test.uri)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err, fmt.Sprintf("incorrect error at test %d", i))
+			continue
+		}
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		assert.Equal(t, test.output, string(output), fmt.Sprintf("incorrect output at test %d", i))
+	}
+}
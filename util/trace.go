@@ -0,0 +1,238 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sort"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// CallFrame is a single call within a trace, as returned by the callTracer.
+type CallFrame struct {
+	Type         string         `json:"type"`
+	From         common.Address `json:"from"`
+	To           common.Address `json:"to"`
+	Value        *hexutil.Big   `json:"value,omitempty"`
+	Gas          hexutil.Uint64 `json:"gas"`
+	GasUsed      hexutil.Uint64 `json:"gasUsed"`
+	Input        hexutil.Bytes  `json:"input"`
+	Output       hexutil.Bytes  `json:"output,omitempty"`
+	Error        string         `json:"error,omitempty"`
+	RevertReason string         `json:"revertReason,omitempty"`
+	Calls        []*CallFrame   `json:"calls,omitempty"`
+}
+
+// TraceCall traces a call at the given block using debug_traceCall.  A nil
+// block traces the call against the latest block.  This requires a node
+// that supports the debug API, and an archive node for historical blocks.
+func TraceCall(ctx context.Context, client *rpc.Client, msg ethereum.CallMsg, block *big.Int) (*CallFrame, error) {
+	blockArg := "latest"
+	if block != nil {
+		blockArg = hexutil.EncodeBig(block)
+	}
+	var res CallFrame
+	if err := client.CallContext(ctx, &res, "debug_traceCall", callArg(msg), blockArg, map[string]string{"tracer": "callTracer"}); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// InternalTransfer is a transfer of Ether made by a contract rather than
+// directly by a transaction.
+type InternalTransfer struct {
+	BlockNumber uint64
+	TxHash      common.Hash
+	Type        string
+	From        common.Address
+	To          common.Address
+	Value       *big.Int
+}
+
+// traceFilterResult is a single trace returned by trace_filter.
+type traceFilterResult struct {
+	Type   string `json:"type"`
+	Action struct {
+		From          common.Address `json:"from"`
+		To            common.Address `json:"to"`
+		Value         *hexutil.Big   `json:"value"`
+		Address       common.Address `json:"address"`
+		RefundAddress common.Address `json:"refundAddress"`
+		Balance       *hexutil.Big   `json:"balance"`
+	} `json:"action"`
+	Result *struct {
+		Address common.Address `json:"address"`
+	} `json:"result"`
+	Error           string      `json:"error"`
+	BlockNumber     uint64      `json:"blockNumber"`
+	TransactionHash common.Hash `json:"transactionHash"`
+	TraceAddress    []uint64    `json:"traceAddress"`
+}
+
+// rpcMethodNotFound is the JSON-RPC error code for a method that is not
+// available.
+const rpcMethodNotFound = -32601
+
+// IsMethodNotFound returns true if the error is a JSON-RPC error stating that
+// the method is not available on the node.
+func IsMethodNotFound(err error) bool {
+	var rpcErr rpc.Error
+	return errors.As(err, &rpcErr) && rpcErr.ErrorCode() == rpcMethodNotFound
+}
+
+// InternalTransfersFromTraceFilter obtains the internal transfers to or from
+// an address between two blocks (inclusive) using trace_filter.  This
+// requires a node that supports the trace API.
+func InternalTransfersFromTraceFilter(ctx context.Context, client *rpc.Client, address common.Address, fromBlock uint64, toBlock uint64) ([]*InternalTransfer, error) {
+	res := make([]*InternalTransfer, 0)
+	// trace_filter requires traces to match both the from and to address
+	// filters if both are supplied, so make separate requests for each.
+	for _, filterField := range []string{"fromAddress", "toAddress"} {
+		var traces []*traceFilterResult
+		filter := map[string]interface{}{
+			"fromBlock": hexutil.EncodeUint64(fromBlock),
+			"toBlock":   hexutil.EncodeUint64(toBlock),
+			filterField: []common.Address{address},
+		}
+		if err := client.CallContext(ctx, &traces, "trace_filter", filter); err != nil {
+			return nil, err
+		}
+		for _, trace := range traces {
+			if len(trace.TraceAddress) == 0 || trace.Error != "" {
+				// Top-level or failed
+				continue
+			}
+			transfer := &InternalTransfer{
+				BlockNumber: trace.BlockNumber,
+				TxHash:      trace.TransactionHash,
+				Type:        trace.Type,
+			}
+			switch trace.Type {
+			case "call":
+				transfer.From = trace.Action.From
+				transfer.To = trace.Action.To
+				transfer.Value = (*big.Int)(trace.Action.Value)
+			case "create":
+				transfer.From = trace.Action.From
+				if trace.Result != nil {
+					transfer.To = trace.Result.Address
+				}
+				transfer.Value = (*big.Int)(trace.Action.Value)
+			case "suicide":
+				transfer.From = trace.Action.Address
+				transfer.To = trace.Action.RefundAddress
+				transfer.Value = (*big.Int)(trace.Action.Balance)
+			default:
+				continue
+			}
+			if transfer.Value == nil || transfer.Value.Sign() == 0 {
+				continue
+			}
+			if filterField == "toAddress" && transfer.From == address {
+				// Already obtained with the from filter
+				continue
+			}
+			res = append(res, transfer)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].BlockNumber < res[j].BlockNumber
+	})
+	return res, nil
+}
+
+// InternalTransfersFromBlockTrace obtains the internal transfers to or from
+// an address in a single block using debug_traceBlockByNumber.  This
+// requires a node that supports the debug API, and an archive node for
+// historical blocks.
+func InternalTransfersFromBlockTrace(ctx context.Context, client *rpc.Client, address common.Address, blockNumber uint64) ([]*InternalTransfer, error) {
+	var traces []*struct {
+		TxHash *common.Hash `json:"txHash"`
+		Result *CallFrame   `json:"result"`
+	}
+	if err := client.CallContext(ctx, &traces, "debug_traceBlockByNumber", hexutil.EncodeUint64(blockNumber), map[string]string{"tracer": "callTracer"}); err != nil {
+		return nil, err
+	}
+
+	var txHashes []common.Hash
+	res := make([]*InternalTransfer, 0)
+	for i, trace := range traces {
+		if trace.Result == nil {
+			continue
+		}
+		frames := internalTransferFrames(trace.Result, address)
+		if len(frames) == 0 {
+			continue
+		}
+		txHash := trace.TxHash
+		if txHash == nil {
+			// Older nodes do not return the transaction hash, so fetch them from the block
+			if txHashes == nil {
+				var block struct {
+					Transactions []common.Hash `json:"transactions"`
+				}
+				if err := client.CallContext(ctx, &block, "eth_getBlockByNumber", hexutil.EncodeUint64(blockNumber), false); err != nil {
+					return nil, err
+				}
+				txHashes = block.Transactions
+			}
+			if i < len(txHashes) {
+				txHash = &txHashes[i]
+			} else {
+				txHash = &common.Hash{}
+			}
+		}
+		for _, frame := range frames {
+			transferType := "call"
+			switch frame.Type {
+			case "CREATE", "CREATE2":
+				transferType = "create"
+			case "SELFDESTRUCT":
+				transferType = "suicide"
+			}
+			res = append(res, &InternalTransfer{
+				BlockNumber: blockNumber,
+				TxHash:      *txHash,
+				Type:        transferType,
+				From:        frame.From,
+				To:          frame.To,
+				Value:       frame.Value.ToInt(),
+			})
+		}
+	}
+	return res, nil
+}
+
+// internalTransferFrames returns the successful subcalls of a frame that
+// transfer value to or from an address.
+func internalTransferFrames(frame *CallFrame, address common.Address) []*CallFrame {
+	res := make([]*CallFrame, 0)
+	for _, call := range frame.Calls {
+		if call.Error != "" {
+			// Failed, so neither it nor its subcalls transferred value
+			continue
+		}
+		if call.Value != nil && call.Value.ToInt().Sign() != 0 && (call.From == address || call.To == address) {
+			res = append(res, call)
+		}
+		res = append(res, internalTransferFrames(call, address)...)
+	}
+	return res
+}
@@ -0,0 +1,67 @@
+// Copyright © 2017-2019 Weald Technology Trading
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsMethodNotFound(t *testing.T) {
+	tests := []struct {
+		code     int
+		message  string
+		notFound bool
+	}{
+		{ // 0 - method not found
+			code:     -32601,
+			message:  "the method trace_filter does not exist/is not available",
+			notFound: true,
+		},
+		{ // 1 - other error
+			code:    -32000,
+			message: "query timeout exceeded",
+		},
+		{ // 2 - invalid parameters
+			code:    -32602,
+			message: "invalid block range",
+		},
+	}
+
+	for i, test := range tests {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"error":{"code":%d,"message":%q}}`, test.code, test.message)
+		}))
+		client, err := rpc.Dial(server.URL)
+		require.Nil(t, err, fmt.Sprintf("failed at test %d", i))
+		_, err = InternalTransfersFromTraceFilter(context.Background(), client, common.Address{}, 1, 2)
+		assert.NotNil(t, err, fmt.Sprintf("failed at test %d", i))
+		assert.Equal(t, test.notFound, IsMethodNotFound(err), fmt.Sprintf("failed at test %d", i))
+		client.Close()
+		server.Close()
+	}
+
+	assert.False(t, IsMethodNotFound(errors.New("method not found")))
+	assert.False(t, IsMethodNotFound(nil))
+}
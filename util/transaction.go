@@ -15,10 +15,15 @@ package util
 
 import (
 	"context"
+	"errors"
+	"math/big"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/spf13/viper"
 )
 
@@ -41,3 +46,79 @@ func WaitForTransaction(client *ethclient.Client, txHash common.Hash, limit time
 	}
 	return false
 }
+
+// WaitForReceipt waits for the transaction to be mined, polling for its
+// receipt at the given interval, or for the limit to expire.  It returns nil
+// if the transaction is not mined within the limit
+func WaitForReceipt(client *ethclient.Client, txHash common.Hash, limit time.Duration, interval time.Duration) *types.Receipt {
+	start := time.Now()
+	first := true
+	for limit == 0 || time.Since(start) < limit {
+		if !first {
+			time.Sleep(interval)
+		} else {
+			first = false
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("timeout"))
+		receipt, err := client.TransactionReceipt(ctx, txHash)
+		cancel()
+		if err == nil && receipt != nil && receipt.BlockNumber != nil {
+			return receipt
+		}
+	}
+	return nil
+}
+
+// BlockNumberForTag obtains the number of the block referenced by a tag such
+// as "finalized" or "safe".  These tags require a post-merge client.
+func BlockNumberForTag(ctx context.Context, client *rpc.Client, tag string) (*big.Int, error) {
+	var header *struct {
+		Number *hexutil.Big `json:"number"`
+	}
+	if err := client.CallContext(ctx, &header, "eth_getBlockByNumber", tag, false); err != nil {
+		return nil, err
+	}
+	if header == nil || header.Number == nil {
+		return nil, errors.New("block not found")
+	}
+	return header.Number.ToInt(), nil
+}
+
+// WaitForFinality waits for a mined transaction to be included in a
+// finalized block, polling at the given interval, or for the limit to
+// expire.  It returns an error if the connection cannot supply the finalized
+// block, for example because it does not support post-merge block tags
+func WaitForFinality(client *ethclient.Client, rpcClient *rpc.Client, txHash common.Hash, limit time.Duration, interval time.Duration) (bool, error) {
+	start := time.Now()
+	first := true
+	checked := false
+	for limit == 0 || time.Since(start) < limit {
+		if !first {
+			time.Sleep(interval)
+		} else {
+			first = false
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("timeout"))
+		receipt, err := client.TransactionReceipt(ctx, txHash)
+		cancel()
+		if err != nil || receipt == nil {
+			// Might have been reorganised out; keep waiting
+			continue
+		}
+		ctx, cancel = context.WithTimeout(context.Background(), viper.GetDuration("timeout"))
+		finalized, err := BlockNumberForTag(ctx, rpcClient, "finalized")
+		cancel()
+		if err != nil {
+			if !checked {
+				return false, err
+			}
+			// Has worked before so assume a temporary failure; keep waiting
+			continue
+		}
+		checked = true
+		if finalized.Cmp(receipt.BlockNumber) >= 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
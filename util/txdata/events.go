@@ -17,6 +17,7 @@ package txdata
 func initEventMap() {
 	AddEventSignature("ABIChanged(bytes32,uint256)")
 	AddEventSignature("AddrChanged(bytes32,address)")
+	AddEventSignature("AddressChanged(bytes32,uint256,bytes)")
 	AddEventSignature("Allowance(address,address,address,uint256)")
 	AddEventSignature("AllowanceSet(address,address,address,uint256)")
 	AddEventSignature("Approval(address,address,uint256)")
@@ -25,6 +26,7 @@ func initEventMap() {
 	AddEventSignature("Burned(address,address,uint256,bytes,bytes)")
 	AddEventSignature("Cleared(bytes32)")
 	AddEventSignature("ContentChanged(bytes32,bytes32)")
+	AddEventSignature("ContenthashChanged(bytes32,bytes)")
 	AddEventSignature("CounterSignatoryCleared(address)")
 	AddEventSignature("CounterSignatorySet(address,address)")
 	AddEventSignature("DeedClosed()")
@@ -47,7 +49,7 @@ func initEventMap() {
 	AddEventSignature("NewInstance(uint256,uint256)")
 	AddEventSignature("NewOwner(bytes32,bytes32,address)")
 	AddEventSignature("NewResolver(bytes32,address)")
-	AddEventSignature("NewTTL(bytes32,uint64")
+	AddEventSignature("NewTTL(bytes32,uint64)")
 	AddEventSignature("OwnerChanged(address)")
 	AddEventSignature("Pause()")
 	AddEventSignature("PausedUntil(uint256)")
@@ -68,7 +70,7 @@ func initEventMap() {
 	AddEventSignature("TokenAdded(address,address)")
 	AddEventSignature("TokenRemoved(address,address)")
 	AddEventSignature("Transfer(address,address,uint256)")
-	AddEventSignature("Transfer(bytes32,address")
+	AddEventSignature("Transfer(bytes32,address)")
 	AddEventSignature("Unpause()")
 	AddEventSignature("Updated(bytes32,bytes,uint16)")
 	AddEventSignature("Updated(bytes32,bytes,uint16,uint256)")
@@ -22,9 +22,10 @@ import (
 
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
-	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/ethereal/util"
 	"golang.org/x/crypto/sha3"
 )
 
@@ -164,7 +165,8 @@ func contractValueToString(client *ethclient.Client, argType abi.Type, index uin
 func valueToString(client *ethclient.Client, argType abi.Type, index uint32, offset uint32, data []byte) (string, error) {
 	switch argType.T {
 	case abi.IntTy:
-		return big.NewInt(0).SetBytes(data[offset+index*32 : offset+index*32+32]).String(), nil
+		// Signed values are two's complement
+		return math.S256(big.NewInt(0).SetBytes(data[offset+index*32 : offset+index*32+32])).String(), nil
 	case abi.UintTy:
 		return big.NewInt(0).SetBytes(data[offset+index*32 : offset+index*32+32]).String(), nil
 	case abi.BoolTy:
@@ -190,7 +192,11 @@ func valueToString(client *ethclient.Client, argType abi.Type, index uint32, off
 		return "[" + strings.Join(res, ",") + "]", nil
 	case abi.AddressTy:
 		address := common.BytesToAddress(data[offset+index*32+12 : offset+index*32+32])
-		return ens.Format(client, address), nil
+		if client == nil {
+			// Offline so cannot reverse resolve
+			return address.Hex(), nil
+		}
+		return util.ENSFormat(client, address), nil
 	case abi.FixedBytesTy:
 		return fmt.Sprintf("0x%x", data[offset+index*32+32-uint32(argType.Size):offset+index*32+32]), nil
 	case abi.BytesTy: